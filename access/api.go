@@ -6,6 +6,7 @@ import (
 	"github.com/onflow/flow/protobuf/go/flow/access"
 	"github.com/onflow/flow/protobuf/go/flow/entities"
 
+	"github.com/onflow/flow-go/engine/access/state_stream"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/model/flow"
 )
@@ -26,6 +27,9 @@ type API interface {
 	GetCollectionByID(ctx context.Context, id flow.Identifier) (*flow.LightCollection, error)
 
 	SendTransaction(ctx context.Context, tx *flow.TransactionBody) error
+	SendAndSubscribeTransactionStatuses(ctx context.Context, tx *flow.TransactionBody) state_stream.Subscription
+	SubscribeTransactionStatuses(ctx context.Context, id flow.Identifier) state_stream.Subscription
+	SimulateTransaction(ctx context.Context, tx *flow.TransactionBody) (*TransactionSimulationResult, error)
 	GetTransaction(ctx context.Context, id flow.Identifier) (*flow.TransactionBody, error)
 	GetTransactionsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.TransactionBody, error)
 	GetTransactionResult(ctx context.Context, id flow.Identifier) (*TransactionResult, error)
@@ -35,6 +39,13 @@ type API interface {
 	GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error)
 	GetAccountAtLatestBlock(ctx context.Context, address flow.Address) (*flow.Account, error)
 	GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error)
+	GetAccountKeyAtLatestBlock(ctx context.Context, address flow.Address, keyIndex uint64) (*flow.AccountPublicKey, error)
+	GetAccountKeyAtBlockHeight(ctx context.Context, address flow.Address, keyIndex uint64, height uint64) (*flow.AccountPublicKey, error)
+	GetAccountKeysAtLatestBlock(ctx context.Context, address flow.Address) ([]flow.AccountPublicKey, error)
+	GetAccountKeysAtBlockHeight(ctx context.Context, address flow.Address, height uint64) ([]flow.AccountPublicKey, error)
+	GetAccountBalance(ctx context.Context, address flow.Address) (uint64, error)
+	GetAccountBalanceAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (uint64, error)
+	GetTransactionsByAddress(ctx context.Context, address flow.Address, limit uint32, cursor *flow.AccountTransactionCursor) (*AccountTransactionsPage, error)
 
 	ExecuteScriptAtLatestBlock(ctx context.Context, script []byte, arguments [][]byte) ([]byte, error)
 	ExecuteScriptAtBlockHeight(ctx context.Context, blockHeight uint64, script []byte, arguments [][]byte) ([]byte, error)
@@ -42,6 +53,7 @@ type API interface {
 
 	GetEventsForHeightRange(ctx context.Context, eventType string, startHeight, endHeight uint64) ([]flow.BlockEvents, error)
 	GetEventsForBlockIDs(ctx context.Context, eventType string, blockIDs []flow.Identifier) ([]flow.BlockEvents, error)
+	GetEventsForHeightRangeWithFilter(ctx context.Context, filter EventFilter, startHeight, endHeight uint64) ([]flow.BlockEvents, error)
 
 	GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error)
 
@@ -59,6 +71,50 @@ type TransactionResult struct {
 	TransactionID flow.Identifier
 	CollectionID  flow.Identifier
 	BlockHeight   uint64
+	Submissions   []TransactionSubmission // submissions of the transaction to collection nodes, if tracked; only returned over gRPC, see TransactionSubmissionsHeader
+}
+
+// TransactionSubmission is a submission of a transaction to a collection node by the access node.
+type TransactionSubmission struct {
+	Height         uint64 // latest finalized block height when the transaction was submitted
+	CollectionNode string // address of the collection node
+	Error          string // error returned by the collection node, empty if it accepted the transaction
+}
+
+// TransactionSimulationResult is the result of executing a transaction against the latest sealed
+// execution state, without submitting it.
+type TransactionSimulationResult struct {
+	ComputationUsed uint64
+	MemoryEstimate  uint64
+	Events          []flow.Event
+	// StorageDelta is the change of the storage used in bytes, for every account whose storage
+	// was updated by the transaction.
+	StorageDelta map[flow.Address]int64
+	Fee          uint64
+	ErrorMessage string
+	BlockID      flow.Identifier
+	BlockHeight  uint64
+}
+
+// AccountTransactionsPage is a page of the transactions an account participated in, ordered from
+// the most recent to the oldest.
+type AccountTransactionsPage struct {
+	Transactions []flow.AccountTransaction
+	// NextCursor is the position of the first transaction of the next page, or nil if this is the
+	// last page.
+	NextCursor *flow.AccountTransactionCursor
+}
+
+// EventFilter selects events by the criteria that are set. At least one of the event type, contract
+// address or transaction ID must be set, and the event type must be set to filter by a field value.
+type EventFilter struct {
+	EventType       flow.EventType
+	ContractAddress *flow.Address
+	TransactionID   *flow.Identifier
+	// FieldName and FieldValue select events whose field has the value, compared by the string
+	// representation of the value, e.g. the hex address without prefix or "10.00000000" for a UFix64.
+	FieldName  string
+	FieldValue string
 }
 
 func TransactionResultToMessage(result *TransactionResult) *access.TransactionResultResponse {
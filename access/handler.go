@@ -2,10 +2,14 @@ package access
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/onflow/flow/protobuf/go/flow/access"
 	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -15,10 +19,16 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 )
 
+// TransactionSubmissionsHeader is the response metadata key of the submissions of a tracked transaction
+// to collection nodes, returned by GetTransactionResult with one value per submission in the order they
+// were made.
+const TransactionSubmissionsHeader = "x-transaction-submissions"
+
 type Handler struct {
 	api                  API
 	chain                flow.Chain
 	signerIndicesDecoder hotstuff.BlockSignerDecoder
+	log                  zerolog.Logger
 }
 
 // HandlerOption is used to hand over optional constructor parameters
@@ -29,6 +39,7 @@ func NewHandler(api API, chain flow.Chain, options ...HandlerOption) *Handler {
 		api:                  api,
 		chain:                chain,
 		signerIndicesDecoder: &signature.NoopBlockSignerDecoder{},
+		log:                  zerolog.Nop(),
 	}
 	for _, opt := range options {
 		opt(h)
@@ -221,6 +232,14 @@ func (h *Handler) GetTransactionResult(
 		return nil, err
 	}
 
+	// the submissions are only metadata, the result is returned even if they can not be set
+	if len(result.Submissions) > 0 {
+		err = grpc.SetHeader(ctx, TransactionSubmissionsMetadata(result.Submissions))
+		if err != nil {
+			h.log.Warn().Err(err).Hex("tx_id", id[:]).Msg("could not set transaction submissions metadata")
+		}
+	}
+
 	return TransactionResultToMessage(result), nil
 }
 
@@ -570,3 +589,24 @@ func WithBlockSignerDecoder(signerIndicesDecoder hotstuff.BlockSignerDecoder) fu
 		handler.signerIndicesDecoder = signerIndicesDecoder
 	}
 }
+
+// WithLogger configures the Handler to log with the provided logger
+func WithLogger(log zerolog.Logger) func(*Handler) {
+	return func(handler *Handler) {
+		handler.log = log
+	}
+}
+
+// TransactionSubmissionsMetadata returns the response metadata of the given submissions of a
+// transaction, e.g. "height=120 collection_node=collection-1:9000 error=..." for each submission.
+func TransactionSubmissionsMetadata(submissions []TransactionSubmission) metadata.MD {
+	md := metadata.MD{}
+	for _, submission := range submissions {
+		value := fmt.Sprintf("height=%d collection_node=%s", submission.Height, submission.CollectionNode)
+		if submission.Error != "" {
+			value += fmt.Sprintf(" error=%q", submission.Error)
+		}
+		md.Append(TransactionSubmissionsHeader, value)
+	}
+	return md
+}
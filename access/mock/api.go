@@ -10,6 +10,8 @@ import (
 	flow "github.com/onflow/flow-go/model/flow"
 
 	mock "github.com/stretchr/testify/mock"
+
+	state_stream "github.com/onflow/flow-go/engine/access/state_stream"
 )
 
 // API is an autogenerated mock type for the API type
@@ -155,6 +157,140 @@ func (_m *API) GetAccountAtLatestBlock(ctx context.Context, address flow.Address
 	return r0, r1
 }
 
+// GetAccountBalance provides a mock function with given fields: ctx, address
+func (_m *API) GetAccountBalance(ctx context.Context, address flow.Address) (uint64, error) {
+	ret := _m.Called(ctx, address)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address) uint64); ok {
+		r0 = rf(ctx, address)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address) error); ok {
+		r1 = rf(ctx, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountBalanceAtBlockHeight provides a mock function with given fields: ctx, address, height
+func (_m *API) GetAccountBalanceAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (uint64, error) {
+	ret := _m.Called(ctx, address, height)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, uint64) uint64); ok {
+		r0 = rf(ctx, address, height)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, uint64) error); ok {
+		r1 = rf(ctx, address, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountKeyAtBlockHeight provides a mock function with given fields: ctx, address, keyIndex, height
+func (_m *API) GetAccountKeyAtBlockHeight(ctx context.Context, address flow.Address, keyIndex uint64, height uint64) (*flow.AccountPublicKey, error) {
+	ret := _m.Called(ctx, address, keyIndex, height)
+
+	var r0 *flow.AccountPublicKey
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, uint64, uint64) *flow.AccountPublicKey); ok {
+		r0 = rf(ctx, address, keyIndex, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.AccountPublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, uint64, uint64) error); ok {
+		r1 = rf(ctx, address, keyIndex, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountKeyAtLatestBlock provides a mock function with given fields: ctx, address, keyIndex
+func (_m *API) GetAccountKeyAtLatestBlock(ctx context.Context, address flow.Address, keyIndex uint64) (*flow.AccountPublicKey, error) {
+	ret := _m.Called(ctx, address, keyIndex)
+
+	var r0 *flow.AccountPublicKey
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, uint64) *flow.AccountPublicKey); ok {
+		r0 = rf(ctx, address, keyIndex)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.AccountPublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, uint64) error); ok {
+		r1 = rf(ctx, address, keyIndex)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountKeysAtBlockHeight provides a mock function with given fields: ctx, address, height
+func (_m *API) GetAccountKeysAtBlockHeight(ctx context.Context, address flow.Address, height uint64) ([]flow.AccountPublicKey, error) {
+	ret := _m.Called(ctx, address, height)
+
+	var r0 []flow.AccountPublicKey
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, uint64) []flow.AccountPublicKey); ok {
+		r0 = rf(ctx, address, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flow.AccountPublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, uint64) error); ok {
+		r1 = rf(ctx, address, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountKeysAtLatestBlock provides a mock function with given fields: ctx, address
+func (_m *API) GetAccountKeysAtLatestBlock(ctx context.Context, address flow.Address) ([]flow.AccountPublicKey, error) {
+	ret := _m.Called(ctx, address)
+
+	var r0 []flow.AccountPublicKey
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address) []flow.AccountPublicKey); ok {
+		r0 = rf(ctx, address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flow.AccountPublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address) error); ok {
+		r1 = rf(ctx, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBlockByHeight provides a mock function with given fields: ctx, height
 func (_m *API) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, flow.BlockStatus, error) {
 	ret := _m.Called(ctx, height)
@@ -344,6 +480,29 @@ func (_m *API) GetEventsForHeightRange(ctx context.Context, eventType string, st
 	return r0, r1
 }
 
+// GetEventsForHeightRangeWithFilter provides a mock function with given fields: ctx, filter, startHeight, endHeight
+func (_m *API) GetEventsForHeightRangeWithFilter(ctx context.Context, filter access.EventFilter, startHeight uint64, endHeight uint64) ([]flow.BlockEvents, error) {
+	ret := _m.Called(ctx, filter, startHeight, endHeight)
+
+	var r0 []flow.BlockEvents
+	if rf, ok := ret.Get(0).(func(context.Context, access.EventFilter, uint64, uint64) []flow.BlockEvents); ok {
+		r0 = rf(ctx, filter, startHeight, endHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flow.BlockEvents)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, access.EventFilter, uint64, uint64) error); ok {
+		r1 = rf(ctx, filter, startHeight, endHeight)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetExecutionResultByID provides a mock function with given fields: ctx, id
 func (_m *API) GetExecutionResultByID(ctx context.Context, id flow.Identifier) (*flow.ExecutionResult, error) {
 	ret := _m.Called(ctx, id)
@@ -579,6 +738,29 @@ func (_m *API) GetTransactionResultsByBlockID(ctx context.Context, blockID flow.
 	return r0, r1
 }
 
+// GetTransactionsByAddress provides a mock function with given fields: ctx, address, limit, cursor
+func (_m *API) GetTransactionsByAddress(ctx context.Context, address flow.Address, limit uint32, cursor *flow.AccountTransactionCursor) (*access.AccountTransactionsPage, error) {
+	ret := _m.Called(ctx, address, limit, cursor)
+
+	var r0 *access.AccountTransactionsPage
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, uint32, *flow.AccountTransactionCursor) *access.AccountTransactionsPage); ok {
+		r0 = rf(ctx, address, limit, cursor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*access.AccountTransactionsPage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, uint32, *flow.AccountTransactionCursor) error); ok {
+		r1 = rf(ctx, address, limit, cursor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionsByBlockID provides a mock function with given fields: ctx, blockID
 func (_m *API) GetTransactionsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.TransactionBody, error) {
 	ret := _m.Called(ctx, blockID)
@@ -616,6 +798,22 @@ func (_m *API) Ping(ctx context.Context) error {
 	return r0
 }
 
+// SendAndSubscribeTransactionStatuses provides a mock function with given fields: ctx, tx
+func (_m *API) SendAndSubscribeTransactionStatuses(ctx context.Context, tx *flow.TransactionBody) state_stream.Subscription {
+	ret := _m.Called(ctx, tx)
+
+	var r0 state_stream.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, *flow.TransactionBody) state_stream.Subscription); ok {
+		r0 = rf(ctx, tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(state_stream.Subscription)
+		}
+	}
+
+	return r0
+}
+
 // SendTransaction provides a mock function with given fields: ctx, tx
 func (_m *API) SendTransaction(ctx context.Context, tx *flow.TransactionBody) error {
 	ret := _m.Called(ctx, tx)
@@ -630,6 +828,45 @@ func (_m *API) SendTransaction(ctx context.Context, tx *flow.TransactionBody) er
 	return r0
 }
 
+// SimulateTransaction provides a mock function with given fields: ctx, tx
+func (_m *API) SimulateTransaction(ctx context.Context, tx *flow.TransactionBody) (*access.TransactionSimulationResult, error) {
+	ret := _m.Called(ctx, tx)
+
+	var r0 *access.TransactionSimulationResult
+	if rf, ok := ret.Get(0).(func(context.Context, *flow.TransactionBody) *access.TransactionSimulationResult); ok {
+		r0 = rf(ctx, tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*access.TransactionSimulationResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *flow.TransactionBody) error); ok {
+		r1 = rf(ctx, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubscribeTransactionStatuses provides a mock function with given fields: ctx, id
+func (_m *API) SubscribeTransactionStatuses(ctx context.Context, id flow.Identifier) state_stream.Subscription {
+	ret := _m.Called(ctx, id)
+
+	var r0 state_stream.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) state_stream.Subscription); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(state_stream.Subscription)
+		}
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewAPI interface {
 	mock.TestingT
 	Cleanup(func())
@@ -74,6 +74,11 @@ func NewTransactionValidator(
 	}
 }
 
+// MaxGasLimit returns the maximum gas limit of the transactions accepted by the validator.
+func (v *TransactionValidator) MaxGasLimit() uint64 {
+	return v.options.MaxGasLimit
+}
+
 func (v *TransactionValidator) Validate(tx *flow.TransactionBody) (err error) {
 	err = v.checkTxSizeLimit(tx)
 	if err != nil {
@@ -0,0 +1,33 @@
+package access
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+)
+
+var _ commands.AdminCommand = (*GetExecutionNodeHealthCommand)(nil)
+
+// GetExecutionNodeHealthCommand returns the health of the execution nodes the access node sends
+// requests to, from the healthiest to the least healthy.
+type GetExecutionNodeHealthCommand struct {
+	nodeHealth *backend.ExecutionNodeHealth
+}
+
+func (g *GetExecutionNodeHealthCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	return commands.ConvertToInterfaceList(g.nodeHealth.Scores())
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetExecutionNodeHealthCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
+
+func NewGetExecutionNodeHealthCommand(nodeHealth *backend.ExecutionNodeHealth) commands.AdminCommand {
+	return &GetExecutionNodeHealthCommand{
+		nodeHealth: nodeHealth,
+	}
+}
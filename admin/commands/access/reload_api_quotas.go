@@ -0,0 +1,46 @@
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/common/rpc"
+)
+
+var _ commands.AdminCommand = (*ReloadAPIQuotasCommand)(nil)
+
+// ReloadAPIQuotasCommand reloads the API keys and per-client quotas from the quota config file,
+// which resets the quota usage of all clients.
+type ReloadAPIQuotasCommand struct {
+	quotas     *rpc.Quotas
+	configPath string
+}
+
+func (r *ReloadAPIQuotasCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	config, err := rpc.LoadQuotaConfig(r.configPath)
+	if err != nil {
+		return nil, admin.NewInvalidAdminReqErrorf("failed to load quota config: %w", err)
+	}
+
+	err = r.quotas.Update(config)
+	if err != nil {
+		return nil, admin.NewInvalidAdminReqErrorf("failed to update quotas: %w", err)
+	}
+
+	return fmt.Sprintf("loaded %d API keys", len(config.APIKeys)), nil
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (r *ReloadAPIQuotasCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
+
+func NewReloadAPIQuotasCommand(quotas *rpc.Quotas, configPath string) commands.AdminCommand {
+	return &ReloadAPIQuotasCommand{
+		quotas:     quotas,
+		configPath: configPath,
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/onflow/go-bitswap"
 
 	"github.com/onflow/flow-go/admin/commands"
+	accessCommands "github.com/onflow/flow-go/admin/commands/access"
 	stateSyncCommands "github.com/onflow/flow-go/admin/commands/state_synchronization"
 	storageCommands "github.com/onflow/flow-go/admin/commands/storage"
 	"github.com/onflow/flow-go/cmd"
@@ -34,15 +36,19 @@ import (
 	"github.com/onflow/flow-go/consensus/hotstuff/verification"
 	recovery "github.com/onflow/flow-go/consensus/recovery/protocol"
 	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/engine/access/apiproxy"
 	"github.com/onflow/flow-go/engine/access/ingestion"
 	pingeng "github.com/onflow/flow-go/engine/access/ping"
+	"github.com/onflow/flow-go/engine/access/rest"
 	"github.com/onflow/flow-go/engine/access/rpc"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
 	"github.com/onflow/flow-go/engine/access/state_stream"
 	"github.com/onflow/flow-go/engine/common/follower"
 	followereng "github.com/onflow/flow-go/engine/common/follower"
 	"github.com/onflow/flow-go/engine/common/requester"
+	commonrpc "github.com/onflow/flow-go/engine/common/rpc"
 	synceng "github.com/onflow/flow-go/engine/common/synchronization"
+	"github.com/onflow/flow-go/model/bootstrap"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
@@ -50,6 +56,7 @@ import (
 	"github.com/onflow/flow-go/module/buffer"
 	"github.com/onflow/flow-go/module/chainsync"
 	"github.com/onflow/flow-go/module/compliance"
+	"github.com/onflow/flow-go/module/execution"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
 	finalizer "github.com/onflow/flow-go/module/finalizer/consensus"
 	"github.com/onflow/flow-go/module/id"
@@ -57,6 +64,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/metrics/unstaked"
 	"github.com/onflow/flow-go/module/state_synchronization"
+	"github.com/onflow/flow-go/module/state_synchronization/indexer"
 	edrequester "github.com/onflow/flow-go/module/state_synchronization/requester"
 	"github.com/onflow/flow-go/network"
 	netcache "github.com/onflow/flow-go/network/cache"
@@ -113,6 +121,7 @@ type AccessNodeConfig struct {
 	rpcConf                      rpc.Config
 	ExecutionNodeAddress         string // deprecated
 	HistoricalAccessRPCs         []access.AccessAPIClient
+	HistoricalSporks             []apiproxy.HistoricalSpork
 	logTxTimeToFinalized         bool
 	logTxTimeToExecuted          bool
 	logTxTimeToFinalizedExecuted bool
@@ -122,6 +131,18 @@ type AccessNodeConfig struct {
 	executionDataDir             string
 	executionDataStartHeight     uint64
 	executionDataConfig          edrequester.ExecutionDataConfig
+	stateStreamConf              state_stream.Config
+	stateStreamFilterConf        map[string]int
+	executionStateIndexEnabled   bool
+	eventIndexEnabled            bool
+	eventIndexFields             []string
+	indexedEventFields           bstorage.IndexedEventFields
+	accountTxIndexEnabled        bool
+	apiQuotaConfigPath           string
+	executionNodeHealthConf      backend.ExecutionNodeHealthConfig
+	executionStateCheckpoint     string
+	scriptExecutionMode          string
+	historicalSporkAddrs         map[string]string
 	PublicNetworkConfig          PublicNetworkConfig
 }
 
@@ -140,21 +161,39 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 		collectionGRPCPort: 9000,
 		executionGRPCPort:  9000,
 		rpcConf: rpc.Config{
-			UnsecureGRPCListenAddr:    "0.0.0.0:9000",
-			SecureGRPCListenAddr:      "0.0.0.0:9001",
-			StateStreamListenAddr:     "",
-			HTTPListenAddr:            "0.0.0.0:8000",
-			RESTListenAddr:            "",
-			CollectionAddr:            "",
-			HistoricalAccessAddrs:     "",
-			CollectionClientTimeout:   3 * time.Second,
-			ExecutionClientTimeout:    3 * time.Second,
-			ConnectionPoolSize:        backend.DefaultConnectionPoolSize,
-			MaxHeightRange:            backend.DefaultMaxHeightRange,
+			UnsecureGRPCListenAddr:  "0.0.0.0:9000",
+			SecureGRPCListenAddr:    "0.0.0.0:9001",
+			StateStreamListenAddr:   "",
+			HTTPListenAddr:          "0.0.0.0:8000",
+			RESTListenAddr:          "",
+			CollectionAddr:          "",
+			HistoricalAccessAddrs:   "",
+			CollectionClientTimeout: 3 * time.Second,
+			ExecutionClientTimeout:  3 * time.Second,
+			ConnectionPoolSize:      backend.DefaultConnectionPoolSize,
+			MaxHeightRange:          backend.DefaultMaxHeightRange,
+			RESTSubscription: rest.SubscriptionConfig{
+				PollInterval:      rest.DefaultSubscriptionPollInterval,
+				MaxStartHeightLag: rest.DefaultSubscriptionMaxStartHeightLag,
+			},
+			ScriptResultCache: backend.ScriptResultCacheConfig{
+				Size:           backend.DefaultScriptResultCacheSize,
+				MaxBytes:       backend.DefaultScriptResultCacheMaxBytes,
+				MaxResultBytes: backend.DefaultScriptResultCacheMaxResultBytes,
+			},
 			PreferredExecutionNodeIDs: nil,
 			FixedExecutionNodeIDs:     nil,
 			MaxExecutionDataMsgSize:   grpcutils.DefaultMaxMsgSize,
 			MaxMsgSize:                grpcutils.DefaultMaxMsgSize,
+			ResultVerification: backend.ResultVerificationConfig{
+				Nodes:  backend.DefaultResultVerificationNodes,
+				Quorum: 0,
+			},
+			SubmissionTracking: backend.SubmissionTrackingConfig{
+				Enabled:          false,
+				ResubmitInterval: backend.DefaultResubmitInterval,
+				MaxTracked:       backend.DefaultMaxTrackedTransactions,
+			},
 		},
 		ExecutionNodeAddress:         "localhost:9000",
 		logTxTimeToFinalized:         false,
@@ -181,6 +220,20 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 			RetryDelay:         edrequester.DefaultRetryDelay,
 			MaxRetryDelay:      edrequester.DefaultMaxRetryDelay,
 		},
+		stateStreamConf: state_stream.Config{
+			ClientSendTimeout:    state_stream.DefaultSendTimeout,
+			ClientSendBufferSize: state_stream.DefaultSendBufferSize,
+			EventFilterConfig:    state_stream.DefaultEventFilterConfig,
+		},
+		executionStateIndexEnabled: false,
+		eventIndexEnabled:          false,
+		eventIndexFields:           nil,
+		accountTxIndexEnabled:      false,
+		apiQuotaConfigPath:         "",
+		executionNodeHealthConf:    backend.DefaultExecutionNodeHealthConfig(),
+		executionStateCheckpoint:   "",
+		scriptExecutionMode:        backend.ScriptExecutionModeExecutionNodesOnly.String(),
+		historicalSporkAddrs:       nil,
 	}
 }
 
@@ -213,6 +266,10 @@ type FlowAccessNodeBuilder struct {
 	ExecutionDataDownloader    execution_data.Downloader
 	ExecutionDataRequester     state_synchronization.ExecutionDataRequester
 	ExecutionDataStore         execution_data.ExecutionDataStore
+	RegisterIndex              storage.RegisterIndex
+	EventIndex                 storage.EventIndex
+	AccountTransactions        storage.AccountTransactions
+	ScriptExecutor             *execution.Scripts
 
 	// The sync engine participants provider is the libp2p peer store for the access node
 	// which is not available until after the network has started.
@@ -539,25 +596,134 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 			return builder.ExecutionDataRequester, nil
 		})
 
+	if builder.executionStateIndexEnabled {
+		builder.
+			Module("register index", func(node *cmd.NodeConfig) error {
+				registers, err := bstorage.NewRegisters(node.DB)
+				if err != nil {
+					return fmt.Errorf("could not create register index: %w", err)
+				}
+				builder.RegisterIndex = registers
+				return nil
+			}).
+			Module("event index", func(node *cmd.NodeConfig) error {
+				if !builder.eventIndexEnabled {
+					return nil
+				}
+				events, err := bstorage.NewEventIndex(node.DB, builder.indexedEventFields)
+				if err != nil {
+					return fmt.Errorf("could not create event index: %w", err)
+				}
+				builder.EventIndex = events
+				return nil
+			}).
+			Module("script executor", func(node *cmd.NodeConfig) error {
+				var err error
+				builder.ScriptExecutor, err = execution.NewScripts(
+					node.Logger,
+					node.FvmOptions,
+					node.Storage.Headers,
+					builder.RegisterIndex,
+					execution.DefaultScriptExecutionTimeLimit,
+				)
+				return err
+			}).
+			Component("execution state indexer", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+				// bootstrap the register index from the checkpoint at the initial execution data
+				// height the first time indexing is enabled
+				_, err := builder.RegisterIndex.FirstHeight()
+				if err != nil {
+					if !errors.Is(err, storage.ErrNotBootstrapped) {
+						return nil, fmt.Errorf("could not get first indexed height: %w", err)
+					}
+
+					height := builder.executionDataConfig.InitialBlockHeight
+					header, err := node.Storage.Headers.ByHeight(height)
+					if err != nil {
+						return nil, fmt.Errorf("could not get header for height %d: %w", height, err)
+					}
+					seal, err := node.Storage.Seals.FinalizedSealForBlock(header.ID())
+					if err != nil {
+						return nil, fmt.Errorf("could not get seal for block %s: %w", header.ID(), err)
+					}
+
+					checkpoint := builder.executionStateCheckpoint
+					if checkpoint == "" {
+						checkpoint = filepath.Join(node.BootstrapDir, bootstrap.PathRootCheckpoint)
+					}
+
+					err = indexer.Bootstrap(node.Logger, builder.RegisterIndex, checkpoint, height, seal.FinalState)
+					if err != nil {
+						return nil, fmt.Errorf("could not bootstrap register index: %w", err)
+					}
+				}
+
+				highestAvailableHeight, err := processedNotifications.ProcessedIndex()
+				if err != nil {
+					if !errors.Is(err, storage.ErrNotFound) {
+						return nil, fmt.Errorf("could not get highest notified execution data height: %w", err)
+					}
+					highestAvailableHeight = builder.executionDataConfig.InitialBlockHeight
+				}
+
+				stateIndexer := indexer.New(
+					node.Logger,
+					builder.RegisterIndex,
+					builder.EventIndex,
+					node.Storage.Headers,
+					node.Storage.Seals,
+					node.Storage.Results,
+					builder.ExecutionDataStore,
+					highestAvailableHeight,
+				)
+				builder.ExecutionDataRequester.AddOnExecutionDataFetchedConsumer(stateIndexer.OnExecutionData)
+
+				return stateIndexer, nil
+			})
+	}
+
 	if builder.rpcConf.StateStreamListenAddr != "" {
 		builder.Component("exec state stream engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
-			conf := state_stream.Config{
-				ListenAddr:              builder.rpcConf.StateStreamListenAddr,
-				MaxExecutionDataMsgSize: builder.rpcConf.MaxExecutionDataMsgSize,
-				RpcMetricsEnabled:       builder.rpcMetricsEnabled,
+			builder.stateStreamConf.ListenAddr = builder.rpcConf.StateStreamListenAddr
+			builder.stateStreamConf.MaxExecutionDataMsgSize = builder.rpcConf.MaxExecutionDataMsgSize
+			builder.stateStreamConf.RpcMetricsEnabled = builder.rpcMetricsEnabled
+
+			for key, value := range builder.stateStreamFilterConf {
+				switch key {
+				case "EventTypes":
+					builder.stateStreamConf.EventFilterConfig.MaxEventTypes = value
+				case "Addresses":
+					builder.stateStreamConf.EventFilterConfig.MaxAddresses = value
+				case "Contracts":
+					builder.stateStreamConf.EventFilterConfig.MaxContracts = value
+				}
+			}
+
+			// the highest height with available execution data is the last height the requester
+			// sent notifications for. Any later heights will be notified once the requester starts.
+			highestAvailableHeight, err := processedNotifications.ProcessedIndex()
+			if err != nil {
+				if !errors.Is(err, storage.ErrNotFound) {
+					return nil, fmt.Errorf("could not get highest notified execution data height: %w", err)
+				}
+				highestAvailableHeight = builder.executionDataConfig.InitialBlockHeight
 			}
 
 			builder.StateStreamEng = state_stream.NewEng(
-				conf,
+				builder.stateStreamConf,
 				builder.ExecutionDataStore,
 				node.Storage.Headers,
 				node.Storage.Seals,
 				node.Storage.Results,
 				node.Logger,
 				node.RootChainID,
+				builder.executionDataConfig.InitialBlockHeight,
+				highestAvailableHeight,
 				builder.apiRatelimits,
 				builder.apiBurstlimits,
 			)
+			builder.ExecutionDataRequester.AddOnExecutionDataFetchedConsumer(builder.StateStreamEng.OnExecutionData)
+
 			return builder.StateStreamEng, nil
 		})
 	}
@@ -593,22 +759,36 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.StringVar(&builder.rpcConf.StateStreamListenAddr, "state-stream-addr", defaultConfig.rpcConf.StateStreamListenAddr, "the address the state stream server listens on (if empty the server will not be started)")
 		flags.StringVarP(&builder.rpcConf.HTTPListenAddr, "http-addr", "h", defaultConfig.rpcConf.HTTPListenAddr, "the address the http proxy server listens on")
 		flags.StringVar(&builder.rpcConf.RESTListenAddr, "rest-addr", defaultConfig.rpcConf.RESTListenAddr, "the address the REST server listens on (if empty the REST server will not be started)")
+		flags.DurationVar(&builder.rpcConf.RESTSubscription.PollInterval, "rest-subscription-poll-interval", defaultConfig.rpcConf.RESTSubscription.PollInterval, "interval at which new data is checked for each REST API subscription")
+		flags.Uint64Var(&builder.rpcConf.RESTSubscription.MaxStartHeightLag, "rest-subscription-max-start-height-lag", defaultConfig.rpcConf.RESTSubscription.MaxStartHeightLag, "maximum number of blocks the start height of a REST API subscription may be behind the latest block")
 		flags.StringVarP(&builder.rpcConf.CollectionAddr, "static-collection-ingress-addr", "", defaultConfig.rpcConf.CollectionAddr, "the address (of the collection node) to send transactions to")
 		flags.StringVarP(&builder.ExecutionNodeAddress, "script-addr", "s", defaultConfig.ExecutionNodeAddress, "the address (of the execution node) forward the script to")
 		flags.StringVarP(&builder.rpcConf.HistoricalAccessAddrs, "historical-access-addr", "", defaultConfig.rpcConf.HistoricalAccessAddrs, "comma separated rpc addresses for historical access nodes")
+		flags.StringToStringVar(&builder.historicalSporkAddrs, "historical-spork-addrs", defaultConfig.historicalSporkAddrs, "rpc addresses of access nodes of previous sporks by spork root height, to which requests for data of these sporks are routed e.g. 7601063=access-001.mainnet1.nodes.onflow.org:9000,8742959=access-001.mainnet2.nodes.onflow.org:9000")
 		flags.DurationVar(&builder.rpcConf.CollectionClientTimeout, "collection-client-timeout", defaultConfig.rpcConf.CollectionClientTimeout, "grpc client timeout for a collection node")
 		flags.DurationVar(&builder.rpcConf.ExecutionClientTimeout, "execution-client-timeout", defaultConfig.rpcConf.ExecutionClientTimeout, "grpc client timeout for an execution node")
 		flags.UintVar(&builder.rpcConf.ConnectionPoolSize, "connection-pool-size", defaultConfig.rpcConf.ConnectionPoolSize, "maximum number of connections allowed in the connection pool, size of 0 disables the connection pooling, and anything less than the default size will be overridden to use the default size")
 		flags.UintVar(&builder.rpcConf.MaxMsgSize, "rpc-max-message-size", grpcutils.DefaultMaxMsgSize, "the maximum message size in bytes for messages sent or received over grpc")
 		flags.UintVar(&builder.rpcConf.MaxHeightRange, "rpc-max-height-range", defaultConfig.rpcConf.MaxHeightRange, "maximum size for height range requests")
+		flags.UintVar(&builder.rpcConf.ScriptResultCache.Size, "script-result-cache-size", defaultConfig.rpcConf.ScriptResultCache.Size, "number of results of scripts executed on execution nodes at sealed blocks to cache (0 disables the cache)")
+		flags.Uint64Var(&builder.rpcConf.ScriptResultCache.MaxBytes, "script-result-cache-max-bytes", defaultConfig.rpcConf.ScriptResultCache.MaxBytes, "maximum total size in bytes of the cached script results")
+		flags.Uint64Var(&builder.rpcConf.ScriptResultCache.MaxResultBytes, "script-result-cache-max-result-bytes", defaultConfig.rpcConf.ScriptResultCache.MaxResultBytes, "maximum size in bytes of a cached script result, larger results are not cached")
 		flags.UintVar(&builder.rpcConf.MaxExecutionDataMsgSize, "max-block-msg-size", defaultConfig.rpcConf.MaxExecutionDataMsgSize, "maximum size for a gRPC message containing block execution data")
 		flags.StringSliceVar(&builder.rpcConf.PreferredExecutionNodeIDs, "preferred-execution-node-ids", defaultConfig.rpcConf.PreferredExecutionNodeIDs, "comma separated list of execution nodes ids to choose from when making an upstream call e.g. b4a4dbdcd443d...,fb386a6a... etc.")
+		flags.UintVar(&builder.rpcConf.ResultVerification.Nodes, "result-verification-nodes", defaultConfig.rpcConf.ResultVerification.Nodes, "number of execution nodes each script and event request is sent to when result-verification-quorum is set")
+		flags.UintVar(&builder.rpcConf.ResultVerification.Quorum, "result-verification-quorum", defaultConfig.rpcConf.ResultVerification.Quorum, "number of execution nodes which must return the same script result or events for the response to be returned, must be a majority of result-verification-nodes (0 to send requests to a single execution node)")
+		flags.UintVar(&builder.executionNodeHealthConf.MaxFailures, "execution-node-max-failures", defaultConfig.executionNodeHealthConf.MaxFailures, "number of consecutive failed requests after which no requests are sent to an execution node until execution-node-restore-timeout elapses (0 to always send requests)")
+		flags.DurationVar(&builder.executionNodeHealthConf.RestoreTimeout, "execution-node-restore-timeout", defaultConfig.executionNodeHealthConf.RestoreTimeout, "time during which no requests are sent to an execution node after execution-node-max-failures consecutive failed requests e.g. 1m")
+		flags.DurationVar(&builder.executionNodeHealthConf.LatencyTarget, "execution-node-latency-target", defaultConfig.executionNodeHealthConf.LatencyTarget, "latency of a healthy execution node, slower execution nodes are sent requests last e.g. 500ms")
 		flags.StringSliceVar(&builder.rpcConf.FixedExecutionNodeIDs, "fixed-execution-node-ids", defaultConfig.rpcConf.FixedExecutionNodeIDs, "comma separated list of execution nodes ids to choose from when making an upstream call if no matching preferred execution id is found e.g. b4a4dbdcd443d...,fb386a6a... etc.")
 		flags.BoolVar(&builder.logTxTimeToFinalized, "log-tx-time-to-finalized", defaultConfig.logTxTimeToFinalized, "log transaction time to finalized")
 		flags.BoolVar(&builder.logTxTimeToExecuted, "log-tx-time-to-executed", defaultConfig.logTxTimeToExecuted, "log transaction time to executed")
 		flags.BoolVar(&builder.logTxTimeToFinalizedExecuted, "log-tx-time-to-finalized-executed", defaultConfig.logTxTimeToFinalizedExecuted, "log transaction time to finalized and executed")
 		flags.BoolVar(&builder.pingEnabled, "ping-enabled", defaultConfig.pingEnabled, "whether to enable the ping process that pings all other peers and report the connectivity to metrics")
 		flags.BoolVar(&builder.retryEnabled, "retry-enabled", defaultConfig.retryEnabled, "whether to enable the retry mechanism at the access node level")
+		flags.BoolVar(&builder.rpcConf.SubmissionTracking.Enabled, "tx-submission-tracking-enabled", defaultConfig.rpcConf.SubmissionTracking.Enabled, "whether to track sent transactions until they are included in a finalized block or expire, resubmitting them to other collection nodes of their cluster. Submissions are returned in the x-transaction-submissions header of gRPC GetTransactionResult responses only")
+		flags.Uint64Var(&builder.rpcConf.SubmissionTracking.ResubmitInterval, "tx-resubmit-interval", defaultConfig.rpcConf.SubmissionTracking.ResubmitInterval, "number of finalized blocks without a tracked transaction being included after which it is resubmitted")
+		flags.UintVar(&builder.rpcConf.SubmissionTracking.MaxTracked, "tx-submission-tracking-max", defaultConfig.rpcConf.SubmissionTracking.MaxTracked, "maximum number of transactions tracked at once, the least recently sent is no longer tracked when it is reached")
 		flags.BoolVar(&builder.rpcMetricsEnabled, "rpc-metrics-enabled", defaultConfig.rpcMetricsEnabled, "whether to enable the rpc metrics")
 		flags.StringVarP(&builder.nodeInfoFile, "node-info-file", "", defaultConfig.nodeInfoFile, "full path to a json file which provides more details about nodes when reporting its reachability metrics")
 		flags.StringToIntVar(&builder.apiRatelimits, "api-rate-limits", defaultConfig.apiRatelimits, "per second rate limits for Access API methods e.g. Ping=300,GetTransaction=500 etc.")
@@ -625,10 +805,54 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.DurationVar(&builder.executionDataConfig.MaxFetchTimeout, "execution-data-max-fetch-timeout", defaultConfig.executionDataConfig.MaxFetchTimeout, "maximum timeout to use when fetching execution data from the network e.g. 300s")
 		flags.DurationVar(&builder.executionDataConfig.RetryDelay, "execution-data-retry-delay", defaultConfig.executionDataConfig.RetryDelay, "initial delay for exponential backoff when fetching execution data fails e.g. 10s")
 		flags.DurationVar(&builder.executionDataConfig.MaxRetryDelay, "execution-data-max-retry-delay", defaultConfig.executionDataConfig.MaxRetryDelay, "maximum delay for exponential backoff when fetching execution data fails e.g. 5m")
+
+		// Execution State Streaming API
+		flags.DurationVar(&builder.stateStreamConf.ClientSendTimeout, "state-stream-send-timeout", defaultConfig.stateStreamConf.ClientSendTimeout, "maximum wait before timing out while sending a response to a streaming client e.g. 30s")
+		flags.UintVar(&builder.stateStreamConf.ClientSendBufferSize, "state-stream-send-buffer-size", defaultConfig.stateStreamConf.ClientSendBufferSize, "maximum number of responses to buffer within a stream")
+		flags.StringToIntVar(&builder.stateStreamFilterConf, "state-stream-event-filter-limits", defaultConfig.stateStreamFilterConf, "event filter limits for ExecutionData SubscribeEvents API e.g. EventTypes=100,Addresses=100,Contracts=100 etc.")
+
+		// Execution State Indexing
+		flags.BoolVar(&builder.executionStateIndexEnabled, "execution-state-index-enabled", defaultConfig.executionStateIndexEnabled, "whether to index registers from synced execution data. requires execution-data-sync-enabled")
+		flags.BoolVar(&builder.accountTxIndexEnabled, "account-transaction-index-enabled", defaultConfig.accountTxIndexEnabled, "whether to index the transactions of received collections by the accounts that participated in them")
+		flags.StringVar(&builder.apiQuotaConfigPath, "api-quota-config", defaultConfig.apiQuotaConfigPath, "path to a JSON file with the API keys and per-client quotas enforced for the gRPC and REST APIs (if empty no per-client quotas are enforced). The file is reloaded with the reload-api-quotas admin command")
+		flags.BoolVar(&builder.eventIndexEnabled, "event-index-enabled", defaultConfig.eventIndexEnabled, "whether to index events from synced execution data, which enables filtered event queries. requires execution-state-index-enabled")
+		flags.StringSliceVar(&builder.eventIndexFields, "event-index-fields", defaultConfig.eventIndexFields, "comma separated event fields indexed by value for filtered event queries, each given as the event type followed by the field name, e.g. A.1654653399040a61.FlowToken.TokensDeposited.to. fields added later are only indexed for blocks indexed afterwards")
+		flags.StringVar(&builder.executionStateCheckpoint, "execution-state-checkpoint", defaultConfig.executionStateCheckpoint, "V6 checkpoint file containing a single trie, used to bootstrap the register index (defaults to the root checkpoint in the bootstrap dir)")
+		flags.StringVar(&builder.scriptExecutionMode, "script-execution-mode", defaultConfig.scriptExecutionMode, "where to execute scripts and get accounts: execution-nodes-only, local-only or failover. local modes require execution-state-index-enabled")
 	}).ValidateFlags(func() error {
 		if builder.supportsObserver && (builder.PublicNetworkConfig.BindAddress == cmd.NotSet || builder.PublicNetworkConfig.BindAddress == "") {
 			return errors.New("public-network-address must be set if supports-observer is true")
 		}
+		if builder.executionStateIndexEnabled && !builder.executionDataSyncEnabled {
+			return errors.New("execution-data-sync-enabled must be set if execution-state-index-enabled is true")
+		}
+		if builder.eventIndexEnabled && !builder.executionStateIndexEnabled {
+			return errors.New("execution-state-index-enabled must be set if event-index-enabled is true")
+		}
+		mode, err := backend.ParseScriptExecutionMode(builder.scriptExecutionMode)
+		if err != nil {
+			return fmt.Errorf("invalid script-execution-mode: %w", err)
+		}
+		if mode != backend.ScriptExecutionModeExecutionNodesOnly && !builder.executionStateIndexEnabled {
+			return fmt.Errorf("execution-state-index-enabled must be set if script-execution-mode is %s", mode)
+		}
+		builder.rpcConf.ScriptExecutionMode = mode
+		builder.indexedEventFields, err = bstorage.ParseIndexedEventFields(builder.eventIndexFields)
+		if err != nil {
+			return fmt.Errorf("invalid event-index-fields: %w", err)
+		}
+		if err := builder.rpcConf.ResultVerification.Validate(); err != nil {
+			return fmt.Errorf("invalid result verification flags: %w", err)
+		}
+		if err := builder.rpcConf.RESTSubscription.Validate(); err != nil {
+			return fmt.Errorf("invalid REST subscription flags: %w", err)
+		}
+		if err := builder.rpcConf.ScriptResultCache.Validate(); err != nil {
+			return fmt.Errorf("invalid script result cache flags: %w", err)
+		}
+		if err := builder.rpcConf.SubmissionTracking.Validate(); err != nil {
+			return fmt.Errorf("invalid transaction submission tracking flags: %w", err)
+		}
 		if builder.executionDataSyncEnabled {
 			if builder.executionDataConfig.FetchTimeout <= 0 {
 				return errors.New("execution-data-fetch-timeout must be greater than 0")
@@ -645,6 +869,19 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 			if builder.executionDataConfig.MaxSearchAhead == 0 {
 				return errors.New("execution-data-max-search-ahead must be greater than 0")
 			}
+			if builder.stateStreamConf.ClientSendBufferSize == 0 {
+				return errors.New("state-stream-send-buffer-size must be greater than 0")
+			}
+			for key, value := range builder.stateStreamFilterConf {
+				switch key {
+				case "EventTypes", "Addresses", "Contracts":
+					if value <= 0 {
+						return fmt.Errorf("state-stream-event-filter-limits %s must be greater than 0", key)
+					}
+				default:
+					return errors.New("state-stream-event-filter-limits may only contain the keys EventTypes, Addresses, Contracts")
+				}
+			}
 		}
 
 		return nil
@@ -745,6 +982,16 @@ func (builder *FlowAccessNodeBuilder) Initialize() error {
 		return storageCommands.NewGetTransactionsCommand(conf.State, conf.Storage.Payloads, conf.Storage.Collections)
 	})
 
+	builder.AdminCommand("get-execution-node-health", func(conf *cmd.NodeConfig) commands.AdminCommand {
+		return accessCommands.NewGetExecutionNodeHealthCommand(builder.rpcConf.ExecutionNodeHealth)
+	})
+
+	if builder.apiQuotaConfigPath != "" {
+		builder.AdminCommand("reload-api-quotas", func(conf *cmd.NodeConfig) commands.AdminCommand {
+			return accessCommands.NewReloadAPIQuotasCommand(builder.rpcConf.APIQuotas, builder.apiQuotaConfigPath)
+		})
+	}
+
 	// if this is an access node that supports public followers, enqueue the public network
 	if builder.supportsObserver {
 		builder.enqueuePublicNetworkInit()
@@ -823,6 +1070,26 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 				}
 				builder.HistoricalAccessRPCs = append(builder.HistoricalAccessRPCs, access.NewAccessAPIClient(historicalAccessRPCConn))
 			}
+
+			for rootHeightStr, addr := range builder.historicalSporkAddrs {
+				rootHeight, err := strconv.ParseUint(rootHeightStr, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid root height of historical spork %s: %w", rootHeightStr, err)
+				}
+				node.Logger.Info().Uint64("spork_root_height", rootHeight).Str("access_node", addr).Msg("historical spork access node address")
+
+				historicalSporkRPCConn, err := grpc.Dial(
+					addr,
+					grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(int(builder.rpcConf.MaxMsgSize))),
+					grpc.WithTransportCredentials(insecure.NewCredentials()))
+				if err != nil {
+					return err
+				}
+				builder.HistoricalSporks = append(builder.HistoricalSporks, apiproxy.HistoricalSpork{
+					RootHeight: rootHeight,
+					Client:     access.NewAccessAPIClient(historicalSporkRPCConn),
+				})
+			}
 			return nil
 		}).
 		Module("transaction timing mempools", func(node *cmd.NodeConfig) error {
@@ -850,6 +1117,38 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 				builder.logTxTimeToExecuted, builder.logTxTimeToFinalizedExecuted)
 			return nil
 		}).
+		Module("account transaction index", func(node *cmd.NodeConfig) error {
+			if builder.accountTxIndexEnabled {
+				builder.AccountTransactions = bstorage.NewAccountTransactions(node.DB)
+			}
+			return nil
+		}).
+		Module("api quotas", func(node *cmd.NodeConfig) error {
+			if builder.apiQuotaConfigPath == "" {
+				return nil
+			}
+
+			config, err := commonrpc.LoadQuotaConfig(builder.apiQuotaConfigPath)
+			if err != nil {
+				return err
+			}
+
+			builder.rpcConf.APIQuotas, err = commonrpc.NewQuotas(node.Logger, metrics.NewAPIQuotaCollector(), config)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		}).
+		Module("execution node health", func(node *cmd.NodeConfig) error {
+			var err error
+			builder.rpcConf.ExecutionNodeHealth, err = backend.NewExecutionNodeHealth(
+				node.Logger,
+				metrics.NewExecutionNodeHealthCollector(),
+				builder.executionNodeHealthConf,
+			)
+			return err
+		}).
 		Module("access metrics", func(node *cmd.NodeConfig) error {
 			builder.AccessMetrics = metrics.NewAccessCollector()
 			return nil
@@ -867,7 +1166,15 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 			tlsConfig := grpcutils.DefaultServerTLSConfig(x509Certificate)
 			builder.rpcConf.TransportCredentials = credentials.NewTLS(tlsConfig)
 			return nil
-		}).
+		})
+
+	// the execution data components are started before the RPC engine, so that the REST API can
+	// serve execution data using the state stream API
+	if builder.executionDataSyncEnabled {
+		builder.BuildExecutionDataRequester()
+	}
+
+	builder.
 		Component("RPC engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			engineBuilder, err := rpc.NewBuilder(
 				node.Logger,
@@ -895,6 +1202,26 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 				return nil, err
 			}
 
+			if builder.ScriptExecutor != nil {
+				engineBuilder.WithScriptExecutor(builder.ScriptExecutor)
+			}
+			if builder.EventIndex != nil {
+				engineBuilder.WithEventIndex(builder.EventIndex)
+			}
+			if builder.AccountTransactions != nil {
+				engineBuilder.WithAccountTransactionIndex(builder.AccountTransactions)
+			}
+			if builder.StateStreamEng != nil {
+				engineBuilder.WithStateStreamAPI(builder.StateStreamEng.API())
+			}
+			if len(builder.HistoricalSporks) > 0 {
+				sporkRootHeight, err := node.State.Params().SporkRootBlockHeight()
+				if err != nil {
+					return nil, fmt.Errorf("could not get spork root block height: %w", err)
+				}
+				engineBuilder.WithHistoricalSporks(sporkRootHeight, builder.HistoricalSporks)
+			}
+
 			builder.RpcEng, err = engineBuilder.
 				WithLegacy().
 				WithBlockSignerDecoder(signature.NewBlockSignerDecoder(builder.Committee)).
@@ -932,6 +1259,7 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 				node.Storage.Headers,
 				node.Storage.Collections,
 				node.Storage.Transactions,
+				builder.AccountTransactions,
 				node.Storage.Results,
 				node.Storage.Receipts,
 				builder.TransactionMetrics,
@@ -975,10 +1303,6 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 		})
 	}
 
-	if builder.executionDataSyncEnabled {
-		builder.BuildExecutionDataRequester()
-	}
-
 	builder.Component("ping engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 		ping, err := pingeng.New(
 			node.Logger,
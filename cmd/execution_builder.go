@@ -1031,6 +1031,7 @@ func (exeNode *ExecutionNode) LoadGrpcServer(
 		exeNode.results,
 		exeNode.txResults,
 		node.Storage.Commits,
+		node.Storage.Seals,
 		node.RootChainID,
 		signature.NewBlockSignerDecoder(exeNode.committee),
 		exeNode.exeConf.apiRatelimits,
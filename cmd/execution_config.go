@@ -85,6 +85,18 @@ func (exeConf *ExecutionConfig) SetupFlags(flags *pflag.FlagSet) {
 		"threshold for logging script execution")
 	flags.DurationVar(&exeConf.computationConfig.ScriptExecutionTimeLimit, "script-execution-time-limit", computation.DefaultScriptExecutionTimeLimit,
 		"script execution time limit")
+	flags.IntVar(&exeConf.computationConfig.ScriptQueue.MaxConcurrentScripts, "script-max-concurrent", computation.DefaultMaxConcurrentScripts,
+		"maximum number of scripts executing concurrently, further scripts wait for execution in the goroutines of their requests. "+
+			"scripts are executed as soon as they are received, without any queue limits, if 0")
+	flags.IntVar(&exeConf.computationConfig.ScriptQueue.MaxQueuedScripts, "script-max-queued", computation.DefaultMaxQueuedScripts,
+		"maximum number of scripts waiting for execution, further scripts are rejected")
+	flags.IntVar(&exeConf.computationConfig.ScriptQueue.MaxQueuedScriptsPerCaller, "script-max-queued-per-caller", computation.DefaultMaxQueuedScriptsPerCaller,
+		"maximum number of scripts of a single caller waiting for execution, further scripts of the caller are rejected. unlimited if 0")
+	flags.Uint64Var(&exeConf.computationConfig.ScriptQueue.ScriptMemoryLimit, "script-memory-limit", computation.DefaultScriptMemoryLimit,
+		"memory limit of each script as metered by the fvm, capping the on-chain memory limit. not applied if 0")
+	flags.IntVar(&exeConf.computationConfig.ParallelExecutionWorkers, "parallel-execution-workers", 0,
+		"number of workers executing the transactions of each collection optimistically in parallel, transactions are executed sequentially if smaller than 2. "+
+			"With transaction fees enabled, all the transactions conflict on the fee vault and are re-executed, which makes the execution slower than sequential")
 	flags.StringVar(&exeConf.preferredExeNodeIDStr, "preferred-exe-node-id", "", "node ID for preferred execution node used for state sync")
 	flags.UintVar(&exeConf.transactionResultsCacheSize, "transaction-results-cache-size", 10000, "number of transaction results to be cached")
 	flags.BoolVar(&exeConf.syncByBlocks, "sync-by-blocks", true, "deprecated, sync by blocks instead of execution state deltas")
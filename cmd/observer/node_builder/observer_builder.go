@@ -31,6 +31,7 @@ import (
 	recovery "github.com/onflow/flow-go/consensus/recovery/protocol"
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/engine/access/apiproxy"
+	"github.com/onflow/flow-go/engine/access/rest"
 	"github.com/onflow/flow-go/engine/access/rpc"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
 	"github.com/onflow/flow-go/engine/common/follower"
@@ -41,6 +42,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/blobs"
 	"github.com/onflow/flow-go/module/buffer"
 	"github.com/onflow/flow-go/module/chainsync"
 	"github.com/onflow/flow-go/module/compliance"
@@ -50,6 +52,7 @@ import (
 	"github.com/onflow/flow-go/module/local"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/state_synchronization"
+	"github.com/onflow/flow-go/module/state_synchronization/indexer"
 	edrequester "github.com/onflow/flow-go/module/state_synchronization/requester"
 	consensus_follower "github.com/onflow/flow-go/module/upstream"
 	"github.com/onflow/flow-go/network"
@@ -109,6 +112,9 @@ type ObserverServiceConfig struct {
 	rpcConf                   rpc.Config
 	rpcMetricsEnabled         bool
 	executionDataSyncEnabled  bool
+	resultsIndexEnabled       bool
+	collectionRelayEnabled    bool
+	collectionGRPCPort        uint
 	executionDataDir          string
 	executionDataStartHeight  uint64
 	executionDataConfig       edrequester.ExecutionDataConfig
@@ -123,15 +129,24 @@ func DefaultObserverServiceConfig() *ObserverServiceConfig {
 	homedir, _ := os.UserHomeDir()
 	return &ObserverServiceConfig{
 		rpcConf: rpc.Config{
-			UnsecureGRPCListenAddr:    "0.0.0.0:9000",
-			SecureGRPCListenAddr:      "0.0.0.0:9001",
-			HTTPListenAddr:            "0.0.0.0:8000",
-			RESTListenAddr:            "",
-			CollectionAddr:            "",
-			HistoricalAccessAddrs:     "",
-			CollectionClientTimeout:   3 * time.Second,
-			ExecutionClientTimeout:    3 * time.Second,
-			MaxHeightRange:            backend.DefaultMaxHeightRange,
+			UnsecureGRPCListenAddr:  "0.0.0.0:9000",
+			SecureGRPCListenAddr:    "0.0.0.0:9001",
+			HTTPListenAddr:          "0.0.0.0:8000",
+			RESTListenAddr:          "",
+			CollectionAddr:          "",
+			HistoricalAccessAddrs:   "",
+			CollectionClientTimeout: 3 * time.Second,
+			ExecutionClientTimeout:  3 * time.Second,
+			MaxHeightRange:          backend.DefaultMaxHeightRange,
+			RESTSubscription: rest.SubscriptionConfig{
+				PollInterval:      rest.DefaultSubscriptionPollInterval,
+				MaxStartHeightLag: rest.DefaultSubscriptionMaxStartHeightLag,
+			},
+			ScriptResultCache: backend.ScriptResultCacheConfig{
+				Size:           backend.DefaultScriptResultCacheSize,
+				MaxBytes:       backend.DefaultScriptResultCacheMaxBytes,
+				MaxResultBytes: backend.DefaultScriptResultCacheMaxResultBytes,
+			},
 			PreferredExecutionNodeIDs: nil,
 			FixedExecutionNodeIDs:     nil,
 			MaxMsgSize:                grpcutils.DefaultMaxMsgSize,
@@ -143,6 +158,9 @@ func DefaultObserverServiceConfig() *ObserverServiceConfig {
 		bootstrapNodePublicKeys:   []string{},
 		observerNetworkingKeyPath: cmd.NotSet,
 		executionDataSyncEnabled:  false,
+		resultsIndexEnabled:       false,
+		collectionRelayEnabled:    false,
+		collectionGRPCPort:        9000,
 		executionDataDir:          filepath.Join(homedir, ".flow", "execution_data"),
 		executionDataStartHeight:  0,
 		executionDataConfig: edrequester.ExecutionDataConfig{
@@ -177,6 +195,10 @@ type ObserverServiceBuilder struct {
 	FollowerCore            module.HotStuffFollower
 	Validator               hotstuff.Validator
 	ExecutionDataDownloader execution_data.Downloader
+	ExecutionDataStore      execution_data.ExecutionDataStore
+	ResultsIndexer          *indexer.ResultsIndexer
+	Events                  storage.Events
+	TransactionResults      storage.TransactionResults
 	ExecutionDataRequester  state_synchronization.ExecutionDataRequester // for the observer, the sync engine participants provider is the libp2p peer store which is not
 	// available until after the network has started. Hence, a factory function that needs to be called just before
 	// creating the sync engine
@@ -479,6 +501,11 @@ func (builder *ObserverServiceBuilder) BuildExecutionDataRequester() *ObserverSe
 			processedNotifications = bstorage.NewConsumerProgress(ds.DB, module.ConsumeProgressExecutionDataRequesterNotification)
 			return nil
 		}).
+		Module("execution datastore", func(node *cmd.NodeConfig) error {
+			blobstore := blobs.NewBlobstore(ds)
+			builder.ExecutionDataStore = execution_data.NewExecutionDataStore(blobstore, execution_data.DefaultSerializer)
+			return nil
+		}).
 		Component("execution data service", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			var err error
 			bs, err = node.Network.RegisterBlobService(channels.ExecutionDataService, ds,
@@ -545,6 +572,57 @@ func (builder *ObserverServiceBuilder) BuildExecutionDataRequester() *ObserverSe
 			return builder.ExecutionDataRequester, nil
 		})
 
+	if builder.resultsIndexEnabled {
+		builder.
+			Module("execution results indexer", func(node *cmd.NodeConfig) error {
+				// the indexer is created in a module, so it is available when the RPC engine is created
+				initialHeight := builder.RootBlock.Header.Height
+				if builder.executionDataStartHeight > 0 {
+					initialHeight = builder.executionDataStartHeight - 1
+				}
+
+				highestAvailableHeight, err := processedNotifications.ProcessedIndex()
+				if err != nil {
+					if !errors.Is(err, storage.ErrNotFound) {
+						return fmt.Errorf("could not get highest notified execution data height: %w", err)
+					}
+					highestAvailableHeight = initialHeight
+				}
+
+				builder.Events = bstorage.NewEvents(node.Metrics.Cache, node.DB)
+				builder.TransactionResults = bstorage.NewTransactionResults(node.Metrics.Cache, node.DB, bstorage.DefaultCacheSize)
+
+				// the error messages of failed transactions are not part of the execution data, so
+				// they are requested from the upstream access nodes
+				upstream, err := apiproxy.NewFlowAccessAPIForwarder(builder.upstreamIdentities, builder.apiTimeout, builder.rpcConf.MaxMsgSize)
+				if err != nil {
+					return fmt.Errorf("could not create upstream forwarder: %w", err)
+				}
+
+				builder.ResultsIndexer, err = indexer.NewResultsIndexer(
+					node.Logger,
+					node.DB,
+					node.Storage.Headers,
+					node.Storage.Blocks,
+					node.Storage.Collections,
+					builder.Events,
+					builder.TransactionResults,
+					node.Storage.Seals,
+					node.Storage.Results,
+					builder.ExecutionDataStore,
+					apiproxy.NewUpstreamErrorMessages(upstream),
+					bstorage.NewConsumerProgress(node.DB, module.ConsumeProgressResultsIndexerBlockHeight),
+					initialHeight,
+					highestAvailableHeight,
+				)
+				return err
+			}).
+			Component("execution results indexer", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+				builder.ExecutionDataRequester.AddOnExecutionDataFetchedConsumer(builder.ResultsIndexer.OnExecutionData)
+				return builder.ResultsIndexer, nil
+			})
+	}
+
 	return builder
 }
 
@@ -583,8 +661,13 @@ func (builder *ObserverServiceBuilder) extraFlags() {
 		flags.StringVar(&builder.rpcConf.SecureGRPCListenAddr, "secure-rpc-addr", defaultConfig.rpcConf.SecureGRPCListenAddr, "the address the secure gRPC server listens on")
 		flags.StringVarP(&builder.rpcConf.HTTPListenAddr, "http-addr", "h", defaultConfig.rpcConf.HTTPListenAddr, "the address the http proxy server listens on")
 		flags.StringVar(&builder.rpcConf.RESTListenAddr, "rest-addr", defaultConfig.rpcConf.RESTListenAddr, "the address the REST server listens on (if empty the REST server will not be started)")
+		flags.DurationVar(&builder.rpcConf.RESTSubscription.PollInterval, "rest-subscription-poll-interval", defaultConfig.rpcConf.RESTSubscription.PollInterval, "interval at which new data is checked for each REST API subscription")
+		flags.Uint64Var(&builder.rpcConf.RESTSubscription.MaxStartHeightLag, "rest-subscription-max-start-height-lag", defaultConfig.rpcConf.RESTSubscription.MaxStartHeightLag, "maximum number of blocks the start height of a REST API subscription may be behind the latest block")
 		flags.UintVar(&builder.rpcConf.MaxMsgSize, "rpc-max-message-size", defaultConfig.rpcConf.MaxMsgSize, "the maximum message size in bytes for messages sent or received over grpc")
 		flags.UintVar(&builder.rpcConf.MaxHeightRange, "rpc-max-height-range", defaultConfig.rpcConf.MaxHeightRange, "maximum size for height range requests")
+		flags.UintVar(&builder.rpcConf.ScriptResultCache.Size, "script-result-cache-size", defaultConfig.rpcConf.ScriptResultCache.Size, "number of results of scripts executed on execution nodes at sealed blocks to cache (0 disables the cache)")
+		flags.Uint64Var(&builder.rpcConf.ScriptResultCache.MaxBytes, "script-result-cache-max-bytes", defaultConfig.rpcConf.ScriptResultCache.MaxBytes, "maximum total size in bytes of the cached script results")
+		flags.Uint64Var(&builder.rpcConf.ScriptResultCache.MaxResultBytes, "script-result-cache-max-result-bytes", defaultConfig.rpcConf.ScriptResultCache.MaxResultBytes, "maximum size in bytes of a cached script result, larger results are not cached")
 		flags.StringToIntVar(&builder.apiRatelimits, "api-rate-limits", defaultConfig.apiRatelimits, "per second rate limits for Access API methods e.g. Ping=300,GetTransaction=500 etc.")
 		flags.StringToIntVar(&builder.apiBurstlimits, "api-burst-limits", defaultConfig.apiBurstlimits, "burst limits for Access API methods e.g. Ping=100,GetTransaction=100 etc.")
 		flags.StringVar(&builder.observerNetworkingKeyPath, "observer-networking-key-path", defaultConfig.observerNetworkingKeyPath, "path to the networking key for observer")
@@ -594,9 +677,12 @@ func (builder *ObserverServiceBuilder) extraFlags() {
 		flags.StringSliceVar(&builder.upstreamNodeAddresses, "upstream-node-addresses", defaultConfig.upstreamNodeAddresses, "the gRPC network addresses of the upstream access node. e.g. access-001.mainnet.flow.org:9000,access-002.mainnet.flow.org:9000")
 		flags.StringSliceVar(&builder.upstreamNodePublicKeys, "upstream-node-public-keys", defaultConfig.upstreamNodePublicKeys, "the networking public key of the upstream access node (in the same order as the upstream node addresses) e.g. \"d57a5e9c5.....\",\"44ded42d....\"")
 		flags.BoolVar(&builder.rpcMetricsEnabled, "rpc-metrics-enabled", defaultConfig.rpcMetricsEnabled, "whether to enable the rpc metrics")
+		flags.BoolVar(&builder.collectionRelayEnabled, "collection-relay-enabled", defaultConfig.collectionRelayEnabled, "whether to send transactions directly to the responsible collection nodes instead of forwarding them to the upstream access node")
+		flags.UintVar(&builder.collectionGRPCPort, "collection-ingress-port", defaultConfig.collectionGRPCPort, "the grpc ingress port for all collection nodes")
 
 		// ExecutionDataRequester config
 		flags.BoolVar(&builder.executionDataSyncEnabled, "execution-data-sync-enabled", defaultConfig.executionDataSyncEnabled, "whether to enable the execution data sync protocol")
+		flags.BoolVar(&builder.resultsIndexEnabled, "execution-results-index-enabled", defaultConfig.resultsIndexEnabled, "whether to index transaction results and events from synced execution data and serve them locally instead of forwarding requests upstream. requires execution-data-sync-enabled")
 		flags.StringVar(&builder.executionDataDir, "execution-data-dir", defaultConfig.executionDataDir, "directory to use for Execution Data database")
 		flags.Uint64Var(&builder.executionDataStartHeight, "execution-data-start-height", defaultConfig.executionDataStartHeight, "height of first block to sync execution data from when starting with an empty Execution Data database")
 		flags.Uint64Var(&builder.executionDataConfig.MaxSearchAhead, "execution-data-max-search-ahead", defaultConfig.executionDataConfig.MaxSearchAhead, "max number of heights to search ahead of the lowest outstanding execution data height")
@@ -618,6 +704,15 @@ func (builder *ObserverServiceBuilder) extraFlags() {
 				return errors.New("execution-data-max-search-ahead must be greater than 0")
 			}
 		}
+		if builder.resultsIndexEnabled && !builder.executionDataSyncEnabled {
+			return errors.New("execution-results-index-enabled requires execution-data-sync-enabled")
+		}
+		if err := builder.rpcConf.RESTSubscription.Validate(); err != nil {
+			return fmt.Errorf("invalid REST subscription flags: %w", err)
+		}
+		if err := builder.rpcConf.ScriptResultCache.Validate(); err != nil {
+			return fmt.Errorf("invalid script result cache flags: %w", err)
+		}
 		return nil
 	})
 }
@@ -994,9 +1089,9 @@ func (builder *ObserverServiceBuilder) enqueueRPCServer() {
 			node.Storage.Receipts,
 			node.Storage.Results,
 			node.RootChainID,
+			metrics.NewNoopCollector(),
 			nil,
-			nil,
-			0,
+			builder.collectionGRPCPort,
 			0,
 			false,
 			builder.rpcMetricsEnabled,
@@ -1025,6 +1120,24 @@ func (builder *ObserverServiceBuilder) enqueueRPCServer() {
 			)),
 		}
 
+		if builder.ResultsIndexer != nil {
+			proxy.Indexed = apiproxy.NewIndexedResults(
+				builder.ResultsIndexer,
+				node.Storage.Headers,
+				node.Storage.Blocks,
+				node.Storage.Collections,
+				builder.Events,
+				builder.TransactionResults,
+				builder.rpcConf.MaxHeightRange,
+			)
+		}
+
+		if builder.collectionRelayEnabled {
+			// the default handler validates transactions and sends them to the collection nodes of the
+			// cluster responsible for them, like on access nodes
+			proxy.Relay = engineBuilder.DefaultHandler()
+		}
+
 		// build the rpc engine
 		builder.RpcEng, err = engineBuilder.
 			WithNewHandler(proxy).
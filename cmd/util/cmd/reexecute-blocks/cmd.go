@@ -0,0 +1,137 @@
+package reexecute
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var (
+	flagDatadir           string
+	flagExecutionStateDir string
+	flagChain             string
+	flagFromHeight        uint64
+	flagToHeight          uint64
+	flagOutput            string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "reexecute-blocks",
+	Short: "Re-executes a range of blocks and compares the results with the stored execution results",
+	Long: `Re-executes a range of finalized blocks of an execution node, each on the stored execution state
+of its parent, and compares the state commitment, events hash and service events of each chunk with
+the stored execution result. A report is written for every block whose results diverge, with the
+registers whose values differ at the end of the diverging chunks.
+
+The re-executed states are added to the ledger in memory only, but the command should be run on a
+copy of the protocol state and execution state directories of a stopped execution node.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagExecutionStateDir, "execution-state-dir", "",
+		"Execution Node state dir (where checkpoint and WAL files are written)")
+	_ = Cmd.MarkFlagRequired("execution-state-dir")
+
+	Cmd.Flags().StringVar(&flagChain, "chain", "", "Chain name")
+	_ = Cmd.MarkFlagRequired("chain")
+
+	Cmd.Flags().Uint64Var(&flagFromHeight, "from-height", 0,
+		"height of the first block to re-execute")
+	_ = Cmd.MarkFlagRequired("from-height")
+
+	Cmd.Flags().Uint64Var(&flagToHeight, "to-height", 0,
+		"height of the last block to re-execute")
+	_ = Cmd.MarkFlagRequired("to-height")
+
+	Cmd.Flags().StringVar(&flagOutput, "output", "",
+		"file to write the report of the diverging blocks to, as JSON lines (default stdout)")
+}
+
+func getChainID(chainName string) (chainID flow.ChainID, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid chain: %s", r)
+		}
+	}()
+	chainID = flow.ChainID(chainName)
+	_ = chainID.Chain()
+	return
+}
+
+func run(*cobra.Command, []string) {
+	if flagFromHeight == 0 || flagFromHeight > flagToHeight {
+		log.Fatal().Msgf("invalid height range [%d, %d]", flagFromHeight, flagToHeight)
+	}
+
+	chainID, err := getChainID(flagChain)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid chain name")
+	}
+
+	var output io.Writer = os.Stdout
+	if flagOutput != "" {
+		file, err := os.Create(flagOutput)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("could not create output file %s", flagOutput)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+	storages := common.InitStorages(db)
+
+	led, closeLedger, err := initLedger(flagExecutionStateDir, log.Logger)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init ledger")
+	}
+	defer closeLedger()
+
+	reexecutor, err := newReexecutor(log.Logger, chainID, storages, led)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init block computer")
+	}
+
+	encoder := json.NewEncoder(output)
+	diverged := 0
+	for height := flagFromHeight; height <= flagToHeight; height++ {
+		report, err := reexecutor.reexecute(context.Background(), height)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("could not re-execute block at height %d", height)
+		}
+
+		if report.Matches() {
+			log.Info().Uint64("height", height).Msg("re-executed block matches the stored result")
+			continue
+		}
+
+		diverged++
+		log.Warn().
+			Uint64("height", height).
+			Int("diverging_chunks", len(report.Chunks)).
+			Bool("service_events_match", report.ServiceEventsMatch).
+			Msg("re-executed block diverges from the stored result")
+
+		err = encoder.Encode(report)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not write report")
+		}
+	}
+
+	log.Info().Msgf("re-executed %d blocks, %d diverged from the stored results",
+		flagToHeight-flagFromHeight+1, diverged)
+}
@@ -0,0 +1,389 @@
+package reexecute
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/engine/execution/computation/committer"
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/derived"
+	"github.com/onflow/flow-go/initialize"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/local"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// BlockReport is the report of a re-executed block whose results diverge from
+// the stored execution result.
+type BlockReport struct {
+	BlockID  flow.Identifier `json:"block_id"`
+	Height   uint64          `json:"height"`
+	ResultID flow.Identifier `json:"result_id"`
+
+	// Error is set when the results could not be compared chunk by chunk,
+	// e.g. because they have different numbers of chunks.
+	Error string `json:"error,omitempty"`
+
+	ServiceEventsMatch bool           `json:"service_events_match"`
+	Chunks             []*ChunkReport `json:"chunks,omitempty"`
+}
+
+// Matches returns true if the re-executed block produced the stored results.
+func (r *BlockReport) Matches() bool {
+	return r.Error == "" && r.ServiceEventsMatch && len(r.Chunks) == 0
+}
+
+// ChunkReport is the report of a chunk whose end state or events diverge from
+// the stored chunk.
+type ChunkReport struct {
+	Index              uint64               `json:"index"`
+	StartState         flow.StateCommitment `json:"start_state"`
+	StoredEndState     flow.StateCommitment `json:"stored_end_state"`
+	ComputedEndState   flow.StateCommitment `json:"computed_end_state"`
+	StoredEventsHash   flow.Identifier      `json:"stored_events_hash"`
+	ComputedEventsHash flow.Identifier      `json:"computed_events_hash"`
+
+	// RegistersError is set when the registers of diverging end states could
+	// not be compared, e.g. because the stored end state is no longer in the
+	// ledger.
+	RegistersError string          `json:"registers_error,omitempty"`
+	Registers      []*RegisterDiff `json:"registers,omitempty"`
+}
+
+// RegisterDiff is a register whose value differs between the stored and the
+// re-executed end state of a chunk.  Values are hex encoded, missing
+// registers have empty values.
+type RegisterDiff struct {
+	Register string `json:"register"`
+	Stored   string `json:"stored"`
+	Computed string `json:"computed"`
+}
+
+// initLedger loads the ledger from the checkpoint and WAL files in the given
+// directory.  The WAL is paused, so that the re-executed states are not
+// recorded.  The returned function must be called to stop the ledger.
+func initLedger(dir string, log zerolog.Logger) (*complete.Ledger, func(), error) {
+	diskWal, err := wal.NewDiskWAL(
+		log,
+		nil,
+		metrics.NewNoopCollector(),
+		dir,
+		complete.DefaultCacheSize,
+		pathfinder.PathByteSize,
+		wal.SegmentSize,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create disk WAL: %w", err)
+	}
+
+	led, err := complete.NewLedger(
+		diskWal,
+		complete.DefaultCacheSize,
+		&metrics.NoopCollector{},
+		log,
+		complete.DefaultPathFinderVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create ledger from write-a-head logs and checkpoints: %w", err)
+	}
+
+	const (
+		checkpointDistance = math.MaxInt // A large number to prevent checkpoint creation.
+		checkpointsToKeep  = 1
+	)
+
+	compactor, err := complete.NewCompactor(led, diskWal, log, complete.DefaultCacheSize, checkpointDistance, checkpointsToKeep, atomic.NewBool(false))
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create compactor: %w", err)
+	}
+
+	log.Info().Msgf("waiting for compactor to load checkpoint and WAL")
+
+	<-compactor.Ready()
+
+	diskWal.PauseRecord()
+
+	return led, func() {
+		<-led.Done()
+		<-compactor.Done()
+	}, nil
+}
+
+// reexecutor re-executes blocks on the stored execution state of their parent
+// and compares the results with the stored execution results.
+type reexecutor struct {
+	log      zerolog.Logger
+	storages *storage.All
+	ledger   ledger.Ledger
+	computer computer.BlockComputer
+}
+
+func newReexecutor(
+	log zerolog.Logger,
+	chainID flow.ChainID,
+	storages *storage.All,
+	led ledger.Ledger,
+) (*reexecutor, error) {
+	// the spocks of the re-executed chunks are not compared, they are signed
+	// with a random key.
+	seed := make([]byte, crypto.KeyGenSeedMinLenBLSBLS12381)
+	_, err := rand.Read(seed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate staking key seed: %w", err)
+	}
+	sk, err := crypto.GeneratePrivateKey(crypto.BLSBLS12381, seed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate staking key: %w", err)
+	}
+	me, err := local.New(&flow.Identity{
+		Role:          flow.RoleExecution,
+		StakingPubKey: sk.PublicKey(),
+	}, sk)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create local: %w", err)
+	}
+
+	tracer := trace.NewNoopTracer()
+	blockComputer, err := computer.NewBlockComputer(
+		fvm.NewVirtualMachine(),
+		fvm.NewContext(initialize.InitFvmOptions(chainID, storages.Headers)...),
+		metrics.NewNoopCollector(),
+		tracer,
+		log,
+		committer.NewLedgerViewCommitter(led, tracer),
+		me,
+		nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create block computer: %w", err)
+	}
+
+	return &reexecutor{
+		log:      log,
+		storages: storages,
+		ledger:   led,
+		computer: blockComputer,
+	}, nil
+}
+
+// reexecute re-executes the finalized block at the given height and compares
+// its results with the stored execution result.
+func (r *reexecutor) reexecute(ctx context.Context, height uint64) (*BlockReport, error) {
+	block, err := r.storages.Blocks.ByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get block: %w", err)
+	}
+	blockID := block.ID()
+
+	stored, err := r.storages.Results.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get execution result of block %v: %w", blockID, err)
+	}
+
+	startState, err := r.storages.Commits.ByBlockID(block.Header.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get state commitment of parent block %v: %w", block.Header.ParentID, err)
+	}
+
+	executableBlock := &entity.ExecutableBlock{
+		Block:               block,
+		CompleteCollections: make(map[flow.Identifier]*entity.CompleteCollection, len(block.Payload.Guarantees)),
+		StartState:          &startState,
+	}
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := r.storages.Collections.ByID(guarantee.CollectionID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get collection %v: %w", guarantee.CollectionID, err)
+		}
+		executableBlock.CompleteCollections[guarantee.CollectionID] = &entity.CompleteCollection{
+			Guarantee:    guarantee,
+			Transactions: collection.Transactions,
+		}
+	}
+
+	computed, err := r.computer.ExecuteBlock(
+		ctx,
+		executableBlock,
+		delta.NewDeltaView(state.LedgerGetRegister(r.ledger, startState)),
+		derived.NewEmptyDerivedBlockData())
+	if err != nil {
+		return nil, fmt.Errorf("cannot execute block %v: %w", blockID, err)
+	}
+
+	return r.compare(block, stored, computed)
+}
+
+// compare compares the results of a re-executed block with its stored
+// execution result.
+func (r *reexecutor) compare(
+	block *flow.Block,
+	stored *flow.ExecutionResult,
+	computed *execution.ComputationResult,
+) (*BlockReport, error) {
+	report := &BlockReport{
+		BlockID:  block.ID(),
+		Height:   block.Header.Height,
+		ResultID: stored.ID(),
+	}
+
+	match, err := stored.ServiceEvents.EqualTo(computed.ConvertedServiceEvents)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compare service events: %w", err)
+	}
+	report.ServiceEventsMatch = match
+
+	if len(stored.Chunks) != len(computed.Chunks) {
+		report.Error = fmt.Sprintf("stored result has %d chunks, re-executed result has %d chunks",
+			len(stored.Chunks), len(computed.Chunks))
+		return report, nil
+	}
+
+	for i, storedChunk := range stored.Chunks {
+		computedChunk := computed.Chunks[i]
+		if storedChunk.EndState == computedChunk.EndState &&
+			storedChunk.EventCollection == computedChunk.EventCollection {
+			continue
+		}
+
+		chunkReport := &ChunkReport{
+			Index:              storedChunk.Index,
+			StartState:         storedChunk.StartState,
+			StoredEndState:     storedChunk.EndState,
+			ComputedEndState:   computedChunk.EndState,
+			StoredEventsHash:   storedChunk.EventCollection,
+			ComputedEventsHash: computedChunk.EventCollection,
+		}
+		report.Chunks = append(report.Chunks, chunkReport)
+
+		if storedChunk.EndState == computedChunk.EndState {
+			continue
+		}
+
+		ids, err := r.touchedRegisters(storedChunk, computed.StateSnapshots[i])
+		if err != nil {
+			return nil, fmt.Errorf("cannot get registers touched by chunk %d: %w", i, err)
+		}
+
+		chunkReport.Registers, err = diffRegisters(
+			ids,
+			state.LedgerGetRegister(r.ledger, storedChunk.EndState),
+			state.LedgerGetRegister(r.ledger, computedChunk.EndState))
+		if err != nil {
+			chunkReport.RegistersError = err.Error()
+		}
+	}
+
+	return report, nil
+}
+
+// touchedRegisters returns the registers touched by the re-execution of a
+// chunk, and the registers touched by its stored execution which are included
+// in its stored chunk data pack, if any.  Registers created by the stored
+// execution only are not included in the chunk data pack, and are missed.
+func (r *reexecutor) touchedRegisters(
+	chunk *flow.Chunk,
+	snapshot *delta.SpockSnapshot,
+) ([]flow.RegisterID, error) {
+	touched := make(map[flow.RegisterID]struct{}, len(snapshot.Reads)+len(snapshot.Delta.Data))
+	for id := range snapshot.Reads {
+		touched[id] = struct{}{}
+	}
+	for _, id := range snapshot.Delta.RegisterIDs() {
+		touched[id] = struct{}{}
+	}
+
+	chunkDataPack, err := r.storages.ChunkDataPacks.ByChunkID(chunk.ID())
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("cannot get chunk data pack: %w", err)
+	}
+	if err == nil {
+		proof, err := ledger.DecodeTrieBatchProof(chunkDataPack.Proof)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode chunk data pack proof: %w", err)
+		}
+		for _, p := range proof.Proofs {
+			if !p.Inclusion {
+				continue
+			}
+			key, err := p.Payload.Key()
+			if err != nil {
+				return nil, fmt.Errorf("cannot decode key of chunk data pack proof: %w", err)
+			}
+			id, err := state.KeyToRegisterID(key)
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert key of chunk data pack proof: %w", err)
+			}
+			touched[id] = struct{}{}
+		}
+	} else {
+		r.log.Debug().
+			Hex("chunk_id", logging.ID(chunk.ID())).
+			Msg("chunk data pack not found, only registers touched by the re-execution are compared")
+	}
+
+	ids := make([]flow.RegisterID, 0, len(touched))
+	for id := range touched {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// diffRegisters returns the registers whose values differ between the stored
+// and computed states, sorted by register ID.
+func diffRegisters(
+	ids []flow.RegisterID,
+	stored delta.GetRegisterFunc,
+	computed delta.GetRegisterFunc,
+) ([]*RegisterDiff, error) {
+	sorted := append([]flow.RegisterID(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Owner != sorted[j].Owner {
+			return sorted[i].Owner < sorted[j].Owner
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	var diffs []*RegisterDiff
+	for _, id := range sorted {
+		storedValue, err := stored(id)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read register %v from stored state: %w", id, err)
+		}
+		computedValue, err := computed(id)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read register %v from computed state: %w", id, err)
+		}
+
+		if bytes.Equal(storedValue, computedValue) {
+			continue
+		}
+
+		diffs = append(diffs, &RegisterDiff{
+			Register: id.String(),
+			Stored:   hex.EncodeToString(storedValue),
+			Computed: hex.EncodeToString(computedValue),
+		})
+	}
+
+	return diffs, nil
+}
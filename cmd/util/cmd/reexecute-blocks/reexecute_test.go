@@ -0,0 +1,62 @@
+package reexecute
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func registers(values map[flow.RegisterID]flow.RegisterValue) delta.GetRegisterFunc {
+	return func(id flow.RegisterID) (flow.RegisterValue, error) {
+		return values[id], nil
+	}
+}
+
+func TestDiffRegisters(t *testing.T) {
+	same := flow.NewRegisterID("a", "same")
+	changed := flow.NewRegisterID("b", "changed")
+	created := flow.NewRegisterID("a", "created")
+	removed := flow.NewRegisterID("", "removed")
+
+	stored := registers(map[flow.RegisterID]flow.RegisterValue{
+		same:    {1},
+		changed: {2},
+		removed: {3},
+	})
+	computed := registers(map[flow.RegisterID]flow.RegisterValue{
+		same:    {1},
+		changed: {4},
+		created: {5},
+	})
+
+	t.Run("differing registers are reported in order", func(t *testing.T) {
+		diffs, err := diffRegisters([]flow.RegisterID{changed, same, created, removed}, stored, computed)
+		require.NoError(t, err)
+
+		assert.Equal(t, []*RegisterDiff{
+			{Register: removed.String(), Stored: "03", Computed: ""},
+			{Register: created.String(), Stored: "", Computed: "05"},
+			{Register: changed.String(), Stored: "02", Computed: "04"},
+		}, diffs)
+	})
+
+	t.Run("no diff for identical registers", func(t *testing.T) {
+		diffs, err := diffRegisters([]flow.RegisterID{same}, stored, computed)
+		require.NoError(t, err)
+		assert.Empty(t, diffs)
+	})
+
+	t.Run("read errors are returned", func(t *testing.T) {
+		failing := func(flow.RegisterID) (flow.RegisterValue, error) {
+			return nil, fmt.Errorf("trie not found")
+		}
+
+		_, err := diffRegisters([]flow.RegisterID{same}, failing, computed)
+		assert.Error(t, err)
+	})
+}
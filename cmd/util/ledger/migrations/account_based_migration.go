@@ -5,6 +5,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/util"
@@ -19,7 +20,7 @@ func PayloadToAccount(p ledger.Payload) (string, bool, error) {
 	if err != nil {
 		return "", false, fmt.Errorf("could not find key for payload: %w", err)
 	}
-	id, err := KeyToRegisterID(k)
+	id, err := state.KeyToRegisterID(k)
 	if err != nil {
 		return "", false, fmt.Errorf("error converting key to register ID")
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/fvm/environment"
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/model/flow"
@@ -15,7 +16,7 @@ func MigrateAccountUsage(payloads []ledger.Payload, nWorker int) ([]ledger.Paylo
 }
 
 func payloadSize(key ledger.Key, payload ledger.Payload) (uint64, error) {
-	id, err := KeyToRegisterID(key)
+	id, err := state.KeyToRegisterID(key)
 	if err != nil {
 		return 0, err
 	}
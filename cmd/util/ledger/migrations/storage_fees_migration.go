@@ -1,6 +1,7 @@
 package migrations
 
 import (
+	"github.com/onflow/flow-go/engine/execution/state"
 	fvm "github.com/onflow/flow-go/fvm/environment"
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/utils"
@@ -42,7 +43,7 @@ func incrementStorageUsed(p ledger.Payload, used map[string]uint64) error {
 	if err != nil {
 		return err
 	}
-	id, err := KeyToRegisterID(k)
+	id, err := state.KeyToRegisterID(k)
 	if err != nil {
 		return err
 	}
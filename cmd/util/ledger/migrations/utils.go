@@ -11,19 +11,6 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 )
 
-func KeyToRegisterID(key ledger.Key) (flow.RegisterID, error) {
-	if len(key.KeyParts) != 2 ||
-		key.KeyParts[0].Type != state.KeyPartOwner ||
-		key.KeyParts[1].Type != state.KeyPartKey {
-		return flow.RegisterID{}, fmt.Errorf("key not in expected format %s", key.String())
-	}
-
-	return flow.NewRegisterID(
-		string(key.KeyParts[0].Value),
-		string(key.KeyParts[1].Value),
-	), nil
-}
-
 func registerIDToKey(registerID flow.RegisterID) ledger.Key {
 	newKey := ledger.Key{}
 	newKey.KeyParts = []ledger.KeyPart{
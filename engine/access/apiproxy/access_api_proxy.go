@@ -2,6 +2,7 @@ package apiproxy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -26,11 +27,24 @@ import (
 
 // FlowAccessAPIRouter is a structure that represents the routing proxy algorithm.
 // It splits requests between a local and a remote API service.
+//
+// Transaction result and event requests are answered from the locally indexed execution data if
+// Indexed is set and the requested blocks are indexed, and are forwarded upstream otherwise.
+//
+// Transactions are sent directly to the responsible collection nodes if Relay is set. They are
+// only forwarded upstream if none of the collection nodes could be reached.
 type FlowAccessAPIRouter struct {
 	Logger   zerolog.Logger
 	Metrics  *metrics.ObserverCollector
 	Upstream *FlowAccessAPIForwarder
 	Observer *protocol.Handler
+	Indexed  *IndexedResults
+	Relay    TransactionSender
+}
+
+// TransactionSender sends transactions to the network.
+type TransactionSender interface {
+	SendTransaction(context.Context, *access.SendTransactionRequest) (*access.SendTransactionResponse, error)
 }
 
 func (h *FlowAccessAPIRouter) log(handler, rpc string, err error) {
@@ -183,6 +197,15 @@ func (h *FlowAccessAPIRouter) GetCollectionByID(context context.Context, req *ac
 }
 
 func (h *FlowAccessAPIRouter) SendTransaction(context context.Context, req *access.SendTransactionRequest) (*access.SendTransactionResponse, error) {
+	if h.Relay != nil {
+		// the relay validates the transaction before sending it, invalid transactions are not forwarded
+		res, err := h.Relay.SendTransaction(context, req)
+		h.log("observer", "SendTransaction", err)
+		if err == nil || status.Code(err) == codes.InvalidArgument {
+			return res, err
+		}
+	}
+
 	res, err := h.Upstream.SendTransaction(context, req)
 	h.log("upstream", "SendTransaction", err)
 	return res, err
@@ -195,13 +218,25 @@ func (h *FlowAccessAPIRouter) GetTransaction(context context.Context, req *acces
 }
 
 func (h *FlowAccessAPIRouter) GetTransactionResult(context context.Context, req *access.GetTransactionRequest) (*access.TransactionResultResponse, error) {
-	res, err := h.Upstream.GetTransactionResult(context, req)
+	res, err := h.Indexed.GetTransactionResult(context, req)
+	if !errors.Is(err, errNotIndexed) {
+		h.log("observer", "GetTransactionResult", err)
+		return res, err
+	}
+
+	res, err = h.Upstream.GetTransactionResult(context, req)
 	h.log("upstream", "GetTransactionResult", err)
 	return res, err
 }
 
 func (h *FlowAccessAPIRouter) GetTransactionResultsByBlockID(context context.Context, req *access.GetTransactionsByBlockIDRequest) (*access.TransactionResultsResponse, error) {
-	res, err := h.Upstream.GetTransactionResultsByBlockID(context, req)
+	res, err := h.Indexed.GetTransactionResultsByBlockID(context, req)
+	if !errors.Is(err, errNotIndexed) {
+		h.log("observer", "GetTransactionResultsByBlockID", err)
+		return res, err
+	}
+
+	res, err = h.Upstream.GetTransactionResultsByBlockID(context, req)
 	h.log("upstream", "GetTransactionResultsByBlockID", err)
 	return res, err
 }
@@ -213,7 +248,13 @@ func (h *FlowAccessAPIRouter) GetTransactionsByBlockID(context context.Context,
 }
 
 func (h *FlowAccessAPIRouter) GetTransactionResultByIndex(context context.Context, req *access.GetTransactionByIndexRequest) (*access.TransactionResultResponse, error) {
-	res, err := h.Upstream.GetTransactionResultByIndex(context, req)
+	res, err := h.Indexed.GetTransactionResultByIndex(context, req)
+	if !errors.Is(err, errNotIndexed) {
+		h.log("observer", "GetTransactionResultByIndex", err)
+		return res, err
+	}
+
+	res, err = h.Upstream.GetTransactionResultByIndex(context, req)
 	h.log("upstream", "GetTransactionResultByIndex", err)
 	return res, err
 }
@@ -255,13 +296,25 @@ func (h *FlowAccessAPIRouter) ExecuteScriptAtBlockHeight(context context.Context
 }
 
 func (h *FlowAccessAPIRouter) GetEventsForHeightRange(context context.Context, req *access.GetEventsForHeightRangeRequest) (*access.EventsResponse, error) {
-	res, err := h.Upstream.GetEventsForHeightRange(context, req)
+	res, err := h.Indexed.GetEventsForHeightRange(context, req)
+	if !errors.Is(err, errNotIndexed) {
+		h.log("observer", "GetEventsForHeightRange", err)
+		return res, err
+	}
+
+	res, err = h.Upstream.GetEventsForHeightRange(context, req)
 	h.log("upstream", "GetEventsForHeightRange", err)
 	return res, err
 }
 
 func (h *FlowAccessAPIRouter) GetEventsForBlockIDs(context context.Context, req *access.GetEventsForBlockIDsRequest) (*access.EventsResponse, error) {
-	res, err := h.Upstream.GetEventsForBlockIDs(context, req)
+	res, err := h.Indexed.GetEventsForBlockIDs(context, req)
+	if !errors.Is(err, errNotIndexed) {
+		h.log("observer", "GetEventsForBlockIDs", err)
+		return res, err
+	}
+
+	res, err = h.Upstream.GetEventsForBlockIDs(context, req)
 	h.log("upstream", "GetEventsForBlockIDs", err)
 	return res, err
 }
@@ -8,8 +8,13 @@ import (
 	"time"
 
 	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	grpcinsecure "google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/utils/grpcutils"
@@ -189,6 +194,45 @@ func TestNewFlowCachedAccessAPIProxy(t *testing.T) {
 	<-done
 }
 
+// TestTransactionRelay tests that transactions are sent through the relay, and only forwarded
+// upstream if the relay could not send them.
+func TestTransactionRelay(t *testing.T) {
+	relay := &mockTransactionSender{}
+	router := &FlowAccessAPIRouter{
+		Logger:  zerolog.Nop(),
+		Metrics: observerMetrics,
+		// without upstream nodes, forwarded requests fail with Unimplemented
+		Upstream: &FlowAccessAPIForwarder{},
+		Relay:    relay,
+	}
+	txID := unittest.IdentifierFixture()
+	req := &access.SendTransactionRequest{}
+
+	t.Run("sent transactions are not forwarded", func(t *testing.T) {
+		relay.res, relay.err = &access.SendTransactionResponse{Id: txID[:]}, nil
+
+		res, err := router.SendTransaction(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, txID[:], res.Id)
+	})
+
+	t.Run("invalid transactions are not forwarded", func(t *testing.T) {
+		relay.res, relay.err = nil, status.Error(codes.InvalidArgument, "invalid transaction")
+
+		_, err := router.SendTransaction(context.Background(), req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("transactions are forwarded if no collection node could be reached", func(t *testing.T) {
+		relay.res, relay.err = nil, status.Error(codes.Internal, "failed to send transaction to a collection node")
+
+		_, err := router.SendTransaction(context.Background(), req)
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	assert.Equal(t, 3, relay.calls)
+}
+
 func makeFlowLite(address string, done chan int) (net.Listener, error) {
 	l, err := net.Listen("unix", address)
 	if err != nil {
@@ -271,3 +315,14 @@ type MockFlowAccessAPI struct {
 func (p MockFlowAccessAPI) Ping(context.Context, *access.PingRequest) (*access.PingResponse, error) {
 	return &access.PingResponse{}, nil
 }
+
+type mockTransactionSender struct {
+	res   *access.SendTransactionResponse
+	err   error
+	calls int
+}
+
+func (s *mockTransactionSender) SendTransaction(context.Context, *access.SendTransactionRequest) (*access.SendTransactionResponse, error) {
+	s.calls++
+	return s.res, s.err
+}
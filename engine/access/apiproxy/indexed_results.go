@@ -0,0 +1,378 @@
+package apiproxy
+
+import (
+	"context"
+	"errors"
+
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	accessmodel "github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// errNotIndexed is returned by IndexedResults if a request can not be answered from the local
+// index, in which case it is forwarded upstream.
+var errNotIndexed = errors.New("not indexed locally")
+
+// IndexedHeights is the range of heights whose execution results are indexed locally.
+type IndexedHeights interface {
+	// FirstHeight returns the first indexed height.
+	FirstHeight() uint64
+
+	// LatestHeight returns the latest indexed height, which is below the first height until the
+	// first height is indexed.
+	LatestHeight() uint64
+}
+
+// IndexedResults answers transaction result and event requests from the collections, events and
+// transaction results indexed locally, see indexer.ResultsIndexer. The results are sealed, and
+// apart from their error messages, are derived from the execution data of their blocks.
+//
+// Requests for blocks which are not indexed yet return errNotIndexed. All methods return
+// errNotIndexed on a nil IndexedResults.
+type IndexedResults struct {
+	heights            IndexedHeights
+	headers            storage.Headers
+	blocks             storage.Blocks
+	collections        storage.Collections
+	events             storage.Events
+	transactionResults storage.TransactionResults
+	maxHeightRange     uint
+}
+
+// NewIndexedResults returns a new IndexedResults reading from the given storage.
+func NewIndexedResults(
+	heights IndexedHeights,
+	headers storage.Headers,
+	blocks storage.Blocks,
+	collections storage.Collections,
+	events storage.Events,
+	transactionResults storage.TransactionResults,
+	maxHeightRange uint,
+) *IndexedResults {
+	return &IndexedResults{
+		heights:            heights,
+		headers:            headers,
+		blocks:             blocks,
+		collections:        collections,
+		events:             events,
+		transactionResults: transactionResults,
+		maxHeightRange:     maxHeightRange,
+	}
+}
+
+func (r *IndexedResults) GetTransactionResult(_ context.Context, req *access.GetTransactionRequest) (*access.TransactionResultResponse, error) {
+	if r == nil {
+		return nil, errNotIndexed
+	}
+
+	txID, err := convert.TransactionID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := r.collections.LightByTransactionID(txID)
+	if err != nil {
+		return nil, lookupError(err)
+	}
+
+	block, err := r.blocks.ByCollectionID(collection.ID())
+	if err != nil {
+		return nil, lookupError(err)
+	}
+
+	if !r.isIndexed(block.Header.Height) {
+		return nil, errNotIndexed
+	}
+
+	result, err := r.transactionResults.ByBlockIDTransactionID(block.ID(), txID)
+	if err != nil {
+		return nil, lookupError(err)
+	}
+
+	events, err := r.events.ByBlockIDTransactionID(block.ID(), txID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get events: %v", err)
+	}
+
+	return accessmodel.TransactionResultToMessage(transactionResult(block.Header, collection.ID(), result, events)), nil
+}
+
+func (r *IndexedResults) GetTransactionResultByIndex(_ context.Context, req *access.GetTransactionByIndexRequest) (*access.TransactionResultResponse, error) {
+	if r == nil {
+		return nil, errNotIndexed
+	}
+
+	block, err := r.indexedBlock(req.GetBlockId())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.transactionResults.ByBlockIDTransactionIndex(block.ID(), req.GetIndex())
+	if err != nil {
+		return nil, lookupError(err)
+	}
+
+	collectionIDs, err := r.collectionIDs(block)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := r.events.ByBlockIDTransactionIndex(block.ID(), req.GetIndex())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get events: %v", err)
+	}
+
+	return accessmodel.TransactionResultToMessage(transactionResult(block.Header, collectionIDs[result.TransactionID], result, events)), nil
+}
+
+func (r *IndexedResults) GetTransactionResultsByBlockID(_ context.Context, req *access.GetTransactionsByBlockIDRequest) (*access.TransactionResultsResponse, error) {
+	if r == nil {
+		return nil, errNotIndexed
+	}
+
+	block, err := r.indexedBlock(req.GetBlockId())
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := r.transactionResults.ByBlockID(block.ID())
+	if err != nil {
+		return nil, lookupError(err)
+	}
+	// every block has at least the system transaction
+	if len(results) == 0 {
+		return nil, errNotIndexed
+	}
+
+	collectionIDs, err := r.collectionIDs(block)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := r.events.ByBlockID(block.ID())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get events: %v", err)
+	}
+	eventsByTransaction := make(map[flow.Identifier][]flow.Event)
+	for _, event := range events {
+		eventsByTransaction[event.TransactionID] = append(eventsByTransaction[event.TransactionID], event)
+	}
+
+	transactionResults := make([]*accessmodel.TransactionResult, len(results))
+	for i := range results {
+		result := &results[i]
+		transactionResults[i] = transactionResult(block.Header, collectionIDs[result.TransactionID], result, eventsByTransaction[result.TransactionID])
+	}
+
+	return accessmodel.TransactionResultsToMessage(transactionResults), nil
+}
+
+func (r *IndexedResults) GetEventsForHeightRange(_ context.Context, req *access.GetEventsForHeightRangeRequest) (*access.EventsResponse, error) {
+	if r == nil {
+		return nil, errNotIndexed
+	}
+
+	eventType, err := convert.EventType(req.GetType())
+	if err != nil {
+		return nil, err
+	}
+
+	startHeight := req.GetStartHeight()
+	endHeight := req.GetEndHeight()
+	if endHeight < startHeight {
+		return nil, status.Error(codes.InvalidArgument, "invalid start or end height")
+	}
+	rangeSize := endHeight - startHeight + 1 // range is inclusive on both ends
+	if rangeSize > uint64(r.maxHeightRange) {
+		return nil, status.Errorf(codes.InvalidArgument, "requested block range (%d) exceeded maximum (%d)", rangeSize, r.maxHeightRange)
+	}
+
+	// ranges ending after the latest indexed height are forwarded, since the upstream node limits
+	// them to its latest sealed height
+	if !r.isIndexed(startHeight) || !r.isIndexed(endHeight) {
+		return nil, errNotIndexed
+	}
+
+	headers := make([]*flow.Header, 0, rangeSize)
+	for height := startHeight; height <= endHeight; height++ {
+		header, err := r.headers.ByHeight(height)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get block header at height %d: %v", height, err)
+		}
+		headers = append(headers, header)
+	}
+
+	return r.blockEvents(headers, flow.EventType(eventType))
+}
+
+func (r *IndexedResults) GetEventsForBlockIDs(_ context.Context, req *access.GetEventsForBlockIDsRequest) (*access.EventsResponse, error) {
+	if r == nil {
+		return nil, errNotIndexed
+	}
+
+	eventType, err := convert.EventType(req.GetType())
+	if err != nil {
+		return nil, err
+	}
+
+	blockIDs, err := convert.BlockIDs(req.GetBlockIds())
+	if err != nil {
+		return nil, err
+	}
+	if uint(len(blockIDs)) > r.maxHeightRange {
+		return nil, status.Errorf(codes.InvalidArgument, "requested block range (%d) exceeded maximum (%d)", len(blockIDs), r.maxHeightRange)
+	}
+
+	headers := make([]*flow.Header, len(blockIDs))
+	for i, blockID := range blockIDs {
+		header, err := r.headers.ByBlockID(blockID)
+		if err != nil {
+			return nil, lookupError(err)
+		}
+		if !r.isIndexed(header.Height) {
+			return nil, errNotIndexed
+		}
+		headers[i] = header
+	}
+
+	return r.blockEvents(headers, flow.EventType(eventType))
+}
+
+// blockEvents returns the events of the given type emitted in each of the blocks.
+func (r *IndexedResults) blockEvents(headers []*flow.Header, eventType flow.EventType) (*access.EventsResponse, error) {
+	results := make([]*access.EventsResponse_Result, len(headers))
+	for i, header := range headers {
+		blockID := header.ID()
+		events, err := r.events.ByBlockIDEventType(blockID, eventType)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get events for block %v: %v", blockID, err)
+		}
+
+		messages := make([]*entities.Event, len(events))
+		for j, event := range events {
+			messages[j] = convert.EventToMessage(event)
+		}
+
+		results[i] = &access.EventsResponse_Result{
+			BlockId:        blockID[:],
+			BlockHeight:    header.Height,
+			BlockTimestamp: timestamppb.New(header.Timestamp),
+			Events:         messages,
+		}
+	}
+
+	return &access.EventsResponse{Results: results}, nil
+}
+
+// indexedBlock returns the block with the given ID, or errNotIndexed if it is not indexed.
+func (r *IndexedResults) indexedBlock(id []byte) (*flow.Block, error) {
+	blockID, err := convert.BlockID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := r.blocks.ByID(blockID)
+	if err != nil {
+		return nil, lookupError(err)
+	}
+
+	if !r.isIndexed(block.Header.Height) {
+		return nil, errNotIndexed
+	}
+
+	return block, nil
+}
+
+// collectionIDs returns the ID of the collection of each transaction of the block. The system
+// transaction is not part of any collection.
+func (r *IndexedResults) collectionIDs(block *flow.Block) (map[flow.Identifier]flow.Identifier, error) {
+	collectionIDs := make(map[flow.Identifier]flow.Identifier)
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := r.collections.LightByID(guarantee.CollectionID)
+		if err != nil {
+			return nil, lookupError(err)
+		}
+		for _, txID := range collection.Transactions {
+			collectionIDs[txID] = guarantee.CollectionID
+		}
+	}
+	return collectionIDs, nil
+}
+
+func (r *IndexedResults) isIndexed(height uint64) bool {
+	return height >= r.heights.FirstHeight() && height <= r.heights.LatestHeight()
+}
+
+// lookupError returns errNotIndexed if the data was not found locally, so the request is
+// forwarded upstream, and an Internal error otherwise.
+func lookupError(err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return errNotIndexed
+	}
+	return status.Errorf(codes.Internal, "failed to read local index: %v", err)
+}
+
+// transactionResult returns the result of a transaction of a sealed block.
+func transactionResult(
+	header *flow.Header,
+	collectionID flow.Identifier,
+	result *flow.TransactionResult,
+	events []flow.Event,
+) *accessmodel.TransactionResult {
+	statusCode := uint(0)
+	if result.ErrorMessage != "" {
+		statusCode = 1
+	}
+
+	return &accessmodel.TransactionResult{
+		Status:        flow.TransactionStatusSealed,
+		StatusCode:    statusCode,
+		Events:        events,
+		ErrorMessage:  result.ErrorMessage,
+		BlockID:       header.ID(),
+		TransactionID: result.TransactionID,
+		CollectionID:  collectionID,
+		BlockHeight:   header.Height,
+	}
+}
+
+// UpstreamErrorMessages returns the error messages of the failed transactions of sealed blocks from
+// the upstream access nodes. The error messages are not part of the execution data, so they are
+// requested once for each block indexed by indexer.ResultsIndexer.
+type UpstreamErrorMessages struct {
+	upstream *FlowAccessAPIForwarder
+}
+
+// NewUpstreamErrorMessages returns a new UpstreamErrorMessages requesting the transaction results
+// from the given upstream access nodes.
+func NewUpstreamErrorMessages(upstream *FlowAccessAPIForwarder) *UpstreamErrorMessages {
+	return &UpstreamErrorMessages{
+		upstream: upstream,
+	}
+}
+
+// ByBlockID returns the error message of each transaction of the block, in execution order. The
+// error messages of successful transactions are empty.
+func (m *UpstreamErrorMessages) ByBlockID(ctx context.Context, blockID flow.Identifier) ([]string, error) {
+	res, err := m.upstream.GetTransactionResultsByBlockID(ctx, &access.GetTransactionsByBlockIDRequest{
+		BlockId: blockID[:],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errorMessages := make([]string, len(res.GetTransactionResults()))
+	for i, result := range res.GetTransactionResults() {
+		if result.GetStatusCode() != 0 {
+			errorMessages[i] = result.GetErrorMessage()
+		}
+	}
+	return errorMessages, nil
+}
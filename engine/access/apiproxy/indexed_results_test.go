@@ -0,0 +1,211 @@
+package apiproxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// the observer metrics are registered globally, so the collector can only be created once
+var observerMetrics = metrics.NewObserverCollector()
+
+type indexedHeights struct {
+	first  uint64
+	latest uint64
+}
+
+func (h *indexedHeights) FirstHeight() uint64 {
+	return h.first
+}
+
+func (h *indexedHeights) LatestHeight() uint64 {
+	return h.latest
+}
+
+// TestIndexedResults tests that transaction results and events are served from the local index,
+// and that requests for blocks which are not indexed are forwarded upstream.
+func TestIndexedResults(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		all := bstorage.InitAll(metrics.NewNoopCollector(), db)
+
+		collection := unittest.CollectionFixture(2)
+		light := collection.Light()
+		block := unittest.BlockFixture()
+		block.SetPayload(flow.Payload{
+			Guarantees: []*flow.CollectionGuarantee{{CollectionID: collection.ID()}},
+		})
+		blockID := block.ID()
+		height := block.Header.Height
+
+		txID := collection.Transactions[0].ID()
+		failedTxID := collection.Transactions[1].ID()
+		systemTxID := unittest.IdentifierFixture()
+
+		eventType := flow.EventType("A.0123456789abcdef.Contract.Event")
+		events := []flow.EventsList{
+			{
+				unittest.EventFixture(eventType, 0, 0, txID, 0),
+				unittest.EventFixture(flow.EventAccountCreated, 1, 0, failedTxID, 0),
+			},
+			{
+				unittest.EventFixture(eventType, 2, 0, systemTxID, 0),
+			},
+		}
+		results := []flow.TransactionResult{
+			{TransactionID: txID},
+			{TransactionID: failedTxID, ErrorMessage: "failed"},
+			{TransactionID: systemTxID},
+		}
+
+		require.NoError(t, all.Blocks.Store(&block))
+		require.NoError(t, db.Update(operation.IndexBlockHeight(height, blockID)))
+		require.NoError(t, all.Collections.StoreLightAndIndexByTransaction(&light))
+		require.NoError(t, all.Blocks.IndexBlockForCollections(blockID, []flow.Identifier{collection.ID()}))
+
+		batch := bstorage.NewBatch(db)
+		require.NoError(t, all.Events.BatchStore(blockID, events, batch))
+		require.NoError(t, all.TransactionResults.BatchStore(blockID, results, batch))
+		require.NoError(t, batch.Flush())
+
+		heights := &indexedHeights{first: height, latest: height}
+		router := &FlowAccessAPIRouter{
+			Logger:  zerolog.Nop(),
+			Metrics: observerMetrics,
+			// without upstream nodes, forwarded requests fail with Unimplemented
+			Upstream: &FlowAccessAPIForwarder{},
+			Indexed: NewIndexedResults(
+				heights,
+				all.Headers,
+				all.Blocks,
+				all.Collections,
+				all.Events,
+				all.TransactionResults,
+				backend.DefaultMaxHeightRange,
+			),
+		}
+
+		ctx := context.Background()
+
+		t.Run("transaction result", func(t *testing.T) {
+			res, err := router.GetTransactionResult(ctx, &access.GetTransactionRequest{Id: failedTxID[:]})
+			require.NoError(t, err)
+
+			assert.Equal(t, entities.TransactionStatus_SEALED, res.Status)
+			assert.Equal(t, uint32(1), res.StatusCode)
+			assert.Equal(t, "failed", res.ErrorMessage)
+			assert.Equal(t, blockID[:], res.BlockId)
+			assert.Equal(t, height, res.BlockHeight)
+			assert.Equal(t, light.ID(), flow.HashToID(res.CollectionId))
+			require.Len(t, res.Events, 1)
+			assert.Equal(t, string(flow.EventAccountCreated), res.Events[0].Type)
+		})
+
+		t.Run("system transaction result by index", func(t *testing.T) {
+			res, err := router.GetTransactionResultByIndex(ctx, &access.GetTransactionByIndexRequest{BlockId: blockID[:], Index: 2})
+			require.NoError(t, err)
+
+			assert.Equal(t, systemTxID[:], res.TransactionId)
+			assert.Equal(t, uint32(0), res.StatusCode)
+			assert.Equal(t, flow.ZeroID[:], res.CollectionId)
+			require.Len(t, res.Events, 1)
+		})
+
+		t.Run("transaction results by block ID", func(t *testing.T) {
+			res, err := router.GetTransactionResultsByBlockID(ctx, &access.GetTransactionsByBlockIDRequest{BlockId: blockID[:]})
+			require.NoError(t, err)
+
+			require.Len(t, res.TransactionResults, len(results))
+			for i, result := range res.TransactionResults {
+				assert.Equal(t, results[i].TransactionID[:], result.TransactionId)
+			}
+			assert.Len(t, res.TransactionResults[0].Events, 1)
+			assert.Len(t, res.TransactionResults[1].Events, 1)
+		})
+
+		t.Run("events", func(t *testing.T) {
+			res, err := router.GetEventsForHeightRange(ctx, &access.GetEventsForHeightRangeRequest{
+				Type:        string(eventType),
+				StartHeight: height,
+				EndHeight:   height,
+			})
+			require.NoError(t, err)
+			require.Len(t, res.Results, 1)
+			assert.Equal(t, blockID[:], res.Results[0].BlockId)
+			assert.Len(t, res.Results[0].Events, 2)
+
+			res, err = router.GetEventsForBlockIDs(ctx, &access.GetEventsForBlockIDsRequest{
+				Type:     string(flow.EventAccountCreated),
+				BlockIds: [][]byte{blockID[:]},
+			})
+			require.NoError(t, err)
+			require.Len(t, res.Results, 1)
+			assert.Len(t, res.Results[0].Events, 1)
+		})
+
+		t.Run("invalid requests are not forwarded", func(t *testing.T) {
+			_, err := router.GetEventsForHeightRange(ctx, &access.GetEventsForHeightRangeRequest{
+				Type:        string(eventType),
+				StartHeight: height,
+				EndHeight:   height - 1,
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+
+		t.Run("unknown transactions are forwarded", func(t *testing.T) {
+			unknownID := unittest.IdentifierFixture()
+			_, err := router.GetTransactionResult(ctx, &access.GetTransactionRequest{Id: unknownID[:]})
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+
+		t.Run("heights after the latest indexed height are forwarded", func(t *testing.T) {
+			_, err := router.GetEventsForHeightRange(ctx, &access.GetEventsForHeightRangeRequest{
+				Type:        string(eventType),
+				StartHeight: height,
+				EndHeight:   height + 1,
+			})
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+
+		t.Run("blocks which are not indexed yet are forwarded", func(t *testing.T) {
+			heights.latest = height - 1
+			defer func() { heights.latest = height }()
+
+			_, err := router.GetTransactionResult(ctx, &access.GetTransactionRequest{Id: txID[:]})
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+			_, err = router.GetTransactionResultsByBlockID(ctx, &access.GetTransactionsByBlockIDRequest{BlockId: blockID[:]})
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+			_, err = router.GetEventsForBlockIDs(ctx, &access.GetEventsForBlockIDsRequest{
+				Type:     string(eventType),
+				BlockIds: [][]byte{blockID[:]},
+			})
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+
+		t.Run("all requests are forwarded without index", func(t *testing.T) {
+			forwarding := &FlowAccessAPIRouter{
+				Logger:   zerolog.Nop(),
+				Metrics:  observerMetrics,
+				Upstream: router.Upstream,
+			}
+			_, err := forwarding.GetTransactionResult(ctx, &access.GetTransactionRequest{Id: txID[:]})
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+	})
+}
@@ -0,0 +1,403 @@
+package apiproxy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// HistoricalSpork is a previous spork of the network, whose data is served by an access node of
+// that spork.
+type HistoricalSpork struct {
+	RootHeight uint64 // the height of the root block of the spork
+	Client     access.AccessAPIClient
+}
+
+// SporkRouter routes Access API requests for data of previous sporks to the access nodes of these
+// sporks, so clients can use a single endpoint for the whole history of the chain. All other
+// requests are served by the local handler, which serves the current spork.
+//
+// Requests for heights before the root height of the current spork are sent to the spork
+// containing the height, which is the spork with the highest root height at or below it. Event
+// requests for height ranges spanning several sporks are split and the results are joined.
+//
+// Requests for block, collection and transaction IDs are served locally first, and are sent to
+// the historical sporks, newest first, if the data is not found locally.
+type SporkRouter struct {
+	access.AccessAPIServer // the local handler
+
+	log            zerolog.Logger
+	rootHeight     uint64            // the root height of the current spork
+	sporks         []HistoricalSpork // ordered by root height
+	maxHeightRange uint
+}
+
+var _ access.AccessAPIServer = (*SporkRouter)(nil)
+
+// NewSporkRouter returns a new router serving the current spork, starting at rootHeight, with the
+// given local handler, and the historical sporks with their access nodes. Event height ranges
+// spanning several sporks are limited to maxHeightRange heights.
+func NewSporkRouter(
+	log zerolog.Logger,
+	local access.AccessAPIServer,
+	rootHeight uint64,
+	sporks []HistoricalSpork,
+	maxHeightRange uint,
+) (*SporkRouter, error) {
+	sorted := make([]HistoricalSpork, len(sporks))
+	copy(sorted, sporks)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].RootHeight < sorted[j].RootHeight
+	})
+
+	for i, spork := range sorted {
+		if spork.RootHeight >= rootHeight {
+			return nil, fmt.Errorf("historical spork root height %d is not below the current spork root height %d", spork.RootHeight, rootHeight)
+		}
+		if i > 0 && spork.RootHeight == sorted[i-1].RootHeight {
+			return nil, fmt.Errorf("duplicate historical spork root height %d", spork.RootHeight)
+		}
+	}
+
+	return &SporkRouter{
+		AccessAPIServer: local,
+		log:             log.With().Str("component", "spork_router").Logger(),
+		rootHeight:      rootHeight,
+		sporks:          sorted,
+		maxHeightRange:  maxHeightRange,
+	}, nil
+}
+
+// sporkAt returns the client of the historical spork containing the given height, or nil if the
+// height is served locally, and the first height after the spork.
+func (r *SporkRouter) sporkAt(height uint64) (access.AccessAPIClient, uint64) {
+	if height >= r.rootHeight {
+		return nil, 0
+	}
+
+	// the first spork starting after the height
+	next := sort.Search(len(r.sporks), func(i int) bool {
+		return r.sporks[i].RootHeight > height
+	})
+
+	nextRootHeight := r.rootHeight
+	if next < len(r.sporks) {
+		nextRootHeight = r.sporks[next].RootHeight
+	}
+
+	// heights before the first known spork are not served by any historical spork
+	if next == 0 {
+		return nil, nextRootHeight
+	}
+	return r.sporks[next-1].Client, nextRootHeight
+}
+
+// atHeight serves a request for a height from the spork containing the height.
+func atHeight[Res any](
+	r *SporkRouter,
+	height uint64,
+	local func() (Res, error),
+	historical func(access.AccessAPIClient) (Res, error),
+) (Res, error) {
+	client, _ := r.sporkAt(height)
+	if client == nil {
+		return local()
+	}
+	return historical(client)
+}
+
+// firstFound serves a request from the local handler, and from the historical sporks, newest first,
+// if the requested data is not found locally. found reports whether a response contains the data.
+func firstFound[Res any](
+	r *SporkRouter,
+	local func() (Res, error),
+	historical func(access.AccessAPIClient) (Res, error),
+	found func(Res) bool,
+) (Res, error) {
+	res, err := local()
+	if (err == nil && found(res)) || (err != nil && status.Code(err) != codes.NotFound) {
+		return res, err
+	}
+
+	notFoundRes, notFoundErr := res, err
+	var sporkErr error
+	for i := len(r.sporks) - 1; i >= 0; i-- {
+		res, err := historical(r.sporks[i].Client)
+		if err == nil && found(res) {
+			return res, nil
+		}
+		if err != nil && status.Code(err) != codes.NotFound {
+			r.log.Warn().Err(err).Uint64("spork_root_height", r.sporks[i].RootHeight).Msg("failed to query historical spork")
+			sporkErr = err
+		}
+	}
+
+	// the data may be missing because a historical spork could not be queried
+	if sporkErr != nil {
+		return notFoundRes, sporkErr
+	}
+	return notFoundRes, notFoundErr
+}
+
+// exists reports that every successful response contains the requested data.
+func exists[Res any](Res) bool {
+	return true
+}
+
+func (r *SporkRouter) GetBlockHeaderByID(ctx context.Context, req *access.GetBlockHeaderByIDRequest) (*access.BlockHeaderResponse, error) {
+	return firstFound(r,
+		func() (*access.BlockHeaderResponse, error) {
+			return r.AccessAPIServer.GetBlockHeaderByID(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.BlockHeaderResponse, error) {
+			return c.GetBlockHeaderByID(ctx, req)
+		},
+		exists[*access.BlockHeaderResponse],
+	)
+}
+
+func (r *SporkRouter) GetBlockHeaderByHeight(ctx context.Context, req *access.GetBlockHeaderByHeightRequest) (*access.BlockHeaderResponse, error) {
+	return atHeight(r, req.GetHeight(),
+		func() (*access.BlockHeaderResponse, error) {
+			return r.AccessAPIServer.GetBlockHeaderByHeight(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.BlockHeaderResponse, error) {
+			return c.GetBlockHeaderByHeight(ctx, req)
+		},
+	)
+}
+
+func (r *SporkRouter) GetBlockByID(ctx context.Context, req *access.GetBlockByIDRequest) (*access.BlockResponse, error) {
+	return firstFound(r,
+		func() (*access.BlockResponse, error) {
+			return r.AccessAPIServer.GetBlockByID(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.BlockResponse, error) {
+			return c.GetBlockByID(ctx, req)
+		},
+		exists[*access.BlockResponse],
+	)
+}
+
+func (r *SporkRouter) GetBlockByHeight(ctx context.Context, req *access.GetBlockByHeightRequest) (*access.BlockResponse, error) {
+	return atHeight(r, req.GetHeight(),
+		func() (*access.BlockResponse, error) {
+			return r.AccessAPIServer.GetBlockByHeight(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.BlockResponse, error) {
+			return c.GetBlockByHeight(ctx, req)
+		},
+	)
+}
+
+func (r *SporkRouter) GetCollectionByID(ctx context.Context, req *access.GetCollectionByIDRequest) (*access.CollectionResponse, error) {
+	return firstFound(r,
+		func() (*access.CollectionResponse, error) {
+			return r.AccessAPIServer.GetCollectionByID(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.CollectionResponse, error) {
+			return c.GetCollectionByID(ctx, req)
+		},
+		exists[*access.CollectionResponse],
+	)
+}
+
+func (r *SporkRouter) GetTransaction(ctx context.Context, req *access.GetTransactionRequest) (*access.TransactionResponse, error) {
+	return firstFound(r,
+		func() (*access.TransactionResponse, error) {
+			return r.AccessAPIServer.GetTransaction(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.TransactionResponse, error) {
+			return c.GetTransaction(ctx, req)
+		},
+		exists[*access.TransactionResponse],
+	)
+}
+
+func (r *SporkRouter) GetTransactionResult(ctx context.Context, req *access.GetTransactionRequest) (*access.TransactionResultResponse, error) {
+	return firstFound(r,
+		func() (*access.TransactionResultResponse, error) {
+			return r.AccessAPIServer.GetTransactionResult(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.TransactionResultResponse, error) {
+			res, err := c.GetTransactionResult(ctx, req)
+			// transactions pending on a historical spork will never be executed
+			if err == nil && res.GetStatus() == entities.TransactionStatus_PENDING {
+				res.Status = entities.TransactionStatus_EXPIRED
+			}
+			return res, err
+		},
+		// unknown transactions are returned with the unknown status rather than as not found
+		func(res *access.TransactionResultResponse) bool {
+			return res.GetStatus() != entities.TransactionStatus_UNKNOWN
+		},
+	)
+}
+
+func (r *SporkRouter) GetTransactionResultByIndex(ctx context.Context, req *access.GetTransactionByIndexRequest) (*access.TransactionResultResponse, error) {
+	return firstFound(r,
+		func() (*access.TransactionResultResponse, error) {
+			return r.AccessAPIServer.GetTransactionResultByIndex(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.TransactionResultResponse, error) {
+			return c.GetTransactionResultByIndex(ctx, req)
+		},
+		exists[*access.TransactionResultResponse],
+	)
+}
+
+func (r *SporkRouter) GetTransactionResultsByBlockID(ctx context.Context, req *access.GetTransactionsByBlockIDRequest) (*access.TransactionResultsResponse, error) {
+	return firstFound(r,
+		func() (*access.TransactionResultsResponse, error) {
+			return r.AccessAPIServer.GetTransactionResultsByBlockID(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.TransactionResultsResponse, error) {
+			return c.GetTransactionResultsByBlockID(ctx, req)
+		},
+		exists[*access.TransactionResultsResponse],
+	)
+}
+
+func (r *SporkRouter) GetTransactionsByBlockID(ctx context.Context, req *access.GetTransactionsByBlockIDRequest) (*access.TransactionsResponse, error) {
+	return firstFound(r,
+		func() (*access.TransactionsResponse, error) {
+			return r.AccessAPIServer.GetTransactionsByBlockID(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.TransactionsResponse, error) {
+			return c.GetTransactionsByBlockID(ctx, req)
+		},
+		exists[*access.TransactionsResponse],
+	)
+}
+
+func (r *SporkRouter) GetAccountAtBlockHeight(ctx context.Context, req *access.GetAccountAtBlockHeightRequest) (*access.AccountResponse, error) {
+	return atHeight(r, req.GetBlockHeight(),
+		func() (*access.AccountResponse, error) {
+			return r.AccessAPIServer.GetAccountAtBlockHeight(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.AccountResponse, error) {
+			return c.GetAccountAtBlockHeight(ctx, req)
+		},
+	)
+}
+
+func (r *SporkRouter) ExecuteScriptAtBlockID(ctx context.Context, req *access.ExecuteScriptAtBlockIDRequest) (*access.ExecuteScriptResponse, error) {
+	return firstFound(r,
+		func() (*access.ExecuteScriptResponse, error) {
+			return r.AccessAPIServer.ExecuteScriptAtBlockID(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.ExecuteScriptResponse, error) {
+			return c.ExecuteScriptAtBlockID(ctx, req)
+		},
+		exists[*access.ExecuteScriptResponse],
+	)
+}
+
+func (r *SporkRouter) ExecuteScriptAtBlockHeight(ctx context.Context, req *access.ExecuteScriptAtBlockHeightRequest) (*access.ExecuteScriptResponse, error) {
+	return atHeight(r, req.GetBlockHeight(),
+		func() (*access.ExecuteScriptResponse, error) {
+			return r.AccessAPIServer.ExecuteScriptAtBlockHeight(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.ExecuteScriptResponse, error) {
+			return c.ExecuteScriptAtBlockHeight(ctx, req)
+		},
+	)
+}
+
+// GetEventsForHeightRange returns the events of a height range, split into one request per spork
+// if the range spans several sporks.
+func (r *SporkRouter) GetEventsForHeightRange(ctx context.Context, req *access.GetEventsForHeightRangeRequest) (*access.EventsResponse, error) {
+	startHeight := req.GetStartHeight()
+	endHeight := req.GetEndHeight()
+	if startHeight >= r.rootHeight || endHeight < startHeight {
+		return r.AccessAPIServer.GetEventsForHeightRange(ctx, req)
+	}
+
+	var results []*access.EventsResponse_Result
+	err := r.forEachSpork(startHeight, endHeight, func(client access.AccessAPIClient, start uint64, end uint64) error {
+		sporkReq := &access.GetEventsForHeightRangeRequest{
+			Type:        req.GetType(),
+			StartHeight: start,
+			EndHeight:   end,
+		}
+
+		var res *access.EventsResponse
+		var err error
+		if client == nil {
+			res, err = r.AccessAPIServer.GetEventsForHeightRange(ctx, sporkReq)
+		} else {
+			res, err = client.GetEventsForHeightRange(ctx, sporkReq)
+		}
+		if err != nil {
+			return err
+		}
+		results = append(results, res.GetResults()...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &access.EventsResponse{Results: results}, nil
+}
+
+// forEachSpork splits the height range starting before the current spork by spork, and calls fn
+// with the client of each spork, nil for the current spork, and the part of the range in the spork.
+func (r *SporkRouter) forEachSpork(
+	startHeight uint64,
+	endHeight uint64,
+	fn func(client access.AccessAPIClient, start uint64, end uint64) error,
+) error {
+	rangeSize := endHeight - startHeight + 1 // range is inclusive on both ends
+	if rangeSize > uint64(r.maxHeightRange) {
+		return status.Errorf(codes.InvalidArgument, "requested block range (%d) exceeded maximum (%d)", rangeSize, r.maxHeightRange)
+	}
+
+	for start := startHeight; ; {
+		client, next := r.sporkAt(start)
+		end := endHeight
+		if start < r.rootHeight && next-1 < end {
+			end = next - 1
+		}
+
+		err := fn(client, start, end)
+		if err != nil {
+			return err
+		}
+
+		if end == endHeight {
+			return nil
+		}
+		start = end + 1
+	}
+}
+
+func (r *SporkRouter) GetEventsForBlockIDs(ctx context.Context, req *access.GetEventsForBlockIDsRequest) (*access.EventsResponse, error) {
+	return firstFound(r,
+		func() (*access.EventsResponse, error) {
+			return r.AccessAPIServer.GetEventsForBlockIDs(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.EventsResponse, error) {
+			return c.GetEventsForBlockIDs(ctx, req)
+		},
+		exists[*access.EventsResponse],
+	)
+}
+
+func (r *SporkRouter) GetExecutionResultForBlockID(ctx context.Context, req *access.GetExecutionResultForBlockIDRequest) (*access.ExecutionResultForBlockIDResponse, error) {
+	return firstFound(r,
+		func() (*access.ExecutionResultForBlockIDResponse, error) {
+			return r.AccessAPIServer.GetExecutionResultForBlockID(ctx, req)
+		},
+		func(c access.AccessAPIClient) (*access.ExecutionResultForBlockIDResponse, error) {
+			return c.GetExecutionResultForBlockID(ctx, req)
+		},
+		exists[*access.ExecutionResultForBlockIDResponse],
+	)
+}
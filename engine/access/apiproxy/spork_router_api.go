@@ -0,0 +1,364 @@
+package apiproxy
+
+import (
+	"context"
+
+	"github.com/onflow/flow/protobuf/go/flow/access"
+
+	flowaccess "github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SporkRouterAPI routes the requests of the access.API interface, used by the REST API, like the
+// SporkRouter routes gRPC requests: requests for data of previous sporks are sent to the access
+// nodes of these sporks, and all other requests are served by the local API.
+//
+// Filtered event requests are only served by the local API, as historical access nodes do not
+// support filters.
+type SporkRouterAPI struct {
+	flowaccess.API // the local API
+
+	router *SporkRouter
+	chain  flow.Chain
+}
+
+var _ flowaccess.API = (*SporkRouterAPI)(nil)
+
+// NewSporkRouterAPI returns a new API serving the current spork with the given local API, and the
+// historical sporks of the router with their access nodes.
+func NewSporkRouterAPI(router *SporkRouter, local flowaccess.API, chain flow.Chain) *SporkRouterAPI {
+	return &SporkRouterAPI{
+		API:    local,
+		router: router,
+		chain:  chain,
+	}
+}
+
+// blockWithStatus is a block or block header with its status, returned together by the routing
+// helpers.
+type blockWithStatus[T any] struct {
+	block  T
+	status flow.BlockStatus
+}
+
+func (a *SporkRouterAPI) GetBlockHeaderByID(ctx context.Context, id flow.Identifier) (*flow.Header, flow.BlockStatus, error) {
+	res, err := firstFound(a.router,
+		func() (blockWithStatus[*flow.Header], error) {
+			header, status, err := a.API.GetBlockHeaderByID(ctx, id)
+			return blockWithStatus[*flow.Header]{header, status}, err
+		},
+		func(c access.AccessAPIClient) (blockWithStatus[*flow.Header], error) {
+			return historicalBlockHeader(c.GetBlockHeaderByID(ctx, &access.GetBlockHeaderByIDRequest{Id: id[:]}))
+		},
+		exists[blockWithStatus[*flow.Header]],
+	)
+	return res.block, res.status, err
+}
+
+func (a *SporkRouterAPI) GetBlockHeaderByHeight(ctx context.Context, height uint64) (*flow.Header, flow.BlockStatus, error) {
+	res, err := atHeight(a.router, height,
+		func() (blockWithStatus[*flow.Header], error) {
+			header, status, err := a.API.GetBlockHeaderByHeight(ctx, height)
+			return blockWithStatus[*flow.Header]{header, status}, err
+		},
+		func(c access.AccessAPIClient) (blockWithStatus[*flow.Header], error) {
+			return historicalBlockHeader(c.GetBlockHeaderByHeight(ctx, &access.GetBlockHeaderByHeightRequest{Height: height}))
+		},
+	)
+	return res.block, res.status, err
+}
+
+func (a *SporkRouterAPI) GetBlockByID(ctx context.Context, id flow.Identifier) (*flow.Block, flow.BlockStatus, error) {
+	res, err := firstFound(a.router,
+		func() (blockWithStatus[*flow.Block], error) {
+			block, status, err := a.API.GetBlockByID(ctx, id)
+			return blockWithStatus[*flow.Block]{block, status}, err
+		},
+		func(c access.AccessAPIClient) (blockWithStatus[*flow.Block], error) {
+			return historicalBlock(c.GetBlockByID(ctx, &access.GetBlockByIDRequest{Id: id[:], FullBlockResponse: true}))
+		},
+		exists[blockWithStatus[*flow.Block]],
+	)
+	return res.block, res.status, err
+}
+
+func (a *SporkRouterAPI) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, flow.BlockStatus, error) {
+	res, err := atHeight(a.router, height,
+		func() (blockWithStatus[*flow.Block], error) {
+			block, status, err := a.API.GetBlockByHeight(ctx, height)
+			return blockWithStatus[*flow.Block]{block, status}, err
+		},
+		func(c access.AccessAPIClient) (blockWithStatus[*flow.Block], error) {
+			return historicalBlock(c.GetBlockByHeight(ctx, &access.GetBlockByHeightRequest{Height: height, FullBlockResponse: true}))
+		},
+	)
+	return res.block, res.status, err
+}
+
+func (a *SporkRouterAPI) GetCollectionByID(ctx context.Context, id flow.Identifier) (*flow.LightCollection, error) {
+	return firstFound(a.router,
+		func() (*flow.LightCollection, error) {
+			return a.API.GetCollectionByID(ctx, id)
+		},
+		func(c access.AccessAPIClient) (*flow.LightCollection, error) {
+			res, err := c.GetCollectionByID(ctx, &access.GetCollectionByIDRequest{Id: id[:]})
+			if err != nil {
+				return nil, err
+			}
+			return &flow.LightCollection{
+				Transactions: convert.MessagesToIdentifiers(res.GetCollection().GetTransactionIds()),
+			}, nil
+		},
+		exists[*flow.LightCollection],
+	)
+}
+
+func (a *SporkRouterAPI) GetTransaction(ctx context.Context, id flow.Identifier) (*flow.TransactionBody, error) {
+	return firstFound(a.router,
+		func() (*flow.TransactionBody, error) {
+			return a.API.GetTransaction(ctx, id)
+		},
+		func(c access.AccessAPIClient) (*flow.TransactionBody, error) {
+			res, err := c.GetTransaction(ctx, &access.GetTransactionRequest{Id: id[:]})
+			if err != nil {
+				return nil, err
+			}
+			tx, err := convert.MessageToTransaction(res.GetTransaction(), a.chain)
+			if err != nil {
+				return nil, err
+			}
+			return &tx, nil
+		},
+		exists[*flow.TransactionBody],
+	)
+}
+
+func (a *SporkRouterAPI) GetTransactionsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.TransactionBody, error) {
+	return firstFound(a.router,
+		func() ([]*flow.TransactionBody, error) {
+			return a.API.GetTransactionsByBlockID(ctx, blockID)
+		},
+		func(c access.AccessAPIClient) ([]*flow.TransactionBody, error) {
+			res, err := c.GetTransactionsByBlockID(ctx, &access.GetTransactionsByBlockIDRequest{BlockId: blockID[:]})
+			if err != nil {
+				return nil, err
+			}
+			transactions := make([]*flow.TransactionBody, len(res.GetTransactions()))
+			for i, message := range res.GetTransactions() {
+				tx, err := convert.MessageToTransaction(message, a.chain)
+				if err != nil {
+					return nil, err
+				}
+				transactions[i] = &tx
+			}
+			return transactions, nil
+		},
+		exists[[]*flow.TransactionBody],
+	)
+}
+
+func (a *SporkRouterAPI) GetTransactionResult(ctx context.Context, id flow.Identifier) (*flowaccess.TransactionResult, error) {
+	return firstFound(a.router,
+		func() (*flowaccess.TransactionResult, error) {
+			return a.API.GetTransactionResult(ctx, id)
+		},
+		func(c access.AccessAPIClient) (*flowaccess.TransactionResult, error) {
+			return historicalTransactionResult(c.GetTransactionResult(ctx, &access.GetTransactionRequest{Id: id[:]}))
+		},
+		// unknown transactions are returned with the unknown status rather than as not found
+		func(res *flowaccess.TransactionResult) bool {
+			return res.Status != flow.TransactionStatusUnknown
+		},
+	)
+}
+
+func (a *SporkRouterAPI) GetTransactionResultByIndex(ctx context.Context, blockID flow.Identifier, index uint32) (*flowaccess.TransactionResult, error) {
+	return firstFound(a.router,
+		func() (*flowaccess.TransactionResult, error) {
+			return a.API.GetTransactionResultByIndex(ctx, blockID, index)
+		},
+		func(c access.AccessAPIClient) (*flowaccess.TransactionResult, error) {
+			return historicalTransactionResult(c.GetTransactionResultByIndex(ctx, &access.GetTransactionByIndexRequest{BlockId: blockID[:], Index: index}))
+		},
+		exists[*flowaccess.TransactionResult],
+	)
+}
+
+func (a *SporkRouterAPI) GetTransactionResultsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flowaccess.TransactionResult, error) {
+	return firstFound(a.router,
+		func() ([]*flowaccess.TransactionResult, error) {
+			return a.API.GetTransactionResultsByBlockID(ctx, blockID)
+		},
+		func(c access.AccessAPIClient) ([]*flowaccess.TransactionResult, error) {
+			res, err := c.GetTransactionResultsByBlockID(ctx, &access.GetTransactionsByBlockIDRequest{BlockId: blockID[:]})
+			if err != nil {
+				return nil, err
+			}
+			results := make([]*flowaccess.TransactionResult, len(res.GetTransactionResults()))
+			for i, message := range res.GetTransactionResults() {
+				results[i] = flowaccess.MessageToTransactionResult(message)
+			}
+			return results, nil
+		},
+		exists[[]*flowaccess.TransactionResult],
+	)
+}
+
+func (a *SporkRouterAPI) GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	return atHeight(a.router, height,
+		func() (*flow.Account, error) {
+			return a.API.GetAccountAtBlockHeight(ctx, address, height)
+		},
+		func(c access.AccessAPIClient) (*flow.Account, error) {
+			res, err := c.GetAccountAtBlockHeight(ctx, &access.GetAccountAtBlockHeightRequest{Address: address.Bytes(), BlockHeight: height})
+			if err != nil {
+				return nil, err
+			}
+			return convert.MessageToAccount(res.GetAccount())
+		},
+	)
+}
+
+func (a *SporkRouterAPI) ExecuteScriptAtBlockID(ctx context.Context, blockID flow.Identifier, script []byte, arguments [][]byte) ([]byte, error) {
+	return firstFound(a.router,
+		func() ([]byte, error) {
+			return a.API.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+		},
+		func(c access.AccessAPIClient) ([]byte, error) {
+			res, err := c.ExecuteScriptAtBlockID(ctx, &access.ExecuteScriptAtBlockIDRequest{BlockId: blockID[:], Script: script, Arguments: arguments})
+			return res.GetValue(), err
+		},
+		exists[[]byte],
+	)
+}
+
+func (a *SporkRouterAPI) ExecuteScriptAtBlockHeight(ctx context.Context, blockHeight uint64, script []byte, arguments [][]byte) ([]byte, error) {
+	return atHeight(a.router, blockHeight,
+		func() ([]byte, error) {
+			return a.API.ExecuteScriptAtBlockHeight(ctx, blockHeight, script, arguments)
+		},
+		func(c access.AccessAPIClient) ([]byte, error) {
+			res, err := c.ExecuteScriptAtBlockHeight(ctx, &access.ExecuteScriptAtBlockHeightRequest{BlockHeight: blockHeight, Script: script, Arguments: arguments})
+			return res.GetValue(), err
+		},
+	)
+}
+
+// GetEventsForHeightRange returns the events of a height range, split into one request per spork
+// if the range spans several sporks.
+func (a *SporkRouterAPI) GetEventsForHeightRange(ctx context.Context, eventType string, startHeight, endHeight uint64) ([]flow.BlockEvents, error) {
+	if startHeight >= a.router.rootHeight || endHeight < startHeight {
+		return a.API.GetEventsForHeightRange(ctx, eventType, startHeight, endHeight)
+	}
+
+	var results []flow.BlockEvents
+	err := a.router.forEachSpork(startHeight, endHeight, func(client access.AccessAPIClient, start uint64, end uint64) error {
+		if client == nil {
+			events, err := a.API.GetEventsForHeightRange(ctx, eventType, start, end)
+			if err != nil {
+				return err
+			}
+			results = append(results, events...)
+			return nil
+		}
+
+		res, err := client.GetEventsForHeightRange(ctx, &access.GetEventsForHeightRangeRequest{
+			Type:        eventType,
+			StartHeight: start,
+			EndHeight:   end,
+		})
+		if err != nil {
+			return err
+		}
+		results = append(results, historicalBlockEvents(res)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (a *SporkRouterAPI) GetEventsForBlockIDs(ctx context.Context, eventType string, blockIDs []flow.Identifier) ([]flow.BlockEvents, error) {
+	return firstFound(a.router,
+		func() ([]flow.BlockEvents, error) {
+			return a.API.GetEventsForBlockIDs(ctx, eventType, blockIDs)
+		},
+		func(c access.AccessAPIClient) ([]flow.BlockEvents, error) {
+			res, err := c.GetEventsForBlockIDs(ctx, &access.GetEventsForBlockIDsRequest{
+				Type:     eventType,
+				BlockIds: convert.IdentifiersToMessages(blockIDs),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return historicalBlockEvents(res), nil
+		},
+		exists[[]flow.BlockEvents],
+	)
+}
+
+func (a *SporkRouterAPI) GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	return firstFound(a.router,
+		func() (*flow.ExecutionResult, error) {
+			return a.API.GetExecutionResultForBlockID(ctx, blockID)
+		},
+		func(c access.AccessAPIClient) (*flow.ExecutionResult, error) {
+			res, err := c.GetExecutionResultForBlockID(ctx, &access.GetExecutionResultForBlockIDRequest{BlockId: blockID[:]})
+			if err != nil {
+				return nil, err
+			}
+			return convert.MessageToExecutionResult(res.GetExecutionResult())
+		},
+		exists[*flow.ExecutionResult],
+	)
+}
+
+func historicalBlockHeader(res *access.BlockHeaderResponse, err error) (blockWithStatus[*flow.Header], error) {
+	if err != nil {
+		return blockWithStatus[*flow.Header]{}, err
+	}
+	header, err := convert.MessageToBlockHeader(res.GetBlock())
+	if err != nil {
+		return blockWithStatus[*flow.Header]{}, err
+	}
+	return blockWithStatus[*flow.Header]{header, flow.BlockStatus(res.GetBlockStatus())}, nil
+}
+
+func historicalBlock(res *access.BlockResponse, err error) (blockWithStatus[*flow.Block], error) {
+	if err != nil {
+		return blockWithStatus[*flow.Block]{}, err
+	}
+	block, err := convert.MessageToBlock(res.GetBlock())
+	if err != nil {
+		return blockWithStatus[*flow.Block]{}, err
+	}
+	return blockWithStatus[*flow.Block]{block, flow.BlockStatus(res.GetBlockStatus())}, nil
+}
+
+func historicalTransactionResult(res *access.TransactionResultResponse, err error) (*flowaccess.TransactionResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	result := flowaccess.MessageToTransactionResult(res)
+	// transactions pending on a historical spork will never be executed
+	if result.Status == flow.TransactionStatusPending {
+		result.Status = flow.TransactionStatusExpired
+	}
+	return result, nil
+}
+
+func historicalBlockEvents(res *access.EventsResponse) []flow.BlockEvents {
+	results := make([]flow.BlockEvents, len(res.GetResults()))
+	for i, result := range res.GetResults() {
+		results[i] = flow.BlockEvents{
+			BlockID:        convert.MessageToIdentifier(result.GetBlockId()),
+			BlockHeight:    result.GetBlockHeight(),
+			BlockTimestamp: result.GetBlockTimestamp().AsTime(),
+			Events:         convert.MessagesToEvents(result.GetEvents()),
+		}
+	}
+	return results
+}
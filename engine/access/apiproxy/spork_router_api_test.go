@@ -0,0 +1,143 @@
+package apiproxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	flowaccess "github.com/onflow/flow-go/access"
+	accessapimock "github.com/onflow/flow-go/access/mock"
+	accessmock "github.com/onflow/flow-go/engine/access/mock"
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestSporkRouterAPI tests that requests of the REST API for data of previous sporks are routed to
+// the access nodes of these sporks.
+func TestSporkRouterAPI(t *testing.T) {
+	ctx := context.Background()
+	notFound := status.Error(codes.NotFound, "not found")
+	chain := flow.Testnet.Chain()
+
+	// the historical sporks start at heights 100 and 200, and the current spork at height 300
+	first := accessmock.NewAccessAPIClient(t)
+	second := accessmock.NewAccessAPIClient(t)
+	router, err := NewSporkRouter(zerolog.Nop(), accessmock.NewAccessAPIServer(t), 300, []HistoricalSpork{
+		{RootHeight: 100, Client: first},
+		{RootHeight: 200, Client: second},
+	}, 250)
+	require.NoError(t, err)
+
+	local := new(accessapimock.API)
+	api := NewSporkRouterAPI(router, local, chain)
+
+	t.Run("heights are routed to the spork containing them", func(t *testing.T) {
+		header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(150))
+		message, err := convert.BlockHeaderToMessage(header, nil)
+		require.NoError(t, err)
+
+		first.On("GetBlockHeaderByHeight", ctx, &access.GetBlockHeaderByHeightRequest{Height: 150}).
+			Return(&access.BlockHeaderResponse{Block: message, BlockStatus: entities.BlockStatus_BLOCK_SEALED}, nil).
+			Once()
+
+		res, status, err := api.GetBlockHeaderByHeight(ctx, 150)
+		require.NoError(t, err)
+		assert.Equal(t, header.ID(), res.ID())
+		assert.Equal(t, flow.BlockStatusSealed, status)
+
+		local.On("GetBlockHeaderByHeight", ctx, uint64(350)).Return(header, flow.BlockStatusFinalized, nil).Once()
+		_, status, err = api.GetBlockHeaderByHeight(ctx, 350)
+		require.NoError(t, err)
+		assert.Equal(t, flow.BlockStatusFinalized, status)
+	})
+
+	t.Run("event height ranges are split by spork", func(t *testing.T) {
+		eventType := "flow.AccountCreated"
+		events := func(height uint64) []flow.BlockEvents {
+			return []flow.BlockEvents{unittest.BlockEventsFixture(unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height)), 1)}
+		}
+		response := func(blockEvents []flow.BlockEvents) *access.EventsResponse {
+			results := make([]*access.EventsResponse_Result, len(blockEvents))
+			for i, e := range blockEvents {
+				results[i] = &access.EventsResponse_Result{
+					BlockId:     e.BlockID[:],
+					BlockHeight: e.BlockHeight,
+					Events:      convert.EventsToMessages(e.Events),
+				}
+			}
+			return &access.EventsResponse{Results: results}
+		}
+		rangeReq := func(start, end uint64) *access.GetEventsForHeightRangeRequest {
+			return &access.GetEventsForHeightRangeRequest{Type: eventType, StartHeight: start, EndHeight: end}
+		}
+
+		firstEvents, secondEvents, localEvents := events(150), events(200), events(300)
+		first.On("GetEventsForHeightRange", ctx, rangeReq(150, 199)).Return(response(firstEvents), nil).Once()
+		second.On("GetEventsForHeightRange", ctx, rangeReq(200, 299)).Return(response(secondEvents), nil).Once()
+		local.On("GetEventsForHeightRange", ctx, eventType, uint64(300), uint64(310)).Return(localEvents, nil).Once()
+
+		res, err := api.GetEventsForHeightRange(ctx, eventType, 150, 310)
+		require.NoError(t, err)
+		require.Len(t, res, 3)
+		assert.Equal(t, firstEvents[0].BlockID, res[0].BlockID)
+		assert.Equal(t, firstEvents[0].Events, res[0].Events)
+		assert.Equal(t, secondEvents[0].BlockID, res[1].BlockID)
+		assert.Equal(t, localEvents[0], res[2])
+
+		_, err = api.GetEventsForHeightRange(ctx, eventType, 100, 400)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unknown transactions are looked up in historical sporks", func(t *testing.T) {
+		txID := unittest.IdentifierFixture()
+		req := &access.GetTransactionRequest{Id: txID[:]}
+
+		local.On("GetTransactionResult", ctx, txID).
+			Return(&flowaccess.TransactionResult{Status: flow.TransactionStatusUnknown}, nil).
+			Once()
+		second.On("GetTransactionResult", ctx, req).
+			Return(&access.TransactionResultResponse{Status: entities.TransactionStatus_PENDING, TransactionId: txID[:]}, nil).
+			Once()
+
+		res, err := api.GetTransactionResult(ctx, txID)
+		require.NoError(t, err)
+		// pending transactions of historical sporks will never be executed
+		assert.Equal(t, flow.TransactionStatusExpired, res.Status)
+		assert.Equal(t, txID, res.TransactionID)
+	})
+
+	t.Run("IDs are looked up in historical sporks if not found locally", func(t *testing.T) {
+		collectionID := unittest.IdentifierFixture()
+		txIDs := unittest.IdentifierListFixture(2)
+		req := &access.GetCollectionByIDRequest{Id: collectionID[:]}
+
+		local.On("GetCollectionByID", ctx, collectionID).Return(nil, notFound).Once()
+		second.On("GetCollectionByID", ctx, req).Return(nil, notFound).Once()
+		first.On("GetCollectionByID", ctx, req).
+			Return(&access.CollectionResponse{Collection: &entities.Collection{TransactionIds: convert.IdentifiersToMessages(txIDs)}}, nil).
+			Once()
+
+		res, err := api.GetCollectionByID(ctx, collectionID)
+		require.NoError(t, err)
+		assert.Equal(t, []flow.Identifier(txIDs), res.Transactions)
+	})
+
+	t.Run("other requests are served locally", func(t *testing.T) {
+		header := unittest.BlockHeaderFixture()
+		local.On("GetLatestBlockHeader", ctx, true).Return(header, flow.BlockStatusSealed, nil).Once()
+
+		res, _, err := api.GetLatestBlockHeader(ctx, true)
+		require.NoError(t, err)
+		assert.Equal(t, header, res)
+	})
+
+	local.AssertExpectations(t)
+}
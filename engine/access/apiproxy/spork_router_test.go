@@ -0,0 +1,156 @@
+package apiproxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	accessmock "github.com/onflow/flow-go/engine/access/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestSporkRouter tests that requests for data of previous sporks are routed to the access nodes
+// of these sporks.
+func TestSporkRouter(t *testing.T) {
+	ctx := context.Background()
+	notFound := status.Error(codes.NotFound, "not found")
+
+	// the historical sporks start at heights 100 and 200, and the current spork at height 300
+	local := accessmock.NewAccessAPIServer(t)
+	first := accessmock.NewAccessAPIClient(t)
+	second := accessmock.NewAccessAPIClient(t)
+
+	router, err := NewSporkRouter(zerolog.Nop(), local, 300, []HistoricalSpork{
+		{RootHeight: 200, Client: second},
+		{RootHeight: 100, Client: first},
+	}, 250)
+	require.NoError(t, err)
+
+	header := func(height uint64) *access.BlockHeaderResponse {
+		return &access.BlockHeaderResponse{Block: &entities.BlockHeader{Height: height}}
+	}
+
+	t.Run("heights are routed to the spork containing them", func(t *testing.T) {
+		for _, height := range []uint64{100, 199, 200, 299, 300, 400, 99} {
+			req := &access.GetBlockHeaderByHeightRequest{Height: height}
+			switch {
+			case height >= 300 || height < 100:
+				local.On("GetBlockHeaderByHeight", ctx, req).Return(header(height), nil).Once()
+			case height >= 200:
+				second.On("GetBlockHeaderByHeight", ctx, req).Return(header(height), nil).Once()
+			default:
+				first.On("GetBlockHeaderByHeight", ctx, req).Return(header(height), nil).Once()
+			}
+
+			res, err := router.GetBlockHeaderByHeight(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, height, res.Block.Height)
+		}
+	})
+
+	t.Run("event height ranges are split by spork", func(t *testing.T) {
+		eventType := "flow.AccountCreated"
+		result := func(height uint64) *access.EventsResponse {
+			return &access.EventsResponse{Results: []*access.EventsResponse_Result{{BlockHeight: height}}}
+		}
+		rangeReq := func(start, end uint64) *access.GetEventsForHeightRangeRequest {
+			return &access.GetEventsForHeightRangeRequest{Type: eventType, StartHeight: start, EndHeight: end}
+		}
+
+		first.On("GetEventsForHeightRange", ctx, rangeReq(150, 199)).Return(result(150), nil).Once()
+		second.On("GetEventsForHeightRange", ctx, rangeReq(200, 299)).Return(result(200), nil).Once()
+		local.On("GetEventsForHeightRange", ctx, rangeReq(300, 310)).Return(result(300), nil).Once()
+
+		res, err := router.GetEventsForHeightRange(ctx, rangeReq(150, 310))
+		require.NoError(t, err)
+		require.Len(t, res.Results, 3)
+		assert.Equal(t, uint64(150), res.Results[0].BlockHeight)
+		assert.Equal(t, uint64(200), res.Results[1].BlockHeight)
+		assert.Equal(t, uint64(300), res.Results[2].BlockHeight)
+
+		// ranges within a single spork are not split
+		second.On("GetEventsForHeightRange", ctx, rangeReq(210, 220)).Return(result(210), nil).Once()
+		_, err = router.GetEventsForHeightRange(ctx, rangeReq(210, 220))
+		require.NoError(t, err)
+
+		_, err = router.GetEventsForHeightRange(ctx, rangeReq(100, 400))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("IDs are looked up in historical sporks if not found locally", func(t *testing.T) {
+		blockID := unittest.IdentifierFixture()
+		req := &access.GetBlockHeaderByIDRequest{Id: blockID[:]}
+
+		local.On("GetBlockHeaderByID", ctx, req).Return(nil, notFound).Once()
+		second.On("GetBlockHeaderByID", ctx, req).Return(nil, notFound).Once()
+		first.On("GetBlockHeaderByID", ctx, req).Return(header(150), nil).Once()
+
+		res, err := router.GetBlockHeaderByID(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(150), res.Block.Height)
+
+		// data found locally is not looked up in historical sporks
+		local.On("GetBlockHeaderByID", ctx, req).Return(header(350), nil).Once()
+		res, err = router.GetBlockHeaderByID(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(350), res.Block.Height)
+
+		// data not found anywhere
+		local.On("GetBlockHeaderByID", ctx, req).Return(nil, notFound).Once()
+		second.On("GetBlockHeaderByID", ctx, req).Return(nil, notFound).Once()
+		first.On("GetBlockHeaderByID", ctx, req).Return(nil, notFound).Once()
+		_, err = router.GetBlockHeaderByID(ctx, req)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		// failing historical sporks are reported, since they may have the data
+		local.On("GetBlockHeaderByID", ctx, req).Return(nil, notFound).Once()
+		second.On("GetBlockHeaderByID", ctx, req).Return(nil, status.Error(codes.Unavailable, "unavailable")).Once()
+		first.On("GetBlockHeaderByID", ctx, req).Return(nil, notFound).Once()
+		_, err = router.GetBlockHeaderByID(ctx, req)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("unknown transactions are looked up in historical sporks", func(t *testing.T) {
+		txID := unittest.IdentifierFixture()
+		req := &access.GetTransactionRequest{Id: txID[:]}
+
+		local.On("GetTransactionResult", ctx, req).
+			Return(&access.TransactionResultResponse{Status: entities.TransactionStatus_UNKNOWN}, nil).
+			Once()
+		second.On("GetTransactionResult", ctx, req).
+			Return(&access.TransactionResultResponse{Status: entities.TransactionStatus_PENDING}, nil).
+			Once()
+
+		res, err := router.GetTransactionResult(ctx, req)
+		require.NoError(t, err)
+		// pending transactions of historical sporks will never be executed
+		assert.Equal(t, entities.TransactionStatus_EXPIRED, res.Status)
+	})
+
+	t.Run("other requests are served locally", func(t *testing.T) {
+		req := &access.GetLatestBlockHeaderRequest{IsSealed: true}
+		local.On("GetLatestBlockHeader", ctx, req).Return(header(400), nil).Once()
+
+		res, err := router.GetLatestBlockHeader(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(400), res.Block.Height)
+	})
+
+	t.Run("historical sporks must be before the current spork", func(t *testing.T) {
+		_, err := NewSporkRouter(zerolog.Nop(), local, 300, []HistoricalSpork{{RootHeight: 300, Client: first}}, 250)
+		assert.Error(t, err)
+
+		_, err = NewSporkRouter(zerolog.Nop(), local, 300, []HistoricalSpork{
+			{RootHeight: 100, Client: first},
+			{RootHeight: 100, Client: second},
+		}, 250)
+		assert.Error(t, err)
+	})
+}
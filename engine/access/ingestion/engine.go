@@ -74,6 +74,7 @@ type Engine struct {
 	headers           storage.Headers
 	collections       storage.Collections
 	transactions      storage.Transactions
+	accountTxs        storage.AccountTransactions // optional, nil if account transactions are not indexed
 	executionReceipts storage.ExecutionReceipts
 	maxReceiptHeight  uint64
 	executionResults  storage.ExecutionResults
@@ -87,7 +88,8 @@ type Engine struct {
 	rpcEngine *rpc.Engine
 }
 
-// New creates a new access ingestion engine
+// New creates a new access ingestion engine. The account transaction index is optional, and may
+// be nil if the transactions of collections should not be indexed by account.
 func New(
 	log zerolog.Logger,
 	net network.Network,
@@ -98,6 +100,7 @@ func New(
 	headers storage.Headers,
 	collections storage.Collections,
 	transactions storage.Transactions,
+	accountTxs storage.AccountTransactions,
 	executionResults storage.ExecutionResults,
 	executionReceipts storage.ExecutionReceipts,
 	transactionMetrics module.TransactionMetrics,
@@ -149,6 +152,7 @@ func New(
 		headers:                    headers,
 		collections:                collections,
 		transactions:               transactions,
+		accountTxs:                 accountTxs,
 		executionResults:           executionResults,
 		executionReceipts:          executionReceipts,
 		maxReceiptHeight:           0,
@@ -382,9 +386,6 @@ func (e *Engine) processFinalizedBlock(blockID flow.Identifier) error {
 		return fmt.Errorf("failed to lookup block: %w", err)
 	}
 
-	// Notify rpc handler of new finalized block height
-	e.rpcEngine.SubmitLocal(block)
-
 	// FIX: we can't index guarantees here, as we might have more than one block
 	// with the same collection as long as it is not finalized
 
@@ -404,6 +405,10 @@ func (e *Engine) processFinalizedBlock(blockID flow.Identifier) error {
 		}
 	}
 
+	// Notify rpc handler of new finalized block height. This is done after indexing the block, so
+	// transaction status subscribers observe the block's transactions as finalized.
+	e.rpcEngine.SubmitLocal(block)
+
 	// skip requesting collections, if this block is below the last full block height
 	// this means that either we have already received these collections, or the block
 	// may contain unverifiable guarantees (in case this node has just joined the network)
@@ -468,6 +473,9 @@ func (e *Engine) handleExecutionReceipt(originID flow.Identifier, r *flow.Execut
 	}
 
 	e.trackExecutionReceiptMetrics(r)
+
+	// notify rpc handler that the results of the receipt's transactions may now be available
+	e.rpcEngine.SubmitLocal(r)
 	return nil
 }
 
@@ -542,9 +550,16 @@ func (e *Engine) handleCollection(originID flow.Identifier, entity flow.Entity)
 	// FIX: we can't index guarantees here, as we might have more than one block
 	// with the same collection as long as it is not finalized
 
+	// index the transactions by account before the collection is stored, so that the index is
+	// completed when a collection whose indexing failed is received again. Indexing is idempotent.
+	err := e.indexAccountTransactions(collection)
+	if err != nil {
+		return err
+	}
+
 	// store the light collection (collection minus the transaction body - those are stored separately)
 	// and add transaction ids as index
-	err := e.collections.StoreLightAndIndexByTransaction(&light)
+	err = e.collections.StoreLightAndIndexByTransaction(&light)
 	if err != nil {
 		// ignore collection if already seen
 		if errors.Is(err, storage.ErrAlreadyExists) {
@@ -564,6 +579,31 @@ func (e *Engine) handleCollection(originID flow.Identifier, entity flow.Entity)
 		}
 	}
 
+	// notify rpc handler that the collection's transactions may now be finalized
+	e.rpcEngine.SubmitLocal(&light)
+
+	return nil
+}
+
+// indexAccountTransactions indexes the transactions of the collection by the accounts that
+// participated in them, if account transactions are indexed. Collections are only requested for
+// finalized blocks, so the block containing the collection is already indexed.
+// No errors are expected during normal operation.
+func (e *Engine) indexAccountTransactions(collection *flow.Collection) error {
+	if e.accountTxs == nil {
+		return nil
+	}
+
+	block, err := e.blocks.ByCollectionID(collection.ID())
+	if err != nil {
+		return fmt.Errorf("could not find block for collection (%x): %w", collection.ID(), err)
+	}
+
+	err = e.accountTxs.Store(block.Header.Height, collection.Transactions)
+	if err != nil {
+		return fmt.Errorf("could not index account transactions of collection (%x): %w", collection.ID(), err)
+	}
+
 	return nil
 }
 
@@ -50,6 +50,7 @@ type Suite struct {
 	headers      *storage.Headers
 	collections  *storage.Collections
 	transactions *storage.Transactions
+	accountTxs   *storage.AccountTransactions
 	receipts     *storage.ExecutionReceipts
 	results      *storage.ExecutionResults
 	seals        *storage.Seals
@@ -95,6 +96,7 @@ func (suite *Suite) SetupTest() {
 	suite.headers = new(storage.Headers)
 	suite.collections = new(storage.Collections)
 	suite.transactions = new(storage.Transactions)
+	suite.accountTxs = new(storage.AccountTransactions)
 	suite.receipts = new(storage.ExecutionReceipts)
 	suite.results = new(storage.ExecutionResults)
 	collectionsToMarkFinalized, err := stdmap.NewTimes(100)
@@ -112,7 +114,7 @@ func (suite *Suite) SetupTest() {
 	require.NoError(suite.T(), err)
 
 	eng, err := New(log, net, suite.proto.state, suite.me, suite.request, suite.blocks, suite.headers, suite.collections,
-		suite.transactions, suite.results, suite.receipts, metrics.NewNoopCollector(), collectionsToMarkFinalized, collectionsToMarkExecuted,
+		suite.transactions, suite.accountTxs, suite.results, suite.receipts, metrics.NewNoopCollector(), collectionsToMarkFinalized, collectionsToMarkExecuted,
 		blocksToMarkExecuted, rpcEng)
 	require.NoError(suite.T(), err)
 
@@ -229,12 +231,18 @@ func (suite *Suite) TestOnCollection() {
 		},
 	)
 
+	// the transactions are indexed by account at the height of the block containing the collection
+	block := unittest.BlockFixture()
+	suite.blocks.On("ByCollectionID", collection.ID()).Return(&block, nil).Once()
+	suite.accountTxs.On("Store", block.Header.Height, collection.Transactions).Return(nil).Once()
+
 	// process the block through the collection callback
 	suite.eng.OnCollection(originID, &collection)
 
 	// check that the collection was stored and indexed, and we stored all transactions
 	suite.collections.AssertExpectations(suite.T())
 	suite.transactions.AssertNumberOfCalls(suite.T(), "Store", len(collection.Transactions))
+	suite.accountTxs.AssertExpectations(suite.T())
 }
 
 // TestExecutionReceiptsAreIndexed checks that execution receipts are properly indexed
@@ -295,6 +303,12 @@ func (suite *Suite) TestOnCollectionDuplicate() {
 	// we should store the light collection and index its transactions
 	suite.collections.On("StoreLightAndIndexByTransaction", &light).Return(storerr.ErrAlreadyExists).Once()
 
+	// the transactions are indexed by account again, in case indexing failed when the collection
+	// was first received
+	block := unittest.BlockFixture()
+	suite.blocks.On("ByCollectionID", collection.ID()).Return(&block, nil).Once()
+	suite.accountTxs.On("Store", block.Header.Height, collection.Transactions).Return(nil).Once()
+
 	// for each transaction in the collection, we should store it
 	needed := make(map[flow.Identifier]struct{})
 	for _, txID := range light.Transactions {
@@ -314,6 +328,7 @@ func (suite *Suite) TestOnCollectionDuplicate() {
 	// check that the collection was stored and indexed, and we stored all transactions
 	suite.collections.AssertExpectations(suite.T())
 	suite.transactions.AssertNotCalled(suite.T(), "Store", "should not store any transactions")
+	suite.accountTxs.AssertExpectations(suite.T())
 }
 
 // TestRequestMissingCollections tests that the all missing collections are requested on the call to requestMissingCollections
@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"context"
+
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine/access/rest/models"
 	"github.com/onflow/flow-go/engine/access/rest/request"
@@ -13,16 +15,12 @@ func GetAccount(r *request.Request, backend access.API, link models.LinkGenerato
 		return nil, NewBadRequestError(err)
 	}
 
-	// in case we receive special height values 'final' and 'sealed', fetch that height and overwrite request with it
-	if req.Height == request.FinalHeight || req.Height == request.SealedHeight {
-		header, _, err := backend.GetLatestBlockHeader(r.Context(), req.Height == request.SealedHeight)
-		if err != nil {
-			return nil, err
-		}
-		req.Height = header.Height
+	height, err := resolveHeight(r.Context(), backend, req.Height)
+	if err != nil {
+		return nil, err
 	}
 
-	account, err := backend.GetAccountAtBlockHeight(r.Context(), req.Address, req.Height)
+	account, err := backend.GetAccountAtBlockHeight(r.Context(), req.Address, height)
 	if err != nil {
 		return nil, err
 	}
@@ -31,3 +29,101 @@ func GetAccount(r *request.Request, backend access.API, link models.LinkGenerato
 	err = response.Build(account, link, r.ExpandFields)
 	return response, err
 }
+
+// GetAccountKeys handler retrieves the public keys of an account by address and returns the response
+func GetAccountKeys(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetAccountRequest()
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	height, err := resolveHeight(r.Context(), backend, req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := backend.GetAccountKeysAtBlockHeight(r.Context(), req.Address, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.AccountPublicKeys
+	response.Build(keys)
+	return response, nil
+}
+
+// GetAccountKeyByIndex handler retrieves a public key of an account by address and key index and returns the response
+func GetAccountKeyByIndex(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetAccountKeyRequest()
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	height, err := resolveHeight(r.Context(), backend, req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := backend.GetAccountKeyAtBlockHeight(r.Context(), req.Address, req.Index, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.AccountPublicKey
+	response.Build(*key)
+	return response, nil
+}
+
+// GetAccountBalance handler retrieves the balance of an account by address and returns the response
+func GetAccountBalance(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetAccountRequest()
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	height, err := resolveHeight(r.Context(), backend, req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := backend.GetAccountBalanceAtBlockHeight(r.Context(), req.Address, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.AccountBalance
+	response.Build(balance)
+	return response, nil
+}
+
+// GetAccountTransactions handler retrieves a page of the transactions an account participated in and returns the response
+func GetAccountTransactions(r *request.Request, backend access.API, link models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetAccountTransactionsRequest()
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	page, err := backend.GetTransactionsByAddress(r.Context(), req.Address, req.Limit, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.AccountTransactions
+	err = response.Build(page, link)
+	return response, err
+}
+
+// resolveHeight returns the height of the latest sealed or finalized block in case we receive
+// the special height values 'sealed' and 'final', and the requested height otherwise.
+func resolveHeight(ctx context.Context, backend access.API, height uint64) (uint64, error) {
+	if height != request.FinalHeight && height != request.SealedHeight {
+		return height, nil
+	}
+
+	header, _, err := backend.GetLatestBlockHeader(ctx, height == request.SealedHeight)
+	if err != nil {
+		return 0, err
+	}
+
+	return header.Height, nil
+}
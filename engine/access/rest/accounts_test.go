@@ -10,9 +10,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	mocktestify "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/access/mock"
 	"github.com/onflow/flow-go/engine/access/rest/middleware"
+	"github.com/onflow/flow-go/engine/access/rest/util"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -127,6 +131,273 @@ func TestGetAccount(t *testing.T) {
 	})
 }
 
+func TestGetAccountKeys(t *testing.T) {
+	backend := &mock.API{}
+
+	t.Run("get by address at latest sealed block", func(t *testing.T) {
+		account := accountFixture(t)
+		var height uint64 = 100
+		block := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height))
+
+		req := getAccountSubResourceRequest(t, account.Address.String(), "keys", sealedHeightQueryParam)
+
+		backend.Mock.
+			On("GetLatestBlockHeader", mocktestify.Anything, true).
+			Return(block, flow.BlockStatusSealed, nil)
+
+		backend.Mock.
+			On("GetAccountKeysAtBlockHeight", mocktestify.Anything, account.Address, height).
+			Return(account.Keys, nil)
+
+		expected := fmt.Sprintf(`[%s]`, expectedAccountKeyResponse(account.Keys[0]))
+
+		assertOKResponse(t, req, expected, backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get by address at height", func(t *testing.T) {
+		var height uint64 = 1337
+		account := accountFixture(t)
+		req := getAccountSubResourceRequest(t, account.Address.String(), "keys", fmt.Sprintf("%d", height))
+
+		backend.Mock.
+			On("GetAccountKeysAtBlockHeight", mocktestify.Anything, account.Address, height).
+			Return(account.Keys, nil)
+
+		expected := fmt.Sprintf(`[%s]`, expectedAccountKeyResponse(account.Keys[0]))
+
+		assertOKResponse(t, req, expected, backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get invalid", func(t *testing.T) {
+		tests := []struct {
+			url string
+			out string
+		}{
+			{accountSubResourceURL(t, "123", "keys", ""), `{"code":400, "message":"invalid address"}`},
+			{accountSubResourceURL(t, unittest.AddressFixture().String(), "keys", "foo"), `{"code":400, "message":"invalid height format"}`},
+		}
+
+		for i, test := range tests {
+			req, _ := http.NewRequest("GET", test.url, nil)
+			rr, err := executeRequest(req, backend)
+			assert.NoError(t, err)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.JSONEq(t, test.out, rr.Body.String(), fmt.Sprintf("test #%d failed: %v", i, test))
+		}
+	})
+}
+
+func TestGetAccountKeyByIndex(t *testing.T) {
+	backend := &mock.API{}
+
+	t.Run("get by address and index at latest finalized block", func(t *testing.T) {
+		account := accountFixture(t)
+		var height uint64 = 100
+		block := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height))
+
+		req := getAccountSubResourceRequest(t, account.Address.String(), "keys/0", finalHeightQueryParam)
+
+		backend.Mock.
+			On("GetLatestBlockHeader", mocktestify.Anything, false).
+			Return(block, flow.BlockStatusFinalized, nil)
+
+		backend.Mock.
+			On("GetAccountKeyAtBlockHeight", mocktestify.Anything, account.Address, uint64(0), height).
+			Return(&account.Keys[0], nil)
+
+		assertOKResponse(t, req, expectedAccountKeyResponse(account.Keys[0]), backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get unknown key", func(t *testing.T) {
+		var height uint64 = 1337
+		account := accountFixture(t)
+		req := getAccountSubResourceRequest(t, account.Address.String(), "keys/5", fmt.Sprintf("%d", height))
+
+		backend.Mock.
+			On("GetAccountKeyAtBlockHeight", mocktestify.Anything, account.Address, uint64(5), height).
+			Return(nil, status.Error(codes.NotFound, "key not found"))
+
+		expected := `{"code":404, "message":"Flow resource not found: key not found"}`
+
+		assertResponse(t, req, http.StatusNotFound, expected, backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get invalid", func(t *testing.T) {
+		address := unittest.AddressFixture().String()
+		tests := []struct {
+			url string
+			out string
+		}{
+			{accountSubResourceURL(t, "123", "keys/0", ""), `{"code":400, "message":"invalid address"}`},
+			{accountSubResourceURL(t, address, "keys/foo", ""), `{"code":400, "message":"invalid key index"}`},
+			{accountSubResourceURL(t, address, "keys/0", "foo"), `{"code":400, "message":"invalid height format"}`},
+		}
+
+		for i, test := range tests {
+			req, _ := http.NewRequest("GET", test.url, nil)
+			rr, err := executeRequest(req, backend)
+			assert.NoError(t, err)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.JSONEq(t, test.out, rr.Body.String(), fmt.Sprintf("test #%d failed: %v", i, test))
+		}
+	})
+}
+
+func TestGetAccountBalance(t *testing.T) {
+	backend := &mock.API{}
+
+	t.Run("get by address at latest sealed block", func(t *testing.T) {
+		account := accountFixture(t)
+		var height uint64 = 100
+		block := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height))
+
+		req := getAccountSubResourceRequest(t, account.Address.String(), "balance", "")
+
+		backend.Mock.
+			On("GetLatestBlockHeader", mocktestify.Anything, true).
+			Return(block, flow.BlockStatusSealed, nil)
+
+		backend.Mock.
+			On("GetAccountBalanceAtBlockHeight", mocktestify.Anything, account.Address, height).
+			Return(account.Balance, nil)
+
+		assertOKResponse(t, req, `{"balance":"100"}`, backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get by address at height", func(t *testing.T) {
+		var height uint64 = 1337
+		account := accountFixture(t)
+		req := getAccountSubResourceRequest(t, account.Address.String(), "balance", fmt.Sprintf("%d", height))
+
+		backend.Mock.
+			On("GetAccountBalanceAtBlockHeight", mocktestify.Anything, account.Address, height).
+			Return(account.Balance, nil)
+
+		assertOKResponse(t, req, `{"balance":"100"}`, backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+}
+
+func TestGetAccountTransactions(t *testing.T) {
+	backend := &mock.API{}
+
+	address := unittest.RandomAddressFixture()
+	accountTx := flow.AccountTransaction{
+		Address:       address,
+		BlockHeight:   10,
+		TransactionID: unittest.IdentifierFixture(),
+		Roles:         flow.TransactionRoleProposer | flow.TransactionRolePayer,
+	}
+	cursor := flow.AccountTransactionCursor{BlockHeight: 9, TransactionID: unittest.IdentifierFixture()}
+
+	transactionsURL := func(limit string, cursor string) string {
+		u, err := url.ParseRequestURI(fmt.Sprintf("/v1/accounts/%s/transactions", address))
+		require.NoError(t, err)
+		q := u.Query()
+		if limit != "" {
+			q.Add("limit", limit)
+		}
+		if cursor != "" {
+			q.Add("cursor", cursor)
+		}
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	t.Run("first page", func(t *testing.T) {
+		req, err := http.NewRequest("GET", transactionsURL("1", ""), nil)
+		require.NoError(t, err)
+
+		backend.Mock.
+			On("GetTransactionsByAddress", mocktestify.Anything, address, uint32(1), (*flow.AccountTransactionCursor)(nil)).
+			Return(&access.AccountTransactionsPage{
+				Transactions: []flow.AccountTransaction{accountTx},
+				NextCursor:   &cursor,
+			}, nil).Once()
+
+		expected := fmt.Sprintf(`{
+			"transactions": [{
+				"transaction_id": "%s",
+				"block_height": "10",
+				"roles": ["proposer", "payer"],
+				"_links": {"_self": "/v1/transactions/%s"}
+			}],
+			"next_cursor": "%s"
+		}`, accountTx.TransactionID, accountTx.TransactionID, util.FromAccountTransactionCursor(cursor))
+
+		assertOKResponse(t, req, expected, backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("next page", func(t *testing.T) {
+		req, err := http.NewRequest("GET", transactionsURL("", util.FromAccountTransactionCursor(cursor)), nil)
+		require.NoError(t, err)
+
+		backend.Mock.
+			On("GetTransactionsByAddress", mocktestify.Anything, address, uint32(0), &cursor).
+			Return(&access.AccountTransactionsPage{Transactions: []flow.AccountTransaction{}}, nil).Once()
+
+		assertOKResponse(t, req, `{"transactions": []}`, backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		req, err := http.NewRequest("GET", transactionsURL("", "foo"), nil)
+		require.NoError(t, err)
+
+		assertResponse(t, req, http.StatusBadRequest, `{"code":400,"message":"invalid cursor"}`, backend)
+	})
+
+	t.Run("not indexed", func(t *testing.T) {
+		req, err := http.NewRequest("GET", transactionsURL("5", ""), nil)
+		require.NoError(t, err)
+
+		backend.Mock.
+			On("GetTransactionsByAddress", mocktestify.Anything, address, uint32(5), (*flow.AccountTransactionCursor)(nil)).
+			Return(nil, status.Error(codes.Unimplemented, "account transactions are not indexed by this node")).Once()
+
+		assertResponse(t, req, http.StatusNotImplemented, `{"code":501,"message":"Not supported by this node: account transactions are not indexed by this node"}`, backend)
+	})
+}
+
+func expectedAccountKeyResponse(key flow.AccountPublicKey) string {
+	return fmt.Sprintf(`{
+			  "index":"%d",
+			  "public_key":"%s",
+			  "signing_algorithm":"ECDSA_P256",
+			  "hashing_algorithm":"SHA3_256",
+			  "sequence_number":"0",
+			  "weight":"1000",
+			  "revoked":false
+			}`, key.Index, key.PublicKey.String())
+}
+
+func accountSubResourceURL(t *testing.T, address string, resource string, height string) string {
+	u, err := url.ParseRequestURI(fmt.Sprintf("/v1/accounts/%s/%s", address, resource))
+	require.NoError(t, err)
+	q := u.Query()
+
+	if height != "" {
+		q.Add("block_height", height)
+	}
+
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func getAccountSubResourceRequest(t *testing.T, address string, resource string, height string) *http.Request {
+	req, err := http.NewRequest("GET", accountSubResourceURL(t, address, resource, height), nil)
+	require.NoError(t, err)
+	return req
+}
+
 func expectedExpandedResponse(account *flow.Account) string {
 	return fmt.Sprintf(`{
 			  "address":"%s",
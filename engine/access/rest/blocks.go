@@ -76,17 +76,25 @@ func GetBlocksByHeight(r *request.Request, backend access.API, link models.LinkG
 		}
 	}
 
-	blocks := make([]*models.Block, 0)
-	// start and end height inclusive
-	for i := req.StartHeight; i <= req.EndHeight; i++ {
-		block, err := getBlock(forHeight(i), r, backend, link)
-		if err != nil {
-			return nil, err
-		}
-		blocks = append(blocks, block)
-	}
-
-	return blocks, nil
+	// the range is returned in pages of at most the requested limit of heights, if any
+	pageEndHeight := req.Page.End(req.StartHeight, req.EndHeight)
+
+	return heightRangeResponse(r, req.Page, req.StartHeight, req.EndHeight, "blocks", link.BlocksLink,
+		func(send func(element interface{}) error) error {
+			// start and end height inclusive
+			for i := req.StartHeight; i <= pageEndHeight; i++ {
+				block, err := getBlock(forHeight(i), r, backend, link)
+				if err != nil {
+					return err
+				}
+				err = send(block)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
 }
 
 // GetBlockPayloadByID gets block payload by ID
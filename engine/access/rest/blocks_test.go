@@ -1,8 +1,10 @@
 package rest
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
@@ -20,6 +22,7 @@ import (
 
 	"github.com/onflow/flow-go/access/mock"
 	"github.com/onflow/flow-go/engine/access/rest/middleware"
+	"github.com/onflow/flow-go/engine/access/rest/models"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -147,6 +150,112 @@ func TestGetBlocks(t *testing.T) {
 	}
 }
 
+// TestGetBlocksPagination tests that height ranges of blocks are returned in pages of at most the
+// requested limit of blocks, each linking to the next page.
+func TestGetBlocksPagination(t *testing.T) {
+	backend := &mock.API{}
+
+	blkCnt := 10
+	_, heights, blocks, executionResults := generateMocks(backend, blkCnt)
+
+	req := requestURL(t, nil, heights[0], heights[len(heights)-1], false)
+	q := req.URL.Query()
+	q.Add("limit", "4")
+	req.URL.RawQuery = q.Encode()
+
+	for _, page := range [][2]int{{0, 4}, {4, 8}, {8, 10}} {
+		rr, err := executeRequest(req, backend)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		expected := expectedBlockResponsesExpanded(blocks[page[0]:page[1]], executionResults[page[0]:page[1]], false, flow.BlockStatusSealed)
+		body := parsePage(t, rr, "blocks")
+		require.JSONEq(t, expected, string(body.Elements))
+
+		link := body.Next
+		if page[1] == blkCnt {
+			assert.Empty(t, body.NextCursor)
+			assert.Empty(t, link)
+			break
+		}
+		req, err = http.NewRequest("GET", link, nil)
+		require.NoError(t, err)
+	}
+
+	t.Run("invalid limit", func(t *testing.T) {
+		req := requestURL(t, nil, heights[0], heights[len(heights)-1], false)
+		q := req.URL.Query()
+		q.Add("limit", "51")
+		req.URL.RawQuery = q.Encode()
+
+		assertResponse(t, req, http.StatusBadRequest, `{"code":400, "message":"limit must be between 1 and 50"}`, backend)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/blocks?cursor=foo", nil)
+		require.NoError(t, err)
+
+		assertResponse(t, req, http.StatusBadRequest, `{"code":400, "message":"invalid cursor"}`, backend)
+	})
+
+	t.Run("range without limit exceeding maximum", func(t *testing.T) {
+		req := requestURL(t, nil, "0", "100", false)
+		assertResponse(t, req, http.StatusBadRequest, `{"code":400, "message":"height range 100 exceeds maximum allowed of 50"}`, backend)
+	})
+
+	// blocks above the generated heights are not found, failing the response after the first blocks were sent
+	t.Run("error while streaming", func(t *testing.T) {
+		expectedErr := fmt.Sprintf(`{"code":404, "message":"error looking up block at height %d"}`, blkCnt)
+
+		req := requestURL(t, nil, heights[blkCnt-2], fmt.Sprint(blkCnt), false)
+		rr, err := executeRequest(req, backend)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var elements []json.RawMessage
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &elements), rr.Body.String())
+		require.Len(t, elements, 3)
+		expected := expectedBlockResponsesExpanded(blocks[blkCnt-2:], executionResults[blkCnt-2:], false, flow.BlockStatusSealed)
+		require.JSONEq(t, expected, fmt.Sprintf("[%s,%s]", elements[0], elements[1]))
+		require.JSONEq(t, expectedErr, string(elements[2]))
+
+		q := req.URL.Query()
+		q.Add("limit", "3")
+		req.URL.RawQuery = q.Encode()
+		rr, err = executeRequest(req, backend)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		page := parsePage(t, rr, "blocks")
+		require.JSONEq(t, expected, string(page.Elements))
+		require.Empty(t, page.Next)
+		require.NotNil(t, page.Error)
+		require.JSONEq(t, expectedErr, fmt.Sprintf(`{"code":%d, "message":%q}`, page.Error.Code, page.Error.Message))
+	})
+}
+
+// heightRangePage is the body of a page of a paginated height range response.
+type heightRangePage struct {
+	Elements   json.RawMessage
+	NextCursor string `json:"next_cursor"`
+	Next       string `json:"next"`
+	Error      *models.ModelError
+}
+
+// parsePage parses the body of a page of a paginated height range response, of which the elements
+// are in the given field.
+func parsePage(t *testing.T, rr *httptest.ResponseRecorder, field string) heightRangePage {
+	var body map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body), rr.Body.String())
+
+	var page heightRangePage
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+	page.Elements = body[field]
+	require.NotNil(t, page.Elements, rr.Body.String())
+
+	return page
+}
+
 func requestURL(t *testing.T, ids []string, start string, end string, expandResponse bool, heights ...string) *http.Request {
 	u, _ := url.Parse("/v1/blocks")
 	q := u.Query()
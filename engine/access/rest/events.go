@@ -7,13 +7,15 @@ import (
 	"github.com/onflow/flow-go/engine/access/rest/request"
 
 	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
 )
 
 const blockQueryParam = "block_ids"
 const eventTypeQuery = "type"
 
-// GetEvents for the provided block range or list of block IDs filtered by type.
-func GetEvents(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+// GetEvents for the provided block range or list of block IDs filtered by type. Events within a
+// block range can additionally be filtered by contract address, transaction ID and field value.
+func GetEvents(r *request.Request, backend access.API, link models.LinkGenerator) (interface{}, error) {
 	req, err := r.GetEventsRequest()
 	if err != nil {
 		return nil, NewBadRequestError(err)
@@ -45,12 +47,37 @@ func GetEvents(r *request.Request, backend access.API, _ models.LinkGenerator) (
 		}
 	}
 
+	// the range is returned in pages of at most the requested limit of heights, if any
+	pageEndHeight := req.Page.End(req.StartHeight, req.EndHeight)
+
 	// if request provided block height range then return events for that range
-	events, err := backend.GetEventsForHeightRange(r.Context(), req.Type, req.StartHeight, req.EndHeight)
+	var events []flow.BlockEvents
+	if req.Filtered() {
+		filter := access.EventFilter{
+			EventType:       flow.EventType(req.Type),
+			ContractAddress: req.ContractAddress,
+			TransactionID:   req.TransactionID,
+			FieldName:       req.FieldName,
+			FieldValue:      req.FieldValue,
+		}
+		events, err = backend.GetEventsForHeightRangeWithFilter(r.Context(), filter, req.StartHeight, pageEndHeight)
+	} else {
+		events, err = backend.GetEventsForHeightRange(r.Context(), req.Type, req.StartHeight, pageEndHeight)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	blocksEvents.Build(events)
-	return blocksEvents, nil
+	return heightRangeResponse(r, req.Page, req.StartHeight, req.EndHeight, "events", link.EventsLink,
+		func(send func(element interface{}) error) error {
+			for _, blockEvents := range blocksEvents {
+				err := send(blockEvents)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
 }
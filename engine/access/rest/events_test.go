@@ -10,6 +10,7 @@ import (
 
 	"github.com/onflow/flow-go/engine/access/rest/util"
 
+	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/access/mock"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -116,6 +117,161 @@ func TestGetEvents(t *testing.T) {
 
 }
 
+// TestGetEventsPagination tests that the events of height ranges larger than the limit are returned
+// in pages, each linking to the next page.
+func TestGetEventsPagination(t *testing.T) {
+	backend := &mock.API{}
+	eventType := "A.179b6b1cb6755e31.Foo.Bar"
+
+	events := make([]flow.BlockEvents, 3)
+	for i := range events {
+		header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(uint64(i * 200)))
+		events[i] = unittest.BlockEventsFixture(header, 2)
+	}
+
+	backend.Mock.
+		On("GetEventsForHeightRange", mocks.Anything, eventType, uint64(0), uint64(249)).
+		Return(events[:2], nil).
+		Once()
+	backend.Mock.
+		On("GetEventsForHeightRange", mocks.Anything, eventType, uint64(250), uint64(499)).
+		Return(events[2:], nil).
+		Once()
+
+	req := getEventReq(t, eventType, "0", "499", nil)
+	q := req.URL.Query()
+	q.Add("limit", "250")
+	req.URL.RawQuery = q.Encode()
+
+	rr, err := executeRequest(req, backend)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	page := parsePage(t, rr, "events")
+	require.JSONEq(t, testBlockEventResponse(events[:2]), string(page.Elements))
+
+	link := page.Next
+	require.NotEmpty(t, link)
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	require.Equal(t, eventType, u.Query().Get(eventTypeQuery))
+	require.Equal(t, page.NextCursor, u.Query().Get(cursorQueryParam))
+
+	req, err = http.NewRequest("GET", link, nil)
+	require.NoError(t, err)
+	rr, err = executeRequest(req, backend)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	page = parsePage(t, rr, "events")
+	require.JSONEq(t, testBlockEventResponse(events[2:]), string(page.Elements))
+	require.Empty(t, page.Next)
+	require.Empty(t, page.NextCursor)
+
+	t.Run("cursor with height range", func(t *testing.T) {
+		req := getEventReq(t, eventType, "0", "499", nil)
+		q := req.URL.Query()
+		q.Set(cursorQueryParam, u.Query().Get(cursorQueryParam))
+		req.URL.RawQuery = q.Encode()
+
+		assertResponse(t, req, http.StatusBadRequest, `{"code":400,"message":"can only provide either cursor or start and end height range"}`, backend)
+	})
+
+	backend.AssertExpectations(t)
+}
+
+func TestGetEventsWithFilter(t *testing.T) {
+	backend := &mock.API{}
+
+	header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(10))
+	events := []flow.BlockEvents{unittest.BlockEventsFixture(header, 2)}
+
+	contract := unittest.RandomAddressFixture()
+	recipient := unittest.RandomAddressFixture()
+	txID := unittest.IdentifierFixture()
+	eventType := "A." + contract.Hex() + ".FlowToken.TokensDeposited"
+
+	backend.Mock.
+		On("GetEventsForHeightRangeWithFilter", mocks.Anything, access.EventFilter{ContractAddress: &contract, TransactionID: &txID}, uint64(10), uint64(20)).
+		Return(events, nil)
+	backend.Mock.
+		On("GetEventsForHeightRangeWithFilter", mocks.Anything, access.EventFilter{EventType: flow.EventType(eventType), FieldName: "to", FieldValue: recipient.Hex()}, uint64(10), uint64(20)).
+		Return(nil, status.Error(codes.Unimplemented, "filtered event queries require events to be indexed"))
+
+	testVectors := []testVector{
+		{
+			description: "Get events by contract address and transaction without type",
+			request: getFilteredEventReq(t, "", "10", "20", nil, map[string]string{
+				"contract_address": contract.Hex(),
+				"transaction_id":   txID.String(),
+			}),
+			expectedStatus:   http.StatusOK,
+			expectedResponse: testBlockEventResponse(events),
+		},
+		{
+			description: "Get events by field value, not supported by the node",
+			request: getFilteredEventReq(t, eventType, "10", "20", nil, map[string]string{
+				"field_name":  "to",
+				"field_value": recipient.Hex(),
+			}),
+			expectedStatus:   http.StatusNotImplemented,
+			expectedResponse: `{"code":501,"message":"Not supported by this node: filtered event queries require events to be indexed"}`,
+		},
+		{
+			description: "Get invalid - field value without type",
+			request: getFilteredEventReq(t, "", "10", "20", nil, map[string]string{
+				"transaction_id": txID.String(),
+				"field_name":     "to",
+				"field_value":    recipient.Hex(),
+			}),
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: `{"code":400,"message":"event type must be provided to filter by field value"}`,
+		},
+		{
+			description: "Get invalid - field name without value",
+			request: getFilteredEventReq(t, eventType, "10", "20", nil, map[string]string{
+				"field_name": "to",
+			}),
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: `{"code":400,"message":"field name and field value must be provided together"}`,
+		},
+		{
+			description: "Get invalid - filter with block IDs",
+			request: getFilteredEventReq(t, eventType, "", "", []string{header.ID().String()}, map[string]string{
+				"contract_address": contract.Hex(),
+			}),
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: `{"code":400,"message":"filters can only be used with a start and end height range"}`,
+		},
+		{
+			description: "Get invalid - invalid contract address",
+			request: getFilteredEventReq(t, "", "10", "20", nil, map[string]string{
+				"contract_address": "foo",
+			}),
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: `{"code":400,"message":"invalid contract address: invalid address"}`,
+		},
+	}
+
+	for _, test := range testVectors {
+		t.Run(test.description, func(t *testing.T) {
+			assertResponse(t, test.request, test.expectedStatus, test.expectedResponse, backend)
+		})
+	}
+}
+
+func getFilteredEventReq(t *testing.T, eventType string, start string, end string, blockIDs []string, filters map[string]string) *http.Request {
+	req := getEventReq(t, eventType, start, end, blockIDs)
+
+	q := req.URL.Query()
+	for name, value := range filters {
+		q.Add(name, value)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	return req
+}
+
 func getEventReq(t *testing.T, eventType string, start string, end string, blockIDs []string) *http.Request {
 	u, _ := url.Parse("/v1/events")
 	q := u.Query()
@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ExecutionDataHandlerFunc is a function that contains the handling logic of an endpoint serving
+// execution data, which is read using the state stream API.
+type ExecutionDataHandlerFunc func(
+	r *request.Request,
+	backend access.API,
+	stateStream state_stream.API,
+	generator models.LinkGenerator,
+) (interface{}, error)
+
+// withStateStream returns an ApiHandlerFunc calling the handler with the given state stream API.
+// Requests fail as not supported if the node does not serve execution data.
+func (f ExecutionDataHandlerFunc) withStateStream(stateStream state_stream.API) ApiHandlerFunc {
+	return func(r *request.Request, backend access.API, generator models.LinkGenerator) (interface{}, error) {
+		if stateStream == nil {
+			return nil, status.Error(codes.Unimplemented, "execution data is not available on this node")
+		}
+		return f(r, backend, stateStream, generator)
+	}
+}
+
+// GetExecutionDataByBlockID handler retrieves the execution data of a block by block ID and returns the response
+func GetExecutionDataByBlockID(r *request.Request, _ access.API, stateStream state_stream.API, link models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetExecutionDataByBlockIDRequest()
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	return getExecutionData(r, stateStream, req.ID, link)
+}
+
+// GetExecutionDataByHeight handler retrieves the execution data of a block by block height and returns the response
+func GetExecutionDataByHeight(r *request.Request, backend access.API, stateStream state_stream.API, link models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetExecutionDataByHeightRequest()
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	height, err := resolveHeight(r.Context(), backend, req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	header, _, err := backend.GetBlockHeaderByHeight(r.Context(), height)
+	if err != nil {
+		return nil, err
+	}
+
+	return getExecutionData(r, stateStream, header.ID(), link)
+}
+
+func getExecutionData(r *request.Request, stateStream state_stream.API, blockID flow.Identifier, link models.LinkGenerator) (interface{}, error) {
+	message, err := stateStream.GetExecutionDataByBlockID(r.Context(), blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	executionData, err := convert.MessageToBlockExecutionData(message, r.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.BlockExecutionData
+	err = response.Build(executionData, link)
+	return response, err
+}
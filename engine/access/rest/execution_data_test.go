@@ -0,0 +1,194 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog"
+	mocks "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	statestreammock "github.com/onflow/flow-go/engine/access/state_stream/mock"
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func executionDataURL(t *testing.T, blockID string, height string, selects string) string {
+	u, err := url.ParseRequestURI("/v1/execution_data")
+	require.NoError(t, err)
+	if blockID != "" {
+		u.Path = fmt.Sprintf("%s/%s", u.Path, blockID)
+	}
+
+	q := u.Query()
+	if height != "" {
+		q.Add("block_height", height)
+	}
+	if selects != "" {
+		q.Add("select", selects)
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func assertExecutionDataResponse(t *testing.T, req *http.Request, status int, expectedRespBody string, backend *mock.API, stateStream *statestreammock.API) {
+	router, err := newRouter(backend, stateStream, nil, zerolog.Nop(), flow.Testnet.Chain(), testSubscriptionConfig)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.JSONEq(t, expectedRespBody, rr.Body.String())
+	require.Equal(t, status, rr.Code)
+}
+
+func TestGetExecutionData(t *testing.T) {
+	tx := unittest.TransactionBodyFixture()
+	event := unittest.EventFixture(flow.EventAccountCreated, 0, 0, tx.ID(), 0)
+
+	key := ledger.NewKey([]ledger.KeyPart{ledger.NewKeyPart(0, []byte("owner")), ledger.NewKeyPart(2, []byte("key"))})
+	trieUpdate := &ledger.TrieUpdate{
+		RootHash: ledger.RootHash(unittest.StateCommitmentFixture()),
+		Paths:    []ledger.Path{ledger.Path(unittest.StateCommitmentFixture())},
+		Payloads: []*ledger.Payload{ledger.NewPayload(key, []byte("value"))},
+	}
+
+	header := unittest.BlockHeaderFixture()
+	executionData := &execution_data.BlockExecutionData{
+		BlockID: header.ID(),
+		ChunkExecutionDatas: []*execution_data.ChunkExecutionData{{
+			Collection: &flow.Collection{Transactions: []*flow.TransactionBody{&tx}},
+			Events:     flow.EventsList{event},
+			TrieUpdate: trieUpdate,
+		}},
+	}
+	message, err := convert.BlockExecutionDataToMessage(executionData)
+	require.NoError(t, err)
+
+	expectedTrieUpdate := fmt.Sprintf(`{
+		"root_hash": "%s",
+		"paths": ["%s"],
+		"payloads": [{
+			"key_parts": [
+				{"type": "0", "value": "%s"},
+				{"type": "2", "value": "%s"}
+			],
+			"value": "%s"
+		}]
+	}`, trieUpdate.RootHash, trieUpdate.Paths[0], util.ToBase64([]byte("owner")), util.ToBase64([]byte("key")), util.ToBase64([]byte("value")))
+
+	t.Run("get by block ID", func(t *testing.T) {
+		backend := &mock.API{}
+		stateStream := statestreammock.NewAPI(t)
+		stateStream.
+			On("GetExecutionDataByBlockID", mocks.Anything, header.ID()).
+			Return(message, nil).
+			Once()
+
+		selectTrieUpdates := "block_id,chunk_execution_data.trie_update.root_hash,chunk_execution_data.trie_update.paths," +
+			"chunk_execution_data.trie_update.payloads.key_parts.type,chunk_execution_data.trie_update.payloads.key_parts.value," +
+			"chunk_execution_data.trie_update.payloads.value"
+		req, err := http.NewRequest("GET", executionDataURL(t, header.ID().String(), "", selectTrieUpdates), nil)
+		require.NoError(t, err)
+
+		expected := fmt.Sprintf(`{
+			"block_id": "%s",
+			"chunk_execution_data": [{"trie_update": %s}]
+		}`, header.ID(), expectedTrieUpdate)
+		assertExecutionDataResponse(t, req, http.StatusOK, expected, backend, stateStream)
+	})
+
+	t.Run("get by height without trie updates", func(t *testing.T) {
+		backend := &mock.API{}
+		backend.Mock.
+			On("GetBlockHeaderByHeight", mocks.Anything, header.Height).
+			Return(header, flow.BlockStatusSealed, nil).
+			Once()
+
+		stateStream := statestreammock.NewAPI(t)
+		stateStream.
+			On("GetExecutionDataByBlockID", mocks.Anything, header.ID()).
+			Return(message, nil).
+			Once()
+
+		req, err := http.NewRequest("GET", executionDataURL(t, "", fmt.Sprint(header.Height), "block_id,chunk_execution_data.collection.transactions.id,chunk_execution_data.events.type"), nil)
+		require.NoError(t, err)
+
+		expected := fmt.Sprintf(`{
+			"block_id": "%s",
+			"chunk_execution_data": [{
+				"collection": {"transactions": [{"id": "%s"}]},
+				"events": [{"type": "%s"}]
+			}]
+		}`, header.ID(), tx.ID(), event.Type)
+		assertExecutionDataResponse(t, req, http.StatusOK, expected, backend, stateStream)
+		mocks.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get by sealed height", func(t *testing.T) {
+		backend := &mock.API{}
+		backend.Mock.
+			On("GetLatestBlockHeader", mocks.Anything, true).
+			Return(header, flow.BlockStatusSealed, nil).
+			Once()
+		backend.Mock.
+			On("GetBlockHeaderByHeight", mocks.Anything, header.Height).
+			Return(header, flow.BlockStatusSealed, nil).
+			Once()
+
+		stateStream := statestreammock.NewAPI(t)
+		stateStream.
+			On("GetExecutionDataByBlockID", mocks.Anything, header.ID()).
+			Return(message, nil).
+			Once()
+
+		req, err := http.NewRequest("GET", executionDataURL(t, "", "sealed", "block_id"), nil)
+		require.NoError(t, err)
+
+		expected := fmt.Sprintf(`{"block_id": "%s"}`, header.ID())
+		assertExecutionDataResponse(t, req, http.StatusOK, expected, backend, stateStream)
+		mocks.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		backend := &mock.API{}
+		stateStream := statestreammock.NewAPI(t)
+		stateStream.
+			On("GetExecutionDataByBlockID", mocks.Anything, header.ID()).
+			Return(nil, status.Error(codes.NotFound, "not found")).
+			Once()
+
+		req, err := http.NewRequest("GET", executionDataURL(t, header.ID().String(), "", ""), nil)
+		require.NoError(t, err)
+
+		expected := `{"code": 404, "message": "Flow resource not found: not found"}`
+		assertExecutionDataResponse(t, req, http.StatusNotFound, expected, backend, stateStream)
+	})
+
+	t.Run("missing height", func(t *testing.T) {
+		req, err := http.NewRequest("GET", executionDataURL(t, "", "", ""), nil)
+		require.NoError(t, err)
+
+		expected := `{"code": 400, "message": "block height must be provided"}`
+		assertExecutionDataResponse(t, req, http.StatusBadRequest, expected, &mock.API{}, statestreammock.NewAPI(t))
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		req, err := http.NewRequest("GET", executionDataURL(t, header.ID().String(), "", ""), nil)
+		require.NoError(t, err)
+
+		expected := `{"code": 501, "message": "Not supported by this node: execution data is not available on this node"}`
+		assertResponse(t, req, http.StatusNotImplemented, expected, &mock.API{})
+	})
+}
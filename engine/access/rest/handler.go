@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/onflow/flow-go/engine/access/rest/models"
 	"github.com/onflow/flow-go/engine/access/rest/request"
@@ -29,6 +30,19 @@ type ApiHandlerFunc func(
 	generator models.LinkGenerator,
 ) (interface{}, error)
 
+// StreamedResponse is a response of a handler function which is a JSON array, sent to the client
+// one element at a time with chunked transfer encoding instead of being encoded at once.
+type StreamedResponse struct {
+	// Field is the name of the field holding the array if the response is a JSON object, or empty if
+	// the response is the array itself.
+	Field string
+	// Trailer holds the other fields of the response object, written after the array once all the
+	// elements were sent, e.g. the cursor of the next page.
+	Trailer map[string]interface{}
+	// Elements calls send with each element of the array in order, and returns the first error.
+	Elements func(send func(element interface{}) error) error
+}
+
 // Handler is custom http handler implementing custom handler function.
 // Handler function allows easier handling of errors and responses as it
 // wraps functionality for handling error and responses outside of endpoint handling.
@@ -80,6 +94,11 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if stream, ok := response.(*StreamedResponse); ok {
+		h.streamResponse(w, stream, decoratedRequest.Selects(), errLog)
+		return
+	}
+
 	// apply the select filter if any select fields have been specified
 	response, err = util.SelectFilter(response, decoratedRequest.Selects())
 	if err != nil {
@@ -92,44 +111,50 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) errorHandler(w http.ResponseWriter, err error, errorLogger zerolog.Logger) {
+	returnCode, msg := errorToStatus(err, errorLogger)
+	h.errorResponse(w, returnCode, msg, errorLogger)
+}
+
+// errorToStatus converts an error returned by a handler into the HTTP status code and user message
+// returned to the client.
+func errorToStatus(err error, errorLogger zerolog.Logger) (int, string) {
 	// rest status type error should be returned with status and user message provided
 	var statusErr StatusError
 	if errors.As(err, &statusErr) {
-		h.errorResponse(w, statusErr.Status(), statusErr.UserMessage(), errorLogger)
-		return
+		return statusErr.Status(), statusErr.UserMessage()
 	}
 
 	// handle cadence errors
 	cadenceError := fvmErrors.Find(err, fvmErrors.ErrCodeCadenceRunTimeError)
 	if cadenceError != nil {
 		msg := fmt.Sprintf("Cadence error: %s", cadenceError.Error())
-		h.errorResponse(w, http.StatusBadRequest, msg, errorLogger)
-		return
+		return http.StatusBadRequest, msg
 	}
 
 	// handle grpc status error returned from the backend calls, we are forwarding the message to the client
 	if se, ok := status.FromError(err); ok {
 		if se.Code() == codes.NotFound {
 			msg := fmt.Sprintf("Flow resource not found: %s", se.Message())
-			h.errorResponse(w, http.StatusNotFound, msg, errorLogger)
-			return
+			return http.StatusNotFound, msg
 		}
 		if se.Code() == codes.InvalidArgument {
 			msg := fmt.Sprintf("Invalid Flow argument: %s", se.Message())
-			h.errorResponse(w, http.StatusBadRequest, msg, errorLogger)
-			return
+			return http.StatusBadRequest, msg
 		}
 		if se.Code() == codes.Internal {
 			msg := fmt.Sprintf("Invalid Flow request: %s", se.Message())
-			h.errorResponse(w, http.StatusBadRequest, msg, errorLogger)
-			return
+			return http.StatusBadRequest, msg
+		}
+		if se.Code() == codes.Unimplemented {
+			msg := fmt.Sprintf("Not supported by this node: %s", se.Message())
+			return http.StatusNotImplemented, msg
 		}
 	}
 
 	// stop going further - catch all error
 	msg := "internal server error"
 	errorLogger.Error().Err(err).Msg(msg)
-	h.errorResponse(w, http.StatusInternalServerError, msg, errorLogger)
+	return http.StatusInternalServerError, msg
 }
 
 // jsonResponse builds a JSON response and send it to the client
@@ -152,6 +177,132 @@ func (h *Handler) jsonResponse(w http.ResponseWriter, code int, response interfa
 	}
 }
 
+// streamResponse sends the elements of a streamed response as a JSON array, flushing each element to
+// the client as soon as it is encoded. An error before the first element is sent as error response.
+// As the status of the response is already sent, a later error terminates the response with the
+// error instead: as the last element of the array, or in the "error" field of a response object in
+// place of the trailer fields. Either way the body stays valid JSON.
+func (h *Handler) streamResponse(w http.ResponseWriter, stream *StreamedResponse, selects []string, errLog zerolog.Logger) {
+	flusher, _ := w.(http.Flusher)
+
+	indent := "\t"
+	if stream.Field != "" {
+		indent = "\t\t"
+	}
+
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		opening := "["
+		if stream.Field != "" {
+			opening = fmt.Sprintf("{\n\t%q: [", stream.Field)
+		}
+		_, err := w.Write([]byte(opening))
+		return err
+	}
+
+	err := stream.Elements(func(element interface{}) error {
+		// apply the select filter to each element if any select fields have been specified
+		element, err := util.SelectFilter(element, selects)
+		if err != nil {
+			return err
+		}
+
+		encodedElement, err := json.MarshalIndent(element, indent, "\t")
+		if err != nil {
+			return err
+		}
+
+		separator := ",\n" + indent
+		if !started {
+			separator = "\n" + indent
+			err = start()
+			if err != nil {
+				return err
+			}
+		}
+		_, err = w.Write(append([]byte(separator), encodedElement...))
+		if err != nil {
+			return err
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil && !started {
+		h.errorHandler(w, err, errLog)
+		return
+	}
+
+	var closing []byte
+	if err != nil {
+		errLog.Error().Err(err).Msg("failed to stream http response")
+		closing, err = streamErrorClosing(stream.Field != "", err, errLog)
+	} else {
+		if !started {
+			err = start()
+		}
+		if err == nil {
+			closing, err = streamClosing(stream)
+		}
+	}
+	if err == nil {
+		_, err = w.Write(closing)
+	}
+	if err != nil {
+		errLog.Error().Err(err).Msg("failed to write http response")
+	}
+}
+
+// streamClosing returns the end of a streamed response of which all the elements were sent.
+func streamClosing(stream *StreamedResponse) ([]byte, error) {
+	if stream.Field == "" {
+		return []byte("\n]"), nil
+	}
+
+	closing := []byte("\n\t]")
+
+	keys := make([]string, 0, len(stream.Trailer))
+	for key := range stream.Trailer {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		encodedValue, err := json.MarshalIndent(stream.Trailer[key], "\t", "\t")
+		if err != nil {
+			return nil, err
+		}
+		closing = append(closing, fmt.Sprintf(",\n\t%q: ", key)...)
+		closing = append(closing, encodedValue...)
+	}
+
+	return append(closing, "\n}"...), nil
+}
+
+// streamErrorClosing returns the end of a streamed response which is terminated by the given error.
+func streamErrorClosing(object bool, err error, errLog zerolog.Logger) ([]byte, error) {
+	returnCode, msg := errorToStatus(err, errLog)
+	modelError := models.ModelError{
+		Code:    int32(returnCode),
+		Message: msg,
+	}
+
+	encodedError, err := json.MarshalIndent(modelError, "\t", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	if !object {
+		return append(append([]byte(",\n\t"), encodedError...), "\n]"...), nil
+	}
+	return append(append([]byte("\n\t],\n\t\"error\": "), encodedError...), "\n}"...), nil
+}
+
 // errorResponse sends an HTTP error response to the client with the given return code
 // and a model error with the given response message in the response body
 func (h *Handler) errorResponse(
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/common/rpc"
+)
+
+// QuotaMiddleware creates a middleware which enforces the per-client quotas for each request.
+// Clients provide their API key in the X-API-Key header, and are otherwise identified by their IP.
+// Methods are identified by the name of the route, e.g. "executeScript", and the quota status is
+// returned in the X-RateLimit-Limit, X-RateLimit-Remaining and Retry-After headers.
+func QuotaMiddleware(quotas *rpc.Quotas) mux.MiddlewareFunc {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			var method string
+			if route := mux.CurrentRoute(req); route != nil {
+				method = route.GetName()
+			}
+
+			quotaStatus, err := quotas.Check(req.Header.Get(rpc.APIKeyHeader), rpc.RemoteIP(req.RemoteAddr), method)
+			if quotaStatus.Limited {
+				for key, value := range rpc.QuotaHeaders(quotaStatus) {
+					w.Header().Set(key, value)
+				}
+			}
+			if err != nil {
+				code := http.StatusUnauthorized
+				if errors.Is(err, rpc.ErrQuotaExceeded) {
+					code = http.StatusTooManyRequests
+					err = fmt.Errorf("%s quota exceeded, please retry later", method)
+				}
+				quotaErrorResponse(w, code, err.Error())
+				return
+			}
+
+			inner.ServeHTTP(w, req)
+		})
+	}
+}
+
+// quotaErrorResponse sends an HTTP error response with the given code and message in the same
+// format as the errors returned by the handlers.
+func quotaErrorResponse(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(models.ModelError{
+		Code:    int32(code),
+		Message: message,
+	})
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/common/rpc"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+func TestQuotaMiddleware(t *testing.T) {
+	quotas, err := rpc.NewQuotas(zerolog.Nop(), metrics.NewNoopCollector(), rpc.QuotaConfig{
+		Anonymous: rpc.QuotaLimits{
+			Methods: map[string]rpc.QuotaLimit{"executeScript": {Rate: 1, Burst: 1}},
+		},
+		APIKeys: []rpc.APIKeyConfig{{Name: "wallet", Key: "secret"}},
+	})
+	require.NoError(t, err)
+
+	r := mux.NewRouter()
+	r.Handle("/scripts", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).Name("executeScript")
+	r.Use(QuotaMiddleware(quotas))
+
+	serve := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/scripts", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if apiKey != "" {
+			req.Header.Set(rpc.APIKeyHeader, apiKey)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve("")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get(rpc.RateLimitLimitHeader))
+	assert.Equal(t, "0", rr.Header().Get(rpc.RateLimitRemainingHeader))
+
+	rr = serve("")
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get(rpc.RetryAfterHeader))
+	assert.JSONEq(t, `{"code": 429, "message": "executeScript quota exceeded, please retry later"}`, rr.Body.String())
+
+	// requests with an API key are not limited by the anonymous quota
+	rr = serve("secret")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get(rpc.RateLimitLimitHeader))
+
+	rr = serve("unknown")
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.JSONEq(t, `{"code": 401, "message": "invalid API key"}`, rr.Body.String())
+}
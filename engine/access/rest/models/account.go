@@ -1,6 +1,7 @@
 package models
 
 import (
+	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine/access/rest/util"
 	"github.com/onflow/flow-go/model/flow"
 )
@@ -66,3 +67,38 @@ func (a *AccountPublicKeys) Build(accountKeys []flow.AccountPublicKey) {
 
 	*a = keys
 }
+
+func (a *AccountBalance) Build(balance uint64) {
+	a.Balance = util.FromUint64(balance)
+}
+
+func (a *AccountTransaction) Build(accountTx flow.AccountTransaction, link LinkGenerator) error {
+	a.TransactionId = accountTx.TransactionID.String()
+	a.BlockHeight = util.FromUint64(accountTx.BlockHeight)
+	a.Roles = accountTx.Roles.Strings()
+
+	var self Links
+	err := self.Build(link.TransactionLink(accountTx.TransactionID))
+	if err != nil {
+		return err
+	}
+	a.Links = &self
+
+	return nil
+}
+
+func (a *AccountTransactions) Build(page *access.AccountTransactionsPage, link LinkGenerator) error {
+	a.Transactions = make([]AccountTransaction, len(page.Transactions))
+	for i, accountTx := range page.Transactions {
+		err := a.Transactions[i].Build(accountTx, link)
+		if err != nil {
+			return err
+		}
+	}
+
+	if page.NextCursor != nil {
+		a.NextCursor = util.FromAccountTransactionCursor(*page.NextCursor)
+	}
+
+	return nil
+}
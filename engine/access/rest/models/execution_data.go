@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+)
+
+func (b *BlockExecutionData) Build(data *execution_data.BlockExecutionData, link LinkGenerator) error {
+	chunks := make([]ChunkExecutionData, len(data.ChunkExecutionDatas))
+	for i, chunkData := range data.ChunkExecutionDatas {
+		err := chunks[i].Build(chunkData, link)
+		if err != nil {
+			return fmt.Errorf("failed to build chunk execution data %d: %w", i, err)
+		}
+	}
+
+	b.BlockId = data.BlockID.String()
+	b.ChunkExecutionData = chunks
+	return nil
+}
+
+func (c *ChunkExecutionData) Build(data *execution_data.ChunkExecutionData, link LinkGenerator) error {
+	if data.Collection != nil {
+		var transactions Transactions
+		transactions.Build(data.Collection.Transactions, link)
+		c.Collection = &ExecutionDataCollection{Transactions: transactions}
+	}
+
+	var events Events
+	events.Build(data.Events)
+	c.Events = events
+
+	if data.TrieUpdate != nil {
+		var trieUpdate TrieUpdate
+		err := trieUpdate.Build(data.TrieUpdate)
+		if err != nil {
+			return err
+		}
+		c.TrieUpdate = &trieUpdate
+	}
+
+	return nil
+}
+
+func (t *TrieUpdate) Build(update *ledger.TrieUpdate) error {
+	paths := make([]string, len(update.Paths))
+	for i, path := range update.Paths {
+		paths[i] = path.String()
+	}
+
+	payloads := make([]TriePayload, len(update.Payloads))
+	for i, payload := range update.Payloads {
+		key, err := payload.Key()
+		if err != nil {
+			return fmt.Errorf("failed to decode key of payload %d: %w", i, err)
+		}
+
+		keyParts := make([]TrieKeyPart, len(key.KeyParts))
+		for j, keyPart := range key.KeyParts {
+			keyParts[j] = TrieKeyPart{
+				Type_: util.FromUint64(uint64(keyPart.Type)),
+				Value: util.ToBase64(keyPart.Value),
+			}
+		}
+
+		payloads[i] = TriePayload{
+			KeyParts: keyParts,
+			Value:    util.ToBase64(payload.Value()),
+		}
+	}
+
+	t.RootHash = update.RootHash.String()
+	t.Paths = paths
+	t.Payloads = payloads
+	return nil
+}
@@ -0,0 +1,15 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type AccountStorageDelta struct {
+	Address string `json:"address"`
+	// Change of the storage used in bytes, negative if storage was freed.
+	Delta string `json:"delta"`
+}
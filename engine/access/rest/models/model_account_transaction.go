@@ -0,0 +1,17 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type AccountTransaction struct {
+	TransactionId string `json:"transaction_id"`
+	BlockHeight   string `json:"block_height"`
+	// Roles of the account in the transaction: proposer, payer or authorizer.
+	Roles []string `json:"roles"`
+	Links *Links   `json:"_links,omitempty"`
+}
@@ -0,0 +1,16 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type AccountTransactions struct {
+	// Transactions the account participated in, from the most recent to the oldest.
+	Transactions []AccountTransaction `json:"transactions"`
+	// Cursor of the next page, omitted on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
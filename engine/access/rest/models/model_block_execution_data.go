@@ -0,0 +1,14 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type BlockExecutionData struct {
+	BlockId            string               `json:"block_id"`
+	ChunkExecutionData []ChunkExecutionData `json:"chunk_execution_data"`
+}
@@ -0,0 +1,15 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type ChunkExecutionData struct {
+	Collection *ExecutionDataCollection `json:"collection,omitempty"`
+	Events     []Event                  `json:"events"`
+	TrieUpdate *TrieUpdate              `json:"trie_update,omitempty"`
+}
@@ -0,0 +1,23 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type TransactionSimulation struct {
+	BlockId         string `json:"block_id"`
+	BlockHeight     string `json:"block_height"`
+	ComputationUsed string `json:"computation_used"`
+	MemoryEstimate  string `json:"memory_estimate"`
+	// Fee charged to the payer, in the smallest unit of FLOW.
+	Fee string `json:"fee"`
+	// Change of the storage used in bytes by every account updated by the transaction.
+	StorageDelta []AccountStorageDelta `json:"storage_delta"`
+	// Provided transaction error in case the transaction wasn't successful.
+	ErrorMessage string  `json:"error_message"`
+	Events       []Event `json:"events"`
+}
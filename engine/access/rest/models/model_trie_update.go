@@ -0,0 +1,15 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type TrieUpdate struct {
+	RootHash string        `json:"root_hash"`
+	Paths    []string      `json:"paths"`
+	Payloads []TriePayload `json:"payloads"`
+}
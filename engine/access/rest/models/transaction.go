@@ -1,6 +1,9 @@
 package models
 
 import (
+	"sort"
+	"strconv"
+
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine/access/rest/util"
 	"github.com/onflow/flow-go/model/flow"
@@ -145,3 +148,28 @@ func (p *ProposalKey) Build(key flow.ProposalKey) {
 	p.KeyIndex = util.FromUint64(key.KeyIndex)
 	p.SequenceNumber = util.FromUint64(key.SequenceNumber)
 }
+
+func (t *TransactionSimulation) Build(result *access.TransactionSimulationResult) {
+	t.BlockId = result.BlockID.String()
+	t.BlockHeight = util.FromUint64(result.BlockHeight)
+	t.ComputationUsed = util.FromUint64(result.ComputationUsed)
+	t.MemoryEstimate = util.FromUint64(result.MemoryEstimate)
+	t.Fee = util.FromUint64(result.Fee)
+	t.ErrorMessage = result.ErrorMessage
+
+	var events Events
+	events.Build(result.Events)
+	t.Events = events
+
+	storageDelta := make([]AccountStorageDelta, 0, len(result.StorageDelta))
+	for address, delta := range result.StorageDelta {
+		storageDelta = append(storageDelta, AccountStorageDelta{
+			Address: address.String(),
+			Delta:   strconv.FormatInt(delta, 10),
+		})
+	}
+	sort.Slice(storageDelta, func(i, j int) bool {
+		return storageDelta[i].Address < storageDelta[j].Address
+	})
+	t.StorageDelta = storageDelta
+}
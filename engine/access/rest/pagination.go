@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"net/url"
+
+	"github.com/onflow/flow-go/engine/access/rest/request"
+	"github.com/onflow/flow-go/engine/access/rest/util"
+)
+
+const cursorQueryParam = "cursor"
+
+// heightRangeResponse returns the streamed response of a page of a height range request, of which
+// the elements are sent by the given function.
+//
+// Requests without pagination parameters are returned as a plain JSON array, as they were before
+// pagination was supported. Paginated requests are returned as an object holding the array in the
+// given field, and unless the page is the last one, the cursor of the next page and a link to it.
+// The link is generated from the query of the request, with the cursor of the next page instead of
+// the heights.
+func heightRangeResponse(
+	r *request.Request,
+	page request.HeightRangePage,
+	startHeight uint64,
+	endHeight uint64,
+	field string,
+	linkFunc func(query url.Values) (string, error),
+	elements func(send func(element interface{}) error) error,
+) (*StreamedResponse, error) {
+	response := &StreamedResponse{Elements: elements}
+	if !page.Paginated() {
+		return response, nil
+	}
+
+	response.Field = field
+	response.Trailer = map[string]interface{}{}
+
+	next := page.Next(startHeight, endHeight)
+	if next == nil {
+		return response, nil
+	}
+
+	cursor := util.FromHeightRangeCursor(*next)
+
+	query := r.URL.Query()
+	query.Del(startHeightQueryParam)
+	query.Del(endHeightQueryParam)
+	query.Set(cursorQueryParam, cursor)
+
+	link, err := linkFunc(query)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Trailer["next_cursor"] = cursor
+	response.Trailer["next"] = link
+
+	return response, nil
+}
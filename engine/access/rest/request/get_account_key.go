@@ -0,0 +1,43 @@
+package request
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const indexVar = "index"
+
+type GetAccountKey struct {
+	Address flow.Address
+	Index   uint64
+	Height  uint64
+}
+
+func (g *GetAccountKey) Build(r *Request) error {
+	return g.Parse(
+		r.GetVar(addressVar),
+		r.GetVar(indexVar),
+		r.GetQueryParam(blockHeightQuery),
+	)
+}
+
+func (g *GetAccountKey) Parse(rawAddress string, rawIndex string, rawHeight string) error {
+	var account GetAccount
+	err := account.Parse(rawAddress, rawHeight)
+	if err != nil {
+		return err
+	}
+
+	index, err := strconv.ParseUint(rawIndex, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid key index")
+	}
+
+	g.Address = account.Address
+	g.Index = index
+	g.Height = account.Height
+
+	return nil
+}
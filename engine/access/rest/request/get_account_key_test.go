@@ -0,0 +1,46 @@
+package request
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetAccountKey_InvalidParse(t *testing.T) {
+	var getAccountKey GetAccountKey
+
+	tests := []struct {
+		address string
+		index   string
+		height  string
+		err     string
+	}{
+		{"", "0", "", "invalid address"},
+		{"f8d6e0586b0a20c7", "", "", "invalid key index"},
+		{"f8d6e0586b0a20c7", "-1", "", "invalid key index"},
+		{"f8d6e0586b0a20c7", "foo", "", "invalid key index"},
+		{"f8d6e0586b0a20c7", "0", "-1", "invalid height format"},
+	}
+
+	for i, test := range tests {
+		err := getAccountKey.Parse(test.address, test.index, test.height)
+		assert.EqualError(t, err, test.err, fmt.Sprintf("test #%d failed", i))
+	}
+}
+
+func Test_GetAccountKey_ValidParse(t *testing.T) {
+	var getAccountKey GetAccountKey
+
+	addr := "f8d6e0586b0a20c7"
+	err := getAccountKey.Parse(addr, "2", "")
+	assert.NoError(t, err)
+	assert.Equal(t, getAccountKey.Address.String(), addr)
+	assert.Equal(t, getAccountKey.Index, uint64(2))
+	assert.Equal(t, getAccountKey.Height, SealedHeight)
+
+	err = getAccountKey.Parse(addr, "0", "100")
+	assert.NoError(t, err)
+	assert.Equal(t, getAccountKey.Index, uint64(0))
+	assert.Equal(t, getAccountKey.Height, uint64(100))
+}
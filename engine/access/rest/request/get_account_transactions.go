@@ -0,0 +1,56 @@
+package request
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const limitQuery = "limit"
+const cursorQuery = "cursor"
+
+type GetAccountTransactions struct {
+	Address flow.Address
+	Limit   uint32
+	Cursor  *flow.AccountTransactionCursor
+}
+
+func (g *GetAccountTransactions) Build(r *Request) error {
+	return g.Parse(
+		r.GetVar(addressVar),
+		r.GetQueryParam(limitQuery),
+		r.GetQueryParam(cursorQuery),
+	)
+}
+
+func (g *GetAccountTransactions) Parse(rawAddress string, rawLimit string, rawCursor string) error {
+	var address Address
+	err := address.Parse(rawAddress)
+	if err != nil {
+		return err
+	}
+	g.Address = address.Flow()
+
+	// the default limit is applied by the backend
+	g.Limit = 0
+	if rawLimit != "" {
+		limit, err := util.ToUint64(rawLimit)
+		if err != nil || limit > math.MaxUint32 {
+			return fmt.Errorf("invalid limit")
+		}
+		g.Limit = uint32(limit)
+	}
+
+	g.Cursor = nil
+	if rawCursor != "" {
+		cursor, err := util.ToAccountTransactionCursor(rawCursor)
+		if err != nil {
+			return err
+		}
+		g.Cursor = &cursor
+	}
+
+	return nil
+}
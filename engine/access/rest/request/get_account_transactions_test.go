@@ -0,0 +1,52 @@
+package request
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func Test_GetAccountTransactions_InvalidParse(t *testing.T) {
+	var getAccountTransactions GetAccountTransactions
+
+	tests := []struct {
+		address string
+		limit   string
+		cursor  string
+		err     string
+	}{
+		{"", "", "", "invalid address"},
+		{"f8d6e0586b0a20c7", "-1", "", "invalid limit"},
+		{"f8d6e0586b0a20c7", "4294967296", "", "invalid limit"},
+		{"f8d6e0586b0a20c7", "", "foo", "invalid cursor"},
+	}
+
+	for i, test := range tests {
+		err := getAccountTransactions.Parse(test.address, test.limit, test.cursor)
+		assert.EqualError(t, err, test.err, fmt.Sprintf("test #%d failed", i))
+	}
+}
+
+func Test_GetAccountTransactions_ValidParse(t *testing.T) {
+	var getAccountTransactions GetAccountTransactions
+
+	addr := "f8d6e0586b0a20c7"
+	err := getAccountTransactions.Parse(addr, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, addr, getAccountTransactions.Address.String())
+	assert.Equal(t, uint32(0), getAccountTransactions.Limit)
+	assert.Nil(t, getAccountTransactions.Cursor)
+
+	cursor := flow.AccountTransactionCursor{BlockHeight: 100, TransactionID: unittest.IdentifierFixture()}
+	err = getAccountTransactions.Parse(addr, "10", util.FromAccountTransactionCursor(cursor))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), getAccountTransactions.Limit)
+	require.NotNil(t, getAccountTransactions.Cursor)
+	assert.Equal(t, cursor, *getAccountTransactions.Cursor)
+}
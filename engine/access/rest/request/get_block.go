@@ -18,13 +18,18 @@ type GetBlock struct {
 	EndHeight    uint64
 	FinalHeight  bool
 	SealedHeight bool
+	Page         HeightRangePage // requested page of the start and end height range
 }
 
 func (g *GetBlock) Build(r *Request) error {
-	return g.Parse(
+	return g.ParsePage(
 		r.GetQueryParams(heightQuery),
 		r.GetQueryParam(startHeightQuery),
 		r.GetQueryParam(endHeightQuery),
+		PageParams{
+			Limit:  r.GetQueryParam(limitQuery),
+			Cursor: r.GetQueryParam(cursorQuery),
+		},
 	)
 }
 
@@ -33,6 +38,12 @@ func (g *GetBlock) HasHeights() bool {
 }
 
 func (g *GetBlock) Parse(rawHeights []string, rawStart string, rawEnd string) error {
+	return g.ParsePage(rawHeights, rawStart, rawEnd, PageParams{})
+}
+
+// ParsePage parses a request for blocks by heights or by a start and end height range, of which a
+// page of at most the requested limit of heights is returned.
+func (g *GetBlock) ParsePage(rawHeights []string, rawStart string, rawEnd string, rawPage PageParams) error {
 	var height Height
 	err := height.Parse(rawStart)
 	if err != nil {
@@ -52,6 +63,21 @@ func (g *GetBlock) Parse(rawHeights []string, rawStart string, rawEnd string) er
 	}
 	g.Heights = heights.Flow()
 
+	err = g.Page.Parse(rawPage, MaxBlockRequestHeightRange)
+	if err != nil {
+		return err
+	}
+	if len(heights) > 0 && (rawPage.Limit != "" || rawPage.Cursor != "") {
+		return fmt.Errorf("limit and cursor can only be provided with a start and end height range")
+	}
+	if g.Page.Cursor != nil {
+		if g.StartHeight != EmptyHeight || g.EndHeight != EmptyHeight {
+			return fmt.Errorf("can only provide either cursor or start and end height range")
+		}
+		g.StartHeight = g.Page.Cursor.StartHeight
+		g.EndHeight = g.Page.Cursor.EndHeight
+	}
+
 	// if both height and one or both of start and end height are provided
 	if len(g.Heights) > 0 && (g.StartHeight != EmptyHeight || g.EndHeight != EmptyHeight) {
 		return fmt.Errorf("can only provide either heights or start and end height range")
@@ -65,8 +91,9 @@ func (g *GetBlock) Parse(rawHeights []string, rawStart string, rawEnd string) er
 	if g.StartHeight > g.EndHeight {
 		return fmt.Errorf("start height must be less than or equal to end height")
 	}
-	// check if range exceeds maximum but only if end is not equal to special value which is not known yet
-	if g.EndHeight-g.StartHeight >= MaxBlockRequestHeightRange && g.EndHeight != FinalHeight && g.EndHeight != SealedHeight {
+	// check if range exceeds maximum but only if end is not equal to special value which is not known yet,
+	// ranges of any size can be requested in pages by providing a limit
+	if !g.Page.Paginated() && g.EndHeight-g.StartHeight >= MaxBlockRequestHeightRange && g.EndHeight != FinalHeight && g.EndHeight != SealedHeight {
 		return fmt.Errorf("height range %d exceeds maximum allowed of %d", g.EndHeight-g.StartHeight, MaxBlockRequestHeightRange)
 	}
 
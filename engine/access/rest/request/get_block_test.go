@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/engine/access/rest/util"
 )
 
 func TestGetBlock_InvalidParse(t *testing.T) {
@@ -78,3 +80,48 @@ func TestGetBlock_ValidParse(t *testing.T) {
 	assert.Equal(t, getBlock.EndHeight, SealedHeight)
 	assert.Equal(t, getBlock.StartHeight, FinalHeight)
 }
+
+func TestGetBlock_ParsePage(t *testing.T) {
+	var getBlock GetBlock
+
+	// ranges larger than the maximum can be requested with a limit
+	err := getBlock.ParsePage(nil, "10", "1000", PageParams{Limit: "20"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), getBlock.Page.Limit)
+	assert.Nil(t, getBlock.Page.Cursor)
+	assert.Equal(t, uint64(29), getBlock.Page.End(getBlock.StartHeight, getBlock.EndHeight))
+
+	next := getBlock.Page.Next(getBlock.StartHeight, getBlock.EndHeight)
+	assert.Equal(t, &util.HeightRangeCursor{StartHeight: 30, EndHeight: 1000}, next)
+
+	// the cursor replaces the start and end height
+	err = getBlock.ParsePage(nil, "", "", PageParams{Cursor: util.FromHeightRangeCursor(*next)})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(MaxBlockRequestHeightRange), getBlock.Page.Limit)
+	assert.Equal(t, uint64(30), getBlock.StartHeight)
+	assert.Equal(t, uint64(1000), getBlock.EndHeight)
+
+	// the last page has no next page
+	assert.Equal(t, uint64(1000), getBlock.Page.End(980, 1000))
+	assert.Nil(t, getBlock.Page.Next(980, 1000))
+
+	tests := []struct {
+		heights []string
+		start   string
+		end     string
+		page    PageParams
+		outErr  string
+	}{
+		{nil, "1", "10", PageParams{Limit: "0"}, "limit must be between 1 and 50"},
+		{nil, "1", "10", PageParams{Limit: "51"}, "limit must be between 1 and 50"},
+		{nil, "1", "10", PageParams{Limit: "foo"}, "limit must be between 1 and 50"},
+		{nil, "", "", PageParams{Cursor: "foo"}, "invalid cursor"},
+		{nil, "1", "10", PageParams{Cursor: util.FromHeightRangeCursor(*next)}, "can only provide either cursor or start and end height range"},
+		{[]string{"1"}, "", "", PageParams{Limit: "10"}, "limit and cursor can only be provided with a start and end height range"},
+	}
+
+	for i, test := range tests {
+		err := getBlock.ParsePage(test.heights, test.start, test.end, test.page)
+		assert.EqualError(t, err, test.outErr, fmt.Sprintf("test #%d -> %v", i, test))
+	}
+}
@@ -9,6 +9,9 @@ import (
 
 const eventTypeQuery = "type"
 const blockQuery = "block_ids"
+const contractAddressQuery = "contract_address"
+const fieldNameQuery = "field_name"
+const fieldValueQuery = "field_value"
 const MaxEventRequestHeightRange = 250
 
 type GetEvents struct {
@@ -16,18 +19,67 @@ type GetEvents struct {
 	EndHeight   uint64
 	Type        string
 	BlockIDs    []flow.Identifier
+
+	// optional filters, only supported for height ranges
+	ContractAddress *flow.Address
+	TransactionID   *flow.Identifier
+	FieldName       string
+	FieldValue      string
+
+	// requested page of the start and end height range
+	Page HeightRangePage
+}
+
+// EventFilterParams are the raw values of the optional event filter query parameters.
+type EventFilterParams struct {
+	ContractAddress string
+	TransactionID   string
+	FieldName       string
+	FieldValue      string
 }
 
 func (g *GetEvents) Build(r *Request) error {
-	return g.Parse(
+	return g.ParsePage(
 		r.GetQueryParam(eventTypeQuery),
 		r.GetQueryParam(startHeightQuery),
 		r.GetQueryParam(endHeightQuery),
 		r.GetQueryParams(blockQuery),
+		EventFilterParams{
+			ContractAddress: r.GetQueryParam(contractAddressQuery),
+			TransactionID:   r.GetQueryParam(transactionIDQuery),
+			FieldName:       r.GetQueryParam(fieldNameQuery),
+			FieldValue:      r.GetQueryParam(fieldValueQuery),
+		},
+		PageParams{
+			Limit:  r.GetQueryParam(limitQuery),
+			Cursor: r.GetQueryParam(cursorQuery),
+		},
 	)
 }
 
 func (g *GetEvents) Parse(rawType string, rawStart string, rawEnd string, rawBlockIDs []string) error {
+	return g.ParseWithFilter(rawType, rawStart, rawEnd, rawBlockIDs, EventFilterParams{})
+}
+
+// Filtered returns true if the request filters events by more than the event type.
+func (g *GetEvents) Filtered() bool {
+	return g.ContractAddress != nil || g.TransactionID != nil || g.FieldName != ""
+}
+
+func (g *GetEvents) ParseWithFilter(rawType string, rawStart string, rawEnd string, rawBlockIDs []string, rawFilter EventFilterParams) error {
+	return g.ParsePage(rawType, rawStart, rawEnd, rawBlockIDs, rawFilter, PageParams{})
+}
+
+// ParsePage parses a request for events by block IDs or by a start and end height range, of which
+// the events of a page of at most the requested limit of heights are returned.
+func (g *GetEvents) ParsePage(
+	rawType string,
+	rawStart string,
+	rawEnd string,
+	rawBlockIDs []string,
+	rawFilter EventFilterParams,
+	rawPage PageParams,
+) error {
 	var height Height
 	err := height.Parse(rawStart)
 	if err != nil {
@@ -47,6 +99,21 @@ func (g *GetEvents) Parse(rawType string, rawStart string, rawEnd string, rawBlo
 	}
 	g.BlockIDs = blockIDs.Flow()
 
+	err = g.Page.Parse(rawPage, MaxEventRequestHeightRange)
+	if err != nil {
+		return err
+	}
+	if len(blockIDs) > 0 && (rawPage.Limit != "" || rawPage.Cursor != "") {
+		return fmt.Errorf("limit and cursor can only be provided with a start and end height range")
+	}
+	if g.Page.Cursor != nil {
+		if g.StartHeight != EmptyHeight || g.EndHeight != EmptyHeight {
+			return fmt.Errorf("can only provide either cursor or start and end height range")
+		}
+		g.StartHeight = g.Page.Cursor.StartHeight
+		g.EndHeight = g.Page.Cursor.EndHeight
+	}
+
 	// if both height and one or both of start and end height are provided
 	if len(blockIDs) > 0 && (g.StartHeight != EmptyHeight || g.EndHeight != EmptyHeight) {
 		return fmt.Errorf("can only provide either block IDs or start and end height range")
@@ -57,18 +124,26 @@ func (g *GetEvents) Parse(rawType string, rawStart string, rawEnd string, rawBlo
 		return fmt.Errorf("must provide either block IDs or start and end height range")
 	}
 
-	g.Type = rawType
-	if g.Type == "" {
-		return fmt.Errorf("event type must be provided")
+	err = g.parseFilter(rawFilter)
+	if err != nil {
+		return err
 	}
 
-	// match basic format A.address.contract.event (ignore err since regex will always compile)
-	basic, _ := regexp.MatchString(`[A-Z]\.[a-f0-9]{16}\.[\w+]*\.[\w+]*`, g.Type)
-	// match core events flow.event
-	core, _ := regexp.MatchString(`flow\.[\w]*`, g.Type)
+	if g.Filtered() && len(blockIDs) > 0 {
+		return fmt.Errorf("filters can only be used with a start and end height range")
+	}
 
-	if !core && !basic {
-		return fmt.Errorf("invalid event type format")
+	g.Type = rawType
+	// the event type is optional when filtering by contract address or transaction
+	if g.Type != "" || (g.ContractAddress == nil && g.TransactionID == nil) {
+		err = validateEventType(g.Type)
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.FieldName != "" && g.Type == "" {
+		return fmt.Errorf("event type must be provided to filter by field value")
 	}
 
 	// validate start end height option
@@ -76,11 +151,65 @@ func (g *GetEvents) Parse(rawType string, rawStart string, rawEnd string, rawBlo
 		if g.StartHeight > g.EndHeight {
 			return fmt.Errorf("start height must be less than or equal to end height")
 		}
-		// check if range exceeds maximum but only if end is not equal to special value which is not known yet
-		if g.EndHeight-g.StartHeight >= MaxEventRequestHeightRange && g.EndHeight != FinalHeight && g.EndHeight != SealedHeight {
+		// check if range exceeds maximum but only if end is not equal to special value which is not known yet,
+		// ranges of any size can be requested in pages by providing a limit
+		if !g.Page.Paginated() && g.EndHeight-g.StartHeight >= MaxEventRequestHeightRange && g.EndHeight != FinalHeight && g.EndHeight != SealedHeight {
 			return fmt.Errorf("height range %d exceeds maximum allowed of %d", g.EndHeight-g.StartHeight, MaxEventRequestHeightRange)
 		}
 	}
 
 	return nil
 }
+
+// parseFilter parses the optional event filters.
+func (g *GetEvents) parseFilter(raw EventFilterParams) error {
+	g.ContractAddress = nil
+	g.TransactionID = nil
+
+	if raw.ContractAddress != "" {
+		var address Address
+		err := address.Parse(raw.ContractAddress)
+		if err != nil {
+			return fmt.Errorf("invalid contract address: %w", err)
+		}
+		contractAddress := address.Flow()
+		g.ContractAddress = &contractAddress
+	}
+
+	if raw.TransactionID != "" {
+		var id ID
+		err := id.Parse(raw.TransactionID)
+		if err != nil {
+			return fmt.Errorf("invalid transaction ID: %w", err)
+		}
+		txID := id.Flow()
+		g.TransactionID = &txID
+	}
+
+	if (raw.FieldName == "") != (raw.FieldValue == "") {
+		return fmt.Errorf("field name and field value must be provided together")
+	}
+	g.FieldName = raw.FieldName
+	g.FieldValue = raw.FieldValue
+
+	return nil
+}
+
+// validateEventType checks that the event type is provided and is either in the account event
+// format A.address.contract.event or the core event format flow.event
+func validateEventType(eventType string) error {
+	if eventType == "" {
+		return fmt.Errorf("event type must be provided")
+	}
+
+	// match basic format A.address.contract.event (ignore err since regex will always compile)
+	basic, _ := regexp.MatchString(`[A-Z]\.[a-f0-9]{16}\.[\w+]*\.[\w+]*`, eventType)
+	// match core events flow.event
+	core, _ := regexp.MatchString(`flow\.[\w]*`, eventType)
+
+	if !core && !basic {
+		return fmt.Errorf("invalid event type format")
+	}
+
+	return nil
+}
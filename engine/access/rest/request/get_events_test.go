@@ -34,6 +34,28 @@ func TestGetEvents_InvalidParse(t *testing.T) {
 	}
 }
 
+func TestGetEvents_ParsePage(t *testing.T) {
+	var getEvents GetEvents
+
+	event := "A.f8d6e0586b0a20c7.Foo.Bar"
+	// without a limit or cursor the whole range is returned
+	err := getEvents.ParsePage(event, "0", "100", nil, EventFilterParams{}, PageParams{})
+	assert.NoError(t, err)
+	assert.False(t, getEvents.Page.Paginated())
+	assert.Equal(t, uint64(100), getEvents.Page.End(getEvents.StartHeight, getEvents.EndHeight))
+
+	err = getEvents.ParsePage(event, "0", "5000", nil, EventFilterParams{}, PageParams{Limit: "250"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(MaxEventRequestHeightRange), getEvents.Page.Limit)
+	assert.Equal(t, uint64(249), getEvents.Page.End(getEvents.StartHeight, getEvents.EndHeight))
+
+	err = getEvents.ParsePage(event, "", "", []string{"7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7"}, EventFilterParams{}, PageParams{Limit: "10"})
+	assert.EqualError(t, err, "limit and cursor can only be provided with a start and end height range")
+
+	err = getEvents.ParsePage(event, "0", "10", nil, EventFilterParams{}, PageParams{Limit: "251"})
+	assert.EqualError(t, err, "limit must be between 1 and 250")
+}
+
 func TestGetEvents_ValidParse(t *testing.T) {
 	var getEvents GetEvents
 
@@ -60,3 +82,44 @@ func TestGetEvents_ValidParse(t *testing.T) {
 	assert.Equal(t, getEvents.BlockIDs[1].String(), "2ab81061b12d95fb81f2923001e340bc808e67e1eaae3c62479057cc14eb57fd")
 
 }
+
+func TestGetEvents_ParseWithFilter(t *testing.T) {
+	var getEvents GetEvents
+
+	address := "f8d6e0586b0a20c7"
+	txID := "7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7"
+
+	// the event type is optional when filtering by contract address or transaction
+	err := getEvents.ParseWithFilter("", "5", "10", nil, EventFilterParams{ContractAddress: "0x" + address})
+	assert.NoError(t, err)
+	assert.True(t, getEvents.Filtered())
+	assert.Equal(t, address, getEvents.ContractAddress.Hex())
+
+	err = getEvents.ParseWithFilter("A.f8d6e0586b0a20c7.Foo.Bar", "5", "10", nil, EventFilterParams{
+		TransactionID: txID,
+		FieldName:     "to",
+		FieldValue:    address,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, txID, getEvents.TransactionID.String())
+	assert.Equal(t, "to", getEvents.FieldName)
+	assert.Equal(t, address, getEvents.FieldValue)
+
+	tests := []struct {
+		eventType string
+		filter    EventFilterParams
+		err       string
+	}{
+		{"", EventFilterParams{}, "event type must be provided"},
+		{"", EventFilterParams{FieldName: "to", FieldValue: address}, "event type must be provided"},
+		{"", EventFilterParams{ContractAddress: address, FieldName: "to", FieldValue: address}, "event type must be provided to filter by field value"},
+		{"flow.AccountCreated", EventFilterParams{FieldName: "address"}, "field name and field value must be provided together"},
+		{"", EventFilterParams{ContractAddress: "123"}, "invalid contract address: invalid address"},
+		{"", EventFilterParams{TransactionID: "123"}, "invalid transaction ID: invalid ID format"},
+	}
+
+	for i, test := range tests {
+		err := getEvents.ParseWithFilter(test.eventType, "5", "10", nil, test.filter)
+		assert.EqualError(t, err, test.err, fmt.Sprintf("test #%d failed", i))
+	}
+}
@@ -0,0 +1,34 @@
+package request
+
+import (
+	"fmt"
+)
+
+type GetExecutionDataByBlockID struct {
+	GetByIDRequest
+}
+
+type GetExecutionDataByHeight struct {
+	Height uint64
+}
+
+func (g *GetExecutionDataByHeight) Build(r *Request) error {
+	return g.Parse(
+		r.GetQueryParam(blockHeightQuery),
+	)
+}
+
+func (g *GetExecutionDataByHeight) Parse(rawHeight string) error {
+	var height Height
+	err := height.Parse(rawHeight)
+	if err != nil {
+		return err
+	}
+
+	if height.Flow() == EmptyHeight {
+		return fmt.Errorf("block height must be provided")
+	}
+	g.Height = height.Flow()
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package request
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/engine/access/rest/util"
+)
+
+// PageParams are the raw values of the pagination query parameters of height range requests.
+type PageParams struct {
+	Limit  string
+	Cursor string
+}
+
+// HeightRangePage is the requested page of a height range. Ranges spanning more heights than the
+// limit are returned in several pages, the cursor of each page pointing to the rest of the range.
+// Pagination is only enabled if a limit or cursor is provided, otherwise the whole range is returned.
+type HeightRangePage struct {
+	Limit  uint64                  // maximum number of heights in the page, 0 if pagination is disabled
+	Cursor *util.HeightRangeCursor // position of the page, nil for the first page
+}
+
+// Parse parses the pagination parameters, with a limit of at most maxLimit heights which is also the
+// default limit once a cursor is provided.
+func (p *HeightRangePage) Parse(raw PageParams, maxLimit uint64) error {
+	p.Limit = 0
+	if raw.Cursor != "" {
+		p.Limit = maxLimit
+	}
+	if raw.Limit != "" {
+		limit, err := util.ToUint64(raw.Limit)
+		if err != nil || limit == 0 || limit > maxLimit {
+			return fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+		p.Limit = limit
+	}
+
+	p.Cursor = nil
+	if raw.Cursor != "" {
+		cursor, err := util.ToHeightRangeCursor(raw.Cursor)
+		if err != nil {
+			return err
+		}
+		p.Cursor = &cursor
+	}
+
+	return nil
+}
+
+// Paginated returns true if the range is returned in pages.
+func (p *HeightRangePage) Paginated() bool {
+	return p.Limit > 0
+}
+
+// End returns the last height of the page of the range from start to end height.
+func (p *HeightRangePage) End(startHeight uint64, endHeight uint64) uint64 {
+	if p.Paginated() && endHeight-startHeight >= p.Limit {
+		return startHeight + p.Limit - 1
+	}
+	return endHeight
+}
+
+// Next returns the cursor of the page following the page of the range from start to end height,
+// or nil if it is the last page.
+func (p *HeightRangePage) Next(startHeight uint64, endHeight uint64) *util.HeightRangeCursor {
+	pageEnd := p.End(startHeight, endHeight)
+	if pageEnd == endHeight {
+		return nil
+	}
+	return &util.HeightRangeCursor{
+		StartHeight: pageEnd + 1,
+		EndHeight:   endHeight,
+	}
+}
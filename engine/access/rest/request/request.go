@@ -54,6 +54,18 @@ func (rd *Request) GetAccountRequest() (GetAccount, error) {
 	return req, err
 }
 
+func (rd *Request) GetAccountKeyRequest() (GetAccountKey, error) {
+	var req GetAccountKey
+	err := req.Build(rd)
+	return req, err
+}
+
+func (rd *Request) GetAccountTransactionsRequest() (GetAccountTransactions, error) {
+	var req GetAccountTransactions
+	err := req.Build(rd)
+	return req, err
+}
+
 func (rd *Request) GetExecutionResultByBlockIDsRequest() (GetExecutionResultByBlockIDs, error) {
 	var req GetExecutionResultByBlockIDs
 	err := req.Build(rd)
@@ -66,6 +78,18 @@ func (rd *Request) GetExecutionResultRequest() (GetExecutionResult, error) {
 	return req, err
 }
 
+func (rd *Request) GetExecutionDataByBlockIDRequest() (GetExecutionDataByBlockID, error) {
+	var req GetExecutionDataByBlockID
+	err := req.Build(rd)
+	return req, err
+}
+
+func (rd *Request) GetExecutionDataByHeightRequest() (GetExecutionDataByHeight, error) {
+	var req GetExecutionDataByHeight
+	err := req.Build(rd)
+	return req, err
+}
+
 func (rd *Request) GetTransactionRequest() (GetTransaction, error) {
 	var req GetTransaction
 	err := req.Build(rd)
@@ -90,6 +114,18 @@ func (rd *Request) CreateTransactionRequest() (CreateTransaction, error) {
 	return req, err
 }
 
+func (rd *Request) SimulateTransactionRequest() (SimulateTransaction, error) {
+	var req SimulateTransaction
+	err := req.Build(rd)
+	return req, err
+}
+
+func (rd *Request) SubscribeRequest() (Subscribe, error) {
+	var req Subscribe
+	err := req.Build(rd)
+	return req, err
+}
+
 func (rd *Request) Expands(field string) bool {
 	return rd.ExpandFields[field]
 }
@@ -0,0 +1,26 @@
+package request
+
+import (
+	"io"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+type SimulateTransaction struct {
+	Transaction flow.TransactionBody
+}
+
+func (s *SimulateTransaction) Build(r *Request) error {
+	return s.Parse(r.Body, r.Chain)
+}
+
+func (s *SimulateTransaction) Parse(rawTransaction io.Reader, chain flow.Chain) error {
+	var tx Transaction
+	err := tx.ParseUnsigned(rawTransaction, chain)
+	if err != nil {
+		return err
+	}
+
+	s.Transaction = tx.Flow()
+	return nil
+}
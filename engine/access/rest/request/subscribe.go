@@ -0,0 +1,142 @@
+package request
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const topicQuery = "topic"
+const blockStatusQuery = "block_status"
+const eventTypesQuery = "event_types"
+const transactionIDQuery = "transaction_id"
+
+const MaxSubscriptionEventTypes = 50
+
+// SubscriptionTopic is the kind of data streamed to a websocket subscriber
+type SubscriptionTopic string
+
+const (
+	BlocksTopic                  SubscriptionTopic = "blocks"
+	EventsTopic                  SubscriptionTopic = "events"
+	TransactionStatusesTopic     SubscriptionTopic = "transaction_statuses"
+	SendTransactionStatusesTopic SubscriptionTopic = "send_and_subscribe_transaction_statuses"
+	ExecutionDataTopic           SubscriptionTopic = "execution_data"
+)
+
+type Subscribe struct {
+	Topic SubscriptionTopic
+	// Sealed is true if only sealed blocks should be streamed, otherwise finalized blocks are streamed.
+	Sealed bool
+	// StartHeight is the first height to stream, or EmptyHeight to start from the latest block.
+	StartHeight   uint64
+	EventTypes    []string
+	TransactionID flow.Identifier
+}
+
+func (s *Subscribe) Build(r *Request) error {
+	return s.Parse(
+		r.GetQueryParam(topicQuery),
+		r.GetQueryParam(blockStatusQuery),
+		r.GetQueryParam(startHeightQuery),
+		r.GetQueryParams(eventTypesQuery),
+		r.GetQueryParam(transactionIDQuery),
+	)
+}
+
+func (s *Subscribe) Parse(
+	rawTopic string,
+	rawBlockStatus string,
+	rawStartHeight string,
+	rawEventTypes []string,
+	rawTransactionID string,
+) error {
+	s.Topic = SubscriptionTopic(rawTopic)
+
+	switch s.Topic {
+	case BlocksTopic:
+		switch rawBlockStatus {
+		case "", final, "finalized":
+			s.Sealed = false
+		case sealed:
+			s.Sealed = true
+		default:
+			return fmt.Errorf("invalid block status, must be either %s or %s", sealed, "finalized")
+		}
+
+		return s.parseStartHeight(rawStartHeight)
+
+	case EventsTopic:
+		if len(rawEventTypes) == 0 {
+			return fmt.Errorf("at least one event type must be provided")
+		}
+		if len(rawEventTypes) > MaxSubscriptionEventTypes {
+			return fmt.Errorf("at most %d event types can be subscribed to at a time", MaxSubscriptionEventTypes)
+		}
+
+		uniqueTypes := make(map[string]bool, len(rawEventTypes))
+		s.EventTypes = make([]string, 0, len(rawEventTypes))
+		for _, eventType := range rawEventTypes {
+			err := validateEventType(eventType)
+			if err != nil {
+				return err
+			}
+
+			if !uniqueTypes[eventType] {
+				uniqueTypes[eventType] = true
+				s.EventTypes = append(s.EventTypes, eventType)
+			}
+		}
+
+		// events are only available for sealed blocks
+		s.Sealed = true
+		return s.parseStartHeight(rawStartHeight)
+
+	case TransactionStatusesTopic:
+		if rawTransactionID == "" {
+			return fmt.Errorf("transaction ID must be provided")
+		}
+
+		var id ID
+		err := id.Parse(rawTransactionID)
+		if err != nil {
+			return err
+		}
+		s.TransactionID = id.Flow()
+
+		return nil
+
+	case SendTransactionStatusesTopic:
+		// the transaction is sent by the client as the first message after the connection is upgraded
+		return nil
+
+	case ExecutionDataTopic:
+		// execution data is only available for sealed blocks
+		s.Sealed = true
+		return s.parseStartHeight(rawStartHeight)
+
+	case "":
+		return fmt.Errorf("subscription topic must be provided")
+
+	default:
+		return fmt.Errorf("invalid subscription topic, must be one of %s, %s, %s, %s or %s", BlocksTopic, EventsTopic, TransactionStatusesTopic, SendTransactionStatusesTopic, ExecutionDataTopic)
+	}
+}
+
+func (s *Subscribe) parseStartHeight(raw string) error {
+	var height Height
+	err := height.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid start height: %w", err)
+	}
+
+	// the special heights all mean streaming should start at the latest block
+	switch height.Flow() {
+	case SealedHeight, FinalHeight:
+		s.StartHeight = EmptyHeight
+	default:
+		s.StartHeight = height.Flow()
+	}
+
+	return nil
+}
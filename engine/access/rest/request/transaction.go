@@ -16,6 +16,15 @@ const maxAllowedScriptArguments = 100
 type Transaction flow.TransactionBody
 
 func (t *Transaction) Parse(raw io.Reader, chain flow.Chain) error {
+	return t.parse(raw, chain, true)
+}
+
+// ParseUnsigned parses a transaction that may not be signed yet, for example to simulate it.
+func (t *Transaction) ParseUnsigned(raw io.Reader, chain flow.Chain) error {
+	return t.parse(raw, chain, false)
+}
+
+func (t *Transaction) parse(raw io.Reader, chain flow.Chain, requireSignatures bool) error {
 	var tx models.TransactionsBody
 	err := parseBody(raw, &tx)
 	if err != nil {
@@ -40,7 +49,7 @@ func (t *Transaction) Parse(raw io.Reader, chain flow.Chain) error {
 	if tx.ReferenceBlockId == "" {
 		return fmt.Errorf("reference block not provided")
 	}
-	if len(tx.EnvelopeSignatures) == 0 {
+	if requireSignatures && len(tx.EnvelopeSignatures) == 0 {
 		return fmt.Errorf("envelope signatures not provided")
 	}
 
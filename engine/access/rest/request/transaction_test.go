@@ -135,3 +135,17 @@ func TestTransaction_ValidParse(t *testing.T) {
 	assert.Equal(t, tx["gas_limit"], fmt.Sprint(transaction.Flow().GasLimit))
 	assert.Equal(t, len(tx["authorizers"].([]string)), len(transaction.Flow().Authorizers))
 }
+
+func TestTransaction_ParseUnsigned(t *testing.T) {
+	unsigned := buildTransaction()
+	delete(unsigned, "envelope_signatures")
+
+	var tx Transaction
+	err := tx.Parse(transactionToReader(unsigned), flow.Testnet.Chain())
+	assert.EqualError(t, err, "envelope signatures not provided")
+
+	err = tx.ParseUnsigned(transactionToReader(unsigned), flow.Testnet.Chain())
+	assert.NoError(t, err)
+	assert.Empty(t, tx.Flow().EnvelopeSignatures)
+	assert.Equal(t, unsigned["payer"], tx.Flow().Payer.String())
+}
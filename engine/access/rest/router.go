@@ -9,15 +9,20 @@ import (
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine/access/rest/middleware"
 	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 )
 
-func newRouter(backend access.API, logger zerolog.Logger, chain flow.Chain) (*mux.Router, error) {
+func newRouter(backend access.API, stateStream state_stream.API, quotas *rpc.Quotas, logger zerolog.Logger, chain flow.Chain, subscriptionConfig SubscriptionConfig) (*mux.Router, error) {
 	router := mux.NewRouter().StrictSlash(true)
 	v1SubRouter := router.PathPrefix("/v1").Subrouter()
 
 	// common middleware for all request
 	v1SubRouter.Use(middleware.LoggingMiddleware(logger))
+	if quotas != nil {
+		v1SubRouter.Use(middleware.QuotaMiddleware(quotas))
+	}
 	v1SubRouter.Use(middleware.QueryExpandable())
 	v1SubRouter.Use(middleware.QuerySelect())
 
@@ -31,6 +36,22 @@ func newRouter(backend access.API, logger zerolog.Logger, chain flow.Chain) (*mu
 			Name(r.Name).
 			Handler(h)
 	}
+
+	for _, r := range ExecutionDataRoutes {
+		h := NewHandler(logger, backend, r.Handler.withStateStream(stateStream), linkGenerator, chain)
+		v1SubRouter.
+			Methods(r.Method).
+			Path(r.Pattern).
+			Name(r.Name).
+			Handler(h)
+	}
+
+	v1SubRouter.
+		Methods(http.MethodGet).
+		Path("/subscribe").
+		Name("subscribe").
+		Handler(NewSubscribeHandler(logger, backend, stateStream, linkGenerator, chain, subscriptionConfig))
+
 	return router, nil
 }
 
@@ -51,6 +72,11 @@ var Routes = []route{{
 	Pattern: "/transactions",
 	Name:    "createTransaction",
 	Handler: CreateTransaction,
+}, {
+	Method:  http.MethodPost,
+	Pattern: "/transactions/simulate",
+	Name:    "simulateTransaction",
+	Handler: SimulateTransaction,
 }, {
 	Method:  http.MethodGet,
 	Pattern: "/transaction_results/{id}",
@@ -96,6 +122,26 @@ var Routes = []route{{
 	Pattern: "/accounts/{address}",
 	Name:    "getAccount",
 	Handler: GetAccount,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/accounts/{address}/keys",
+	Name:    "getAccountKeys",
+	Handler: GetAccountKeys,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/accounts/{address}/keys/{index}",
+	Name:    "getAccountKeyByIndex",
+	Handler: GetAccountKeyByIndex,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/accounts/{address}/balance",
+	Name:    "getAccountBalance",
+	Handler: GetAccountBalance,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/accounts/{address}/transactions",
+	Name:    "getAccountTransactions",
+	Handler: GetAccountTransactions,
 }, {
 	Method:  http.MethodGet,
 	Pattern: "/events",
@@ -107,3 +153,24 @@ var Routes = []route{{
 	Name:    "getNetworkParameters",
 	Handler: GetNetworkParameters,
 }}
+
+type executionDataRoute struct {
+	Name    string
+	Method  string
+	Pattern string
+	Handler ExecutionDataHandlerFunc
+}
+
+// ExecutionDataRoutes are the routes serving execution data, which are only supported if the
+// node serves the state stream API.
+var ExecutionDataRoutes = []executionDataRoute{{
+	Method:  http.MethodGet,
+	Pattern: "/execution_data/{id}",
+	Name:    "getExecutionDataByBlockID",
+	Handler: GetExecutionDataByBlockID,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/execution_data",
+	Name:    "getExecutionDataByHeight",
+	Handler: GetExecutionDataByHeight,
+}}
@@ -8,13 +8,17 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 )
 
-// NewServer returns an HTTP server initialized with the REST API handler
-func NewServer(backend access.API, listenAddress string, logger zerolog.Logger, chain flow.Chain) (*http.Server, error) {
+// NewServer returns an HTTP server initialized with the REST API handler. Execution data endpoints
+// are only supported if a state stream API is provided, and per-client quotas are only enforced if
+// quotas are provided.
+func NewServer(backend access.API, stateStream state_stream.API, quotas *rpc.Quotas, listenAddress string, logger zerolog.Logger, chain flow.Chain, subscriptionConfig SubscriptionConfig) (*http.Server, error) {
 
-	router, err := newRouter(backend, logger, chain)
+	router, err := newRouter(backend, stateStream, quotas, logger, chain, subscriptionConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,493 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// DefaultSubscriptionPollInterval is the default interval at which the backend is checked for
+	// new data for each active subscription.
+	DefaultSubscriptionPollInterval = time.Second
+
+	// DefaultSubscriptionMaxStartHeightLag is the default maximum number of blocks the start height
+	// of a subscription may be behind the latest block.
+	DefaultSubscriptionMaxStartHeightLag = 3_600
+
+	// subscriptionWriteWait is the time allowed to write a single message to the client.
+	subscriptionWriteWait = 10 * time.Second
+
+	// subscriptionPongWait is the time allowed to read the next pong message from the client.
+	subscriptionPongWait = 60 * time.Second
+
+	// subscriptionPingPeriod is the interval at which pings are sent to the client. It must be less
+	// than subscriptionPongWait.
+	subscriptionPingPeriod = (subscriptionPongWait * 9) / 10
+)
+
+// SubscriptionConfig configures the subscriptions served over websockets by the REST API.
+type SubscriptionConfig struct {
+	// PollInterval is the interval at which the backend is checked for new data for each active
+	// subscription.
+	PollInterval time.Duration
+
+	// MaxStartHeightLag is the maximum number of blocks the start height of a subscription may be
+	// behind the latest block. Subscriptions starting further behind are rejected.
+	MaxStartHeightLag uint64
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c SubscriptionConfig) Validate() error {
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("subscription poll interval must be greater than 0")
+	}
+	return nil
+}
+
+// SubscribeHandler upgrades requests to websocket connections and streams data for the requested
+// topic to the client until either the client disconnects or the subscription completes.
+//
+// Supported topics are:
+//   - blocks: every new finalized or sealed block, starting at an optional start height
+//   - events: the events of the requested types for every new sealed block containing any,
+//     starting at an optional start height. Only available if the node serves execution data.
+//   - transaction_statuses: the transaction result each time the transaction's status changes.
+//     The connection is closed once the transaction is sealed or expired.
+//   - send_and_subscribe_transaction_statuses: same as transaction_statuses, for a transaction sent
+//     by the client as the first websocket message, using the request body of POST /transactions.
+//   - execution_data: the execution data of every new sealed block, starting at an optional start
+//     height. Only available if the node serves execution data.
+//
+// Each websocket message contains a single JSON encoded model, using the same shapes as the
+// equivalent request/response endpoints, and honors the `select` and `expand` query parameters.
+// Errors are sent as a JSON encoded models.ModelError before the connection is closed.
+type SubscribeHandler struct {
+	*Handler
+	upgrader    websocket.Upgrader
+	stateStream state_stream.API
+	config      SubscriptionConfig
+}
+
+func NewSubscribeHandler(
+	logger zerolog.Logger,
+	backend access.API,
+	stateStream state_stream.API,
+	generator models.LinkGenerator,
+	chain flow.Chain,
+	config SubscriptionConfig,
+) *SubscribeHandler {
+	return &SubscribeHandler{
+		Handler: NewHandler(logger, backend, nil, generator, chain),
+		upgrader: websocket.Upgrader{
+			HandshakeTimeout: subscriptionWriteWait,
+			// the REST API allows all origins, see the CORS configuration of the server
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		stateStream: stateStream,
+		config:      config,
+	}
+}
+
+// ServeHTTP validates the subscription request, upgrades the connection to a websocket and streams
+// the subscription data to the client.
+func (h *SubscribeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	errLog := h.logger.With().Str("request_url", r.URL.String()).Logger()
+
+	decoratedRequest := request.Decorate(r, h.chain)
+
+	// validate the request before upgrading, so invalid requests receive a regular http error
+	req, err := decoratedRequest.SubscribeRequest()
+	if err != nil {
+		h.errorHandler(w, NewBadRequestError(err), errLog)
+		return
+	}
+
+	if (req.Topic == request.EventsTopic || req.Topic == request.ExecutionDataTopic) && h.stateStream == nil {
+		h.errorHandler(w, status.Error(codes.Unimplemented, "execution data is not available on this node"), errLog)
+		return
+	}
+
+	err = h.checkStartHeight(r.Context(), req)
+	if err != nil {
+		h.errorHandler(w, err, errLog)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already responded to the client with an http error
+		errLog.Debug().Err(err).Msg("failed to upgrade connection to websocket")
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	sub := &subscription{
+		conn:    conn,
+		request: decoratedRequest,
+		log:     errLog,
+	}
+
+	// the transaction must be read before keepAlive starts discarding the client's messages
+	var tx *flow.TransactionBody
+	if req.Topic == request.SendTransactionStatusesTopic {
+		tx, err = h.readTransaction(conn)
+		if err != nil {
+			sub.fail(NewBadRequestError(err))
+			return
+		}
+	}
+
+	go h.keepAlive(ctx, cancel, conn)
+
+	switch req.Topic {
+	case request.BlocksTopic:
+		err = h.streamBlocks(ctx, sub, req)
+	case request.EventsTopic:
+		err = h.streamEvents(ctx, sub, req)
+	case request.TransactionStatusesTopic:
+		txSub := h.backend.SubscribeTransactionStatuses(ctx, req.TransactionID)
+		err = h.streamTransactionStatuses(ctx, sub, txSub, req.TransactionID)
+	case request.SendTransactionStatusesTopic:
+		txSub := h.backend.SendAndSubscribeTransactionStatuses(ctx, tx)
+		err = h.streamTransactionStatuses(ctx, sub, txSub, tx.ID())
+	case request.ExecutionDataTopic:
+		err = h.streamExecutionData(ctx, sub, req)
+	}
+
+	if ctx.Err() != nil {
+		// client disconnected
+		return
+	}
+
+	if err != nil {
+		sub.fail(err)
+		return
+	}
+
+	sub.close(websocket.CloseNormalClosure, "subscription completed")
+}
+
+// checkStartHeight returns a bad request error if the requested start height is more than the
+// maximum lag behind the latest block, so that a single subscription can not make the node catch
+// up on an unbounded number of blocks.
+func (h *SubscribeHandler) checkStartHeight(ctx context.Context, req request.Subscribe) error {
+	if req.Topic == request.TransactionStatusesTopic || req.Topic == request.SendTransactionStatusesTopic || req.StartHeight == request.EmptyHeight {
+		return nil
+	}
+
+	latest, _, err := h.backend.GetLatestBlockHeader(ctx, req.Sealed)
+	if err != nil {
+		return err
+	}
+
+	if latest.Height > h.config.MaxStartHeightLag && req.StartHeight < latest.Height-h.config.MaxStartHeightLag {
+		return NewBadRequestError(fmt.Errorf("start height %d is more than %d blocks behind the latest block %d",
+			req.StartHeight, h.config.MaxStartHeightLag, latest.Height))
+	}
+
+	return nil
+}
+
+// readTransaction reads the transaction to send from the first message of the client.
+func (h *SubscribeHandler) readTransaction(conn *websocket.Conn) (*flow.TransactionBody, error) {
+	err := conn.SetReadDeadline(time.Now().Add(subscriptionPongWait))
+	if err != nil {
+		return nil, fmt.Errorf("could not set read deadline: %w", err)
+	}
+
+	_, r, err := conn.NextReader()
+	if err != nil {
+		return nil, fmt.Errorf("could not read transaction: %w", err)
+	}
+
+	var req request.CreateTransaction
+	err = req.Parse(r, h.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &req.Transaction, nil
+}
+
+// keepAlive sends pings to the client, and reads (and discards) any messages sent by the client.
+// The context is cancelled when the client closes the connection or stops responding to pings.
+func (h *SubscribeHandler) keepAlive(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn) {
+	defer cancel()
+
+	_ = conn.SetReadDeadline(time.Now().Add(subscriptionPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(subscriptionPongWait))
+	})
+
+	// reading is required to process control messages (pong and close) from the client
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(subscriptionPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(subscriptionWriteWait))
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// streamBlocks sends every new block with the requested status to the client.
+func (h *SubscribeHandler) streamBlocks(ctx context.Context, sub *subscription, req request.Subscribe) error {
+	return h.pollHeights(ctx, req, func(startHeight, endHeight uint64) error {
+		for height := startHeight; height <= endHeight; height++ {
+			block, err := getBlock(forHeight(height), sub.request, h.backend, h.linkGenerator)
+			if err != nil {
+				return err
+			}
+
+			err = sub.send(block)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// streamEvents sends the events matching any of the requested types to the client, grouped by
+// block. The events are read from the local execution data by the state stream, and blocks
+// without matching events are skipped.
+func (h *SubscribeHandler) streamEvents(ctx context.Context, sub *subscription, req request.Subscribe) error {
+	filter, err := state_stream.NewEventFilter(state_stream.DefaultEventFilterConfig, h.chain, req.EventTypes, nil, nil)
+	if err != nil {
+		return NewBadRequestError(err)
+	}
+
+	// the state stream starts at the latest block with execution data if no start height is provided
+	startHeight := req.StartHeight
+	if startHeight == request.EmptyHeight {
+		startHeight = 0
+	}
+
+	eventsSub := h.stateStream.SubscribeEvents(ctx, flow.ZeroID, startHeight, filter)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case v, ok := <-eventsSub.Channel():
+			if !ok {
+				return eventsSub.Err()
+			}
+
+			resp, ok := v.(*state_stream.EventsResponse)
+			if !ok {
+				return fmt.Errorf("unexpected response type: %T", v)
+			}
+
+			if len(resp.Events) == 0 {
+				continue
+			}
+
+			header, _, err := h.backend.GetBlockHeaderByID(ctx, resp.BlockID)
+			if err != nil {
+				return err
+			}
+
+			var response models.BlockEvents
+			response.Build(flow.BlockEvents{
+				BlockID:        resp.BlockID,
+				BlockHeight:    resp.Height,
+				BlockTimestamp: header.Timestamp,
+				Events:         resp.Events,
+			})
+
+			err = sub.send(response)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// streamTransactionStatuses forwards the transaction results received from the backend
+// subscription to the client. The backend only sends a result when the transaction's status
+// changes, and closes the subscription once the transaction is sealed or expired.
+func (h *SubscribeHandler) streamTransactionStatuses(ctx context.Context, sub *subscription, txSub state_stream.Subscription, txID flow.Identifier) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case v, ok := <-txSub.Channel():
+			if !ok {
+				return txSub.Err()
+			}
+
+			txr, ok := v.(*access.TransactionResult)
+			if !ok {
+				return fmt.Errorf("unexpected response type: %T", v)
+			}
+
+			var response models.TransactionResult
+			response.Build(txr, txID, h.linkGenerator)
+
+			err := sub.send(response)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// streamExecutionData forwards the execution data of every sealed block received from the state
+// stream subscription to the client.
+func (h *SubscribeHandler) streamExecutionData(ctx context.Context, sub *subscription, req request.Subscribe) error {
+	// the state stream starts at the latest block with execution data if no start height is provided
+	startHeight := req.StartHeight
+	if startHeight == request.EmptyHeight {
+		startHeight = 0
+	}
+
+	execDataSub := h.stateStream.SubscribeExecutionData(ctx, flow.ZeroID, startHeight)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case v, ok := <-execDataSub.Channel():
+			if !ok {
+				return execDataSub.Err()
+			}
+
+			resp, ok := v.(*state_stream.ExecutionDataResponse)
+			if !ok {
+				return fmt.Errorf("unexpected response type: %T", v)
+			}
+
+			var response models.BlockExecutionData
+			err := response.Build(resp.ExecutionData, h.linkGenerator)
+			if err != nil {
+				return err
+			}
+
+			err = sub.send(response)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// pollHeights periodically checks for new finalized or sealed blocks, and calls process with the
+// range of heights which have not been processed yet. Processing starts at the requested start
+// height, or at the latest block if no start height was requested.
+func (h *SubscribeHandler) pollHeights(ctx context.Context, req request.Subscribe, process func(startHeight, endHeight uint64) error) error {
+	ticker := time.NewTicker(h.config.PollInterval)
+	defer ticker.Stop()
+
+	nextHeight := req.StartHeight
+	for {
+		latest, _, err := h.backend.GetLatestBlockHeader(ctx, req.Sealed)
+		if err != nil {
+			return err
+		}
+
+		if nextHeight == request.EmptyHeight {
+			nextHeight = latest.Height
+		}
+
+		if nextHeight <= latest.Height {
+			err = process(nextHeight, latest.Height)
+			if err != nil {
+				return err
+			}
+			nextHeight = latest.Height + 1
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// subscription wraps a websocket connection used to stream responses to a single client
+type subscription struct {
+	conn    *websocket.Conn
+	request *request.Request
+	log     zerolog.Logger
+}
+
+// send applies the request's select filter to the response, and writes it to the client as JSON
+func (s *subscription) send(response interface{}) error {
+	filtered, err := util.SelectFilter(response, s.request.Selects())
+	if err != nil {
+		return err
+	}
+
+	err = s.conn.SetWriteDeadline(time.Now().Add(subscriptionWriteWait))
+	if err != nil {
+		return fmt.Errorf("could not set write deadline: %w", err)
+	}
+
+	err = s.conn.WriteJSON(filtered)
+	if err != nil {
+		return fmt.Errorf("could not write response: %w", err)
+	}
+
+	return nil
+}
+
+// fail sends the error to the client and closes the connection
+func (s *subscription) fail(err error) {
+	returnCode, msg := errorToStatus(err, s.log)
+
+	sendErr := s.send(models.ModelError{
+		Code:    int32(returnCode),
+		Message: msg,
+	})
+	if sendErr != nil {
+		s.log.Debug().Err(sendErr).Msg("failed to send error to client")
+		return
+	}
+
+	closeCode := websocket.CloseInternalServerErr
+	if returnCode < http.StatusInternalServerError {
+		closeCode = websocket.ClosePolicyViolation
+	}
+	s.close(closeCode, "subscription failed")
+}
+
+// close sends a close message to the client
+func (s *subscription) close(code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	err := s.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(subscriptionWriteWait))
+	if err != nil {
+		s.log.Debug().Err(err).Msg("failed to send close message to client")
+	}
+}
@@ -0,0 +1,423 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	mocks "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	statestreammock "github.com/onflow/flow-go/engine/access/state_stream/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func subscribeURL(t *testing.T, server *httptest.Server, params map[string]string) string {
+	u, err := url.Parse(strings.Replace(server.URL, "http", "ws", 1) + "/v1/subscribe")
+	require.NoError(t, err)
+
+	q := u.Query()
+	for k, v := range params {
+		q.Add(k, v)
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func newSubscribeServer(t *testing.T, backend *mock.API, stateStream state_stream.API) *httptest.Server {
+	router, err := newRouter(backend, stateStream, nil, zerolog.Nop(), flow.Testnet.Chain(), testSubscriptionConfig)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	var msg map[string]interface{}
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+// transactionStatusSubscription returns a subscription which sends a result for each of the
+// given statuses before it is closed.
+func transactionStatusSubscription(t *testing.T, blockID flow.Identifier, statuses ...flow.TransactionStatus) *state_stream.SubscriptionImpl {
+	sub := state_stream.NewSubscription(len(statuses))
+	for _, status := range statuses {
+		err := sub.Send(context.Background(), &access.TransactionResult{
+			Status:  status,
+			BlockID: blockID,
+			Events:  []flow.Event{},
+		}, time.Second)
+		require.NoError(t, err)
+	}
+	sub.Close()
+	return sub
+}
+
+func TestSubscribeTransactionStatuses(t *testing.T) {
+	backend := &mock.API{}
+	server := newSubscribeServer(t, backend, nil)
+
+	txID := unittest.IdentifierFixture()
+	blockID := unittest.IdentifierFixture()
+
+	backend.Mock.
+		On("SubscribeTransactionStatuses", mocks.Anything, txID).
+		Return(transactionStatusSubscription(t, blockID,
+			flow.TransactionStatusPending,
+			flow.TransactionStatusFinalized,
+			flow.TransactionStatusSealed,
+		)).
+		Once()
+
+	conn, _, err := websocket.DefaultDialer.Dial(subscribeURL(t, server, map[string]string{
+		"topic":          "transaction_statuses",
+		"transaction_id": txID.String(),
+		"select":         "status,block_id",
+	}), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for _, expected := range []string{"Pending", "Finalized", "Sealed"} {
+		msg := readMessage(t, conn)
+		assert.Equal(t, map[string]interface{}{
+			"status":   expected,
+			"block_id": blockID.String(),
+		}, msg)
+	}
+
+	// the connection is closed normally once the transaction is sealed
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), "unexpected error: %v", err)
+
+	backend.AssertExpectations(t)
+}
+
+func TestSendAndSubscribeTransactionStatuses(t *testing.T) {
+	backend := &mock.API{}
+	server := newSubscribeServer(t, backend, nil)
+
+	tx := unittest.TransactionBodyFixture()
+	tx.PayloadSignatures = []flow.TransactionSignature{unittest.TransactionSignatureFixture()}
+	tx.Arguments = [][]uint8{}
+	blockID := unittest.IdentifierFixture()
+
+	backend.Mock.
+		On("SendAndSubscribeTransactionStatuses", mocks.Anything, mocks.MatchedBy(func(sent *flow.TransactionBody) bool {
+			return sent.ID() == tx.ID()
+		})).
+		Return(transactionStatusSubscription(t, blockID,
+			flow.TransactionStatusPending,
+			flow.TransactionStatusExpired,
+		)).
+		Once()
+
+	conn, _, err := websocket.DefaultDialer.Dial(subscribeURL(t, server, map[string]string{
+		"topic":  "send_and_subscribe_transaction_statuses",
+		"select": "status,block_id",
+	}), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(validCreateBody(tx)))
+
+	for _, expected := range []string{"Pending", "Expired"} {
+		msg := readMessage(t, conn)
+		assert.Equal(t, map[string]interface{}{
+			"status":   expected,
+			"block_id": blockID.String(),
+		}, msg)
+	}
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), "unexpected error: %v", err)
+
+	backend.AssertExpectations(t)
+}
+
+func TestSendAndSubscribeTransactionStatusesInvalidTransaction(t *testing.T) {
+	backend := &mock.API{}
+	server := newSubscribeServer(t, backend, nil)
+
+	conn, _, err := websocket.DefaultDialer.Dial(subscribeURL(t, server, map[string]string{
+		"topic": "send_and_subscribe_transaction_statuses",
+	}), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"script": "foo"}`)))
+
+	msg := readMessage(t, conn)
+	assert.Equal(t, float64(http.StatusBadRequest), msg["code"])
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), "unexpected error: %v", err)
+
+	backend.AssertNotCalled(t, "SendAndSubscribeTransactionStatuses", mocks.Anything, mocks.Anything)
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	backend := &mock.API{}
+	stateStream := statestreammock.NewAPI(t)
+	server := newSubscribeServer(t, backend, stateStream)
+
+	typeA := "A.179b6b1cb6755e31.Foo.Bar"
+	typeB := "flow.AccountCreated"
+
+	header := unittest.BlockHeaderFixture()
+	startHeight := header.Height - 2
+
+	backend.Mock.
+		On("GetLatestBlockHeader", mocks.Anything, true).
+		Return(header, flow.BlockStatusSealed, nil)
+	backend.Mock.
+		On("GetBlockHeaderByID", mocks.Anything, header.ID()).
+		Return(header, flow.BlockStatusSealed, nil).
+		Once()
+
+	// the state stream sends a response for every block, with the events matching the filter
+	txID := unittest.IdentifierFixture()
+	eventsSub := state_stream.NewSubscription(2)
+	for _, resp := range []*state_stream.EventsResponse{
+		{BlockID: unittest.IdentifierFixture(), Height: startHeight, Events: flow.EventsList{}},
+		{BlockID: header.ID(), Height: header.Height, Events: flow.EventsList{
+			unittest.EventFixture(flow.EventType(typeB), 0, 0, txID, 0),
+			unittest.EventFixture(flow.EventType(typeA), 0, 1, txID, 0),
+		}},
+	} {
+		require.NoError(t, eventsSub.Send(context.Background(), resp, time.Second))
+	}
+	eventsSub.Close()
+
+	stateStream.
+		On("SubscribeEvents", mocks.Anything, flow.ZeroID, startHeight, mocks.MatchedBy(func(filter state_stream.EventFilter) bool {
+			_, hasA := filter.EventTypes[flow.EventType(typeA)]
+			_, hasB := filter.EventTypes[flow.EventType(typeB)]
+			return len(filter.EventTypes) == 2 && hasA && hasB
+		})).
+		Return(eventsSub).
+		Once()
+
+	conn, _, err := websocket.DefaultDialer.Dial(subscribeURL(t, server, map[string]string{
+		"topic":        "events",
+		"event_types":  fmt.Sprintf("%s,%s", typeA, typeB),
+		"start_height": fmt.Sprintf("%d", startHeight),
+	}), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// only the block with events is sent, with events in emitted order
+	msg := readMessage(t, conn)
+	assert.Equal(t, header.ID().String(), msg["block_id"])
+	assert.Equal(t, fmt.Sprintf("%d", header.Height), msg["block_height"])
+	assert.NotEmpty(t, msg["block_timestamp"])
+
+	events, ok := msg["events"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, events, 2)
+	assert.Equal(t, typeB, events[0].(map[string]interface{})["type"])
+	assert.Equal(t, typeA, events[1].(map[string]interface{})["type"])
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), "unexpected error: %v", err)
+
+	backend.AssertExpectations(t)
+}
+
+func TestSubscribeEventsNotAvailable(t *testing.T) {
+	server := newSubscribeServer(t, &mock.API{}, nil)
+
+	_, resp, err := websocket.DefaultDialer.Dial(subscribeURL(t, server, map[string]string{
+		"topic":       "events",
+		"event_types": "flow.AccountCreated",
+	}), nil)
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
+
+func TestSubscribeBackendError(t *testing.T) {
+	backend := &mock.API{}
+	server := newSubscribeServer(t, backend, nil)
+
+	backend.Mock.
+		On("GetLatestBlockHeader", mocks.Anything, false).
+		Return(nil, flow.BlockStatusUnknown, fmt.Errorf("backend failure"))
+
+	conn, _, err := websocket.DefaultDialer.Dial(subscribeURL(t, server, map[string]string{
+		"topic": "blocks",
+	}), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	msg := readMessage(t, conn)
+	assert.Equal(t, map[string]interface{}{
+		"code":    float64(http.StatusInternalServerError),
+		"message": "internal server error",
+	}, msg)
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseInternalServerErr), "unexpected error: %v", err)
+}
+
+func TestSubscribeInvalidRequest(t *testing.T) {
+	backend := &mock.API{}
+	server := newSubscribeServer(t, backend, nil)
+
+	tests := []struct {
+		description string
+		params      map[string]string
+		message     string
+	}{
+		{
+			description: "missing topic",
+			params:      map[string]string{},
+			message:     `{"code":400,"message":"subscription topic must be provided"}`,
+		},
+		{
+			description: "invalid topic",
+			params:      map[string]string{"topic": "foo"},
+			message:     `{"code":400,"message":"invalid subscription topic, must be one of blocks, events, transaction_statuses, send_and_subscribe_transaction_statuses or execution_data"}`,
+		},
+		{
+			description: "invalid block status",
+			params:      map[string]string{"topic": "blocks", "block_status": "foo"},
+			message:     `{"code":400,"message":"invalid block status, must be either sealed or finalized"}`,
+		},
+		{
+			description: "missing event types",
+			params:      map[string]string{"topic": "events"},
+			message:     `{"code":400,"message":"at least one event type must be provided"}`,
+		},
+		{
+			description: "invalid event type",
+			params:      map[string]string{"topic": "events", "event_types": "foo"},
+			message:     `{"code":400,"message":"invalid event type format"}`,
+		},
+		{
+			description: "missing transaction ID",
+			params:      map[string]string{"topic": "transaction_statuses"},
+			message:     `{"code":400,"message":"transaction ID must be provided"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			_, resp, err := websocket.DefaultDialer.Dial(subscribeURL(t, server, test.params), nil)
+			require.ErrorIs(t, err, websocket.ErrBadHandshake)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			rr := httptest.NewRecorder()
+			_, err = rr.Body.ReadFrom(resp.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.message, rr.Body.String())
+		})
+	}
+}
+
+func TestSubscribeStartHeightTooOld(t *testing.T) {
+	backend := &mock.API{}
+	server := newSubscribeServer(t, backend, nil)
+
+	header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(DefaultSubscriptionMaxStartHeightLag + 100))
+	backend.Mock.
+		On("GetLatestBlockHeader", mocks.Anything, false).
+		Return(header, flow.BlockStatusFinalized, nil)
+
+	_, resp, err := websocket.DefaultDialer.Dial(subscribeURL(t, server, map[string]string{
+		"topic":        "blocks",
+		"start_height": "99",
+	}), nil)
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	rr := httptest.NewRecorder()
+	_, err = rr.Body.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{"code":400,"message":"start height 99 is more than %d blocks behind the latest block %d"}`,
+		DefaultSubscriptionMaxStartHeightLag, header.Height), rr.Body.String())
+}
+
+func TestSubscribeExecutionData(t *testing.T) {
+	backend := &mock.API{}
+	stateStream := statestreammock.NewAPI(t)
+	server := newSubscribeServer(t, backend, stateStream)
+
+	header := unittest.BlockHeaderFixture()
+	backend.Mock.
+		On("GetLatestBlockHeader", mocks.Anything, true).
+		Return(header, flow.BlockStatusSealed, nil)
+
+	// the subscription sends the execution data of two blocks before failing
+	blockIDs := unittest.IdentifierListFixture(2)
+	execDataSub := state_stream.NewSubscription(len(blockIDs))
+	for i, blockID := range blockIDs {
+		err := execDataSub.Send(context.Background(), &state_stream.ExecutionDataResponse{
+			Height:        header.Height + uint64(i),
+			ExecutionData: &execution_data.BlockExecutionData{BlockID: blockID},
+		}, time.Second)
+		require.NoError(t, err)
+	}
+	execDataSub.Fail(status.Error(codes.InvalidArgument, "start height is too low"))
+
+	stateStream.
+		On("SubscribeExecutionData", mocks.Anything, flow.ZeroID, header.Height).
+		Return(execDataSub).
+		Once()
+
+	conn, _, err := websocket.DefaultDialer.Dial(subscribeURL(t, server, map[string]string{
+		"topic":        "execution_data",
+		"start_height": fmt.Sprintf("%d", header.Height),
+	}), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for _, blockID := range blockIDs {
+		msg := readMessage(t, conn)
+		assert.Equal(t, blockID.String(), msg["block_id"])
+	}
+
+	// the subscription error is sent to the client before the connection is closed
+	msg := readMessage(t, conn)
+	assert.Equal(t, float64(http.StatusBadRequest), msg["code"])
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), "unexpected error: %v", err)
+}
+
+func TestSubscribeExecutionDataNotAvailable(t *testing.T) {
+	server := newSubscribeServer(t, &mock.API{}, nil)
+
+	_, resp, err := websocket.DefaultDialer.Dial(subscribeURL(t, server, map[string]string{
+		"topic": "execution_data",
+	}), nil)
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
@@ -25,10 +25,15 @@ const (
 	heightQueryParam          = "height"
 )
 
+var testSubscriptionConfig = SubscriptionConfig{
+	PollInterval:      DefaultSubscriptionPollInterval,
+	MaxStartHeightLag: DefaultSubscriptionMaxStartHeightLag,
+}
+
 func executeRequest(req *http.Request, backend *mock.API) (*httptest.ResponseRecorder, error) {
 	var b bytes.Buffer
 	logger := zerolog.New(&b)
-	router, err := newRouter(backend, logger, flow.Testnet.Chain())
+	router, err := newRouter(backend, nil, nil, logger, flow.Testnet.Chain(), testSubscriptionConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -65,3 +65,21 @@ func CreateTransaction(r *request.Request, backend access.API, link models.LinkG
 	response.Build(&req.Transaction, nil, link)
 	return response, nil
 }
+
+// SimulateTransaction executes the provided transaction against the latest sealed state without
+// submitting it, and returns the resources it used and the fee it would be charged.
+func SimulateTransaction(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.SimulateTransactionRequest()
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	result, err := backend.SimulateTransaction(r.Context(), &req.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.TransactionSimulation
+	response.Build(result)
+	return response, nil
+}
@@ -404,6 +404,108 @@ func TestCreateTransaction(t *testing.T) {
 	})
 }
 
+func simulateTransactionReq(body interface{}) *http.Request {
+	jsonBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", "/v1/transactions/simulate", bytes.NewBuffer(jsonBody))
+	return req
+}
+
+// unsignedCreateBody returns a valid create transaction body without any signatures.
+func unsignedCreateBody(tx flow.TransactionBody) map[string]interface{} {
+	tx.PayloadSignatures = []flow.TransactionSignature{unittest.TransactionSignatureFixture()}
+	body := validCreateBody(tx)
+	delete(body, "payload_signatures")
+	delete(body, "envelope_signatures")
+	return body
+}
+
+func TestSimulateTransaction(t *testing.T) {
+
+	t.Run("simulate unsigned transaction", func(t *testing.T) {
+		backend := &mock.API{}
+		tx := unittest.TransactionBodyFixture()
+		req := simulateTransactionReq(unsignedCreateBody(tx))
+
+		event := unittest.EventFixture(flow.EventAccountCreated, 0, 0, tx.ID(), 255)
+		payer := tx.Payer
+		authorizer := unittest.RandomAddressFixture()
+		result := &access.TransactionSimulationResult{
+			ComputationUsed: 12,
+			MemoryEstimate:  3456,
+			Events:          []flow.Event{event},
+			StorageDelta:    map[flow.Address]int64{payer: 120, authorizer: -8},
+			Fee:             1000,
+			BlockID:         unittest.IdentifierFixture(),
+			BlockHeight:     42,
+		}
+
+		backend.Mock.
+			On("SimulateTransaction", mocks.Anything, mocks.MatchedBy(func(simulated *flow.TransactionBody) bool {
+				return bytes.Equal(simulated.PayloadMessage(), tx.PayloadMessage()) &&
+					len(simulated.PayloadSignatures) == 0 &&
+					len(simulated.EnvelopeSignatures) == 0
+			})).
+			Return(result, nil)
+
+		first, second := payer, authorizer
+		firstDelta, secondDelta := 120, -8
+		if second.String() < first.String() {
+			first, second = second, first
+			firstDelta, secondDelta = secondDelta, firstDelta
+		}
+
+		expected := fmt.Sprintf(`
+			{
+			   "block_id":"%s",
+			   "block_height":"42",
+			   "computation_used":"12",
+			   "memory_estimate":"3456",
+			   "fee":"1000",
+			   "storage_delta":[
+				  {"address":"%s", "delta":"%d"},
+				  {"address":"%s", "delta":"%d"}
+			   ],
+			   "error_message":"",
+			   "events":[
+				  {
+					 "type":"%s",
+					 "transaction_id":"%s",
+					 "transaction_index":"0",
+					 "event_index":"0",
+					 "payload":"%s"
+				  }
+			   ]
+			}`,
+			result.BlockID, first, firstDelta, second, secondDelta,
+			event.Type, event.TransactionID, util.ToBase64(event.Payload))
+		assertOKResponse(t, req, expected, backend)
+	})
+
+	t.Run("simulation not supported", func(t *testing.T) {
+		backend := &mock.API{}
+		tx := unittest.TransactionBodyFixture()
+		req := simulateTransactionReq(unsignedCreateBody(tx))
+
+		backend.Mock.
+			On("SimulateTransaction", mocks.Anything, mocks.Anything).
+			Return(nil, status.Error(codes.Unimplemented, "not indexed"))
+
+		expected := `{"code":501, "message":"Not supported by this node: not indexed"}`
+		assertResponse(t, req, http.StatusNotImplemented, expected, backend)
+	})
+
+	t.Run("simulate invalid transaction", func(t *testing.T) {
+		backend := &mock.API{}
+		tx := unittest.TransactionBodyFixture()
+		body := unsignedCreateBody(tx)
+		body["script"] = ""
+		req := simulateTransactionReq(body)
+
+		expected := `{"code":400, "message":"script not provided"}`
+		assertResponse(t, req, http.StatusBadRequest, expected, backend)
+	})
+}
+
 func transactionResultFixture(tx flow.Transaction) *access.TransactionResult {
 	return &access.TransactionResult{
 		Status:     flow.TransactionStatusSealed,
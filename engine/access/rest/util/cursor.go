@@ -0,0 +1,61 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// FromAccountTransactionCursor encodes the cursor as an opaque URL safe string
+func FromAccountTransactionCursor(cursor flow.AccountTransactionCursor) string {
+	raw := make([]byte, 8, 8+flow.IdentifierLen)
+	binary.BigEndian.PutUint64(raw, cursor.BlockHeight)
+	raw = append(raw, cursor.TransactionID[:]...)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ToAccountTransactionCursor decodes a cursor encoded by FromAccountTransactionCursor
+func ToAccountTransactionCursor(cursorStr string) (flow.AccountTransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursorStr)
+	if err != nil || len(raw) != 8+flow.IdentifierLen {
+		return flow.AccountTransactionCursor{}, fmt.Errorf("invalid cursor") // hide error from user
+	}
+
+	return flow.AccountTransactionCursor{
+		BlockHeight:   binary.BigEndian.Uint64(raw[:8]),
+		TransactionID: flow.HashToID(raw[8:]),
+	}, nil
+}
+
+// HeightRangeCursor is the position of the next page of a paginated height range request
+type HeightRangeCursor struct {
+	StartHeight uint64 // first height of the next page
+	EndHeight   uint64 // last height of the range, resolved when the first page was requested
+}
+
+// FromHeightRangeCursor encodes the cursor as an opaque URL safe string
+func FromHeightRangeCursor(cursor HeightRangeCursor) string {
+	raw := make([]byte, 16)
+	binary.BigEndian.PutUint64(raw[:8], cursor.StartHeight)
+	binary.BigEndian.PutUint64(raw[8:], cursor.EndHeight)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ToHeightRangeCursor decodes a cursor encoded by FromHeightRangeCursor
+func ToHeightRangeCursor(cursorStr string) (HeightRangeCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursorStr)
+	if err != nil || len(raw) != 16 {
+		return HeightRangeCursor{}, fmt.Errorf("invalid cursor") // hide error from user
+	}
+
+	cursor := HeightRangeCursor{
+		StartHeight: binary.BigEndian.Uint64(raw[:8]),
+		EndHeight:   binary.BigEndian.Uint64(raw[8:]),
+	}
+	if cursor.StartHeight > cursor.EndHeight {
+		return HeightRangeCursor{}, fmt.Errorf("invalid cursor")
+	}
+	return cursor, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/model/flow"
@@ -128,6 +129,8 @@ func New(
 			transactionMetrics:   transactionMetrics,
 			retry:                retry,
 			connFactory:          connFactory,
+			txStatusBroadcaster:  engine.NewBroadcaster(),
+			watchedTransactions:  newWatchedTransactions(),
 			previousAccessNodes:  historicalAccessNodes,
 			log:                  log,
 		},
@@ -183,6 +186,41 @@ func New(
 	return b
 }
 
+// SetScriptExecutor configures the backend to execute scripts, read accounts and simulate
+// transactions using the given executor of the local execution state, according to the given mode.
+// It must be called before the backend starts serving requests.
+func (b *Backend) SetScriptExecutor(executor ScriptExecutor, mode ScriptExecutionMode) {
+	b.backendScripts.scriptExecutor = executor
+	b.backendScripts.scriptExecMode = mode
+	b.backendAccounts.scriptExecutor = executor
+	b.backendAccounts.scriptExecMode = mode
+	b.backendTransactions.scriptExecutor = executor
+	b.backendTransactions.scriptExecMode = mode
+}
+
+// SetExecutionNodeHealth configures the backend to order the execution nodes it sends requests to
+// by their health, and to stop sending requests to failing nodes, using the given tracker, which
+// records the outcome of all requests to execution nodes. It must be called before the backend
+// starts serving requests.
+func (b *Backend) SetExecutionNodeHealth(nodeHealth *ExecutionNodeHealth) {
+	b.backendScripts.nodeHealth = nodeHealth
+	b.backendAccounts.nodeHealth = nodeHealth
+	b.backendEvents.nodeHealth = nodeHealth
+	b.backendTransactions.nodeHealth = nodeHealth
+}
+
+// SetEventIndex configures the backend to serve filtered event queries from the given local event
+// index. It must be called before the backend starts serving requests.
+func (b *Backend) SetEventIndex(index storage.EventIndex) {
+	b.backendEvents.eventIndex = index
+}
+
+// SetAccountTransactionIndex configures the backend to serve the transactions of accounts from the
+// given index. It must be called before the backend starts serving requests.
+func (b *Backend) SetAccountTransactionIndex(index storage.AccountTransactions) {
+	b.backendAccounts.accountTxs = index
+}
+
 func identifierList(ids []string) (flow.IdentifierList, error) {
 	idList := make(flow.IdentifierList, len(ids))
 	for i, idStr := range ids {
@@ -260,13 +298,14 @@ func (b *Backend) GetLatestProtocolStateSnapshot(_ context.Context) ([]byte, err
 }
 
 // executionNodesForBlockID returns upto maxExecutionNodesCnt number of randomly chosen execution node identities
-// which have executed the given block ID.
+// which have executed the given block ID, ordered by their health if a health tracker is given.
 // If no such execution node is found, an InsufficientExecutionReceipts error is returned.
 func executionNodesForBlockID(
 	ctx context.Context,
 	blockID flow.Identifier,
 	executionReceipts storage.ExecutionReceipts,
 	state protocol.State,
+	nodeHealth *ExecutionNodeHealth,
 	log zerolog.Logger) (flow.IdentityList, error) {
 
 	var executorIDs flow.IdentifierList
@@ -287,7 +326,7 @@ func executionNodesForBlockID(
 	} else {
 		// try to find atleast minExecutionNodesCnt execution node ids from the execution receipts for the given blockID
 		for attempt := 0; attempt < maxAttemptsForExecutionReceipt; attempt++ {
-			executorIDs, err = findAllExecutionNodes(blockID, executionReceipts, log)
+			executorIDs, err = findAllExecutionNodes(blockID, executionReceipts, nodeHealth, log)
 			if err != nil {
 				return nil, err
 			}
@@ -330,8 +369,11 @@ func executionNodesForBlockID(
 		return nil, fmt.Errorf("failed to retreive execution IDs for block ID %v: %w", blockID, err)
 	}
 
-	// randomly choose upto maxExecutionNodesCnt identities
-	executionIdentitiesRandom := subsetENs.Sample(maxExecutionNodesCnt)
+	// randomly choose upto maxExecutionNodesCnt identities, preferring the healthiest ones
+	executionIdentitiesRandom := nodeHealth.Order(subsetENs.Sample(uint(len(subsetENs))))
+	if len(executionIdentitiesRandom) > maxExecutionNodesCnt {
+		executionIdentitiesRandom = executionIdentitiesRandom[:maxExecutionNodesCnt]
+	}
 
 	if len(executionIdentitiesRandom) == 0 {
 		return nil, fmt.Errorf("no matching execution node found for block ID %v", blockID)
@@ -341,10 +383,12 @@ func executionNodesForBlockID(
 }
 
 // findAllExecutionNodes find all the execution nodes ids from the execution receipts that have been received for the
-// given blockID
+// given blockID. Execution nodes whose receipts do not match the largest list of matching receipts are reported to
+// the health tracker.
 func findAllExecutionNodes(
 	blockID flow.Identifier,
 	executionReceipts storage.ExecutionReceipts,
+	nodeHealth *ExecutionNodeHealth,
 	log zerolog.Logger) (flow.IdentifierList, error) {
 
 	// lookup the receipt's storage with the block ID
@@ -385,6 +429,16 @@ func findAllExecutionNodes(
 	// pick the largest list of matching receipts
 	matchingReceiptMetaList := executionResultGroupedMetaList.GetGroup(maxMatchedReceiptResultID)
 
+	// the execution nodes with any other result disagree with the majority
+	for resultID, executionReceiptList := range executionResultGroupedMetaList {
+		if resultID == maxMatchedReceiptResultID {
+			continue
+		}
+		for _, receipt := range executionReceiptList {
+			nodeHealth.RecordMismatch(blockID, receipt.ExecutorID)
+		}
+	}
+
 	metaReceiptGroupedByExecutorID := matchingReceiptMetaList.GroupByExecutorID()
 
 	// collect all unique execution node ids from the receipts
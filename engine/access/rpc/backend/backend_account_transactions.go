@@ -0,0 +1,64 @@
+package backend
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// DefaultAccountTransactionsLimit is the number of transactions returned per page if no limit is requested.
+	DefaultAccountTransactionsLimit = 50
+
+	// MaxAccountTransactionsLimit is the maximum number of transactions that can be requested per page.
+	MaxAccountTransactionsLimit = 500
+)
+
+// GetTransactionsByAddress returns a page of the transactions the account participated in as
+// proposer, payer or authorizer, from the most recent to the oldest. The page starts at the cursor,
+// or at the most recent transaction if the cursor is nil, and the cursor of the next page is
+// returned if there are more transactions.
+//
+// Transactions are indexed as their collections are received, so only transactions of collections
+// received since the index was enabled are included.
+func (b *backendAccounts) GetTransactionsByAddress(
+	_ context.Context,
+	address flow.Address,
+	limit uint32,
+	cursor *flow.AccountTransactionCursor,
+) (*access.AccountTransactionsPage, error) {
+	if b.accountTxs == nil {
+		return nil, status.Errorf(codes.Unimplemented, "account transactions are not indexed by this node")
+	}
+
+	if limit == 0 {
+		limit = DefaultAccountTransactionsLimit
+	}
+	if limit > MaxAccountTransactionsLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "requested limit (%d) exceeded maximum (%d)", limit, MaxAccountTransactionsLimit)
+	}
+
+	// read one more transaction than requested to find the start of the next page
+	accountTxs, err := b.accountTxs.ByAddress(address, cursor, uint(limit)+1)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get transactions of account: %v", err)
+	}
+
+	page := &access.AccountTransactionsPage{
+		Transactions: accountTxs,
+	}
+	if len(accountTxs) > int(limit) {
+		next := accountTxs[limit]
+		page.Transactions = accountTxs[:limit]
+		page.NextCursor = &flow.AccountTransactionCursor{
+			BlockHeight:   next.BlockHeight,
+			TransactionID: next.TransactionID,
+		}
+	}
+
+	return page, nil
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
 	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
@@ -12,17 +14,30 @@ import (
 
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/execution/rpc/registers"
+	"github.com/onflow/flow-go/fvm/environment"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
 
+// accountBalanceScript returns the balance of the account with the given address.
+var accountBalanceScript = []byte(`
+pub fun main(address: Address): UFix64 {
+	return getAccount(address).balance
+}
+`)
+
 type backendAccounts struct {
 	state             protocol.State
 	headers           storage.Headers
 	executionReceipts storage.ExecutionReceipts
 	connFactory       ConnectionFactory
 	log               zerolog.Logger
+	scriptExecutor    ScriptExecutor
+	scriptExecMode    ScriptExecutionMode
+	accountTxs        storage.AccountTransactions
+	nodeHealth        *ExecutionNodeHealth
 }
 
 func (b *backendAccounts) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
@@ -37,12 +52,9 @@ func (b *backendAccounts) GetAccountAtLatestBlock(ctx context.Context, address f
 		return nil, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
 	}
 
-	// get the block id of the latest sealed header
-	latestBlockID := latestHeader.ID()
-
-	account, err := b.getAccountAtBlockID(ctx, address, latestBlockID)
+	account, err := b.getAccount(ctx, address, latestHeader)
 	if err != nil {
-		b.log.Error().Err(err).Msgf("failed to get account at blockID: %v", latestBlockID)
+		b.log.Error().Err(err).Msgf("failed to get account at blockID: %v", latestHeader.ID())
 		return nil, err
 	}
 
@@ -61,14 +73,529 @@ func (b *backendAccounts) GetAccountAtBlockHeight(
 		return nil, err
 	}
 
-	// get block ID of the header at the given height
-	blockID := header.ID()
+	account, err := b.getAccount(ctx, address, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// GetAccountKeyAtLatestBlock returns the public key with the given index of the account with the
+// given address at the latest sealed block.
+func (b *backendAccounts) GetAccountKeyAtLatestBlock(
+	ctx context.Context,
+	address flow.Address,
+	keyIndex uint64,
+) (*flow.AccountPublicKey, error) {
+	latestHeader, err := b.state.Sealed().Head()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
+	}
+
+	return b.getAccountKey(ctx, address, keyIndex, latestHeader)
+}
+
+// GetAccountKeyAtBlockHeight returns the public key with the given index of the account with the
+// given address at the given block height.
+func (b *backendAccounts) GetAccountKeyAtBlockHeight(
+	ctx context.Context,
+	address flow.Address,
+	keyIndex uint64,
+	height uint64,
+) (*flow.AccountPublicKey, error) {
+	header, err := b.headers.ByHeight(height)
+	if err != nil {
+		return nil, rpc.ConvertStorageError(err)
+	}
+
+	return b.getAccountKey(ctx, address, keyIndex, header)
+}
+
+// GetAccountKeysAtLatestBlock returns the public keys of the account with the given address at
+// the latest sealed block.
+func (b *backendAccounts) GetAccountKeysAtLatestBlock(
+	ctx context.Context,
+	address flow.Address,
+) ([]flow.AccountPublicKey, error) {
+	latestHeader, err := b.state.Sealed().Head()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
+	}
+
+	return b.getAccountKeys(ctx, address, latestHeader)
+}
+
+// GetAccountKeysAtBlockHeight returns the public keys of the account with the given address at
+// the given block height.
+func (b *backendAccounts) GetAccountKeysAtBlockHeight(
+	ctx context.Context,
+	address flow.Address,
+	height uint64,
+) ([]flow.AccountPublicKey, error) {
+	header, err := b.headers.ByHeight(height)
+	if err != nil {
+		return nil, rpc.ConvertStorageError(err)
+	}
+
+	return b.getAccountKeys(ctx, address, header)
+}
+
+// GetAccountBalance returns the balance of the account with the given address at the latest
+// sealed block.
+func (b *backendAccounts) GetAccountBalance(ctx context.Context, address flow.Address) (uint64, error) {
+	latestHeader, err := b.state.Sealed().Head()
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
+	}
+
+	return b.getAccountBalance(ctx, address, latestHeader)
+}
 
-	account, err := b.getAccountAtBlockID(ctx, address, blockID)
+// GetAccountBalanceAtBlockHeight returns the balance of the account with the given address at the
+// given block height.
+func (b *backendAccounts) GetAccountBalanceAtBlockHeight(
+	ctx context.Context,
+	address flow.Address,
+	height uint64,
+) (uint64, error) {
+	header, err := b.headers.ByHeight(height)
+	if err != nil {
+		return 0, rpc.ConvertStorageError(err)
+	}
+
+	return b.getAccountBalance(ctx, address, header)
+}
+
+// getAccountKeys returns the public keys of the account at the given block.
+func (b *backendAccounts) getAccountKeys(
+	ctx context.Context,
+	address flow.Address,
+	header *flow.Header,
+) ([]flow.AccountPublicKey, error) {
+	var keys []flow.AccountPublicKey
+	err := b.readAccount(ctx, address, header,
+		func() error {
+			var err error
+			keys, err = b.scriptExecutor.GetAccountKeysAtBlockHeight(ctx, address, header.Height)
+			if err != nil {
+				return convertLocalExecutionError(err, "failed to get account keys locally")
+			}
+			return nil
+		},
+		func(execNodes flow.IdentityList) error {
+			keyCount, err := b.accountKeyCountAtBlockID(ctx, execNodes, address, header.ID())
+			if err != nil {
+				return err
+			}
+
+			registerIDs := make([]flow.RegisterID, 0, keyCount)
+			for i := uint64(0); i < keyCount; i++ {
+				registerIDs = append(registerIDs, flow.PublicKeyRegisterID(address, i))
+			}
+			values, err := b.getRegistersFromAnyExeNode(ctx, execNodes, header.ID(), registerIDs)
+			if err != nil {
+				return err
+			}
+
+			keys = make([]flow.AccountPublicKey, 0, keyCount)
+			for i, value := range values {
+				key, err := decodeAccountKey(address, uint64(i), value)
+				if err != nil {
+					return err
+				}
+				keys = append(keys, key)
+			}
+			return nil
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	return keys, nil
+}
+
+// getAccountKey returns the public key with the given index of the account at the given block.
+func (b *backendAccounts) getAccountKey(
+	ctx context.Context,
+	address flow.Address,
+	keyIndex uint64,
+	header *flow.Header,
+) (*flow.AccountPublicKey, error) {
+	var key *flow.AccountPublicKey
+	err := b.readAccount(ctx, address, header,
+		func() error {
+			var err error
+			key, err = b.scriptExecutor.GetAccountKeyAtBlockHeight(ctx, address, keyIndex, header.Height)
+			if err != nil {
+				return convertLocalExecutionError(err, "failed to get account key locally")
+			}
+			return nil
+		},
+		func(execNodes flow.IdentityList) error {
+			// the account status and the key are fetched together, the status telling whether the
+			// account and the key exist
+			values, err := b.getRegistersFromAnyExeNode(ctx, execNodes, header.ID(), []flow.RegisterID{
+				flow.AccountStatusRegisterID(address),
+				flow.PublicKeyRegisterID(address, keyIndex),
+			})
+			if err != nil {
+				return err
+			}
+
+			keyCount, err := decodeAccountKeyCount(address, values[0])
+			if err != nil {
+				return err
+			}
+			if keyIndex >= keyCount {
+				return status.Errorf(codes.NotFound, "failed to get account key: key %d not found for account %s", keyIndex, address)
+			}
+
+			accountKey, err := decodeAccountKey(address, keyIndex, values[1])
+			if err != nil {
+				return err
+			}
+			key = &accountKey
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// getAccountBalance returns the balance of the account at the given block.
+func (b *backendAccounts) getAccountBalance(
+	ctx context.Context,
+	address flow.Address,
+	header *flow.Header,
+) (uint64, error) {
+	var balance uint64
+	err := b.readAccount(ctx, address, header,
+		func() error {
+			var err error
+			balance, err = b.scriptExecutor.GetAccountBalanceAtBlockHeight(ctx, address, header.Height)
+			if err != nil {
+				return convertLocalExecutionError(err, "failed to get account balance locally")
+			}
+			return nil
+		},
+		func(execNodes flow.IdentityList) error {
+			// check the account exists, as the balance of a missing account is zero
+			_, err := b.accountKeyCountAtBlockID(ctx, execNodes, address, header.ID())
+			if err != nil {
+				return err
+			}
+
+			balance, err = b.accountBalanceAtBlockID(ctx, execNodes, address, header.ID())
+			return err
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+// readAccount reads a part of the account at the given block, depending on the script execution
+// mode. readLocally reads it from the local execution state. readFromExecutionNodes reads it from
+// the given execution nodes, which executed the block, fetching only the registers or running only
+// the script needed instead of the full account with its contracts.
+func (b *backendAccounts) readAccount(
+	ctx context.Context,
+	address flow.Address,
+	header *flow.Header,
+	readLocally func() error,
+	readFromExecutionNodes func(execNodes flow.IdentityList) error,
+) error {
+	switch b.scriptExecMode {
+	case ScriptExecutionModeLocalOnly:
+		return readLocally()
+
+	case ScriptExecutionModeFailover:
+		err := readLocally()
+		if err == nil {
+			return nil
+		}
+
+		b.log.Debug().Err(err).
+			Str("address", address.String()).
+			Uint64("block_height", header.Height).
+			Msg("failed to read account locally, falling back to execution nodes")
+	}
+
+	blockID := header.ID()
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeHealth, b.log)
+	if err != nil {
+		return getAccountError(err)
+	}
+
+	return readFromExecutionNodes(execNodes)
+}
+
+// accountKeyCountAtBlockID returns the number of public keys of the account at the given block,
+// read from the account status register served by the execution nodes.
+// A NotFound error is returned if the account does not exist.
+func (b *backendAccounts) accountKeyCountAtBlockID(
+	ctx context.Context,
+	execNodes flow.IdentityList,
+	address flow.Address,
+	blockID flow.Identifier,
+) (uint64, error) {
+	values, err := b.getRegistersFromAnyExeNode(ctx, execNodes, blockID, []flow.RegisterID{flow.AccountStatusRegisterID(address)})
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeAccountKeyCount(address, values[0])
+}
+
+// decodeAccountKeyCount returns the number of public keys of the account from the value of its
+// status register. A NotFound error is returned if the register is not set, as the account does
+// not exist.
+func decodeAccountKeyCount(address flow.Address, value []byte) (uint64, error) {
+	if len(value) == 0 {
+		return 0, status.Errorf(codes.NotFound, "account not found: %s", address)
+	}
+
+	accountStatus, err := environment.AccountStatusFromBytes(value)
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to decode status of account %s: %v", address, err)
+	}
+
+	return accountStatus.PublicKeyCount(), nil
+}
+
+// decodeAccountKey returns the public key with the given index of the account from the value of
+// its key register. A NotFound error is returned if the register is not set.
+func decodeAccountKey(address flow.Address, keyIndex uint64, value []byte) (flow.AccountPublicKey, error) {
+	if len(value) == 0 {
+		return flow.AccountPublicKey{}, status.Errorf(codes.NotFound, "failed to get account key: key %d not found for account %s", keyIndex, address)
+	}
+
+	key, err := flow.DecodeAccountPublicKey(value, keyIndex)
+	if err != nil {
+		return flow.AccountPublicKey{}, status.Errorf(codes.Internal, "failed to decode key %d of account %s: %v", keyIndex, address, err)
+	}
+
+	return key, nil
+}
+
+// accountBalanceAtBlockID returns the balance of the account at the given block, by executing a
+// script returning it on the execution nodes. The balance is held in the account's storage, which
+// can not be read from a fixed register.
+func (b *backendAccounts) accountBalanceAtBlockID(
+	ctx context.Context,
+	execNodes flow.IdentityList,
+	address flow.Address,
+	blockID flow.Identifier,
+) (uint64, error) {
+	encodedAddress, err := jsoncdc.Encode(cadence.NewAddress(address))
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to encode address %s: %v", address, err)
+	}
+
+	req := &execproto.ExecuteScriptAtBlockIDRequest{
+		BlockId:   blockID[:],
+		Script:    accountBalanceScript,
+		Arguments: [][]byte{encodedAddress},
+	}
+
+	var res *execproto.ExecuteScriptAtBlockIDResponse
+	err = b.fromAnyExeNode(ctx, execNodes, "failed to get account balance from the execution node",
+		func(execNode *flow.Identity) error {
+			return b.tryExeNode(ctx, execNode, func(client execproto.ExecutionAPIClient) error {
+				var err error
+				res, err = client.ExecuteScriptAtBlockID(ctx, req)
+				return err
+			})
+		})
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := jsoncdc.Decode(nil, res.GetValue())
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to decode balance of account %s: %v", address, err)
+	}
+
+	balance, ok := value.(cadence.UFix64)
+	if !ok {
+		return 0, status.Errorf(codes.Internal, "unexpected balance type %T of account %s", value, address)
+	}
+
+	return uint64(balance), nil
+}
+
+// getRegistersFromAnyExeNode returns the values of the registers at the given block, in the order
+// of registerIDs, requested in batches of at most registers.MaxRegisters registers from the first
+// of the execution nodes to respond. Empty values are returned for registers which are not set.
+func (b *backendAccounts) getRegistersFromAnyExeNode(
+	ctx context.Context,
+	execNodes flow.IdentityList,
+	blockID flow.Identifier,
+	registerIDs []flow.RegisterID,
+) ([][]byte, error) {
+	values := make([][]byte, 0, len(registerIDs))
+	for start := 0; start < len(registerIDs); start += registers.MaxRegisters {
+		end := start + registers.MaxRegisters
+		if end > len(registerIDs) {
+			end = len(registerIDs)
+		}
+
+		req := &registers.GetRegistersAtBlockIDRequest{
+			BlockId:     blockID[:],
+			RegisterIds: registerIDs[start:end],
+		}
+
+		var res *registers.GetRegistersAtBlockIDResponse
+		err := b.fromAnyExeNode(ctx, execNodes, "failed to get registers from the execution node",
+			func(execNode *flow.Identity) error {
+				return b.tryExeNodeRegisters(ctx, execNode, func(client registers.Client) error {
+					var err error
+					res, err = client.GetRegistersAtBlockID(ctx, req)
+					return err
+				})
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(res.Values) != len(req.RegisterIds) {
+			return nil, status.Errorf(codes.Internal, "execution node returned %d register values, expected %d", len(res.Values), len(req.RegisterIds))
+		}
+		values = append(values, res.Values...)
+	}
+
+	return values, nil
+}
+
+// fromAnyExeNode calls the given function with the execution nodes in turn, until it succeeds for
+// one of them. Only errors of execution nodes which could not be reached are retried with the next
+// one, as other errors, e.g. of missing data or invalid requests, would be the same for all of them.
+func (b *backendAccounts) fromAnyExeNode(
+	ctx context.Context,
+	execNodes flow.IdentityList,
+	errMsg string,
+	call func(execNode *flow.Identity) error,
+) error {
+	var errors *multierror.Error
+	for _, execNode := range execNodes {
+		err := call(execNode)
+		if err == nil {
+			return nil
+		}
+
+		b.log.Error().
+			Str("execution_node", execNode.String()).
+			Err(err).
+			Msg(errMsg)
+		errors = multierror.Append(errors, err)
+
+		if !isExeNodeUnavailable(err) {
+			break
+		}
+	}
+
+	return rpc.ConvertMultiError(errors, errMsg, codes.Internal)
+}
+
+// isExeNodeUnavailable returns true if the error is a transport error, either connecting to the
+// execution node or returned as Unavailable by the gRPC client, rather than an error of the request.
+func isExeNodeUnavailable(err error) bool {
+	st, ok := status.FromError(err)
+	return !ok || st.Code() == codes.Unavailable
+}
+
+// tryExeNode calls the given function with the execution API client of the execution node.
+func (b *backendAccounts) tryExeNode(
+	ctx context.Context,
+	execNode *flow.Identity,
+	call func(client execproto.ExecutionAPIClient) error,
+) error {
+	execRPCClient, closer, err := b.connFactory.GetExecutionAPIClient(execNode.Address)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	startTime := time.Now()
+	err = call(execRPCClient)
+	b.recordExeNodeRequest(ctx, execNode, time.Since(startTime), err)
+	return err
+}
+
+// tryExeNodeRegisters calls the given function with the registers client of the execution node.
+func (b *backendAccounts) tryExeNodeRegisters(
+	ctx context.Context,
+	execNode *flow.Identity,
+	call func(client registers.Client) error,
+) error {
+	registersClient, closer, err := b.connFactory.GetExecutionRegistersClient(execNode.Address)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	startTime := time.Now()
+	err = call(registersClient)
+	b.recordExeNodeRequest(ctx, execNode, time.Since(startTime), err)
+	return err
+}
+
+// recordExeNodeRequest records the outcome of a request to the execution node, invalidating its
+// cached connection if it is unavailable.
+func (b *backendAccounts) recordExeNodeRequest(ctx context.Context, execNode *flow.Identity, duration time.Duration, err error) {
+	b.nodeHealth.RecordRequest(ctx, execNode.NodeID, duration, err)
+	if status.Code(err) == codes.Unavailable {
+		b.connFactory.InvalidateExecutionAPIClient(execNode.Address)
+	}
+}
+
+// getAccount returns the account at the given block, either using the local execution state or
+// from execution nodes, depending on the script execution mode.
+func (b *backendAccounts) getAccount(
+	ctx context.Context,
+	address flow.Address,
+	header *flow.Header,
+) (*flow.Account, error) {
+	switch b.scriptExecMode {
+	case ScriptExecutionModeLocalOnly:
+		return b.getAccountLocally(ctx, address, header)
+
+	case ScriptExecutionModeFailover:
+		account, err := b.getAccountLocally(ctx, address, header)
+		if err == nil {
+			return account, nil
+		}
+
+		b.log.Debug().Err(err).
+			Str("address", address.String()).
+			Uint64("block_height", header.Height).
+			Msg("failed to get account locally, falling back to execution nodes")
+
+		return b.getAccountAtBlockID(ctx, address, header.ID())
+
+	default:
+		return b.getAccountAtBlockID(ctx, address, header.ID())
+	}
+}
+
+// getAccountLocally returns the account using the local execution state at the given block
+func (b *backendAccounts) getAccountLocally(
+	ctx context.Context,
+	address flow.Address,
+	header *flow.Header,
+) (*flow.Account, error) {
+	account, err := b.scriptExecutor.GetAccountAtBlockHeight(ctx, address, header.Height)
+	if err != nil {
+		return nil, convertLocalExecutionError(err, "failed to get account locally")
+	}
+
 	return account, nil
 }
 
@@ -83,7 +610,7 @@ func (b *backendAccounts) getAccountAtBlockID(
 		BlockId: blockID[:],
 	}
 
-	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.log)
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeHealth, b.log)
 	if err != nil {
 		return nil, getAccountError(err)
 	}
@@ -149,7 +676,9 @@ func (b *backendAccounts) tryGetAccount(ctx context.Context, execNode *flow.Iden
 	}
 	defer closer.Close()
 
+	startTime := time.Now()
 	resp, err := execRPCClient.GetAccountAtBlockID(ctx, req)
+	b.nodeHealth.RecordRequest(ctx, execNode.NodeID, time.Since(startTime), err)
 	if err != nil {
 		if status.Code(err) == codes.Unavailable {
 			b.connFactory.InvalidateExecutionAPIClient(execNode.Address)
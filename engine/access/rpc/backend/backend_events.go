@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
@@ -20,12 +21,15 @@ import (
 )
 
 type backendEvents struct {
-	headers           storage.Headers
-	executionReceipts storage.ExecutionReceipts
-	state             protocol.State
-	connFactory       ConnectionFactory
-	log               zerolog.Logger
-	maxHeightRange    uint
+	headers            storage.Headers
+	executionReceipts  storage.ExecutionReceipts
+	state              protocol.State
+	connFactory        ConnectionFactory
+	log                zerolog.Logger
+	maxHeightRange     uint
+	eventIndex         storage.EventIndex
+	nodeHealth         *ExecutionNodeHealth
+	resultVerification ResultVerificationConfig
 }
 
 // GetEventsForHeightRange retrieves events for all sealed blocks between the start block height and
@@ -128,7 +132,16 @@ func (b *backendEvents) getBlockEventsFromExecutionNode(
 	// choose the last block ID to find the list of execution nodes
 	lastBlockID := blockIDs[len(blockIDs)-1]
 
-	execNodes, err := executionNodesForBlockID(ctx, lastBlockID, b.executionReceipts, b.state, b.log)
+	if b.resultVerification.Enabled() {
+		resp, err := b.getEventsVerified(ctx, lastBlockID, req)
+		if err != nil {
+			b.log.Error().Err(err).Msg("failed to retrieve verified events from execution nodes")
+			return nil, err
+		}
+		return b.convertEvents(resp, blockHeaders)
+	}
+
+	execNodes, err := executionNodesForBlockID(ctx, lastBlockID, b.executionReceipts, b.state, b.nodeHealth, b.log)
 	if err != nil {
 		b.log.Error().Err(err).Msg("failed to retrieve events from execution node")
 		return nil, status.Errorf(codes.Internal, "failed to retrieve events from execution node: %v", err)
@@ -146,7 +159,14 @@ func (b *backendEvents) getBlockEventsFromExecutionNode(
 		Str("last_block_id", lastBlockID.String()).
 		Msg("successfully got events")
 
-	// convert execution node api result to access node api result
+	return b.convertEvents(resp, blockHeaders)
+}
+
+// convertEvents converts the execution node api result to the access node api result.
+func (b *backendEvents) convertEvents(
+	resp *execproto.GetEventsForBlockIDsResponse,
+	blockHeaders []*flow.Header,
+) ([]flow.BlockEvents, error) {
 	results, err := verifyAndConvertToAccessEvents(resp.GetResults(), blockHeaders)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to verify retrieved events from execution node: %v", err)
@@ -215,7 +235,9 @@ func (b *backendEvents) tryGetEvents(ctx context.Context,
 	}
 	defer closer.Close()
 
+	startTime := time.Now()
 	resp, err := execRPCClient.GetEventsForBlockIDs(ctx, req)
+	b.nodeHealth.RecordRequest(ctx, execNode.NodeID, time.Since(startTime), err)
 	if err != nil {
 		if status.Code(err) == codes.Unavailable {
 			b.connFactory.InvalidateExecutionAPIClient(execNode.Address)
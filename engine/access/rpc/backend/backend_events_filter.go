@@ -0,0 +1,164 @@
+package backend
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/convert"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// GetEventsForHeightRangeWithFilter retrieves the events matching the filter for all indexed blocks
+// between the start block height and the end block height (inclusive). Only blocks that contain
+// matching events are included in the result.
+//
+// Events are read from the local event index, using the most selective index for the filter: the
+// transaction, the field value or the contract address, in that order. All remaining criteria are
+// checked for each event read from the index. Fields which are not indexed by value are filtered
+// from the events of the contract. A filter on the type of an event that is not emitted by a
+// contract alone cannot be served from the index, and is forwarded to the execution nodes like
+// GetEventsForHeightRange.
+func (b *backendEvents) GetEventsForHeightRangeWithFilter(
+	ctx context.Context,
+	filter access.EventFilter,
+	startHeight, endHeight uint64,
+) ([]flow.BlockEvents, error) {
+	if b.eventIndex == nil {
+		return nil, status.Errorf(codes.Unimplemented, "filtered event queries require events to be indexed")
+	}
+
+	if filter.EventType == "" && filter.ContractAddress == nil && filter.TransactionID == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "event type, contract address or transaction ID must be provided")
+	}
+	if filter.FieldName == "" && filter.FieldValue != "" {
+		return nil, status.Errorf(codes.InvalidArgument, "field name must be provided to filter by field value")
+	}
+	if filter.FieldName != "" && filter.EventType == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "event type must be provided to filter by field value")
+	}
+
+	if endHeight < startHeight {
+		return nil, status.Error(codes.InvalidArgument, "invalid start or end height")
+	}
+
+	rangeSize := endHeight - startHeight + 1 // range is inclusive on both ends
+	if rangeSize > uint64(b.maxHeightRange) {
+		return nil, status.Errorf(codes.InvalidArgument, "requested block range (%d) exceeded maximum (%d)", rangeSize, b.maxHeightRange)
+	}
+
+	contractAddress, isContractEvent := convert.EventContractAddress(filter.EventType)
+	if filter.ContractAddress == nil && filter.TransactionID == nil && filter.FieldName == "" && !isContractEvent {
+		return b.GetEventsForHeightRange(ctx, string(filter.EventType), startHeight, endHeight)
+	}
+
+	firstHeight, latestHeight, err := b.indexedEventHeights()
+	if err != nil {
+		return nil, err
+	}
+
+	// the start height must be indexed, and the end height is limited to the last indexed height
+	if startHeight < firstHeight || startHeight > latestHeight {
+		return nil, status.Errorf(codes.OutOfRange,
+			"start height %d is outside the indexed range [%d, %d]", startHeight, firstHeight, latestHeight)
+	}
+	if latestHeight < endHeight {
+		endHeight = latestHeight
+	}
+
+	var results []flow.BlockEvents
+	switch {
+	case filter.TransactionID != nil:
+		results, err = b.eventIndex.ByTransactionID(*filter.TransactionID, startHeight, endHeight)
+	case filter.FieldName != "":
+		results, err = b.eventIndex.ByFieldValue(filter.EventType, filter.FieldName, filter.FieldValue, startHeight, endHeight)
+		if errors.Is(err, storage.ErrFieldNotIndexed) {
+			if !isContractEvent {
+				return nil, status.Errorf(codes.InvalidArgument, "field %s of event type %s is not indexed", filter.FieldName, filter.EventType)
+			}
+			results, err = b.eventIndex.ByContractAddress(contractAddress, startHeight, endHeight)
+		}
+	case filter.ContractAddress != nil:
+		results, err = b.eventIndex.ByContractAddress(*filter.ContractAddress, startHeight, endHeight)
+	default:
+		results, err = b.eventIndex.ByContractAddress(contractAddress, startHeight, endHeight)
+	}
+	if err != nil {
+		if errors.Is(err, storage.ErrHeightNotIndexed) || errors.Is(err, storage.ErrNotBootstrapped) {
+			return nil, status.Errorf(codes.OutOfRange, "failed to get events: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get events: %v", err)
+	}
+
+	filtered := make([]flow.BlockEvents, 0, len(results))
+	for _, blockEvents := range results {
+		events := make([]flow.Event, 0, len(blockEvents.Events))
+		for _, event := range blockEvents.Events {
+			if matchesEventFilter(filter, event) {
+				events = append(events, event)
+			}
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		blockEvents.Events = events
+		filtered = append(filtered, blockEvents)
+	}
+
+	return filtered, nil
+}
+
+// indexedEventHeights returns the range of heights for which events are indexed.
+func (b *backendEvents) indexedEventHeights() (uint64, uint64, error) {
+	firstHeight, err := b.eventIndex.FirstHeight()
+	if err != nil {
+		if errors.Is(err, storage.ErrNotBootstrapped) {
+			return 0, 0, status.Errorf(codes.OutOfRange, "no events are indexed yet")
+		}
+		return 0, 0, status.Errorf(codes.Internal, "failed to get first indexed height: %v", err)
+	}
+
+	latestHeight, err := b.eventIndex.LatestHeight()
+	if err != nil {
+		return 0, 0, status.Errorf(codes.Internal, "failed to get latest indexed height: %v", err)
+	}
+
+	return firstHeight, latestHeight, nil
+}
+
+// matchesEventFilter returns true if the event matches all criteria of the filter. Events whose
+// payload cannot be decoded never match a field value.
+func matchesEventFilter(filter access.EventFilter, event flow.Event) bool {
+	if filter.EventType != "" && event.Type != filter.EventType {
+		return false
+	}
+
+	if filter.TransactionID != nil && event.TransactionID != *filter.TransactionID {
+		return false
+	}
+
+	if filter.ContractAddress != nil {
+		address, ok := convert.EventContractAddress(event.Type)
+		if !ok || address != *filter.ContractAddress {
+			return false
+		}
+	}
+
+	if filter.FieldName != "" {
+		values, err := convert.EventFieldValues(event)
+		if err != nil {
+			return false
+		}
+		value, ok := values[filter.FieldName]
+		if !ok || value != filter.FieldValue {
+			return false
+		}
+	}
+
+	return true
+}
@@ -3,6 +3,7 @@ package backend
 import (
 	"context"
 	"crypto/md5" //nolint:gosec
+	"errors"
 	"time"
 
 	lru "github.com/hashicorp/golang-lru"
@@ -10,6 +11,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
 	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -24,13 +26,19 @@ import (
 const uniqueScriptLoggingTimeWindow = 10 * time.Minute
 
 type backendScripts struct {
-	headers           storage.Headers
-	executionReceipts storage.ExecutionReceipts
-	state             protocol.State
-	connFactory       ConnectionFactory
-	log               zerolog.Logger
-	metrics           module.BackendScriptsMetrics
-	loggedScripts     *lru.Cache
+	headers            storage.Headers
+	executionReceipts  storage.ExecutionReceipts
+	state              protocol.State
+	connFactory        ConnectionFactory
+	log                zerolog.Logger
+	metrics            module.BackendScriptsMetrics
+	loggedScripts      *lru.Cache
+	scriptExecutor     ScriptExecutor
+	scriptExecMode     ScriptExecutionMode
+	scriptResults      *scriptResultCache // optional cache of script results at sealed blocks
+	scriptRequests     singleflight.Group // coalesces identical script requests to execution nodes
+	nodeHealth         *ExecutionNodeHealth
+	resultVerification ResultVerificationConfig
 }
 
 func (b *backendScripts) ExecuteScriptAtLatestBlock(
@@ -45,11 +53,7 @@ func (b *backendScripts) ExecuteScriptAtLatestBlock(
 		return nil, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
 	}
 
-	// get the block id of the latest sealed header
-	latestBlockID := latestHeader.ID()
-
-	// execute script on the execution node at that block id
-	return b.executeScriptOnExecutionNode(ctx, latestBlockID, script, arguments)
+	return b.executeScript(ctx, latestHeader, script, arguments)
 }
 
 func (b *backendScripts) ExecuteScriptAtBlockID(
@@ -58,8 +62,36 @@ func (b *backendScripts) ExecuteScriptAtBlockID(
 	script []byte,
 	arguments [][]byte,
 ) ([]byte, error) {
-	// execute script on the execution node at that block id
-	return b.executeScriptOnExecutionNode(ctx, blockID, script, arguments)
+	if b.scriptExecMode == ScriptExecutionModeExecutionNodesOnly {
+		// execute script on the execution node at that block id
+		return b.executeScriptOnExecutionNodeCached(ctx, blockID, script, arguments)
+	}
+
+	// local execution state is indexed by height
+	header, err := b.headers.ByBlockID(blockID)
+	if err != nil {
+		return nil, rpc.ConvertStorageError(err)
+	}
+
+	// only the execution state of finalized blocks is indexed, so scripts at other blocks must not
+	// be executed at the finalized block of the same height
+	finalizedID, err := b.headers.BlockIDByHeight(header.Height)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, rpc.ConvertStorageError(err)
+	}
+	if err != nil || finalizedID != blockID {
+		if b.scriptExecMode == ScriptExecutionModeLocalOnly {
+			return nil, status.Errorf(codes.NotFound, "execution state of block %v is not available locally: block is not finalized", blockID)
+		}
+
+		b.log.Debug().
+			Hex("block_id", blockID[:]).
+			Msg("block is not finalized, executing script on execution nodes")
+
+		return b.executeScriptOnExecutionNodeCached(ctx, blockID, script, arguments)
+	}
+
+	return b.executeScript(ctx, header, script, arguments)
 }
 
 func (b *backendScripts) ExecuteScriptAtBlockHeight(
@@ -75,10 +107,60 @@ func (b *backendScripts) ExecuteScriptAtBlockHeight(
 		return nil, err
 	}
 
-	blockID := header.ID()
+	return b.executeScript(ctx, header, script, arguments)
+}
+
+// executeScript executes the script at the given block, either using the local execution state or
+// on execution nodes, depending on the script execution mode.
+func (b *backendScripts) executeScript(
+	ctx context.Context,
+	header *flow.Header,
+	script []byte,
+	arguments [][]byte,
+) ([]byte, error) {
+	switch b.scriptExecMode {
+	case ScriptExecutionModeLocalOnly:
+		return b.executeScriptLocally(ctx, header, script, arguments)
+
+	case ScriptExecutionModeFailover:
+		result, err := b.executeScriptLocally(ctx, header, script, arguments)
+		// a failing script fails on the execution nodes too, so there is no need to fall back
+		if err == nil || status.Code(err) == codes.InvalidArgument {
+			return result, err
+		}
+
+		b.log.Debug().Err(err).
+			Uint64("block_height", header.Height).
+			Msg("failed to execute script locally, falling back to execution nodes")
+
+		return b.executeScriptOnExecutionNodeCached(ctx, header.ID(), script, arguments)
+
+	default:
+		// execute script on the execution node at that block id
+		return b.executeScriptOnExecutionNodeCached(ctx, header.ID(), script, arguments)
+	}
+}
+
+// executeScriptLocally executes the script using the local execution state at the given block
+func (b *backendScripts) executeScriptLocally(
+	ctx context.Context,
+	header *flow.Header,
+	script []byte,
+	arguments [][]byte,
+) ([]byte, error) {
+	execStartTime := time.Now()
+
+	result, err := b.scriptExecutor.ExecuteAtBlockHeight(ctx, script, arguments, header.Height)
+	if err != nil {
+		return nil, convertLocalExecutionError(err, "failed to execute script locally")
+	}
+
+	b.metrics.ScriptExecuted(
+		time.Since(execStartTime),
+		len(script),
+	)
 
-	// execute script on the execution node at that block id
-	return b.executeScriptOnExecutionNode(ctx, blockID, script, arguments)
+	return result, nil
 }
 
 // executeScriptOnExecutionNode forwards the request to the execution node using the execution node
@@ -96,8 +178,12 @@ func (b *backendScripts) executeScriptOnExecutionNode(
 		Arguments: arguments,
 	}
 
+	if b.resultVerification.Enabled() {
+		return b.executeScriptVerified(ctx, blockID, execReq)
+	}
+
 	// find few execution nodes which have executed the block earlier and provided an execution receipt for it
-	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.log)
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeHealth, b.log)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to find execution nodes at blockId %v: %v", blockID.String(), err)
 	}
@@ -174,7 +260,9 @@ func (b *backendScripts) tryExecuteScript(ctx context.Context, execNode *flow.Id
 	}
 	defer closer.Close()
 
+	startTime := time.Now()
 	execResp, err := execRPCClient.ExecuteScriptAtBlockID(ctx, req)
+	b.nodeHealth.RecordRequest(ctx, execNode.NodeID, time.Since(startTime), err)
 	if err != nil {
 		if status.Code(err) == codes.Unavailable {
 			b.connFactory.InvalidateExecutionAPIClient(execNode.Address)
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/dgraph-io/badger/v2"
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
 	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
 	entitiesproto "github.com/onflow/flow/protobuf/go/flow/entities"
 	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
@@ -16,13 +18,21 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/atomic"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	accessapi "github.com/onflow/flow-go/access"
 	access "github.com/onflow/flow-go/engine/access/mock"
 	backendmock "github.com/onflow/flow-go/engine/access/rpc/backend/mock"
+	"github.com/onflow/flow-go/engine/access/state_stream"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/execution/rpc/registers"
+	registersmock "github.com/onflow/flow-go/engine/execution/rpc/registers/mock"
+	"github.com/onflow/flow-go/fvm/environment"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/execution"
 	"github.com/onflow/flow-go/module/metrics"
 	bprotocol "github.com/onflow/flow-go/state/protocol/badger"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
@@ -1147,6 +1157,136 @@ func (suite *Suite) TestTransactionResultUnknown() {
 	suite.Assert().Equal(flow.TransactionStatusUnknown, result.Status)
 }
 
+// TestSubscribeTransactionStatuses tests that a transaction status subscription sends the current status of the
+// transaction, and then sends each status change after the backend is notified of an update. Execution nodes are
+// only queried when the state of the transaction's block changes, once for all subscriptions of the transaction.
+func (suite *Suite) TestSubscribeTransactionStatuses() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	collection := unittest.CollectionFixture(1)
+	transactionBody := collection.Transactions[0]
+	block := unittest.BlockFixture()
+	refBlock := unittest.BlockFixture()
+	refBlock.Header.Height = 2
+	transactionBody.SetReferenceBlockID(refBlock.ID())
+	txID := transactionBody.ID()
+
+	headBlock := unittest.BlockFixture()
+	headBlock.Header.Height = refBlock.Header.Height - 1
+
+	snapshotAtBlock := new(protocol.Snapshot)
+	snapshotAtBlock.On("Head").Return(refBlock.Header, nil)
+	suite.state.On("AtBlockID", refBlock.ID()).Return(snapshotAtBlock, nil)
+	suite.state.On("Final").Return(suite.snapshot, nil).Maybe()
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+	suite.snapshot.On("Head").Return(headBlock.Header, nil)
+	suite.blocks.On("GetLastFullBlockHeight").Return(headBlock.Header.Height, nil)
+
+	suite.transactions.On("ByID", txID).Return(transactionBody, nil)
+
+	currentState := flow.TransactionStatusPending
+	suite.collections.
+		On("LightByTransactionID", txID).
+		Return(func(txID flow.Identifier) *flow.LightCollection {
+			if currentState == flow.TransactionStatusPending {
+				return nil
+			}
+			collLight := collection.Light()
+			return &collLight
+		},
+			func(txID flow.Identifier) error {
+				if currentState == flow.TransactionStatusPending {
+					return storage.ErrNotFound
+				}
+				return nil
+			})
+	suite.blocks.On("ByCollectionID", collection.ID()).Return(&block, nil)
+
+	receipts, enIDs := suite.setupReceipts(&block)
+	suite.snapshot.On("Identities", mock.Anything).Return(enIDs, nil)
+	preferredENIdentifiers = flow.IdentifierList{receipts[0].ExecutorID}
+
+	// simulate that the execution node has not yet executed the transaction
+	executionQueries := atomic.NewInt64(0)
+	suite.execClient.
+		On("GetTransactionResult", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { executionQueries.Inc() }).
+		Return(nil, status.Errorf(codes.NotFound, "not found"))
+
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		suite.blocks,
+		suite.headers,
+		suite.collections,
+		suite.transactions,
+		suite.receipts,
+		suite.results,
+		suite.chainID,
+		metrics.NewNoopCollector(),
+		suite.setupConnectionFactory(),
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		flow.IdentifierList(enIDs.NodeIDs()).Strings(),
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+
+	sub := backend.SubscribeTransactionStatuses(ctx, txID)
+
+	receive := func(sub state_stream.Subscription) *accessapi.TransactionResult {
+		select {
+		case v, ok := <-sub.Channel():
+			suite.Require().True(ok, "subscription closed unexpectedly: %v", sub.Err())
+			result, ok := v.(*accessapi.TransactionResult)
+			suite.Require().True(ok, "unexpected response type: %T", v)
+			return result
+		case <-time.After(time.Second):
+			suite.FailNow("timed out waiting for transaction status")
+			return nil
+		}
+	}
+
+	// the current status is sent immediately
+	suite.Assert().Equal(flow.TransactionStatusPending, receive(sub).Status)
+	suite.Assert().Zero(executionQueries.Load())
+
+	currentState = flow.TransactionStatusFinalized
+	backend.NotifyTransactionStatusUpdate()
+
+	result := receive(sub)
+	suite.Assert().Equal(flow.TransactionStatusFinalized, result.Status)
+	suite.Assert().Equal(block.ID(), result.BlockID)
+
+	queries := executionQueries.Load()
+	suite.Require().Positive(queries)
+
+	// a new subscription of the transaction is sent the current status without querying execution nodes again
+	other := backend.SubscribeTransactionStatuses(ctx, txID)
+	suite.Assert().Equal(flow.TransactionStatusFinalized, receive(other).Status)
+	suite.Assert().Equal(queries, executionQueries.Load())
+
+	// updates which do not change the state of the transaction's block do not query execution nodes
+	backend.NotifyTransactionStatusUpdate()
+	backend.NotifyTransactionStatusUpdate()
+	suite.Assert().Never(func() bool {
+		return executionQueries.Load() != queries
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	// the subscription fails once the client disconnects
+	cancel()
+	select {
+	case _, ok := <-sub.Channel():
+		suite.Assert().False(ok)
+	case <-time.After(time.Second):
+		suite.FailNow("timed out waiting for subscription to close")
+	}
+	suite.Assert().ErrorIs(sub.Err(), context.Canceled)
+}
+
 func (suite *Suite) TestGetLatestFinalizedBlock() {
 	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
 	suite.state.On("Final").Return(suite.snapshot, nil).Maybe()
@@ -2086,7 +2226,7 @@ func (suite *Suite) TestExecutionNodesForBlockID() {
 		if fixedENs != nil {
 			fixedENIdentifiers = fixedENs.NodeIDs()
 		}
-		actualList, err := executionNodesForBlockID(context.Background(), block.ID(), suite.receipts, suite.state, suite.log)
+		actualList, err := executionNodesForBlockID(context.Background(), block.ID(), suite.receipts, suite.state, nil, suite.log)
 		require.NoError(suite.T(), err)
 		if expectedENs == nil {
 			expectedENs = flow.IdentityList{}
@@ -2106,7 +2246,7 @@ func (suite *Suite) TestExecutionNodesForBlockID() {
 		attempt2Receipts = flow.ExecutionReceiptList{}
 		attempt3Receipts = flow.ExecutionReceiptList{}
 		suite.state.On("AtBlockID", mock.Anything).Return(suite.snapshot)
-		actualList, err := executionNodesForBlockID(context.Background(), block.ID(), suite.receipts, suite.state, suite.log)
+		actualList, err := executionNodesForBlockID(context.Background(), block.ID(), suite.receipts, suite.state, nil, suite.log)
 		require.NoError(suite.T(), err)
 		require.Equal(suite.T(), len(actualList), maxExecutionNodesCnt)
 	})
@@ -2166,6 +2306,39 @@ func (suite *Suite) TestExecutionNodesForBlockID() {
 		expectedList := preferredENs
 		testExecutionNodesForBlockID(preferredENs, nil, expectedList)
 	})
+	// failing ENs should not be chosen, and ENs which disagree with the majority should be reported
+	suite.Run("unhealthy ENs", func() {
+		preferredENIdentifiers = nil
+		fixedENIdentifiers = nil
+
+		config := DefaultExecutionNodeHealthConfig()
+		config.MaxFailures = 1
+		health, err := NewExecutionNodeHealth(suite.log, metrics.NewNoopCollector(), config)
+		require.NoError(suite.T(), err)
+
+		failingENs := allExecutionNodes[0:2]
+		for _, en := range failingENs {
+			health.RecordRequest(context.Background(), en.NodeID, time.Millisecond, status.Error(codes.Unavailable, "unavailable"))
+		}
+
+		mismatchedReceipt := unittest.ReceiptForBlockFixture(&block)
+		mismatchedReceipt.ExecutorID = allExecutionNodes[4].NodeID
+		mismatchedReceipts := append(flow.ExecutionReceiptList{mismatchedReceipt}, receipts[:4]...)
+		attempt1Receipts, attempt2Receipts, attempt3Receipts = mismatchedReceipts, mismatchedReceipts, mismatchedReceipts
+		currentAttempt = 0
+
+		actualList, err := executionNodesForBlockID(context.Background(), block.ID(), suite.receipts, suite.state, health, suite.log)
+		require.NoError(suite.T(), err)
+		require.ElementsMatch(suite.T(), allExecutionNodes[2:4], actualList)
+
+		scores := health.Scores()
+		require.Len(suite.T(), scores, 3)
+		mismatches := make(map[flow.Identifier]uint64, len(scores))
+		for _, score := range scores {
+			mismatches[score.NodeID] = score.Mismatches
+		}
+		require.Equal(suite.T(), uint64(1), mismatches[allExecutionNodes[4].NodeID])
+	})
 }
 
 // TestExecuteScriptOnExecutionNode tests the method backend.scripts.executeScriptOnExecutionNode for script execution
@@ -2240,6 +2413,900 @@ func (suite *Suite) TestExecuteScriptOnExecutionNode() {
 	})
 }
 
+func (suite *Suite) TestExecuteScriptLocally() {
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+	suite.state.On("Final").Return(suite.snapshot, nil).Maybe()
+
+	ctx := context.Background()
+	block := unittest.BlockFixture()
+	header := block.Header
+	script := []byte("dummy script")
+	arguments := [][]byte(nil)
+	expected := []byte{4, 5, 6}
+
+	suite.headers.On("ByHeight", header.Height).Return(header, nil)
+
+	receipts, ids := suite.setupReceipts(&block)
+	suite.snapshot.On("Identities", mock.Anything).Return(ids, nil)
+
+	connFactory := new(backendmock.ConnectionFactory)
+	connFactory.On("GetExecutionAPIClient", mock.Anything).Return(suite.execClient, &mockCloser{}, nil)
+
+	scriptExecutor := backendmock.NewScriptExecutor(suite.T())
+
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		nil,
+		suite.headers,
+		nil,
+		nil,
+		suite.receipts,
+		suite.results,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		connFactory,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+
+	preferredENIdentifiers = flow.IdentifierList{receipts[0].ExecutorID}
+
+	suite.Run("local only - executes locally", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeLocalOnly)
+		scriptExecutor.On("ExecuteAtBlockHeight", ctx, script, arguments, header.Height).Return(expected, nil).Once()
+
+		res, err := backend.ExecuteScriptAtBlockHeight(ctx, header.Height, script, arguments)
+		suite.Require().NoError(err)
+		suite.Require().Equal(expected, res)
+	})
+
+	suite.Run("local only - failing script returns InvalidArgument", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeLocalOnly)
+		scriptExecutor.On("ExecuteAtBlockHeight", ctx, script, arguments, header.Height).
+			Return(nil, execution.NewScriptExecutionError(fmt.Errorf("script failed"))).Once()
+
+		_, err := backend.ExecuteScriptAtBlockHeight(ctx, header.Height, script, arguments)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("local only - missing execution state returns OutOfRange", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeLocalOnly)
+		scriptExecutor.On("ExecuteAtBlockHeight", ctx, script, arguments, header.Height).
+			Return(nil, storage.ErrHeightNotIndexed).Once()
+
+		_, err := backend.ExecuteScriptAtBlockHeight(ctx, header.Height, script, arguments)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.OutOfRange, status.Code(err))
+	})
+
+	suite.Run("failover - failing script does not fall back", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeFailover)
+		scriptExecutor.On("ExecuteAtBlockHeight", ctx, script, arguments, header.Height).
+			Return(nil, execution.NewScriptExecutionError(fmt.Errorf("script failed"))).Once()
+
+		_, err := backend.ExecuteScriptAtBlockHeight(ctx, header.Height, script, arguments)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("failover - missing execution state falls back to execution nodes", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeFailover)
+		scriptExecutor.On("ExecuteAtBlockHeight", ctx, script, arguments, header.Height).
+			Return(nil, storage.ErrHeightNotIndexed).Once()
+
+		blockID := header.ID()
+		execReq := &execproto.ExecuteScriptAtBlockIDRequest{
+			BlockId:   blockID[:],
+			Script:    script,
+			Arguments: arguments,
+		}
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, execReq).
+			Return(&execproto.ExecuteScriptAtBlockIDResponse{Value: expected}, nil).Once()
+
+		res, err := backend.ExecuteScriptAtBlockHeight(ctx, header.Height, script, arguments)
+		suite.Require().NoError(err)
+		suite.Require().Equal(expected, res)
+		suite.execClient.AssertExpectations(suite.T())
+	})
+
+	// a block conflicting with the finalized block at the same height
+	forkBlock := unittest.BlockWithParentFixture(header)
+	forkBlock.Header.Height = header.Height
+	forkID := forkBlock.ID()
+	suite.headers.On("ByBlockID", forkID).Return(forkBlock.Header, nil)
+	suite.headers.On("BlockIDByHeight", header.Height).Return(header.ID(), nil)
+
+	suite.Run("local only - non-finalized block returns NotFound", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeLocalOnly)
+
+		_, err := backend.ExecuteScriptAtBlockID(ctx, forkID, script, arguments)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+	})
+
+	suite.Run("failover - non-finalized block is executed on execution nodes", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeFailover)
+		forkReceipts := make(flow.ExecutionReceiptList, 0, len(ids))
+		for _, id := range ids {
+			receipt := unittest.ReceiptForBlockFixture(forkBlock)
+			receipt.ExecutorID = id.NodeID
+			forkReceipts = append(forkReceipts, receipt)
+		}
+		forkReceipts[1].ExecutionResult = forkReceipts[0].ExecutionResult
+		suite.receipts.On("ByBlockID", forkID).Return(forkReceipts, nil)
+
+		execReq := &execproto.ExecuteScriptAtBlockIDRequest{
+			BlockId:   forkID[:],
+			Script:    script,
+			Arguments: arguments,
+		}
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, execReq).
+			Return(&execproto.ExecuteScriptAtBlockIDResponse{Value: expected}, nil).Once()
+
+		res, err := backend.ExecuteScriptAtBlockID(ctx, forkID, script, arguments)
+		suite.Require().NoError(err)
+		suite.Require().Equal(expected, res)
+		suite.execClient.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *Suite) TestExecuteScriptResultCache() {
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+	suite.state.On("Final").Return(suite.snapshot, nil).Maybe()
+
+	ctx := context.Background()
+	script := []byte("dummy script")
+	arguments := [][]byte{[]byte("argument")}
+	expected := []byte{4, 5, 6}
+
+	sealedBlock := unittest.BlockFixture()
+	unsealedBlock := unittest.BlockWithParentFixture(sealedBlock.Header)
+
+	for _, block := range []*flow.Block{&sealedBlock, unsealedBlock} {
+		suite.headers.On("ByBlockID", block.ID()).Return(block.Header, nil)
+		suite.headers.On("BlockIDByHeight", block.Header.Height).Return(block.ID(), nil)
+	}
+	suite.snapshot.On("Head").Return(sealedBlock.Header, nil)
+
+	_, ids := suite.setupReceipts(&sealedBlock)
+	unsealedReceipts := make(flow.ExecutionReceiptList, 0, len(ids))
+	for _, id := range ids {
+		receipt := unittest.ReceiptForBlockFixture(unsealedBlock)
+		receipt.ExecutorID = id.NodeID
+		unsealedReceipts = append(unsealedReceipts, receipt)
+	}
+	for _, receipt := range unsealedReceipts[1:] {
+		receipt.ExecutionResult = unsealedReceipts[0].ExecutionResult
+	}
+	suite.receipts.On("ByBlockID", unsealedBlock.ID()).Return(unsealedReceipts, nil)
+	suite.snapshot.On("Identities", mock.Anything).Return(ids, nil)
+
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		nil,
+		suite.headers,
+		nil,
+		nil,
+		suite.receipts,
+		suite.results,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		suite.setupConnectionFactory(),
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+	suite.Require().Error(backend.SetScriptResultCache(ScriptResultCacheConfig{Size: 10, MaxBytes: 4, MaxResultBytes: 8}))
+	suite.Require().NoError(backend.SetScriptResultCache(ScriptResultCacheConfig{Size: 10, MaxBytes: 1024, MaxResultBytes: 64}))
+
+	execRequest := func(blockID flow.Identifier) *execproto.ExecuteScriptAtBlockIDRequest {
+		return &execproto.ExecuteScriptAtBlockIDRequest{
+			BlockId:   blockID[:],
+			Script:    script,
+			Arguments: arguments,
+		}
+	}
+
+	suite.Run("results at sealed blocks are cached", func() {
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, execRequest(sealedBlock.ID())).
+			Return(&execproto.ExecuteScriptAtBlockIDResponse{Value: expected}, nil).Once()
+
+		for i := 0; i < 3; i++ {
+			res, err := backend.ExecuteScriptAtBlockID(ctx, sealedBlock.ID(), script, arguments)
+			suite.Require().NoError(err)
+			suite.Require().Equal(expected, res)
+		}
+		suite.execClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("results with different arguments are not shared", func() {
+		otherArguments := [][]byte{[]byte("argu"), []byte("ment")}
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, &execproto.ExecuteScriptAtBlockIDRequest{
+			BlockId:   convert.IdentifierToMessage(sealedBlock.ID()),
+			Script:    script,
+			Arguments: otherArguments,
+		}).Return(&execproto.ExecuteScriptAtBlockIDResponse{Value: []byte{7}}, nil).Once()
+
+		res, err := backend.ExecuteScriptAtBlockID(ctx, sealedBlock.ID(), script, otherArguments)
+		suite.Require().NoError(err)
+		suite.Require().Equal([]byte{7}, res)
+		suite.execClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("results larger than the maximum result size are not cached", func() {
+		largeScript := []byte("large result script")
+		large := make([]byte, 65)
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, &execproto.ExecuteScriptAtBlockIDRequest{
+			BlockId:   convert.IdentifierToMessage(sealedBlock.ID()),
+			Script:    largeScript,
+			Arguments: arguments,
+		}).Return(&execproto.ExecuteScriptAtBlockIDResponse{Value: large}, nil).Twice()
+
+		for i := 0; i < 2; i++ {
+			res, err := backend.ExecuteScriptAtBlockID(ctx, sealedBlock.ID(), largeScript, arguments)
+			suite.Require().NoError(err)
+			suite.Require().Equal(large, res)
+		}
+		suite.execClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("results at unsealed blocks are not cached", func() {
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, execRequest(unsealedBlock.ID())).
+			Return(&execproto.ExecuteScriptAtBlockIDResponse{Value: expected}, nil).Twice()
+
+		for i := 0; i < 2; i++ {
+			res, err := backend.ExecuteScriptAtBlockID(ctx, unsealedBlock.ID(), script, arguments)
+			suite.Require().NoError(err)
+			suite.Require().Equal(expected, res)
+		}
+		suite.execClient.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *Suite) TestExecuteScriptResultVerification() {
+	suite.state.On("Final").Return(suite.snapshot, nil).Maybe()
+
+	ctx := context.Background()
+	script := []byte("dummy script")
+	block := unittest.BlockFixture()
+
+	_, ids := suite.setupReceipts(&block)
+	suite.snapshot.On("Identities", mock.Anything).Return(ids, nil)
+
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		nil,
+		suite.headers,
+		nil,
+		nil,
+		suite.receipts,
+		suite.results,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		suite.setupConnectionFactory(),
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+	suite.Require().NoError(backend.SetResultVerification(ResultVerificationConfig{Nodes: 3, Quorum: 2}))
+
+	execReq := &execproto.ExecuteScriptAtBlockIDRequest{
+		BlockId: convert.IdentifierToMessage(block.ID()),
+		Script:  script,
+	}
+
+	suite.Run("matching results are returned", func() {
+		expected := []byte{4, 5, 6}
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, execReq).
+			Return(&execproto.ExecuteScriptAtBlockIDResponse{Value: expected}, nil).Twice()
+
+		res, err := backend.ExecuteScriptAtBlockID(ctx, block.ID(), script, nil)
+		suite.Require().NoError(err)
+		suite.Require().Equal(expected, res)
+		suite.execClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("mismatched results are rejected", func() {
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, execReq).
+			Return(&execproto.ExecuteScriptAtBlockIDResponse{Value: []byte{1}}, nil).Once()
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, execReq).
+			Return(&execproto.ExecuteScriptAtBlockIDResponse{Value: []byte{2}}, nil).Once()
+
+		_, err := backend.ExecuteScriptAtBlockID(ctx, block.ID(), script, nil)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.Aborted, status.Code(err))
+
+		var mismatch ResultMismatchError
+		suite.Require().ErrorAs(err, &mismatch)
+		suite.Require().Len(mismatch.Responses, 2)
+		suite.Require().ElementsMatch(ids.NodeIDs(), append(mismatch.Responses[0], mismatch.Responses[1]...))
+		suite.execClient.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *Suite) TestGetAccountLocally() {
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+	suite.state.On("Final").Return(suite.snapshot, nil).Maybe()
+
+	ctx := context.Background()
+	block := unittest.BlockFixture()
+	header := block.Header
+	address := unittest.AddressFixture()
+	account := &flow.Account{Address: address}
+
+	suite.headers.On("ByHeight", header.Height).Return(header, nil)
+
+	receipts, ids := suite.setupReceipts(&block)
+	suite.snapshot.On("Identities", mock.Anything).Return(ids, nil)
+
+	connFactory := new(backendmock.ConnectionFactory)
+	connFactory.On("GetExecutionAPIClient", mock.Anything).Return(suite.execClient, &mockCloser{}, nil)
+
+	scriptExecutor := backendmock.NewScriptExecutor(suite.T())
+
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		nil,
+		suite.headers,
+		nil,
+		nil,
+		suite.receipts,
+		suite.results,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		connFactory,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+
+	preferredENIdentifiers = flow.IdentifierList{receipts[0].ExecutorID}
+
+	suite.Run("local only - reads account locally", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeLocalOnly)
+		scriptExecutor.On("GetAccountAtBlockHeight", ctx, address, header.Height).Return(account, nil).Once()
+
+		res, err := backend.GetAccountAtBlockHeight(ctx, address, header.Height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account, res)
+	})
+
+	suite.Run("local only - unknown account returns NotFound", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeLocalOnly)
+		scriptExecutor.On("GetAccountAtBlockHeight", ctx, address, header.Height).Return(nil, storage.ErrNotFound).Once()
+
+		_, err := backend.GetAccountAtBlockHeight(ctx, address, header.Height)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+	})
+
+	suite.Run("failover - missing execution state falls back to execution nodes", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeFailover)
+		scriptExecutor.On("GetAccountAtBlockHeight", ctx, address, header.Height).
+			Return(nil, storage.ErrHeightNotIndexed).Once()
+
+		blockID := header.ID()
+		exeReq := &execproto.GetAccountAtBlockIDRequest{
+			BlockId: blockID[:],
+			Address: address.Bytes(),
+		}
+		exeResp := &execproto.GetAccountAtBlockIDResponse{
+			Account: &entitiesproto.Account{Address: address.Bytes()},
+		}
+		suite.execClient.On("GetAccountAtBlockID", ctx, exeReq).Return(exeResp, nil).Once()
+
+		res, err := backend.GetAccountAtBlockHeight(ctx, address, header.Height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(address, res.Address)
+		suite.execClient.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *Suite) TestGetAccountKeysAndBalance() {
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+	suite.state.On("Final").Return(suite.snapshot, nil).Maybe()
+
+	ctx := context.Background()
+	block := unittest.BlockFixture()
+	header := block.Header
+	account, err := unittest.AccountFixture()
+	suite.Require().NoError(err)
+	address := account.Address
+
+	suite.headers.On("ByHeight", header.Height).Return(header, nil)
+
+	receipts, ids := suite.setupReceipts(&block)
+	suite.snapshot.On("Identities", mock.Anything).Return(ids, nil)
+
+	registersClient := registersmock.NewClient(suite.T())
+	connFactory := new(backendmock.ConnectionFactory)
+	connFactory.On("GetExecutionAPIClient", mock.Anything).Return(suite.execClient, &mockCloser{}, nil)
+	connFactory.On("GetExecutionRegistersClient", mock.Anything).Return(registersClient, &mockCloser{}, nil)
+	connFactory.On("InvalidateExecutionAPIClient", mock.Anything)
+
+	scriptExecutor := backendmock.NewScriptExecutor(suite.T())
+
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		nil,
+		suite.headers,
+		nil,
+		nil,
+		suite.receipts,
+		suite.results,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		connFactory,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+
+	preferredENIdentifiers = flow.IdentifierList{receipts[0].ExecutorID}
+
+	blockID := header.ID()
+	registersReq := func(registerIDs ...flow.RegisterID) *registers.GetRegistersAtBlockIDRequest {
+		return &registers.GetRegistersAtBlockIDRequest{
+			BlockId:     blockID[:],
+			RegisterIds: registerIDs,
+		}
+	}
+	registersResp := func(values ...[]byte) *registers.GetRegistersAtBlockIDResponse {
+		return &registers.GetRegistersAtBlockIDResponse{Values: values}
+	}
+
+	suite.Require().Len(account.Keys, 1)
+	accountStatus := environment.NewAccountStatus()
+	accountStatus.SetPublicKeyCount(uint64(len(account.Keys)))
+	statusReq := registersReq(flow.AccountStatusRegisterID(address))
+	statusResp := registersResp(accountStatus.ToBytes())
+
+	encodedKey, err := flow.EncodeAccountPublicKey(account.Keys[0])
+	suite.Require().NoError(err)
+
+	encodedAddress, err := jsoncdc.Encode(cadence.NewAddress(address))
+	suite.Require().NoError(err)
+	encodedBalance, err := jsoncdc.Encode(cadence.UFix64(account.Balance))
+	suite.Require().NoError(err)
+	balanceReq := &execproto.ExecuteScriptAtBlockIDRequest{
+		BlockId:   blockID[:],
+		Script:    accountBalanceScript,
+		Arguments: [][]byte{encodedAddress},
+	}
+	balanceResp := &execproto.ExecuteScriptAtBlockIDResponse{Value: encodedBalance}
+
+	suite.Run("execution nodes only - reads only the needed registers and balance", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeExecutionNodesOnly)
+		// the keys are fetched in a single batch once their count is known, and a single key is
+		// fetched along with the account status
+		registersClient.On("GetRegistersAtBlockID", ctx, statusReq).Return(statusResp, nil).Twice()
+		registersClient.On("GetRegistersAtBlockID", ctx, registersReq(flow.PublicKeyRegisterID(address, 0))).
+			Return(registersResp(encodedKey), nil).Once()
+		registersClient.On("GetRegistersAtBlockID", ctx, registersReq(flow.AccountStatusRegisterID(address), flow.PublicKeyRegisterID(address, 0))).
+			Return(registersResp(accountStatus.ToBytes(), encodedKey), nil).Once()
+		registersClient.On("GetRegistersAtBlockID", ctx, registersReq(flow.AccountStatusRegisterID(address), flow.PublicKeyRegisterID(address, 1))).
+			Return(registersResp(accountStatus.ToBytes(), nil), nil).Once()
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, balanceReq).Return(balanceResp, nil).Once()
+
+		keys, err := backend.GetAccountKeysAtBlockHeight(ctx, address, header.Height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Keys, keys)
+
+		key, err := backend.GetAccountKeyAtBlockHeight(ctx, address, 0, header.Height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Keys[0], *key)
+
+		_, err = backend.GetAccountKeyAtBlockHeight(ctx, address, 1, header.Height)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+
+		balance, err := backend.GetAccountBalanceAtBlockHeight(ctx, address, header.Height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Balance, balance)
+		suite.execClient.AssertNotCalled(suite.T(), "GetAccountAtBlockID", mock.Anything, mock.Anything)
+		suite.execClient.AssertNotCalled(suite.T(), "GetRegisterAtBlockID", mock.Anything, mock.Anything)
+		suite.execClient.AssertExpectations(suite.T())
+		registersClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("execution nodes only - unknown account returns NotFound", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeExecutionNodesOnly)
+		unknown := unittest.RandomAddressFixture()
+		registersClient.On("GetRegistersAtBlockID", ctx, registersReq(flow.AccountStatusRegisterID(unknown))).
+			Return(registersResp(nil), nil).Once()
+
+		_, err := backend.GetAccountBalanceAtBlockHeight(ctx, unknown, header.Height)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+		registersClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("execution nodes only - unavailable execution node is retried", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeExecutionNodesOnly)
+		preferredENIdentifiers = ids.NodeIDs()
+		defer func() {
+			preferredENIdentifiers = flow.IdentifierList{receipts[0].ExecutorID}
+		}()
+
+		registersClient.On("GetRegistersAtBlockID", ctx, statusReq).
+			Return(nil, status.Error(codes.Unavailable, "")).Once()
+		registersClient.On("GetRegistersAtBlockID", ctx, statusReq).Return(statusResp, nil).Once()
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, balanceReq).Return(balanceResp, nil).Once()
+
+		balance, err := backend.GetAccountBalanceAtBlockHeight(ctx, address, header.Height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Balance, balance)
+		registersClient.AssertExpectations(suite.T())
+		suite.execClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("execution nodes only - request errors are not retried", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeExecutionNodesOnly)
+		preferredENIdentifiers = ids.NodeIDs()
+		defer func() {
+			preferredENIdentifiers = flow.IdentifierList{receipts[0].ExecutorID}
+		}()
+
+		registersClient.On("GetRegistersAtBlockID", ctx, statusReq).
+			Return(nil, status.Error(codes.InvalidArgument, "")).Once()
+
+		_, err := backend.GetAccountKeysAtBlockHeight(ctx, address, header.Height)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+		registersClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("local only - reads keys and balance locally", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeLocalOnly)
+		scriptExecutor.On("GetAccountKeysAtBlockHeight", ctx, address, header.Height).Return(account.Keys, nil).Once()
+		scriptExecutor.On("GetAccountKeyAtBlockHeight", ctx, address, uint64(0), header.Height).Return(&account.Keys[0], nil).Once()
+		scriptExecutor.On("GetAccountBalanceAtBlockHeight", ctx, address, header.Height).Return(account.Balance, nil).Once()
+
+		keys, err := backend.GetAccountKeysAtBlockHeight(ctx, address, header.Height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Keys, keys)
+
+		key, err := backend.GetAccountKeyAtBlockHeight(ctx, address, 0, header.Height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Keys[0], *key)
+
+		balance, err := backend.GetAccountBalanceAtBlockHeight(ctx, address, header.Height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Balance, balance)
+	})
+
+	suite.Run("local only - unknown key returns NotFound", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeLocalOnly)
+		scriptExecutor.On("GetAccountKeyAtBlockHeight", ctx, address, uint64(1), header.Height).
+			Return(nil, storage.ErrNotFound).Once()
+
+		_, err := backend.GetAccountKeyAtBlockHeight(ctx, address, 1, header.Height)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+	})
+
+	suite.Run("failover - missing execution state falls back to execution nodes", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeFailover)
+		scriptExecutor.On("GetAccountBalanceAtBlockHeight", ctx, address, header.Height).
+			Return(uint64(0), storage.ErrHeightNotIndexed).Once()
+		registersClient.On("GetRegistersAtBlockID", ctx, statusReq).Return(statusResp, nil).Once()
+		suite.execClient.On("ExecuteScriptAtBlockID", ctx, balanceReq).Return(balanceResp, nil).Once()
+
+		balance, err := backend.GetAccountBalanceAtBlockHeight(ctx, address, header.Height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Balance, balance)
+		suite.execClient.AssertExpectations(suite.T())
+		registersClient.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *Suite) TestSimulateTransaction() {
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+
+	ctx := context.Background()
+	header := unittest.BlockHeaderFixture()
+	suite.snapshot.On("Head").Return(header, nil).Maybe()
+
+	scriptExecutor := backendmock.NewScriptExecutor(suite.T())
+
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		nil,
+		suite.headers,
+		nil,
+		nil,
+		suite.receipts,
+		suite.results,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		nil,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+
+	tx := unittest.TransactionBodyFixture()
+	tx.GasLimit = 0
+
+	suite.Run("execution nodes only - simulation is not supported", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeExecutionNodesOnly)
+
+		_, err := backend.SimulateTransaction(ctx, &tx)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.Unimplemented, status.Code(err))
+	})
+
+	suite.Run("simulates at the latest sealed block with the maximum gas limit", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeLocalOnly)
+
+		simulation := &execution.TransactionSimulation{
+			ComputationUsed: 12,
+			MemoryEstimate:  3456,
+			Events:          []flow.Event{unittest.EventFixture(flow.EventAccountCreated, 0, 0, tx.ID(), 0)},
+			StorageDelta:    map[flow.Address]int64{tx.Payer: 120},
+			Fee:             1000,
+			Err:             fvmerrors.NewComputationLimitExceededError(flow.DefaultMaxTransactionGasLimit),
+		}
+		scriptExecutor.
+			On("SimulateTransactionAtBlockHeight", ctx, mock.MatchedBy(func(simulated *flow.TransactionBody) bool {
+				return simulated.GasLimit == flow.DefaultMaxTransactionGasLimit
+			}), header.Height).
+			Return(simulation, nil).Once()
+
+		result, err := backend.SimulateTransaction(ctx, &tx)
+		suite.Require().NoError(err)
+		suite.Require().Equal(uint64(0), tx.GasLimit, "the transaction must not be modified")
+		suite.Require().Equal(&accessapi.TransactionSimulationResult{
+			ComputationUsed: simulation.ComputationUsed,
+			MemoryEstimate:  simulation.MemoryEstimate,
+			Events:          simulation.Events,
+			StorageDelta:    simulation.StorageDelta,
+			Fee:             simulation.Fee,
+			ErrorMessage:    simulation.Err.Error(),
+			BlockID:         header.ID(),
+			BlockHeight:     header.Height,
+		}, result)
+	})
+
+	suite.Run("gas limit above the maximum is rejected", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeLocalOnly)
+
+		tx := unittest.TransactionBodyFixture()
+		tx.GasLimit = flow.DefaultMaxTransactionGasLimit + 1
+
+		_, err := backend.SimulateTransaction(ctx, &tx)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("missing execution state returns OutOfRange", func() {
+		backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeFailover)
+		scriptExecutor.On("SimulateTransactionAtBlockHeight", ctx, mock.Anything, header.Height).
+			Return(nil, storage.ErrHeightNotIndexed).Once()
+
+		_, err := backend.SimulateTransaction(ctx, &tx)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.OutOfRange, status.Code(err))
+	})
+}
+
+func (suite *Suite) TestGetEventsForHeightRangeWithFilter() {
+	ctx := context.Background()
+
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		nil,
+		suite.headers,
+		nil,
+		nil,
+		suite.receipts,
+		suite.results,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		nil,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+
+	contract := unittest.RandomAddressFixture()
+	recipient := unittest.RandomAddressFixture()
+	txID := unittest.IdentifierFixture()
+
+	deposit := unittest.TokensDepositedEventFixture(contract, recipient, 100, txID, 0, 0)
+	otherDeposit := unittest.TokensDepositedEventFixture(contract, unittest.RandomAddressFixture(), 100, txID, 0, 1)
+	blockEvents := []flow.BlockEvents{
+		{
+			BlockID:     unittest.IdentifierFixture(),
+			BlockHeight: 10,
+			Events:      []flow.Event{deposit, otherDeposit},
+		},
+	}
+
+	suite.Run("events are not indexed", func() {
+		_, err := backend.GetEventsForHeightRangeWithFilter(ctx, accessapi.EventFilter{TransactionID: &txID}, 10, 20)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.Unimplemented, status.Code(err))
+	})
+
+	eventIndex := storagemock.NewEventIndex(suite.T())
+	eventIndex.On("FirstHeight").Return(uint64(10), nil).Maybe()
+	eventIndex.On("LatestHeight").Return(uint64(15), nil).Maybe()
+	backend.SetEventIndex(eventIndex)
+
+	suite.Run("invalid filters", func() {
+		for _, filter := range []accessapi.EventFilter{
+			{},
+			{FieldName: "to", FieldValue: recipient.Hex(), ContractAddress: &contract},
+			{EventType: deposit.Type, FieldValue: recipient.Hex()},
+		} {
+			_, err := backend.GetEventsForHeightRangeWithFilter(ctx, filter, 10, 20)
+			suite.Require().Error(err)
+			suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+		}
+	})
+
+	suite.Run("start height outside the indexed range", func() {
+		_, err := backend.GetEventsForHeightRangeWithFilter(ctx, accessapi.EventFilter{TransactionID: &txID}, 9, 20)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.OutOfRange, status.Code(err))
+
+		_, err = backend.GetEventsForHeightRangeWithFilter(ctx, accessapi.EventFilter{TransactionID: &txID}, 16, 20)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.OutOfRange, status.Code(err))
+	})
+
+	suite.Run("by transaction, limited to the last indexed height", func() {
+		eventIndex.On("ByTransactionID", txID, uint64(10), uint64(15)).Return(blockEvents, nil).Once()
+
+		results, err := backend.GetEventsForHeightRangeWithFilter(ctx, accessapi.EventFilter{TransactionID: &txID}, 10, 20)
+		suite.Require().NoError(err)
+		suite.Require().Equal(blockEvents, results)
+	})
+
+	suite.Run("by field value", func() {
+		eventIndex.On("ByFieldValue", deposit.Type, "to", recipient.Hex(), uint64(10), uint64(12)).Return(blockEvents, nil).Once()
+
+		filter := accessapi.EventFilter{EventType: deposit.Type, FieldName: "to", FieldValue: recipient.Hex()}
+		results, err := backend.GetEventsForHeightRangeWithFilter(ctx, filter, 10, 12)
+		suite.Require().NoError(err)
+		suite.Require().Len(results, 1)
+		suite.Require().Equal([]flow.Event{deposit}, results[0].Events)
+	})
+
+	suite.Run("by value of a field which is not indexed", func() {
+		eventIndex.On("ByFieldValue", deposit.Type, "amount", "0.00000100", uint64(10), uint64(12)).Return(nil, storage.ErrFieldNotIndexed).Once()
+		eventIndex.On("ByContractAddress", contract, uint64(10), uint64(12)).Return(blockEvents, nil).Once()
+
+		filter := accessapi.EventFilter{EventType: deposit.Type, FieldName: "amount", FieldValue: "0.00000100"}
+		results, err := backend.GetEventsForHeightRangeWithFilter(ctx, filter, 10, 12)
+		suite.Require().NoError(err)
+		suite.Require().Len(results, 1)
+		suite.Require().Equal([]flow.Event{deposit, otherDeposit}, results[0].Events)
+
+		eventIndex.On("ByFieldValue", flow.EventAccountCreated, "address", recipient.Hex(), uint64(10), uint64(12)).Return(nil, storage.ErrFieldNotIndexed).Once()
+
+		filter = accessapi.EventFilter{EventType: flow.EventAccountCreated, FieldName: "address", FieldValue: recipient.Hex()}
+		_, err = backend.GetEventsForHeightRangeWithFilter(ctx, filter, 10, 12)
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("by event type of a contract, excluding blocks without matching events", func() {
+		eventIndex.On("ByContractAddress", contract, uint64(10), uint64(12)).Return(blockEvents, nil).Once()
+
+		results, err := backend.GetEventsForHeightRangeWithFilter(ctx, accessapi.EventFilter{EventType: "A." + flow.EventType(contract.Hex()) + ".FlowToken.TokensWithdrawn"}, 10, 12)
+		suite.Require().NoError(err)
+		suite.Require().Empty(results)
+	})
+}
+
+func (suite *Suite) TestGetTransactionsByAddress() {
+	ctx := context.Background()
+
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		nil,
+		suite.headers,
+		nil,
+		nil,
+		suite.receipts,
+		suite.results,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		nil,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+
+	address := unittest.RandomAddressFixture()
+	accountTxs := make([]flow.AccountTransaction, 3)
+	for i := range accountTxs {
+		accountTxs[i] = flow.AccountTransaction{
+			Address:       address,
+			BlockHeight:   uint64(12 - i),
+			TransactionID: unittest.IdentifierFixture(),
+			Roles:         flow.TransactionRolePayer,
+		}
+	}
+
+	suite.Run("account transactions are not indexed", func() {
+		_, err := backend.GetTransactionsByAddress(ctx, address, 0, nil)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.Unimplemented, status.Code(err))
+	})
+
+	index := storagemock.NewAccountTransactions(suite.T())
+	backend.SetAccountTransactionIndex(index)
+
+	suite.Run("limit exceeds the maximum", func() {
+		_, err := backend.GetTransactionsByAddress(ctx, address, MaxAccountTransactionsLimit+1, nil)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("first page with the default limit", func() {
+		index.On("ByAddress", address, (*flow.AccountTransactionCursor)(nil), uint(DefaultAccountTransactionsLimit+1)).Return(accountTxs, nil).Once()
+
+		page, err := backend.GetTransactionsByAddress(ctx, address, 0, nil)
+		suite.Require().NoError(err)
+		suite.Require().Equal(accountTxs, page.Transactions)
+		suite.Require().Nil(page.NextCursor)
+	})
+
+	suite.Run("page with a next page", func() {
+		cursor := &flow.AccountTransactionCursor{BlockHeight: 12, TransactionID: accountTxs[0].TransactionID}
+		index.On("ByAddress", address, cursor, uint(3)).Return(accountTxs, nil).Once()
+
+		page, err := backend.GetTransactionsByAddress(ctx, address, 2, cursor)
+		suite.Require().NoError(err)
+		suite.Require().Equal(accountTxs[:2], page.Transactions)
+		suite.Require().Equal(&flow.AccountTransactionCursor{BlockHeight: 10, TransactionID: accountTxs[2].TransactionID}, page.NextCursor)
+	})
+}
+
 func (suite *Suite) assertAllExpectations() {
 	suite.snapshot.AssertExpectations(suite.T())
 	suite.state.AssertExpectations(suite.T())
@@ -0,0 +1,69 @@
+package backend
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SimulateTransaction executes the transaction against the latest sealed execution state without
+// submitting it, and returns the computation and memory it used, the events it emitted, the
+// changes of the storage used by the accounts it updated and the fee it would be charged.
+//
+// Signatures and the proposal key sequence number are not checked, so the transaction can be
+// simulated before it is signed. If the gas limit is not set, the transaction is simulated with the
+// maximum gas limit accepted by this node, so the computation used can be used as the gas limit.
+// Transactions with a gas limit above the maximum are rejected.
+//
+// Execution nodes do not support simulating transactions, so the local execution state must be
+// available.
+func (b *backendTransactions) SimulateTransaction(ctx context.Context, tx *flow.TransactionBody) (*access.TransactionSimulationResult, error) {
+	if b.scriptExecutor == nil || b.scriptExecMode == ScriptExecutionModeExecutionNodesOnly {
+		return nil, status.Errorf(codes.Unimplemented, "transaction simulation requires the local execution state to be indexed")
+	}
+
+	if len(tx.Script) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "transaction script must not be empty")
+	}
+
+	maxGasLimit := b.transactionValidator.MaxGasLimit()
+	if tx.GasLimit > maxGasLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "transaction gas limit (%d) exceeds the maximum gas limit (%d)", tx.GasLimit, maxGasLimit)
+	}
+
+	if tx.GasLimit == 0 {
+		simulated := *tx
+		simulated.GasLimit = maxGasLimit
+		tx = &simulated
+	}
+
+	header, err := b.state.Sealed().Head()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
+	}
+
+	simulation, err := b.scriptExecutor.SimulateTransactionAtBlockHeight(ctx, tx, header.Height)
+	if err != nil {
+		return nil, convertLocalExecutionError(err, "failed to simulate transaction")
+	}
+
+	var errorMessage string
+	if simulation.Err != nil {
+		errorMessage = simulation.Err.Error()
+	}
+
+	return &access.TransactionSimulationResult{
+		ComputationUsed: simulation.ComputationUsed,
+		MemoryEstimate:  simulation.MemoryEstimate,
+		Events:          simulation.Events,
+		StorageDelta:    simulation.StorageDelta,
+		Fee:             simulation.Fee,
+		ErrorMessage:    errorMessage,
+		BlockID:         header.ID(),
+		BlockHeight:     header.Height,
+	}, nil
+}
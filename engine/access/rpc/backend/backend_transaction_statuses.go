@@ -0,0 +1,266 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/engine/common/rpc"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// SendAndSubscribeTransactionStatuses sends the transaction to a collection node, then streams the
+// transaction's result each time its status changes. See SubscribeTransactionStatuses.
+//
+// If the transaction is invalid or could not be sent, the returned subscription has already failed
+// with the same error SendTransaction would return.
+func (b *backendTransactions) SendAndSubscribeTransactionStatuses(ctx context.Context, tx *flow.TransactionBody) state_stream.Subscription {
+	err := b.SendTransaction(ctx, tx)
+	if err != nil {
+		sub := state_stream.NewSubscription(state_stream.DefaultSendBufferSize)
+		sub.Fail(err)
+		return sub
+	}
+
+	return b.SubscribeTransactionStatuses(ctx, tx.ID())
+}
+
+// SubscribeTransactionStatuses streams the result of the transaction each time its status changes,
+// starting with its current status. The subscription is closed once the transaction is either
+// sealed or expired.
+//
+// Each message sent on the subscription channel is an *access.TransactionResult. The status is
+// re-evaluated whenever the ingestion engine processes a finalized block, a collection or an
+// execution receipt, so transitions are pushed as soon as they are observable by this node.
+func (b *backendTransactions) SubscribeTransactionStatuses(ctx context.Context, txID flow.Identifier) state_stream.Subscription {
+	sub := state_stream.NewSubscription(state_stream.DefaultSendBufferSize)
+
+	go b.streamTransactionStatuses(ctx, sub, txID)
+
+	return sub
+}
+
+// NotifyTransactionStatusUpdate notifies all transaction status subscriptions that the status of
+// their transaction may have changed.
+func (b *backendTransactions) NotifyTransactionStatusUpdate() {
+	b.txStatusBroadcaster.Publish()
+}
+
+// streamTransactionStatuses sends the transaction's result to the subscription every time the
+// transaction's status changes, until it reaches a final status or the context is cancelled.
+func (b *backendTransactions) streamTransactionStatuses(ctx context.Context, sub *state_stream.SubscriptionImpl, txID flow.Identifier) {
+	lg := b.log.With().Str("sub_id", sub.ID()).Hex("tx_id", txID[:]).Logger()
+
+	lg.Debug().Msg("starting transaction status streaming")
+	defer lg.Debug().Msg("finished transaction status streaming")
+
+	watched := b.watchedTransactions.watch(txID)
+	defer b.watchedTransactions.unwatch(txID)
+
+	notifier := engine.NewNotifier()
+	b.txStatusBroadcaster.Subscribe(notifier)
+	defer b.txStatusBroadcaster.Unsubscribe(notifier)
+
+	// always check the first time, so the client receives the current status immediately
+	notifier.Notify()
+
+	sent := false
+	lastStatus := flow.TransactionStatusUnknown
+	for {
+		select {
+		case <-ctx.Done():
+			sub.Fail(fmt.Errorf("client disconnected: %w", ctx.Err()))
+			return
+		case <-notifier.Channel():
+		}
+
+		result, err := b.watchedTransactionResult(ctx, watched)
+		if err != nil {
+			lg.Err(err).Msg("could not get transaction result")
+			sub.Fail(err)
+			return
+		}
+
+		if sent && result.Status == lastStatus {
+			continue
+		}
+
+		err = sub.Send(ctx, result, state_stream.DefaultSendTimeout)
+		if err != nil {
+			lg.Err(err).Msg("error sending response")
+			sub.Fail(err)
+			return
+		}
+		sent = true
+		lastStatus = result.Status
+
+		if result.Status == flow.TransactionStatusSealed || result.Status == flow.TransactionStatusExpired {
+			sub.Close()
+			return
+		}
+	}
+}
+
+// watchedTransactionResult returns the result of the watched transaction. The result is only
+// queried again when the local state the result is derived from has changed since the last query,
+// so updates which do not concern the transaction cost no request to execution nodes, and all
+// subscriptions of the transaction share the same queries.
+func (b *backendTransactions) watchedTransactionResult(ctx context.Context, watched *watchedTransaction) (*access.TransactionResult, error) {
+	watched.mu.Lock()
+	defer watched.mu.Unlock()
+
+	state, err := b.transactionStateOf(watched.txID)
+	if err != nil {
+		return nil, err
+	}
+
+	if watched.result == nil || state != watched.state {
+		result, err := b.GetTransactionResult(ctx, watched.txID)
+		if err != nil {
+			return nil, err
+		}
+		watched.result = result
+		watched.state = state
+	}
+
+	// the submissions are tracked independently of the status, so they are always current
+	result := *watched.result
+	result.Submissions = b.submissionsOf(watched.txID)
+
+	return &result, nil
+}
+
+// transactionState is the local state the result of a transaction is derived from. The result of
+// the transaction may only change when this state changes.
+type transactionState struct {
+	// stored is whether the transaction is in the local storage. Results of other transactions are
+	// looked up from the access nodes of previous sporks.
+	stored bool
+
+	// finalizedHeight and fullHeight are the latest finalized height and the last full block
+	// height, which determine whether a pending transaction has expired. They are only set while
+	// the transaction is not included in a finalized block.
+	finalizedHeight uint64
+	fullHeight      uint64
+
+	// blockID is the ID of the finalized block including the transaction, if any.
+	blockID flow.Identifier
+
+	// receipts is the number of execution receipts of the block, which determines whether an
+	// execution node may have executed the transaction.
+	receipts int
+
+	// sealed is whether the block is sealed.
+	sealed bool
+}
+
+// transactionStateOf returns the local state the result of the transaction is derived from. It
+// only reads the local storage and protocol state.
+func (b *backendTransactions) transactionStateOf(txID flow.Identifier) (transactionState, error) {
+	var state transactionState
+
+	_, err := b.transactions.ByID(txID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return state, nil
+		}
+		return state, rpc.ConvertStorageError(err)
+	}
+	state.stored = true
+
+	block, err := b.lookupBlock(txID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return state, rpc.ConvertStorageError(err)
+	}
+
+	if block == nil {
+		finalized, err := b.state.Final().Head()
+		if err != nil {
+			return state, rpc.ConvertStorageError(err)
+		}
+		state.finalizedHeight = finalized.Height
+
+		state.fullHeight, err = b.blocks.GetLastFullBlockHeight()
+		if err != nil {
+			return state, rpc.ConvertStorageError(err)
+		}
+
+		return state, nil
+	}
+
+	state.blockID = block.ID()
+
+	receipts, err := b.executionReceipts.ByBlockID(state.blockID)
+	if err != nil {
+		return state, rpc.ConvertStorageError(err)
+	}
+	state.receipts = len(receipts)
+
+	sealed, err := b.state.Sealed().Head()
+	if err != nil {
+		return state, rpc.ConvertStorageError(err)
+	}
+	state.sealed = block.Header.Height <= sealed.Height
+
+	return state, nil
+}
+
+// watchedTransaction is the latest result of a transaction with status subscriptions, shared by
+// all the subscriptions of the transaction.
+type watchedTransaction struct {
+	txID flow.Identifier
+
+	mu     sync.Mutex
+	state  transactionState
+	result *access.TransactionResult
+
+	subscriptions int // guarded by the lock of watchedTransactions
+}
+
+// watchedTransactions are the transactions with status subscriptions.
+type watchedTransactions struct {
+	mu           sync.Mutex
+	transactions map[flow.Identifier]*watchedTransaction
+}
+
+func newWatchedTransactions() *watchedTransactions {
+	return &watchedTransactions{
+		transactions: make(map[flow.Identifier]*watchedTransaction),
+	}
+}
+
+// watch returns the watched transaction for a new subscription of the transaction.
+func (w *watchedTransactions) watch(txID flow.Identifier) *watchedTransaction {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	watched, ok := w.transactions[txID]
+	if !ok {
+		watched = &watchedTransaction{txID: txID}
+		w.transactions[txID] = watched
+	}
+	watched.subscriptions++
+
+	return watched
+}
+
+// unwatch releases the watched transaction of a finished subscription of the transaction. The
+// transaction is no longer watched once all its subscriptions have finished.
+func (w *watchedTransactions) unwatch(txID flow.Identifier) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	watched, ok := w.transactions[txID]
+	if !ok {
+		return
+	}
+	watched.subscriptions--
+	if watched.subscriptions == 0 {
+		delete(w.transactions, txID)
+	}
+}
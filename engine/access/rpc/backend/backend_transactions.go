@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/fvm/blueprints"
@@ -38,6 +39,12 @@ type backendTransactions struct {
 	transactionValidator *access.TransactionValidator
 	retry                *Retry
 	connFactory          ConnectionFactory
+	txStatusBroadcaster  *engine.Broadcaster
+	watchedTransactions  *watchedTransactions // transactions with status subscriptions
+	scriptExecutor       ScriptExecutor
+	scriptExecMode       ScriptExecutionMode
+	nodeHealth           *ExecutionNodeHealth
+	submissions          *submissionTracker // optional tracker of sent transactions
 
 	previousAccessNodes []accessproto.AccessAPIClient
 	log                 zerolog.Logger
@@ -56,7 +63,12 @@ func (b *backendTransactions) SendTransaction(
 	}
 
 	// send the transaction to the collection node if valid
-	err = b.trySendTransaction(ctx, tx)
+	var submissions []access.TransactionSubmission
+	if b.submissions != nil {
+		submissions, err = b.submitTransaction(ctx, tx, nil)
+	} else {
+		err = b.trySendTransaction(ctx, tx)
+	}
 	if err != nil {
 		b.transactionMetrics.TransactionSubmissionFailed()
 		return status.Error(codes.Internal, fmt.Sprintf("failed to send transaction to a collection node: %v", err))
@@ -75,6 +87,8 @@ func (b *backendTransactions) SendTransaction(
 		go b.registerTransactionForRetry(tx)
 	}
 
+	b.trackTransaction(tx, submissions)
+
 	return nil
 }
 
@@ -86,10 +100,24 @@ func (b *backendTransactions) trySendTransaction(ctx context.Context, tx *flow.T
 		return b.grpcTxSend(ctx, b.staticCollectionRPC, tx)
 	}
 
-	// otherwise choose a random set of collections nodes to try
-	collAddrs, err := b.chooseCollectionNodes(tx, collectionNodesToTry)
+	_, err := b.submitTransaction(ctx, tx, nil)
+	return err
+}
+
+// submitTransaction tries to send the transaction to a random set of collection nodes of its cluster,
+// in order until one of them accepts it. Collection nodes whose address is in the excluded set are only
+// chosen if all nodes of the cluster are excluded. It returns a submission for each collection node the
+// transaction was sent to, without height.
+func (b *backendTransactions) submitTransaction(
+	ctx context.Context,
+	tx *flow.TransactionBody,
+	excluded map[string]struct{},
+) ([]access.TransactionSubmission, error) {
+
+	// choose a random set of collections nodes to try
+	collAddrs, err := b.chooseCollectionNodes(tx, collectionNodesToTry, excluded)
 	if err != nil {
-		return fmt.Errorf("failed to determine collection node for tx %x: %w", tx, err)
+		return nil, fmt.Errorf("failed to determine collection node for tx %x: %w", tx, err)
 	}
 
 	var sendErrors *multierror.Error
@@ -102,20 +130,28 @@ func (b *backendTransactions) trySendTransaction(ctx context.Context, tx *flow.T
 	defer logAnyError()
 
 	// try sending the transaction to one of the chosen collection nodes
+	var submissions []access.TransactionSubmission
 	for _, addr := range collAddrs {
 		err = b.sendTransactionToCollector(ctx, tx, addr)
+		submission := access.TransactionSubmission{CollectionNode: addr}
 		if err == nil {
-			return nil
+			return append(submissions, submission), nil
 		}
+		submission.Error = err.Error()
+		submissions = append(submissions, submission)
 		sendErrors = multierror.Append(sendErrors, err)
 	}
 
-	return sendErrors.ErrorOrNil()
+	return submissions, sendErrors.ErrorOrNil()
 }
 
 // chooseCollectionNodes finds a random subset of size sampleSize of collection node addresses from the
-// collection node cluster responsible for the given tx
-func (b *backendTransactions) chooseCollectionNodes(tx *flow.TransactionBody, sampleSize uint) ([]string, error) {
+// collection node cluster responsible for the given tx, preferring nodes whose address is not excluded
+func (b *backendTransactions) chooseCollectionNodes(
+	tx *flow.TransactionBody,
+	sampleSize uint,
+	excluded map[string]struct{},
+) ([]string, error) {
 
 	// retrieve the set of collector clusters
 	clusters, err := b.state.Final().Epochs().Current().Clustering()
@@ -129,8 +165,17 @@ func (b *backendTransactions) chooseCollectionNodes(tx *flow.TransactionBody, sa
 		return nil, fmt.Errorf("could not get local cluster by txID: %x", tx.ID())
 	}
 
+	// only choose excluded collection nodes if there are no others left
+	candidates := txCluster.Filter(func(id *flow.Identity) bool {
+		_, ok := excluded[id.Address]
+		return !ok
+	})
+	if len(candidates) == 0 {
+		candidates = txCluster
+	}
+
 	// select a random subset of collection nodes from the cluster to be tried in order
-	targetNodes := txCluster.Sample(sampleSize)
+	targetNodes := candidates.Sample(sampleSize)
 
 	// collect the addresses of all the chosen collection nodes
 	var targetAddrs = make([]string, len(targetNodes))
@@ -296,6 +341,7 @@ func (b *backendTransactions) GetTransactionResult(
 		BlockID:       blockID,
 		TransactionID: txID,
 		BlockHeight:   blockHeight,
+		Submissions:   b.submissionsOf(txID),
 	}, nil
 }
 
@@ -312,7 +358,7 @@ func (b *backendTransactions) GetTransactionResultsByBlockID(
 	req := &execproto.GetTransactionsByBlockIDRequest{
 		BlockId: blockID[:],
 	}
-	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.log)
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeHealth, b.log)
 	if err != nil {
 		_, isInsufficientExecReceipts := err.(*InsufficientExecutionReceipts)
 		if isInsufficientExecReceipts {
@@ -422,7 +468,7 @@ func (b *backendTransactions) GetTransactionResultByIndex(
 		BlockId: blockID[:],
 		Index:   index,
 	}
-	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.log)
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeHealth, b.log)
 	if err != nil {
 		_, isInsufficientExecReceipts := err.(*InsufficientExecutionReceipts)
 		if isInsufficientExecReceipts {
@@ -647,7 +693,7 @@ func (b *backendTransactions) getTransactionResultFromExecutionNode(
 		TransactionId: transactionID,
 	}
 
-	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.log)
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeHealth, b.log)
 	if err != nil {
 		// if no execution receipt were found, return a NotFound GRPC error
 		if errors.As(err, &InsufficientExecutionReceipts{}) {
@@ -668,6 +714,10 @@ func (b *backendTransactions) getTransactionResultFromExecutionNode(
 
 func (b *backendTransactions) NotifyFinalizedBlockHeight(height uint64) {
 	b.retry.Retry(height)
+
+	if b.submissions != nil {
+		go b.resubmitTransactions(height)
+	}
 }
 
 func (b *backendTransactions) getTransactionResultFromAnyExeNode(
@@ -713,7 +763,9 @@ func (b *backendTransactions) tryGetTransactionResult(
 	}
 	defer closer.Close()
 
+	startTime := time.Now()
 	resp, err := execRPCClient.GetTransactionResult(ctx, req)
+	b.nodeHealth.RecordRequest(ctx, execNode.NodeID, time.Since(startTime), err)
 	if err != nil {
 		if status.Code(err) == codes.Unavailable {
 			b.connFactory.InvalidateExecutionAPIClient(execNode.Address)
@@ -771,7 +823,9 @@ func (b *backendTransactions) tryGetTransactionResultsByBlockID(
 	}
 	defer closer.Close()
 
+	startTime := time.Now()
 	resp, err := execRPCClient.GetTransactionResultsByBlockID(ctx, req)
+	b.nodeHealth.RecordRequest(ctx, execNode.NodeID, time.Since(startTime), err)
 	if err != nil {
 		if status.Code(err) == codes.Unavailable {
 			b.connFactory.InvalidateExecutionAPIClient(execNode.Address)
@@ -830,7 +884,9 @@ func (b *backendTransactions) tryGetTransactionResultByIndex(
 	}
 	defer closer.Close()
 
+	startTime := time.Now()
 	resp, err := execRPCClient.GetTransactionResultByIndex(ctx, req)
+	b.nodeHealth.RecordRequest(ctx, execNode.NodeID, time.Since(startTime), err)
 	if err != nil {
 		if status.Code(err) == codes.Unavailable {
 			b.connFactory.InvalidateExecutionAPIClient(execNode.Address)
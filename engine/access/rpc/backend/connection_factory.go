@@ -17,6 +17,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
 
+	"github.com/onflow/flow-go/engine/execution/rpc/registers"
 	"github.com/onflow/flow-go/module"
 )
 
@@ -28,6 +29,7 @@ type ConnectionFactory interface {
 	GetAccessAPIClient(address string) (access.AccessAPIClient, io.Closer, error)
 	InvalidateAccessAPIClient(address string)
 	GetExecutionAPIClient(address string) (execution.ExecutionAPIClient, io.Closer, error)
+	GetExecutionRegistersClient(address string) (registers.Client, io.Closer, error)
 	InvalidateExecutionAPIClient(address string)
 }
 
@@ -50,6 +52,10 @@ func (p *ProxyConnectionFactory) GetExecutionAPIClient(address string) (executio
 	return p.ConnectionFactory.GetExecutionAPIClient(p.targetAddress)
 }
 
+func (p *ProxyConnectionFactory) GetExecutionRegistersClient(address string) (registers.Client, io.Closer, error) {
+	return p.ConnectionFactory.GetExecutionRegistersClient(p.targetAddress)
+}
+
 type ConnectionFactoryImpl struct {
 	CollectionGRPCPort        uint
 	ExecutionGRPCPort         uint
@@ -204,6 +210,34 @@ func (cf *ConnectionFactoryImpl) GetExecutionAPIClient(address string) (executio
 	return executionAPIClient, closer, nil
 }
 
+// GetExecutionRegistersClient returns a client of the batched register RPCs of the execution node,
+// which are served alongside its execution API and share its connection.
+func (cf *ConnectionFactoryImpl) GetExecutionRegistersClient(address string) (registers.Client, io.Closer, error) {
+
+	grpcAddress, err := getGRPCAddress(address, cf.ExecutionGRPCPort)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conn *grpc.ClientConn
+	if cf.ConnectionsCache != nil {
+		conn, err = cf.retrieveConnection(grpcAddress, cf.ExecutionNodeGRPCTimeout)
+		if err != nil {
+			return nil, nil, err
+		}
+		return registers.NewClient(conn), &noopCloser{}, nil
+	}
+
+	conn, err = cf.createConnection(grpcAddress, cf.ExecutionNodeGRPCTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	registersClient := registers.NewClient(conn)
+	closer := io.Closer(conn)
+	return registersClient, closer, nil
+}
+
 func (cf *ConnectionFactoryImpl) InvalidateExecutionAPIClient(address string) {
 	if cf.ConnectionsCache != nil {
 		cf.Log.Debug().Str("cached_execution_client_invalidated", address).Msg("invalidating cached execution client")
@@ -0,0 +1,333 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+const (
+	// DefaultExecutionNodeMaxFailures is the default number of consecutive failed requests after
+	// which requests stop being sent to an execution node.
+	DefaultExecutionNodeMaxFailures = 5
+
+	// DefaultExecutionNodeRestoreTimeout is the default time during which requests are not sent to
+	// an execution node after too many consecutive failures.
+	DefaultExecutionNodeRestoreTimeout = time.Minute
+
+	// DefaultExecutionNodeLatencyTarget is the default latency of a healthy execution node. Nodes
+	// with a higher average latency are scored lower.
+	DefaultExecutionNodeLatencyTarget = 500 * time.Millisecond
+
+	// mismatchCacheSize is the number of recorded mismatches remembered to not record them twice.
+	mismatchCacheSize = 1_000
+
+	// healthDecay is the weight of the latest request in the moving averages of the latency,
+	// error rate and mismatch rate of an execution node.
+	healthDecay = 0.1
+)
+
+// ExecutionNodeHealthConfig is the configuration of the execution node health tracker.
+type ExecutionNodeHealthConfig struct {
+	// MaxFailures is the number of consecutive failed requests after which requests stop being
+	// sent to an execution node, zero to never stop sending requests.
+	MaxFailures uint
+
+	// RestoreTimeout is the time during which requests are not sent to an execution node after
+	// too many consecutive failures. After the timeout, requests are sent to the node again, and
+	// the first failure stops them again.
+	RestoreTimeout time.Duration
+
+	// LatencyTarget is the latency of a healthy execution node.
+	LatencyTarget time.Duration
+}
+
+// DefaultExecutionNodeHealthConfig returns the default configuration of the execution node health tracker.
+func DefaultExecutionNodeHealthConfig() ExecutionNodeHealthConfig {
+	return ExecutionNodeHealthConfig{
+		MaxFailures:    DefaultExecutionNodeMaxFailures,
+		RestoreTimeout: DefaultExecutionNodeRestoreTimeout,
+		LatencyTarget:  DefaultExecutionNodeLatencyTarget,
+	}
+}
+
+// ExecutionNodeScore is the health of an execution node, as reported by the admin command.
+type ExecutionNodeScore struct {
+	NodeID       flow.Identifier `json:"node_id"`
+	Score        float64         `json:"score"`
+	LatencyMs    float64         `json:"latency_ms"`
+	ErrorRate    float64         `json:"error_rate"`
+	MismatchRate float64         `json:"mismatch_rate"`
+	Requests     uint64          `json:"requests"`
+	Failures     uint64          `json:"failures"`
+	Mismatches   uint64          `json:"mismatches"`
+	Available    bool            `json:"available"`
+}
+
+// ExecutionNodeHealth tracks the latency, error rate and result mismatches of the execution nodes
+// the backend sends requests to, and uses them to choose which nodes to send requests to first.
+//
+// Each node has a score between 0 and 1, computed from the moving averages of its latency, error
+// rate and mismatch rate. Nodes with higher scores are tried first. Nodes without any request yet
+// have a score of 1, so they are tried as well. After too many consecutive failures, requests stop
+// being sent to a node for a while, unless no other node is available.
+//
+// All methods are no-ops on a nil ExecutionNodeHealth. ExecutionNodeHealth is safe for concurrent use.
+type ExecutionNodeHealth struct {
+	log     zerolog.Logger
+	metrics module.ExecutionNodeHealthMetrics
+	config  ExecutionNodeHealthConfig
+
+	mu         sync.RWMutex
+	nodes      map[flow.Identifier]*executionNodeStats
+	mismatches *lru.Cache // mismatchKey -> nil
+}
+
+// mismatchKey identifies a mismatched result of an execution node.
+type mismatchKey struct {
+	blockID flow.Identifier
+	nodeID  flow.Identifier
+}
+
+// executionNodeStats are the health statistics of an execution node.
+type executionNodeStats struct {
+	latency      time.Duration // moving average of the latency of successful requests
+	errorRate    float64       // moving average of failed requests
+	mismatchRate float64       // moving average of mismatched results
+
+	requests   uint64
+	failures   uint64
+	mismatches uint64
+
+	consecutiveFailures uint
+	unavailableUntil    time.Time
+}
+
+// NewExecutionNodeHealth returns a new execution node health tracker.
+func NewExecutionNodeHealth(
+	log zerolog.Logger,
+	metrics module.ExecutionNodeHealthMetrics,
+	config ExecutionNodeHealthConfig,
+) (*ExecutionNodeHealth, error) {
+	mismatches, err := lru.New(mismatchCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("could not create mismatch cache: %w", err)
+	}
+
+	return &ExecutionNodeHealth{
+		log:        log.With().Str("component", "execution_node_health").Logger(),
+		metrics:    metrics,
+		config:     config,
+		nodes:      make(map[flow.Identifier]*executionNodeStats),
+		mismatches: mismatches,
+	}, nil
+}
+
+// Order returns the given execution nodes that are available, ordered by decreasing score. Nodes
+// with the same score keep their relative order. If none of the nodes are available, all of them
+// are returned, so requests are still attempted.
+func (h *ExecutionNodeHealth) Order(nodes flow.IdentityList) flow.IdentityList {
+	if h == nil {
+		return nodes
+	}
+
+	now := time.Now()
+	scores := make(map[flow.Identifier]float64, len(nodes))
+	available := make(flow.IdentityList, 0, len(nodes))
+
+	h.mu.RLock()
+	for _, node := range nodes {
+		stats, ok := h.nodes[node.NodeID]
+		if !ok {
+			scores[node.NodeID] = 1
+			available = append(available, node)
+			continue
+		}
+		scores[node.NodeID] = stats.score(h.config.LatencyTarget)
+		if stats.available(now) {
+			available = append(available, node)
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(available) == 0 {
+		available = nodes.Copy()
+	}
+
+	sort.SliceStable(available, func(i, j int) bool {
+		return scores[available[i].NodeID] > scores[available[j].NodeID]
+	})
+
+	return available
+}
+
+// RecordRequest records the outcome of a request to the execution node, sent with the given context.
+// Requests canceled by the client, or of which the client deadline expired, are ignored as the node
+// is not at fault. Only errors caused by the node failing to respond are counted as failures, since
+// other errors are a valid response, e.g. for an invalid script.
+func (h *ExecutionNodeHealth) RecordRequest(ctx context.Context, nodeID flow.Identifier, latency time.Duration, err error) {
+	if h == nil {
+		return
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+	code := status.Code(err)
+	if code == codes.Canceled {
+		return
+	}
+	failed := isExecutionNodeFailure(code)
+
+	h.mu.Lock()
+	stats := h.stats(nodeID)
+	stats.requests++
+	if failed {
+		stats.failures++
+		stats.errorRate = movingAverage(stats.errorRate, 1)
+		stats.consecutiveFailures++
+	} else {
+		if stats.latency == 0 {
+			stats.latency = latency
+		} else {
+			stats.latency = time.Duration(movingAverage(float64(stats.latency), float64(latency)))
+		}
+		stats.errorRate = movingAverage(stats.errorRate, 0)
+		stats.mismatchRate = movingAverage(stats.mismatchRate, 0)
+		stats.consecutiveFailures = 0
+		stats.unavailableUntil = time.Time{}
+	}
+
+	// a node that is still failing after it became available again is made unavailable right away
+	now := time.Now()
+	circuitOpened := false
+	if failed && h.config.MaxFailures > 0 && stats.consecutiveFailures >= h.config.MaxFailures {
+		stats.unavailableUntil = now.Add(h.config.RestoreTimeout)
+		circuitOpened = true
+	}
+	score := stats.score(h.config.LatencyTarget)
+	available := stats.available(now)
+	h.mu.Unlock()
+
+	if circuitOpened {
+		h.log.Warn().
+			Str("execution_node", nodeID.String()).
+			Err(err).
+			Dur("restore_timeout", h.config.RestoreTimeout).
+			Msg("too many failed requests, execution node made unavailable")
+	}
+
+	h.metrics.ExecutionNodeRequest(nodeID, latency, failed)
+	h.metrics.ExecutionNodeHealth(nodeID, score, available)
+}
+
+// RecordMismatch records that the execution node produced a result for the block different from
+// the result of the majority of execution nodes. Each mismatch is only recorded once, even though
+// the receipts of the block are checked for every request.
+func (h *ExecutionNodeHealth) RecordMismatch(blockID flow.Identifier, nodeID flow.Identifier) {
+	if h == nil {
+		return
+	}
+
+	if seen, _ := h.mismatches.ContainsOrAdd(mismatchKey{blockID: blockID, nodeID: nodeID}, nil); seen {
+		return
+	}
+
+	now := time.Now()
+
+	h.mu.Lock()
+	stats := h.stats(nodeID)
+	stats.mismatches++
+	stats.mismatchRate = movingAverage(stats.mismatchRate, 1)
+	score := stats.score(h.config.LatencyTarget)
+	available := stats.available(now)
+	h.mu.Unlock()
+
+	h.metrics.ExecutionNodeResultMismatch(nodeID)
+	h.metrics.ExecutionNodeHealth(nodeID, score, available)
+}
+
+// Scores returns the health of all execution nodes requests were sent to, by decreasing score.
+func (h *ExecutionNodeHealth) Scores() []ExecutionNodeScore {
+	if h == nil {
+		return nil
+	}
+
+	now := time.Now()
+
+	h.mu.RLock()
+	scores := make([]ExecutionNodeScore, 0, len(h.nodes))
+	for nodeID, stats := range h.nodes {
+		scores = append(scores, ExecutionNodeScore{
+			NodeID:       nodeID,
+			Score:        stats.score(h.config.LatencyTarget),
+			LatencyMs:    float64(stats.latency) / float64(time.Millisecond),
+			ErrorRate:    stats.errorRate,
+			MismatchRate: stats.mismatchRate,
+			Requests:     stats.requests,
+			Failures:     stats.failures,
+			Mismatches:   stats.mismatches,
+			Available:    stats.available(now),
+		})
+	}
+	h.mu.RUnlock()
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].NodeID.String() < scores[j].NodeID.String()
+	})
+
+	return scores
+}
+
+// stats returns the statistics of the node, creating them if needed.
+// The caller must hold the write lock.
+func (h *ExecutionNodeHealth) stats(nodeID flow.Identifier) *executionNodeStats {
+	stats, ok := h.nodes[nodeID]
+	if !ok {
+		stats = &executionNodeStats{}
+		h.nodes[nodeID] = stats
+	}
+	return stats
+}
+
+// score returns the score of the node between 0 and 1, which decreases as the error rate and
+// mismatch rate increase, and as the latency exceeds the target.
+func (s *executionNodeStats) score(latencyTarget time.Duration) float64 {
+	score := (1 - s.errorRate) * (1 - s.mismatchRate)
+	if latencyTarget > 0 && s.latency > latencyTarget {
+		score *= float64(latencyTarget) / float64(s.latency)
+	}
+	return score
+}
+
+// available returns true if requests can be sent to the node.
+func (s *executionNodeStats) available(now time.Time) bool {
+	return !now.Before(s.unavailableUntil)
+}
+
+// isExecutionNodeFailure returns true if the error code means the execution node failed to respond.
+func isExecutionNodeFailure(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+func movingAverage(average float64, value float64) float64 {
+	return average + healthDecay*(value-average)
+}
@@ -0,0 +1,137 @@
+package backend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func executionNodes(n int) flow.IdentityList {
+	nodes := make(flow.IdentityList, n)
+	for i := range nodes {
+		nodes[i] = &flow.Identity{NodeID: unittest.IdentifierFixture(), Role: flow.RoleExecution}
+	}
+	return nodes
+}
+
+func TestExecutionNodeHealth_Order(t *testing.T) {
+	health, err := NewExecutionNodeHealth(zerolog.Nop(), metrics.NewNoopCollector(), DefaultExecutionNodeHealthConfig())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	nodes := executionNodes(4)
+	failing, slow, mismatched, healthy := nodes[0], nodes[1], nodes[2], nodes[3]
+
+	health.RecordRequest(ctx, failing.NodeID, 10*time.Millisecond, status.Error(codes.Unavailable, "unavailable"))
+	health.RecordRequest(ctx, slow.NodeID, 2*time.Second, nil)
+	health.RecordMismatch(unittest.IdentifierFixture(), mismatched.NodeID)
+	health.RecordRequest(ctx, healthy.NodeID, 10*time.Millisecond, nil)
+
+	// nodes without requests yet are as healthy as healthy nodes
+	unknown := executionNodes(1)[0]
+
+	ordered := health.Order(flow.IdentityList{failing, slow, unknown, mismatched, healthy})
+	assert.Equal(t, flow.IdentityList{unknown, healthy, failing, mismatched, slow}, ordered)
+
+	// a nil tracker does not change the order
+	var noHealth *ExecutionNodeHealth
+	assert.Equal(t, nodes, noHealth.Order(nodes))
+}
+
+func TestExecutionNodeHealth_RecordRequest(t *testing.T) {
+	health, err := NewExecutionNodeHealth(zerolog.Nop(), metrics.NewNoopCollector(), DefaultExecutionNodeHealthConfig())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	nodeID := unittest.IdentifierFixture()
+
+	// errors returned by the node for invalid requests are not failures of the node
+	health.RecordRequest(ctx, nodeID, time.Millisecond, status.Error(codes.InvalidArgument, "invalid script"))
+	health.RecordRequest(ctx, nodeID, time.Millisecond, status.Error(codes.NotFound, "not found"))
+	// requests canceled by the client are ignored
+	health.RecordRequest(ctx, nodeID, time.Millisecond, status.Error(codes.Canceled, "canceled"))
+	// as are requests of which the client deadline expired, or which the client canceled while the
+	// node was unavailable
+	expiredCtx, cancel := context.WithTimeout(ctx, 0)
+	defer cancel()
+	health.RecordRequest(expiredCtx, nodeID, time.Millisecond, status.Error(codes.DeadlineExceeded, "deadline exceeded"))
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	health.RecordRequest(canceledCtx, nodeID, time.Millisecond, status.Error(codes.Unavailable, "unavailable"))
+
+	scores := health.Scores()
+	require.Len(t, scores, 1)
+	assert.Equal(t, nodeID, scores[0].NodeID)
+	assert.Equal(t, uint64(2), scores[0].Requests)
+	assert.Equal(t, uint64(0), scores[0].Failures)
+	assert.Equal(t, 1.0, scores[0].Score)
+	assert.Equal(t, 1.0, scores[0].LatencyMs)
+	assert.True(t, scores[0].Available)
+}
+
+func TestExecutionNodeHealth_CircuitBreaker(t *testing.T) {
+	config := DefaultExecutionNodeHealthConfig()
+	config.MaxFailures = 2
+	config.RestoreTimeout = 100 * time.Millisecond
+
+	health, err := NewExecutionNodeHealth(zerolog.Nop(), metrics.NewNoopCollector(), config)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	nodes := executionNodes(2)
+	failing, healthy := nodes[0], nodes[1]
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+
+	health.RecordRequest(ctx, failing.NodeID, time.Millisecond, unavailable)
+	assert.Len(t, health.Order(nodes), 2)
+
+	// after too many consecutive failures, the node is not used anymore
+	health.RecordRequest(ctx, failing.NodeID, time.Millisecond, unavailable)
+	assert.Equal(t, flow.IdentityList{healthy}, health.Order(nodes))
+
+	// unless there is no other node
+	assert.Equal(t, flow.IdentityList{failing}, health.Order(flow.IdentityList{failing}))
+
+	// the node is used again after the restore timeout, but a single failure makes it unavailable again
+	require.Eventually(t, func() bool {
+		return len(health.Order(nodes)) == 2
+	}, time.Second, 10*time.Millisecond)
+	health.RecordRequest(ctx, failing.NodeID, time.Millisecond, unavailable)
+	assert.Equal(t, flow.IdentityList{healthy}, health.Order(nodes))
+
+	// a successful request makes the node available right away
+	health.RecordRequest(ctx, failing.NodeID, time.Millisecond, nil)
+	assert.Len(t, health.Order(nodes), 2)
+}
+
+func TestExecutionNodeHealth_RecordMismatch(t *testing.T) {
+	health, err := NewExecutionNodeHealth(zerolog.Nop(), metrics.NewNoopCollector(), DefaultExecutionNodeHealthConfig())
+	require.NoError(t, err)
+
+	blockID := unittest.IdentifierFixture()
+	nodeID := unittest.IdentifierFixture()
+
+	// the same mismatch is only recorded once
+	health.RecordMismatch(blockID, nodeID)
+	health.RecordMismatch(blockID, nodeID)
+	health.RecordMismatch(unittest.IdentifierFixture(), nodeID)
+
+	scores := health.Scores()
+	require.Len(t, scores, 1)
+	assert.Equal(t, uint64(2), scores[0].Mismatches)
+	assert.Less(t, scores[0].Score, 1.0)
+	assert.True(t, scores[0].Available)
+}
@@ -0,0 +1,315 @@
+package backend
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+// DefaultResultVerificationNodes is the default number of execution nodes requests are sent to
+// when their responses are verified.
+const DefaultResultVerificationNodes = 3
+
+// ResultVerificationConfig configures the backend to send script and event requests to several
+// execution nodes, and to only return the response if enough of them agree on it.
+type ResultVerificationConfig struct {
+	// Nodes is the number of execution nodes each request is sent to.
+	Nodes uint
+
+	// Quorum is the number of execution nodes which must return the same response, which must be
+	// a majority of Nodes, or zero to send requests to a single execution node without verification.
+	Quorum uint
+}
+
+// Enabled returns true if responses are verified.
+func (c ResultVerificationConfig) Enabled() bool {
+	return c.Quorum > 0
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c ResultVerificationConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Nodes < c.Quorum {
+		return fmt.Errorf("result verification quorum (%d) must not exceed the number of nodes (%d)", c.Quorum, c.Nodes)
+	}
+	// with a majority, two different responses can never both reach the quorum
+	if 2*c.Quorum <= c.Nodes {
+		return fmt.Errorf("result verification quorum (%d) must be a majority of the number of nodes (%d)", c.Quorum, c.Nodes)
+	}
+	return nil
+}
+
+// SetResultVerification configures the backend to verify the responses of execution nodes to
+// script and event requests according to the given configuration. It must be called before the
+// backend starts serving requests.
+func (b *Backend) SetResultVerification(config ResultVerificationConfig) error {
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
+	b.backendScripts.resultVerification = config
+	b.backendEvents.resultVerification = config
+	return nil
+}
+
+// ResultMismatchError is returned if the execution nodes a request was sent to returned different
+// responses, and not enough of them agreed on any response.
+type ResultMismatchError struct {
+	BlockID flow.Identifier
+	Quorum  uint
+
+	// Responses are the execution nodes which returned each distinct response, by decreasing
+	// number of nodes.
+	Responses []flow.IdentifierList
+}
+
+func (e ResultMismatchError) Error() string {
+	groups := make([]string, len(e.Responses))
+	for i, nodeIDs := range e.Responses {
+		ids := make([]string, len(nodeIDs))
+		for j, nodeID := range nodeIDs {
+			ids[j] = nodeID.String()
+		}
+		groups[i] = fmt.Sprintf("response %d from [%s]", i+1, strings.Join(ids, ", "))
+	}
+	return fmt.Sprintf("execution nodes disagree on the result for block %v, %d matching responses required: %s",
+		e.BlockID, e.Quorum, strings.Join(groups, "; "))
+}
+
+// GRPCStatus returns the gRPC status of the error, so it is returned to clients as is.
+func (e ResultMismatchError) GRPCStatus() *status.Status {
+	return status.New(codes.Aborted, e.Error())
+}
+
+// verifiedExecutionNodesForBlockID returns up to the configured number of execution nodes, which
+// all committed to the same execution result for the block, ordered by their health. An error is
+// returned if fewer execution nodes than the quorum committed to the same result.
+func verifiedExecutionNodesForBlockID(
+	blockID flow.Identifier,
+	executionReceipts storage.ExecutionReceipts,
+	state protocol.State,
+	nodeHealth *ExecutionNodeHealth,
+	config ResultVerificationConfig,
+	log zerolog.Logger,
+) (flow.IdentityList, error) {
+	allENs, err := state.Final().Identities(filter.HasRole(flow.RoleExecution))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retreive all execution IDs: %w", err)
+	}
+
+	// there are no receipts for the root block, whose result all execution nodes agree on
+	rootBlock, err := state.Params().Root()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retreive execution IDs for block ID %v: %w", blockID, err)
+	}
+
+	executorENs := allENs
+	if rootBlock.ID() != blockID {
+		executorIDs, err := findAllExecutionNodes(blockID, executionReceipts, nodeHealth, log)
+		if err != nil {
+			return nil, err
+		}
+		executorENs = allENs.Filter(filter.HasNodeID(executorIDs...))
+	}
+
+	if uint(len(executorENs)) < config.Quorum {
+		return nil, InsufficientExecutionReceipts{blockID: blockID, receiptCount: len(executorENs)}
+	}
+
+	execNodes := nodeHealth.Order(executorENs.Sample(uint(len(executorENs))))
+	if uint(len(execNodes)) > config.Nodes {
+		execNodes = execNodes[:config.Nodes]
+	}
+
+	return execNodes, nil
+}
+
+// verifiedResponse is the response of an execution node to a verified request.
+type verifiedResponse struct {
+	nodeID flow.Identifier
+	key    string // identifies identical responses
+	value  interface{}
+	err    error
+}
+
+// queryWithQuorum sends the request to all the execution nodes at once, and returns the response
+// that at least quorum nodes returned. Errors returned by the nodes for invalid requests are
+// responses too, since a quorum of nodes must agree that the request is invalid. Nodes which
+// returned another response are reported to the health tracker.
+//
+// A ResultMismatchError is returned if the nodes returned different responses and not enough of
+// them agreed, and an Unavailable error if not enough nodes responded.
+func queryWithQuorum(
+	ctx context.Context,
+	blockID flow.Identifier,
+	execNodes flow.IdentityList,
+	quorum uint,
+	nodeHealth *ExecutionNodeHealth,
+	log zerolog.Logger,
+	query func(ctx context.Context, execNode *flow.Identity) (value interface{}, key string, err error),
+) (interface{}, error) {
+	responses := make([]verifiedResponse, len(execNodes))
+
+	var wg sync.WaitGroup
+	wg.Add(len(execNodes))
+	for i, execNode := range execNodes {
+		go func(i int, execNode *flow.Identity) {
+			defer wg.Done()
+
+			value, key, err := query(ctx, execNode)
+			if err != nil {
+				// error messages include the node, so nodes only need to agree that the request is invalid
+				key = "invalid argument"
+			} else {
+				key = "result: " + key
+			}
+			responses[i] = verifiedResponse{nodeID: execNode.NodeID, key: key, value: value, err: err}
+		}(i, execNode)
+	}
+	wg.Wait()
+
+	// group the nodes by response
+	var errs *multierror.Error
+	groups := make(map[string][]verifiedResponse)
+	for _, response := range responses {
+		if response.err != nil && status.Code(response.err) != codes.InvalidArgument {
+			errs = multierror.Append(errs, response.err)
+			continue
+		}
+		groups[response.key] = append(groups[response.key], response)
+	}
+
+	sortedGroups := make([][]verifiedResponse, 0, len(groups))
+	for _, group := range groups {
+		sortedGroups = append(sortedGroups, group)
+	}
+	sort.SliceStable(sortedGroups, func(i, j int) bool {
+		return len(sortedGroups[i]) > len(sortedGroups[j])
+	})
+
+	if len(sortedGroups) > 0 && uint(len(sortedGroups[0])) >= quorum {
+		agreed := sortedGroups[0][0]
+		for _, group := range sortedGroups[1:] {
+			for _, response := range group {
+				log.Warn().
+					Str("execution_node", response.nodeID.String()).
+					Hex("block_id", blockID[:]).
+					Msg("execution node response does not match the quorum")
+				nodeHealth.RecordMismatch(blockID, response.nodeID)
+			}
+		}
+		return agreed.value, agreed.err
+	}
+
+	if len(sortedGroups) > 1 {
+		mismatch := ResultMismatchError{BlockID: blockID, Quorum: quorum}
+		for _, group := range sortedGroups {
+			nodeIDs := make(flow.IdentifierList, len(group))
+			for i, response := range group {
+				nodeIDs[i] = response.nodeID
+			}
+			mismatch.Responses = append(mismatch.Responses, nodeIDs)
+		}
+		log.Error().Err(mismatch).Msg("execution node responses do not match")
+		return nil, mismatch
+	}
+
+	responded := 0
+	if len(sortedGroups) > 0 {
+		responded = len(sortedGroups[0])
+	}
+	return nil, status.Errorf(codes.Unavailable, "only %d of %d required execution nodes responded: %v",
+		responded, quorum, errs.ErrorOrNil())
+}
+
+// executeScriptVerified executes the script on several execution nodes, and returns the result if
+// enough of them agree on it.
+func (b *backendScripts) executeScriptVerified(
+	ctx context.Context,
+	blockID flow.Identifier,
+	req *execproto.ExecuteScriptAtBlockIDRequest,
+) ([]byte, error) {
+	execNodes, err := verifiedExecutionNodesForBlockID(blockID, b.executionReceipts, b.state, b.nodeHealth, b.resultVerification, b.log)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to find execution nodes at blockId %v: %v", blockID.String(), err)
+	}
+
+	execStartTime := time.Now()
+	result, err := queryWithQuorum(ctx, blockID, execNodes, b.resultVerification.Quorum, b.nodeHealth, b.log,
+		func(ctx context.Context, execNode *flow.Identity) (interface{}, string, error) {
+			value, err := b.tryExecuteScript(ctx, execNode, req)
+			return value, string(value), err
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	b.metrics.ScriptExecuted(time.Since(execStartTime), len(req.GetScript()))
+
+	return result.([]byte), nil
+}
+
+// getEventsVerified gets the events from several execution nodes, and returns them if enough of
+// the execution nodes agree on them.
+func (b *backendEvents) getEventsVerified(
+	ctx context.Context,
+	blockID flow.Identifier,
+	req *execproto.GetEventsForBlockIDsRequest,
+) (*execproto.GetEventsForBlockIDsResponse, error) {
+	execNodes, err := verifiedExecutionNodesForBlockID(blockID, b.executionReceipts, b.state, b.nodeHealth, b.resultVerification, b.log)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to retrieve events from execution node: %v", err)
+	}
+
+	resp, err := queryWithQuorum(ctx, blockID, execNodes, b.resultVerification.Quorum, b.nodeHealth, b.log,
+		func(ctx context.Context, execNode *flow.Identity) (interface{}, string, error) {
+			resp, err := b.tryGetEvents(ctx, execNode, req)
+			if err != nil {
+				return nil, "", err
+			}
+			return resp, eventsResponseKey(resp), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*execproto.GetEventsForBlockIDsResponse), nil
+}
+
+// eventsResponseKey returns a key that is the same for identical events responses.
+func eventsResponseKey(resp *execproto.GetEventsForBlockIDsResponse) string {
+	h := sha256.New()
+	var height [8]byte
+	for _, result := range resp.GetResults() {
+		_, _ = h.Write(result.GetBlockId())
+		binary.BigEndian.PutUint64(height[:], result.GetBlockHeight())
+		_, _ = h.Write(height[:])
+		for _, event := range convert.MessagesToEvents(result.GetEvents()) {
+			checksum := event.Checksum()
+			_, _ = h.Write(checksum[:])
+		}
+		// separate the events of each block
+		_, _ = h.Write([]byte{0})
+	}
+	return string(h.Sum(nil))
+}
@@ -0,0 +1,96 @@
+package backend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestResultVerificationConfig_Validate(t *testing.T) {
+	assert.NoError(t, ResultVerificationConfig{}.Validate())
+	assert.NoError(t, ResultVerificationConfig{Nodes: 3, Quorum: 0}.Validate())
+	assert.NoError(t, ResultVerificationConfig{Nodes: 3, Quorum: 2}.Validate())
+	assert.NoError(t, ResultVerificationConfig{Nodes: 1, Quorum: 1}.Validate())
+	assert.Error(t, ResultVerificationConfig{Nodes: 2, Quorum: 3}.Validate())
+	assert.Error(t, ResultVerificationConfig{Nodes: 4, Quorum: 2}.Validate())
+}
+
+func TestQueryWithQuorum(t *testing.T) {
+	blockID := unittest.IdentifierFixture()
+	nodes := executionNodes(3)
+
+	// query returns the response of each node
+	query := func(responses map[flow.Identifier]error, values map[flow.Identifier]string) func(context.Context, *flow.Identity) (interface{}, string, error) {
+		return func(_ context.Context, node *flow.Identity) (interface{}, string, error) {
+			if err := responses[node.NodeID]; err != nil {
+				return nil, "", err
+			}
+			return values[node.NodeID], values[node.NodeID], nil
+		}
+	}
+
+	t.Run("quorum agrees", func(t *testing.T) {
+		health, err := NewExecutionNodeHealth(zerolog.Nop(), metrics.NewNoopCollector(), DefaultExecutionNodeHealthConfig())
+		require.NoError(t, err)
+
+		values := map[flow.Identifier]string{
+			nodes[0].NodeID: "a",
+			nodes[1].NodeID: "a",
+			nodes[2].NodeID: "b",
+		}
+		value, err := queryWithQuorum(context.Background(), blockID, nodes, 2, health, zerolog.Nop(), query(nil, values))
+		require.NoError(t, err)
+		assert.Equal(t, "a", value)
+
+		// the node that disagreed is reported
+		scores := health.Scores()
+		require.Len(t, scores, 1)
+		assert.Equal(t, nodes[2].NodeID, scores[0].NodeID)
+		assert.Equal(t, uint64(1), scores[0].Mismatches)
+	})
+
+	t.Run("quorum agrees the request is invalid", func(t *testing.T) {
+		responses := map[flow.Identifier]error{
+			nodes[0].NodeID: status.Error(codes.InvalidArgument, "invalid script on node 0"),
+			nodes[1].NodeID: status.Error(codes.InvalidArgument, "invalid script on node 1"),
+		}
+		values := map[flow.Identifier]string{nodes[2].NodeID: "a"}
+		_, err := queryWithQuorum(context.Background(), blockID, nodes, 2, nil, zerolog.Nop(), query(responses, values))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("no quorum", func(t *testing.T) {
+		responses := map[flow.Identifier]error{nodes[2].NodeID: status.Error(codes.Unavailable, "unavailable")}
+		values := map[flow.Identifier]string{
+			nodes[0].NodeID: "a",
+			nodes[1].NodeID: "b",
+		}
+		_, err := queryWithQuorum(context.Background(), blockID, nodes, 2, nil, zerolog.Nop(), query(responses, values))
+		require.Error(t, err)
+		assert.Equal(t, codes.Aborted, status.Code(err))
+
+		var mismatch ResultMismatchError
+		require.ErrorAs(t, err, &mismatch)
+		assert.Equal(t, blockID, mismatch.BlockID)
+		assert.ElementsMatch(t, []flow.IdentifierList{{nodes[0].NodeID}, {nodes[1].NodeID}}, mismatch.Responses)
+	})
+
+	t.Run("not enough responses", func(t *testing.T) {
+		responses := map[flow.Identifier]error{
+			nodes[1].NodeID: status.Error(codes.Unavailable, "unavailable"),
+			nodes[2].NodeID: status.Error(codes.DeadlineExceeded, "timeout"),
+		}
+		values := map[flow.Identifier]string{nodes[0].NodeID: "a"}
+		_, err := queryWithQuorum(context.Background(), blockID, nodes, 2, nil, zerolog.Nop(), query(responses, values))
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
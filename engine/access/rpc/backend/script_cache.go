@@ -0,0 +1,240 @@
+package backend
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// DefaultScriptResultCacheSize is the default number of script results cached by the backend.
+	DefaultScriptResultCacheSize = 1_000
+
+	// DefaultScriptResultCacheMaxBytes is the default total size of the script results cached by
+	// the backend.
+	DefaultScriptResultCacheMaxBytes = 64 << 20 // 64 MiB
+
+	// DefaultScriptResultCacheMaxResultBytes is the default size above which script results are not
+	// cached.
+	DefaultScriptResultCacheMaxResultBytes = 1 << 20 // 1 MiB
+)
+
+// ScriptResultCacheConfig configures the cache of the results of scripts executed on execution
+// nodes at sealed blocks.
+type ScriptResultCacheConfig struct {
+	// Size is the maximum number of cached results. The cache is disabled if it is zero.
+	Size uint
+
+	// MaxBytes is the maximum total size of the cached results. The least recently used results are
+	// evicted when it is exceeded.
+	MaxBytes uint64
+
+	// MaxResultBytes is the maximum size of a cached result. Larger results are not cached.
+	MaxResultBytes uint64
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c ScriptResultCacheConfig) Validate() error {
+	if c.Size == 0 {
+		return nil
+	}
+	if c.MaxBytes == 0 {
+		return fmt.Errorf("script result cache max bytes must be greater than 0")
+	}
+	if c.MaxResultBytes == 0 || c.MaxResultBytes > c.MaxBytes {
+		return fmt.Errorf("script result cache max result bytes (%d) must be between 1 and the max bytes (%d)",
+			c.MaxResultBytes, c.MaxBytes)
+	}
+	return nil
+}
+
+// scriptResultCache is an LRU cache of script results, bounded by the number of results and by
+// their total size.
+type scriptResultCache struct {
+	mu             sync.Mutex
+	results        *simplelru.LRU
+	bytes          uint64 // total size of the cached results
+	maxBytes       uint64
+	maxResultBytes uint64
+}
+
+func newScriptResultCache(config ScriptResultCacheConfig) (*scriptResultCache, error) {
+	c := &scriptResultCache{
+		maxBytes:       config.MaxBytes,
+		maxResultBytes: config.MaxResultBytes,
+	}
+
+	results, err := simplelru.NewLRU(int(config.Size), func(_ interface{}, value interface{}) {
+		c.bytes -= uint64(len(value.([]byte)))
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.results = results
+
+	return c, nil
+}
+
+// get returns the cached result of the script execution request with the given key.
+func (c *scriptResultCache) get(key scriptCacheKey) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.results.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return value.([]byte), true
+}
+
+// add caches the result of the script execution request with the given key, unless it is larger
+// than the maximum result size. The least recently used results are evicted until the total size
+// is within the limit.
+func (c *scriptResultCache) add(key scriptCacheKey, result []byte) {
+	size := uint64(len(result))
+	if size > c.maxResultBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.results.Contains(key) {
+		return
+	}
+
+	c.results.Add(key, result)
+	c.bytes += size
+	for c.bytes > c.maxBytes {
+		c.results.RemoveOldest()
+	}
+}
+
+// scriptCacheKey identifies a script execution request: the same script with the same arguments
+// at the same block always returns the same result.
+type scriptCacheKey [sha256.Size]byte
+
+// newScriptCacheKey returns the key of the request to execute the script with the arguments at the
+// given block.
+func newScriptCacheKey(blockID flow.Identifier, script []byte, arguments [][]byte) scriptCacheKey {
+	h := sha256.New()
+	_, _ = h.Write(blockID[:])
+
+	// length prefix all values, so that different scripts and arguments never produce the same input
+	writeWithLength := func(data []byte) {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(data)))
+		_, _ = h.Write(length[:])
+		_, _ = h.Write(data)
+	}
+	writeWithLength(script)
+	for _, argument := range arguments {
+		writeWithLength(argument)
+	}
+
+	var key scriptCacheKey
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// SetScriptResultCache configures the backend to cache the results of scripts executed on execution
+// nodes at sealed blocks, according to the given configuration. The cache is disabled if its size is
+// zero. It must be called before the backend starts serving requests.
+func (b *Backend) SetScriptResultCache(config ScriptResultCacheConfig) error {
+	if config.Size == 0 {
+		b.backendScripts.scriptResults = nil
+		return nil
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
+	cache, err := newScriptResultCache(config)
+	if err != nil {
+		return fmt.Errorf("could not create script result cache: %w", err)
+	}
+	b.backendScripts.scriptResults = cache
+	return nil
+}
+
+// executeScriptOnExecutionNodeCached executes the script on execution nodes, using the cached result
+// if the same script was already executed with the same arguments at the same sealed block.
+// Identical requests that are in flight at the same time are coalesced into a single request to the
+// execution nodes.
+func (b *backendScripts) executeScriptOnExecutionNodeCached(
+	ctx context.Context,
+	blockID flow.Identifier,
+	script []byte,
+	arguments [][]byte,
+) ([]byte, error) {
+	key := newScriptCacheKey(blockID, script, arguments)
+
+	if b.scriptResults != nil {
+		if cached, ok := b.scriptResults.get(key); ok {
+			return cached, nil
+		}
+	}
+
+	result, err, shared := b.scriptRequests.Do(string(key[:]), func() (interface{}, error) {
+		result, err := b.executeScriptOnExecutionNode(ctx, blockID, script, arguments)
+		if err != nil {
+			return nil, err
+		}
+
+		if b.scriptResults != nil && b.isSealedBlock(blockID) {
+			b.scriptResults.add(key, result)
+		}
+		return result, nil
+	})
+	if err != nil {
+		// the coalesced request is canceled if the client that made it goes away, in which case the
+		// other clients retry on their own
+		if shared && ctx.Err() == nil && isCanceled(err) {
+			return b.executeScriptOnExecutionNode(ctx, blockID, script, arguments)
+		}
+		return nil, err
+	}
+
+	return result.([]byte), nil
+}
+
+// isCanceled returns true if the error was caused by a canceled context or an expired deadline.
+func isCanceled(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	code := status.Code(err)
+	return code == codes.Canceled || code == codes.DeadlineExceeded
+}
+
+// isSealedBlock returns true if the block is finalized and sealed, which means the result of a
+// script executed at the block never changes. Errors are treated as the block not being sealed.
+func (b *backendScripts) isSealedBlock(blockID flow.Identifier) bool {
+	header, err := b.headers.ByBlockID(blockID)
+	if err != nil {
+		return false
+	}
+
+	sealed, err := b.state.Sealed().Head()
+	if err != nil || header.Height > sealed.Height {
+		return false
+	}
+
+	finalizedID, err := b.headers.BlockIDByHeight(header.Height)
+	if err != nil {
+		return false
+	}
+
+	return finalizedID == blockID
+}
@@ -0,0 +1,44 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestScriptResultCache_MaxBytes tests that the least recently used results are evicted when the
+// total size of the cached results exceeds the limit, and that large results are not cached.
+func TestScriptResultCache_MaxBytes(t *testing.T) {
+	cache, err := newScriptResultCache(ScriptResultCacheConfig{Size: 10, MaxBytes: 10, MaxResultBytes: 5})
+	require.NoError(t, err)
+
+	key := func() scriptCacheKey {
+		return newScriptCacheKey(unittest.IdentifierFixture(), []byte("script"), nil)
+	}
+
+	first, second, third := key(), key(), key()
+	cache.add(first, make([]byte, 4))
+	cache.add(second, make([]byte, 4))
+
+	// the first result is used most recently, so the second one is evicted
+	_, ok := cache.get(first)
+	require.True(t, ok)
+	cache.add(third, make([]byte, 4))
+
+	_, ok = cache.get(second)
+	assert.False(t, ok)
+	_, ok = cache.get(first)
+	assert.True(t, ok)
+	_, ok = cache.get(third)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(8), cache.bytes)
+
+	// results larger than the maximum result size are not cached
+	large := key()
+	cache.add(large, make([]byte, 6))
+	_, ok = cache.get(large)
+	assert.False(t, ok)
+}
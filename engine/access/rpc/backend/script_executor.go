@@ -0,0 +1,128 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/execution"
+	"github.com/onflow/flow-go/storage"
+)
+
+// ScriptExecutor executes scripts and reads accounts using locally available execution state.
+type ScriptExecutor interface {
+	// ExecuteAtBlockHeight executes the script against the execution state at the given height, and
+	// returns the JSON-CDC encoded result.
+	// Expected errors:
+	// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+	// - storage.ErrNotBootstrapped if the local execution state is not initialized yet
+	// - storage.ErrNotFound if the block at the height is not known
+	// - execution.ScriptExecutionError if the script failed
+	ExecuteAtBlockHeight(ctx context.Context, script []byte, arguments [][]byte, height uint64) ([]byte, error)
+
+	// GetAccountAtBlockHeight returns the account with the given address at the given height.
+	// Expected errors:
+	// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+	// - storage.ErrNotBootstrapped if the local execution state is not initialized yet
+	// - storage.ErrNotFound if the block at the height or the account is not known
+	GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error)
+
+	// GetAccountKeysAtBlockHeight returns the public keys of the account with the given address at
+	// the given height.
+	// Expected errors:
+	// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+	// - storage.ErrNotBootstrapped if the local execution state is not initialized yet
+	// - storage.ErrNotFound if the block at the height or the account is not known
+	GetAccountKeysAtBlockHeight(ctx context.Context, address flow.Address, height uint64) ([]flow.AccountPublicKey, error)
+
+	// GetAccountKeyAtBlockHeight returns the public key with the given index of the account with
+	// the given address at the given height.
+	// Expected errors:
+	// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+	// - storage.ErrNotBootstrapped if the local execution state is not initialized yet
+	// - storage.ErrNotFound if the block at the height, the account or the key is not known
+	GetAccountKeyAtBlockHeight(ctx context.Context, address flow.Address, keyIndex uint64, height uint64) (*flow.AccountPublicKey, error)
+
+	// GetAccountBalanceAtBlockHeight returns the balance of the account with the given address at
+	// the given height.
+	// Expected errors:
+	// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+	// - storage.ErrNotBootstrapped if the local execution state is not initialized yet
+	// - storage.ErrNotFound if the block at the height or the account is not known
+	GetAccountBalanceAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (uint64, error)
+
+	// SimulateTransactionAtBlockHeight executes the transaction against the execution state at the
+	// given height without committing it, and returns the resources it used and the changes it made.
+	// A failing transaction is not an error, it is reported in the returned simulation.
+	// Expected errors:
+	// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+	// - storage.ErrNotBootstrapped if the local execution state is not initialized yet
+	// - storage.ErrNotFound if the block at the height is not known
+	SimulateTransactionAtBlockHeight(ctx context.Context, tx *flow.TransactionBody, height uint64) (*execution.TransactionSimulation, error)
+}
+
+// ScriptExecutionMode determines where scripts are executed and accounts are read from.
+type ScriptExecutionMode int
+
+const (
+	// ScriptExecutionModeExecutionNodesOnly forwards all requests to execution nodes.
+	ScriptExecutionModeExecutionNodesOnly ScriptExecutionMode = iota
+
+	// ScriptExecutionModeLocalOnly serves all requests using the local execution state, and fails
+	// requests for heights that are not available locally.
+	ScriptExecutionModeLocalOnly
+
+	// ScriptExecutionModeFailover serves requests using the local execution state, and forwards
+	// requests to execution nodes if the local execution state is not available or fails
+	// for reasons other than a failing script.
+	ScriptExecutionModeFailover
+)
+
+func (m ScriptExecutionMode) String() string {
+	switch m {
+	case ScriptExecutionModeExecutionNodesOnly:
+		return "execution-nodes-only"
+	case ScriptExecutionModeLocalOnly:
+		return "local-only"
+	case ScriptExecutionModeFailover:
+		return "failover"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(m))
+	}
+}
+
+// ParseScriptExecutionMode parses the string representation of a script execution mode.
+func ParseScriptExecutionMode(s string) (ScriptExecutionMode, error) {
+	for _, mode := range []ScriptExecutionMode{
+		ScriptExecutionModeExecutionNodesOnly,
+		ScriptExecutionModeLocalOnly,
+		ScriptExecutionModeFailover,
+	} {
+		if mode.String() == s {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid script execution mode: %s", s)
+}
+
+// convertLocalExecutionError converts an error returned by the ScriptExecutor into a grpc status error.
+func convertLocalExecutionError(err error, msg string) error {
+	switch {
+	case execution.IsScriptExecutionError(err):
+		return status.Errorf(codes.InvalidArgument, "failed to execute script: %v", err)
+	case errors.Is(err, storage.ErrHeightNotIndexed), errors.Is(err, storage.ErrNotBootstrapped):
+		return status.Errorf(codes.OutOfRange, "%s: execution state not available: %v", msg, err)
+	case errors.Is(err, storage.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Errorf(codes.DeadlineExceeded, "%s: %v", msg, err)
+	case errors.Is(err, context.Canceled):
+		return status.Errorf(codes.Canceled, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
@@ -0,0 +1,287 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// DefaultResubmitInterval is the default number of finalized blocks after which a tracked
+// transaction is resubmitted, if it was not included in a finalized block yet.
+const DefaultResubmitInterval uint64 = 10 // blocks
+
+// DefaultMaxTrackedTransactions is the default maximum number of transactions tracked at once.
+const DefaultMaxTrackedTransactions uint = 10_000
+
+// SubmissionTrackingConfig configures the backend to track sent transactions until they are
+// included in a finalized block or expire, and to resubmit them to other collection nodes of their
+// cluster when they are not included in time. The submissions of tracked transactions are returned
+// with their results.
+//
+// Submissions are only exposed over gRPC, in the access.TransactionSubmissionsHeader response header
+// of GetTransactionResult, since neither the pinned flow protobuf nor the REST API define a field
+// for them.
+type SubmissionTrackingConfig struct {
+	// Enabled enables the tracking of sent transactions.
+	Enabled bool
+
+	// ResubmitInterval is the number of finalized blocks after a submission without the transaction
+	// being included in a finalized block, after which the transaction is resubmitted.
+	ResubmitInterval uint64
+
+	// MaxTracked is the maximum number of transactions tracked at once. When it is reached, the
+	// least recently sent transaction is no longer tracked, nor resubmitted.
+	MaxTracked uint
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c SubmissionTrackingConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.ResubmitInterval == 0 || c.ResubmitInterval >= flow.DefaultTransactionExpiry {
+		return fmt.Errorf("transaction resubmit interval (%d) must be between 1 and the transaction expiry (%d)",
+			c.ResubmitInterval, flow.DefaultTransactionExpiry)
+	}
+	if c.MaxTracked == 0 {
+		return fmt.Errorf("maximum number of tracked transactions must be greater than 0")
+	}
+	return nil
+}
+
+// SetSubmissionTracking configures the backend to track the transactions it sends, according to the
+// given configuration. It must be called before the backend starts serving requests.
+func (b *Backend) SetSubmissionTracking(config SubmissionTrackingConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
+	// transactions can only be resubmitted to other collection nodes if the backend chooses them
+	if b.backendTransactions.staticCollectionRPC != nil {
+		return fmt.Errorf("transaction submission tracking cannot be used with a fixed collection node")
+	}
+
+	submissions, err := newSubmissionTracker(config.ResubmitInterval, config.MaxTracked)
+	if err != nil {
+		return fmt.Errorf("could not create submission tracker: %w", err)
+	}
+
+	b.backendTransactions.submissions = submissions
+	return nil
+}
+
+// submissionTracker tracks sent transactions and their submissions to collection nodes.
+type submissionTracker struct {
+	mu               sync.Mutex
+	resubmitInterval uint64
+	transactions     *lru.Cache // tracked transactions by ID, the least recently sent is evicted when full
+}
+
+// trackedTransaction is a sent transaction and its submissions to collection nodes.
+type trackedTransaction struct {
+	tx              *flow.TransactionBody
+	referenceHeight uint64 // height of the reference block, after which the transaction expires
+	submittedHeight uint64 // finalized height of the latest submission
+	done            bool   // whether the transaction was included in a finalized block or expired
+	doneHeight      uint64 // finalized height at which tracking stopped
+	submissions     []access.TransactionSubmission
+}
+
+func newSubmissionTracker(resubmitInterval uint64, maxTracked uint) (*submissionTracker, error) {
+	transactions, err := lru.New(int(maxTracked))
+	if err != nil {
+		return nil, err
+	}
+
+	return &submissionTracker{
+		resubmitInterval: resubmitInterval,
+		transactions:     transactions,
+	}, nil
+}
+
+// get returns the tracked transaction with the given ID, without marking it as recently used.
+func (t *submissionTracker) get(txID flow.Identifier) (*trackedTransaction, bool) {
+	value, ok := t.transactions.Peek(txID)
+	if !ok {
+		return nil, false
+	}
+	return value.(*trackedTransaction), true
+}
+
+// track starts tracking a transaction after its first submissions at the given finalized height.
+func (t *submissionTracker) track(
+	tx *flow.TransactionBody,
+	referenceHeight uint64,
+	height uint64,
+	submissions []access.TransactionSubmission,
+) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracked := &trackedTransaction{
+		tx:              tx,
+		referenceHeight: referenceHeight,
+		submittedHeight: height,
+	}
+	tracked.record(height, submissions)
+	t.transactions.Add(tx.ID(), tracked)
+}
+
+// record adds the submissions of a tracked transaction made at the given finalized height.
+func (t *submissionTracker) record(txID flow.Identifier, height uint64, submissions []access.TransactionSubmission) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracked, ok := t.get(txID)
+	if ok {
+		tracked.record(height, submissions)
+	}
+}
+
+func (t *trackedTransaction) record(height uint64, submissions []access.TransactionSubmission) {
+	for _, submission := range submissions {
+		submission.Height = height
+		t.submissions = append(t.submissions, submission)
+	}
+}
+
+// stop stops tracking a transaction at the given finalized height. Its submissions are kept until
+// the transaction expiry has passed again, so they can still be returned with its result.
+func (t *submissionTracker) stop(txID flow.Identifier, height uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracked, ok := t.get(txID)
+	if ok {
+		tracked.done = true
+		tracked.doneHeight = height
+	}
+}
+
+// submissionsOf returns the submissions of a transaction, or nil if it is not tracked.
+func (t *submissionTracker) submissionsOf(txID flow.Identifier) []access.TransactionSubmission {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracked, ok := t.get(txID)
+	if !ok {
+		return nil
+	}
+	return append([]access.TransactionSubmission(nil), tracked.submissions...)
+}
+
+// due returns copies of the tracked transactions due for resubmission at the given finalized
+// height, and marks them as submitted at that height. Transactions which stopped being tracked more
+// than the transaction expiry ago are removed.
+func (t *submissionTracker) due(height uint64) []trackedTransaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var due []trackedTransaction
+	for _, key := range t.transactions.Keys() {
+		tracked, ok := t.get(key.(flow.Identifier))
+		if !ok {
+			continue
+		}
+		if tracked.done {
+			if height > tracked.doneHeight+flow.DefaultTransactionExpiry {
+				t.transactions.Remove(key)
+			}
+			continue
+		}
+		if height < tracked.submittedHeight+t.resubmitInterval {
+			continue
+		}
+
+		tracked.submittedHeight = height
+		dueTx := *tracked
+		dueTx.submissions = append([]access.TransactionSubmission(nil), tracked.submissions...)
+		due = append(due, dueTx)
+	}
+	return due
+}
+
+// trackTransaction starts tracking a sent transaction, if tracking is enabled.
+func (b *backendTransactions) trackTransaction(tx *flow.TransactionBody, submissions []access.TransactionSubmission) {
+	if b.submissions == nil {
+		return
+	}
+
+	referenceBlock, err := b.state.AtBlockID(tx.ReferenceBlockID).Head()
+	if err != nil {
+		b.log.Warn().Err(err).Str("tx_id", tx.ID().String()).Msg("could not track transaction without reference block")
+		return
+	}
+	finalized, err := b.state.Final().Head()
+	if err != nil {
+		b.log.Warn().Err(err).Str("tx_id", tx.ID().String()).Msg("could not track transaction without finalized block")
+		return
+	}
+
+	b.submissions.track(tx, referenceBlock.Height, finalized.Height, submissions)
+}
+
+// resubmitTransactions checks the tracked transactions which were not included in a finalized block
+// within the resubmit interval after their latest submission, at the given finalized height. They are
+// resubmitted to collection nodes of their cluster they were not submitted to yet, unless they were
+// included or expired in the meantime, in which case they are no longer tracked.
+func (b *backendTransactions) resubmitTransactions(height uint64) {
+	if b.submissions == nil {
+		return
+	}
+
+	for _, tracked := range b.submissions.due(height) {
+		txID := tracked.tx.ID()
+		log := b.log.With().Str("tx_id", txID.String()).Uint64("height", height).Logger()
+
+		if b.isExpired(tracked.referenceHeight, height) {
+			log.Info().Int("submissions", len(tracked.submissions)).Msg("tracked transaction expired")
+			b.submissions.stop(txID, height)
+			continue
+		}
+
+		_, err := b.lookupBlock(txID)
+		if err == nil {
+			b.submissions.stop(txID, height)
+			continue
+		}
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Warn().Err(err).Msg("could not look up block of tracked transaction")
+			continue
+		}
+
+		// prefer the collection nodes the transaction was not submitted to yet
+		submitted := make(map[string]struct{}, len(tracked.submissions))
+		for _, submission := range tracked.submissions {
+			submitted[submission.CollectionNode] = struct{}{}
+		}
+
+		submissions, err := b.submitTransaction(context.Background(), tracked.tx, submitted)
+		b.submissions.record(txID, height, submissions)
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to resubmit tracked transaction")
+			continue
+		}
+		log.Info().Int("submissions", len(tracked.submissions)+len(submissions)).Msg("resubmitted tracked transaction")
+	}
+}
+
+// submissionsOf returns the submissions of a transaction, or nil if it is not tracked.
+func (b *backendTransactions) submissionsOf(txID flow.Identifier) []access.TransactionSubmission {
+	if b.submissions == nil {
+		return nil
+	}
+	return b.submissions.submissionsOf(txID)
+}
@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	accessapi "github.com/onflow/flow-go/access"
+	backendmock "github.com/onflow/flow-go/engine/access/rpc/backend/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	realstorage "github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestTransactionSubmissionTracking tests that tracked transactions are resubmitted to other
+// collection nodes of their cluster until they are included in a finalized block.
+func (suite *Suite) TestTransactionSubmissionTracking() {
+	ctx := context.Background()
+	collection := unittest.CollectionFixture(1)
+	referenceBlock := unittest.BlockFixture()
+	transactionBody := collection.Transactions[0]
+	transactionBody.SetReferenceBlockID(referenceBlock.ID())
+	txID := transactionBody.ID()
+	light := collection.Light()
+	finalized := unittest.BlockHeaderWithParentFixture(referenceBlock.Header)
+
+	// the transaction cluster consists of all collection nodes
+	cluster := make(flow.IdentityList, 5)
+	for i := range cluster {
+		cluster[i] = &flow.Identity{
+			NodeID:  unittest.IdentifierFixture(),
+			Role:    flow.RoleCollection,
+			Address: fmt.Sprintf("collection-%d:9000", i),
+		}
+	}
+	epoch := new(protocol.Epoch)
+	epoch.On("Clustering").Return(flow.ClusterList{cluster}, nil)
+	epochQuery := new(protocol.EpochQuery)
+	epochQuery.On("Current").Return(epoch)
+	suite.state.On("Final").Return(suite.snapshot)
+	suite.snapshot.On("Epochs").Return(epochQuery)
+	suite.snapshot.On("Head").Return(finalized, nil)
+	snapshotAtBlock := new(protocol.Snapshot)
+	snapshotAtBlock.On("Head").Return(referenceBlock.Header, nil)
+	suite.state.On("AtBlockID", referenceBlock.ID()).Return(snapshotAtBlock)
+
+	connFactory := new(backendmock.ConnectionFactory)
+	connFactory.On("GetAccessAPIClient", mock.Anything).Return(suite.colClient, &mockCloser{}, nil)
+	suite.colClient.On("SendTransaction", mock.Anything, mock.Anything).Return(&access.SendTransactionResponse{}, nil)
+
+	backend := New(suite.state,
+		nil,
+		nil,
+		suite.blocks,
+		suite.headers,
+		suite.collections,
+		suite.transactions,
+		suite.receipts,
+		suite.results,
+		suite.chainID,
+		metrics.NewNoopCollector(),
+		connFactory,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+	suite.Require().Error(backend.SetSubmissionTracking(SubmissionTrackingConfig{Enabled: true, ResubmitInterval: 0, MaxTracked: 10}))
+	suite.Require().Error(backend.SetSubmissionTracking(SubmissionTrackingConfig{Enabled: true, ResubmitInterval: 10, MaxTracked: 0}))
+	suite.Require().NoError(backend.SetSubmissionTracking(SubmissionTrackingConfig{Enabled: true, ResubmitInterval: 10, MaxTracked: 10}))
+
+	// the first submission is accepted by the first collection node it is sent to
+	submissions, err := backend.submitTransaction(ctx, transactionBody, nil)
+	suite.Require().NoError(err)
+	suite.Require().Len(submissions, 1)
+	backend.trackTransaction(transactionBody, submissions)
+
+	history := backend.submissionsOf(txID)
+	suite.Require().Len(history, 1)
+	suite.Assert().Equal(finalized.Height, history[0].Height)
+	suite.Assert().Empty(history[0].Error)
+
+	// the transaction is not included in a finalized block
+	suite.collections.On("LightByTransactionID", txID).Return(nil, realstorage.ErrNotFound).Once()
+
+	// the transaction is not resubmitted before the resubmit interval elapsed
+	backend.resubmitTransactions(finalized.Height + 9)
+	suite.Assert().Len(backend.submissionsOf(txID), 1)
+
+	// the transaction is resubmitted to another collection node of its cluster
+	backend.resubmitTransactions(finalized.Height + 10)
+	history = backend.submissionsOf(txID)
+	suite.Require().Len(history, 2)
+	suite.Assert().Equal(finalized.Height+10, history[1].Height)
+	suite.Assert().NotEqual(history[0].CollectionNode, history[1].CollectionNode)
+	suite.colClient.AssertNumberOfCalls(suite.T(), "SendTransaction", 2)
+
+	// the transaction is not resubmitted once it is included in a finalized block
+	suite.collections.On("LightByTransactionID", txID).Return(&light, nil).Once()
+	suite.blocks.On("ByCollectionID", light.ID()).Return(&referenceBlock, nil).Once()
+	backend.resubmitTransactions(finalized.Height + 20)
+	backend.resubmitTransactions(finalized.Height + 30)
+	suite.colClient.AssertNumberOfCalls(suite.T(), "SendTransaction", 2)
+
+	// the submissions are kept after tracking stopped
+	suite.Assert().Len(backend.submissionsOf(txID), 2)
+	backend.resubmitTransactions(finalized.Height + 21 + flow.DefaultTransactionExpiry)
+	suite.Assert().Empty(backend.submissionsOf(txID))
+
+	suite.assertAllExpectations()
+}
+
+// TestSubmissionTracker_MaxTracked tests that the least recently sent transaction is no longer
+// tracked once the maximum number of tracked transactions is reached.
+func TestSubmissionTracker_MaxTracked(t *testing.T) {
+	tracker, err := newSubmissionTracker(DefaultResubmitInterval, 2)
+	require.NoError(t, err)
+
+	submissions := []accessapi.TransactionSubmission{{CollectionNode: "collection-0:9000"}}
+	txs := make([]*flow.TransactionBody, 3)
+	for i := range txs {
+		tx := unittest.TransactionBodyFixture()
+		txs[i] = &tx
+		tracker.track(txs[i], 100, 100, submissions)
+	}
+
+	assert.Empty(t, tracker.submissionsOf(txs[0].ID()))
+	assert.Len(t, tracker.submissionsOf(txs[1].ID()), 1)
+	assert.Len(t, tracker.submissionsOf(txs[2].ID()), 1)
+	assert.Len(t, tracker.due(100+DefaultResubmitInterval), 2)
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	grpcmiddleware "github.com/grpc-ecosystem/go-grpc-middleware/v2"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	lru "github.com/hashicorp/golang-lru"
 	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
@@ -16,9 +17,11 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
+	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/access/rest"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	"github.com/onflow/flow-go/engine/access/state_stream"
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
@@ -36,6 +39,7 @@ type Config struct {
 	TransportCredentials      credentials.TransportCredentials // the secure GRPC credentials
 	HTTPListenAddr            string                           // the HTTP web proxy address as ip:port
 	RESTListenAddr            string                           // the REST server address as ip:port (if empty the REST server will not be started)
+	RESTSubscription          rest.SubscriptionConfig          // the configuration of the REST API websocket subscriptions
 	CollectionAddr            string                           // the address of the upstream collection node
 	HistoricalAccessAddrs     string                           // the list of all access nodes from previous spork
 	MaxMsgSize                uint                             // GRPC max message size
@@ -46,6 +50,12 @@ type Config struct {
 	MaxHeightRange            uint                             // max size of height range requests
 	PreferredExecutionNodeIDs []string                         // preferred list of upstream execution node IDs
 	FixedExecutionNodeIDs     []string                         // fixed list of execution node IDs to choose from if no node node ID can be chosen from the PreferredExecutionNodeIDs
+	ScriptExecutionMode       backend.ScriptExecutionMode      // where scripts are executed and accounts are read from, if a script executor is configured
+	APIQuotas                 *rpc.Quotas                      // optional per-client quotas enforced for the gRPC and REST APIs
+	ScriptResultCache         backend.ScriptResultCacheConfig  // optional cache of script results at sealed blocks
+	ExecutionNodeHealth       *backend.ExecutionNodeHealth     // optional tracker used to order execution nodes by health
+	ResultVerification        backend.ResultVerificationConfig // optional verification of script and event responses by several execution nodes
+	SubmissionTracking        backend.SubmissionTrackingConfig // optional tracking and resubmission of sent transactions until they are included or expire
 }
 
 // Engine exposes the server with a simplified version of the Access API.
@@ -55,10 +65,13 @@ type Engine struct {
 	unit               *engine.Unit
 	log                zerolog.Logger
 	backend            *backend.Backend // the gRPC service implementation
+	restAPI            access.API       // the API served over REST, the backend unless requests are routed to historical sporks
 	unsecureGrpcServer *grpc.Server     // the unsecure gRPC server
 	secureGrpcServer   *grpc.Server     // the secure gRPC server
 	httpServer         *http.Server
+	transcoder         *HTTPTranscoder // serves the gRPC API as JSON on the HTTP proxy server
 	restServer         *http.Server
+	stateStream        state_stream.API // the optional state stream API serving execution data over REST
 	config             Config
 	chain              flow.Chain
 
@@ -105,6 +118,12 @@ func NewBuilder(log zerolog.Logger,
 		interceptors = append(interceptors, grpc_prometheus.UnaryServerInterceptor)
 	}
 
+	// enforce the per-client quotas before the per-method rate limits, so that requests rejected
+	// for exceeding the quota of a client do not count towards the limits shared by all clients
+	if config.APIQuotas != nil {
+		interceptors = append(interceptors, config.APIQuotas.UnaryServerInterceptor)
+	}
+
 	if len(apiRatelimits) > 0 {
 		// create a rate limit interceptor
 		rateLimitInterceptor := rpc.NewRateLimiterInterceptor(log, apiRatelimits, apiBurstLimits).UnaryServerInterceptor
@@ -126,8 +145,11 @@ func NewBuilder(log zerolog.Logger,
 	grpcOpts = append(grpcOpts, grpc.Creds(config.TransportCredentials))
 	secureGrpcServer := grpc.NewServer(grpcOpts...)
 
+	// the transcoder applies the same interceptors to the requests it serves over HTTP
+	transcoder := NewHTTPTranscoder(grpcmiddleware.ChainUnaryServer(interceptors...), config.MaxMsgSize)
+
 	// wrap the unsecured server with an HTTP proxy server to serve HTTP clients
-	httpServer := NewHTTPServer(unsecureGrpcServer, config.HTTPListenAddr)
+	httpServer := NewHTTPServer(unsecureGrpcServer, transcoder, config.HTTPListenAddr)
 
 	var cache *lru.Cache
 	cacheSize := config.ConnectionPoolSize
@@ -185,13 +207,31 @@ func NewBuilder(log zerolog.Logger,
 		backend.DefaultSnapshotHistoryLimit,
 	)
 
+	err := backend.SetScriptResultCache(config.ScriptResultCache)
+	if err != nil {
+		return nil, err
+	}
+	backend.SetExecutionNodeHealth(config.ExecutionNodeHealth)
+
+	err = backend.SetResultVerification(config.ResultVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	err = backend.SetSubmissionTracking(config.SubmissionTracking)
+	if err != nil {
+		return nil, err
+	}
+
 	eng := &Engine{
 		log:                log,
 		unit:               engine.NewUnit(),
 		backend:            backend,
+		restAPI:            backend,
 		unsecureGrpcServer: unsecureGrpcServer,
 		secureGrpcServer:   secureGrpcServer,
 		httpServer:         httpServer,
+		transcoder:         transcoder,
 		config:             config,
 		chain:              chainID.Chain(),
 	}
@@ -274,6 +314,10 @@ func (e *Engine) process(event interface{}) error {
 	switch entity := event.(type) {
 	case *flow.Block:
 		e.backend.NotifyFinalizedBlockHeight(entity.Header.Height)
+		e.backend.NotifyTransactionStatusUpdate()
+		return nil
+	case *flow.LightCollection, *flow.ExecutionReceipt:
+		e.backend.NotifyTransactionStatusUpdate()
 		return nil
 	default:
 		return fmt.Errorf("invalid event type (%T)", event)
@@ -350,7 +394,7 @@ func (e *Engine) serveREST() {
 
 	e.log.Info().Str("rest_api_address", e.config.RESTListenAddr).Msg("starting REST server on address")
 
-	r, err := rest.NewServer(e.backend, e.config.RESTListenAddr, e.log, e.chain)
+	r, err := rest.NewServer(e.restAPI, e.stateStream, e.config.APIQuotas, e.config.RESTListenAddr, e.log, e.chain, e.config.RESTSubscription)
 	if err != nil {
 		e.log.Err(err).Msg("failed to initialize the REST server")
 		return
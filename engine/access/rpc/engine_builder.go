@@ -10,6 +10,11 @@ import (
 	"github.com/onflow/flow-go/access"
 	legacyaccess "github.com/onflow/flow-go/access/legacy"
 	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/engine/access/apiproxy"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	"github.com/onflow/flow-go/engine/access/rpc/txstatus"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/storage"
 )
 
 type RPCEngineBuilder struct {
@@ -18,6 +23,10 @@ type RPCEngineBuilder struct {
 	// optional parameters, only one can be set during build phase
 	signerIndicesDecoder hotstuff.BlockSignerDecoder
 	handler              accessproto.AccessAPIServer // Use the parent interface instead of implementation, so that we can assign it to proxy.
+
+	// optional historical sporks, whose requests are routed to their access nodes
+	sporkRootHeight  uint64
+	historicalSporks []apiproxy.HistoricalSpork
 }
 
 // NewRPCEngineBuilder helps to build a new RPC engine.
@@ -32,6 +41,13 @@ func (builder *RPCEngineBuilder) Handler() accessproto.AccessAPIServer {
 	return builder.handler
 }
 
+// DefaultHandler returns a handler serving API queries from the engine's backend, regardless of
+// the handler set with WithNewHandler. This allows a custom handler to serve some queries from the
+// backend.
+func (builder *RPCEngineBuilder) DefaultHandler() accessproto.AccessAPIServer {
+	return access.NewHandler(builder.Engine.backend, builder.Engine.chain)
+}
+
 // WithBlockSignerDecoder specifies that signer indices in block headers should be translated
 // to full node IDs with the given decoder.
 // Caution:
@@ -57,6 +73,48 @@ func (builder *RPCEngineBuilder) WithNewHandler(handler accessproto.AccessAPISer
 	return builder
 }
 
+// WithHistoricalSporks specifies that requests for data of the given previous sporks should be
+// routed to their access nodes, in addition to the handler serving the current spork, which starts
+// at sporkRootHeight.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithHistoricalSporks(sporkRootHeight uint64, sporks []apiproxy.HistoricalSpork) *RPCEngineBuilder {
+	builder.sporkRootHeight = sporkRootHeight
+	builder.historicalSporks = sporks
+	return builder
+}
+
+// WithScriptExecutor specifies that scripts should be executed and accounts read using the given
+// executor of the local execution state, according to the configured script execution mode.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithScriptExecutor(executor backend.ScriptExecutor) *RPCEngineBuilder {
+	builder.backend.SetScriptExecutor(executor, builder.config.ScriptExecutionMode)
+	return builder
+}
+
+// WithEventIndex specifies that filtered event queries should be served from the given local
+// event index.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithEventIndex(index storage.EventIndex) *RPCEngineBuilder {
+	builder.backend.SetEventIndex(index)
+	return builder
+}
+
+// WithAccountTransactionIndex specifies that the transactions of accounts should be served from
+// the given index.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithAccountTransactionIndex(index storage.AccountTransactions) *RPCEngineBuilder {
+	builder.backend.SetAccountTransactionIndex(index)
+	return builder
+}
+
+// WithStateStreamAPI specifies that the REST API should serve execution data using the given state
+// stream API.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithStateStreamAPI(api state_stream.API) *RPCEngineBuilder {
+	builder.stateStream = api
+	return builder
+}
+
 // WithLegacy specifies that a legacy access API should be instantiated
 // Returns self-reference for chaining.
 func (builder *RPCEngineBuilder) WithLegacy() *RPCEngineBuilder {
@@ -89,12 +147,29 @@ func (builder *RPCEngineBuilder) Build() (*Engine, error) {
 	handler := builder.handler
 	if handler == nil {
 		if builder.signerIndicesDecoder == nil {
-			handler = access.NewHandler(builder.Engine.backend, builder.Engine.chain)
+			handler = access.NewHandler(builder.Engine.backend, builder.Engine.chain, access.WithLogger(builder.log))
 		} else {
-			handler = access.NewHandler(builder.Engine.backend, builder.Engine.chain, access.WithBlockSignerDecoder(builder.signerIndicesDecoder))
+			handler = access.NewHandler(builder.Engine.backend, builder.Engine.chain, access.WithLogger(builder.log), access.WithBlockSignerDecoder(builder.signerIndicesDecoder))
+		}
+	}
+	if len(builder.historicalSporks) > 0 {
+		router, err := apiproxy.NewSporkRouter(builder.log, handler, builder.sporkRootHeight, builder.historicalSporks, builder.config.MaxHeightRange)
+		if err != nil {
+			return nil, fmt.Errorf("could not create historical spork router: %w", err)
 		}
+		handler = router
+		builder.Engine.restAPI = apiproxy.NewSporkRouterAPI(router, builder.Engine.backend, builder.Engine.chain)
 	}
 	accessproto.RegisterAccessAPIServer(builder.unsecureGrpcServer, handler)
 	accessproto.RegisterAccessAPIServer(builder.secureGrpcServer, handler)
+	builder.transcoder.RegisterService(&accessproto.AccessAPI_ServiceDesc, handler)
+
+	// transaction statuses are streamed from the engine's backend, which is only notified of status
+	// changes when the engine serves the access API itself rather than a custom handler
+	if builder.handler == nil {
+		statuses := txstatus.NewHandler(builder.Engine.backend, builder.Engine.chain)
+		txstatus.RegisterServer(builder.unsecureGrpcServer, statuses)
+		txstatus.RegisterServer(builder.secureGrpcServer, statuses)
+	}
 	return builder.Engine, nil
 }
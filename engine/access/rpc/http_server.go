@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	"google.golang.org/grpc"
@@ -27,9 +28,11 @@ var defaultHTTPHeaders = []HTTPHeader{
 	},
 }
 
-// NewHTTPServer creates and intializes a new HTTP GRPC proxy server
+// NewHTTPServer creates and intializes a new HTTP GRPC proxy server. gRPC-web requests are served
+// by the gRPC server, and all other requests are served as JSON by the transcoder.
 func NewHTTPServer(
 	grpcServer *grpc.Server,
+	transcoder *HTTPTranscoder,
 	address string,
 ) *http.Server {
 	wrappedServer := grpcweb.WrapServer(
@@ -40,7 +43,7 @@ func NewHTTPServer(
 	mux := http.NewServeMux()
 
 	// register gRPC HTTP proxy
-	mux.Handle("/", wrappedHandler(wrappedServer, defaultHTTPHeaders))
+	mux.Handle("/", wrappedHandler(wrappedServer, transcoder, defaultHTTPHeaders))
 
 	httpServer := &http.Server{
 		Addr:    address,
@@ -50,7 +53,7 @@ func NewHTTPServer(
 	return httpServer
 }
 
-func wrappedHandler(wrappedServer *grpcweb.WrappedGrpcServer, headers []HTTPHeader) http.HandlerFunc {
+func wrappedHandler(wrappedServer *grpcweb.WrappedGrpcServer, transcoder *HTTPTranscoder, headers []HTTPHeader) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		setResponseHeaders(res, headers)
 
@@ -58,7 +61,13 @@ func wrappedHandler(wrappedServer *grpcweb.WrappedGrpcServer, headers []HTTPHead
 			return
 		}
 
-		wrappedServer.ServeHTTP(res, req)
+		// covers both the application/grpc-web and application/grpc-web-text content types
+		if strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc") {
+			wrappedServer.ServeHTTP(res, req)
+			return
+		}
+
+		transcoder.ServeHTTP(res, req)
 	}
 }
 
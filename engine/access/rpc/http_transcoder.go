@@ -0,0 +1,196 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/engine/access/rest/models"
+)
+
+// HTTPTranscoder serves the unary methods of gRPC services as JSON over HTTP, so every method is
+// reachable without a protobuf client. Methods are transcoded from their protobuf definitions and
+// are served at POST /<package>.<service>/<method>, the same path as over gRPC, e.g.
+// POST /flow.access.AccessAPI/GetLatestBlock. The request and response bodies are the JSON encodings
+// of the request and response messages, following the canonical protobuf JSON mapping (bytes are
+// base64 encoded). An empty request body is an empty request message.
+//
+// Requests pass through the same interceptors as gRPC requests, with the HTTP request headers as
+// incoming metadata and the HTTP client as peer, so per-client quotas and rate limits apply
+// equally to both. Headers set by the method are returned as HTTP response headers.
+//
+// Errors are returned with the HTTP status corresponding to their gRPC code, the same mapping as
+// used by gRPC gateways, and with the same error body as the REST API.
+type HTTPTranscoder struct {
+	interceptor grpc.UnaryServerInterceptor
+	maxMsgSize  uint
+	methods     map[string]transcodedMethod // methods by full method name, e.g. "/flow.access.AccessAPI/Ping"
+}
+
+type transcodedMethod struct {
+	impl interface{}
+	desc grpc.MethodDesc
+}
+
+// NewHTTPTranscoder returns a new transcoder calling the given interceptor for every request, and
+// rejecting request bodies larger than maxMsgSize.
+func NewHTTPTranscoder(interceptor grpc.UnaryServerInterceptor, maxMsgSize uint) *HTTPTranscoder {
+	return &HTTPTranscoder{
+		interceptor: interceptor,
+		maxMsgSize:  maxMsgSize,
+		methods:     make(map[string]transcodedMethod),
+	}
+}
+
+// RegisterService registers the unary methods of a service and its implementation, like
+// grpc.Server.RegisterService. Streaming methods are not transcoded.
+// It must be called before the transcoder serves any request.
+func (t *HTTPTranscoder) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	for _, method := range desc.Methods {
+		t.methods[fmt.Sprintf("/%s/%s", desc.ServiceName, method.MethodName)] = transcodedMethod{
+			impl: impl,
+			desc: method,
+		}
+	}
+}
+
+func (t *HTTPTranscoder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	method, ok := t.methods[req.URL.Path]
+	if !ok {
+		writeTranscodedError(w, status.Errorf(codes.Unimplemented, "unknown method %s", req.URL.Path))
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeTranscodedErrorResponse(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s must be called with POST", req.URL.Path))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, int64(t.maxMsgSize)))
+	if err != nil {
+		writeTranscodedError(w, status.Errorf(codes.InvalidArgument, "could not read request body: %v", err))
+		return
+	}
+
+	decode := func(msg interface{}) error {
+		if len(bytes.TrimSpace(body)) == 0 {
+			return nil
+		}
+		unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+		err := unmarshaler.Unmarshal(bytes.NewReader(body), msg.(proto.Message))
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid request body: %v", err)
+		}
+		return nil
+	}
+
+	stream := &transcodedStream{method: req.URL.Path}
+	ctx := grpc.NewContextWithServerTransportStream(t.incomingContext(req), stream)
+
+	res, err := method.desc.Handler(method.impl, ctx, decode, t.interceptor)
+
+	for key, values := range stream.header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+
+	if err != nil {
+		writeTranscodedError(w, err)
+		return
+	}
+
+	var encoded bytes.Buffer
+	marshaler := jsonpb.Marshaler{EmitDefaults: true}
+	err = marshaler.Marshal(&encoded, res.(proto.Message))
+	if err != nil {
+		writeTranscodedError(w, status.Errorf(codes.Internal, "could not encode response: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(encoded.Bytes())
+}
+
+// incomingContext returns the context of a request, with its headers as incoming gRPC metadata and
+// its client as peer.
+func (t *HTTPTranscoder) incomingContext(req *http.Request) context.Context {
+	md := metadata.MD{}
+	for key, values := range req.Header {
+		md.Append(key, values...)
+	}
+
+	ctx := metadata.NewIncomingContext(req.Context(), md)
+	return peer.NewContext(ctx, &peer.Peer{Addr: remoteAddr(req.RemoteAddr)})
+}
+
+// transcodedStream collects the headers and trailers set while handling a transcoded request,
+// which are all returned as HTTP response headers.
+type transcodedStream struct {
+	method string
+	header metadata.MD
+}
+
+var _ grpc.ServerTransportStream = (*transcodedStream)(nil)
+
+func (s *transcodedStream) Method() string {
+	return s.method
+}
+
+func (s *transcodedStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *transcodedStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *transcodedStream) SetTrailer(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+// remoteAddr is the address of an HTTP client.
+type remoteAddr string
+
+func (a remoteAddr) Network() string {
+	return "tcp"
+}
+
+func (a remoteAddr) String() string {
+	return string(a)
+}
+
+// writeTranscodedError sends an error response with the HTTP status corresponding to the gRPC code
+// of the error.
+func writeTranscodedError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	writeTranscodedErrorResponse(w, runtime.HTTPStatusFromCode(st.Code()), st.Message())
+}
+
+// writeTranscodedErrorResponse sends an error response with the given HTTP status, in the same
+// format as the REST API.
+func writeTranscodedErrorResponse(w http.ResponseWriter, code int, message string) {
+	encoded, _ := json.Marshal(models.ModelError{
+		Code:    int32(code),
+		Message: message,
+	})
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+	_, _ = w.Write(encoded)
+}
@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	accessmock "github.com/onflow/flow-go/engine/access/mock"
+	"github.com/onflow/flow-go/engine/common/rpc"
+	"github.com/onflow/flow-go/utils/grpcutils"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestHTTPTranscoder tests that Access API methods are served as JSON over HTTP, through the
+// interceptors of the gRPC server.
+func TestHTTPTranscoder(t *testing.T) {
+	handler := accessmock.NewAccessAPIServer(t)
+
+	var intercepted []string
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		p, _ := peer.FromContext(ctx)
+		intercepted = append(intercepted, info.FullMethod, md.Get(rpc.APIKeyHeader)[0], rpc.RemoteIP(p.Addr.String()))
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs(rpc.RateLimitRemainingHeader, "9"))
+		return next(ctx, req)
+	}
+
+	transcoder := NewHTTPTranscoder(interceptor, grpcutils.DefaultMaxMsgSize)
+	transcoder.RegisterService(&accessproto.AccessAPI_ServiceDesc, handler)
+
+	send := func(method string, path string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(rpc.APIKeyHeader, "key")
+		req.RemoteAddr = "10.0.0.1:1234"
+		rr := httptest.NewRecorder()
+		transcoder.ServeHTTP(rr, req)
+		return rr
+	}
+
+	errorBody := func(t *testing.T, rr *httptest.ResponseRecorder) map[string]interface{} {
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		return body
+	}
+
+	t.Run("request and response are transcoded", func(t *testing.T) {
+		intercepted = nil
+		blockID := unittest.IdentifierFixture()
+		handler.
+			On("GetLatestBlockHeader", mock.Anything, &accessproto.GetLatestBlockHeaderRequest{IsSealed: true}).
+			Return(&accessproto.BlockHeaderResponse{Block: &entities.BlockHeader{Id: blockID[:], Height: 10}}, nil).
+			Once()
+
+		rr := send(http.MethodPost, "/flow.access.AccessAPI/GetLatestBlockHeader", `{"isSealed": true}`)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var res struct {
+			Block struct {
+				ID     []byte `json:"id"`
+				Height string `json:"height"`
+			} `json:"block"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		assert.Equal(t, blockID[:], res.Block.ID)
+		assert.Equal(t, "10", res.Block.Height)
+
+		assert.Equal(t, []string{"/flow.access.AccessAPI/GetLatestBlockHeader", "key", "10.0.0.1"}, intercepted)
+		assert.Equal(t, "9", rr.Header().Get(rpc.RateLimitRemainingHeader))
+	})
+
+	t.Run("empty body is an empty request", func(t *testing.T) {
+		handler.
+			On("Ping", mock.Anything, &accessproto.PingRequest{}).
+			Return(&accessproto.PingResponse{}, nil).
+			Once()
+
+		rr := send(http.MethodPost, "/flow.access.AccessAPI/Ping", "")
+		assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	})
+
+	t.Run("errors are mapped from gRPC codes", func(t *testing.T) {
+		handler.
+			On("GetTransactionResultByIndex", mock.Anything, mock.Anything).
+			Return(nil, status.Error(codes.NotFound, "transaction not found")).
+			Once()
+
+		rr := send(http.MethodPost, "/flow.access.AccessAPI/GetTransactionResultByIndex", `{"index": 1}`)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, map[string]interface{}{
+			"code":    float64(http.StatusNotFound),
+			"message": "transaction not found",
+		}, errorBody(t, rr))
+	})
+
+	t.Run("invalid request body", func(t *testing.T) {
+		rr := send(http.MethodPost, "/flow.access.AccessAPI/GetLatestBlock", `{"isSealed": "maybe"}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, float64(http.StatusBadRequest), errorBody(t, rr)["code"])
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		rr := send(http.MethodPost, "/flow.access.AccessAPI/Unknown", "")
+		assert.Equal(t, http.StatusNotImplemented, rr.Code)
+	})
+
+	t.Run("methods must be called with POST", func(t *testing.T) {
+		rr := send(http.MethodGet, "/flow.access.AccessAPI/Ping", "")
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+		assert.Equal(t, http.MethodPost, rr.Header().Get("Allow"))
+	})
+}
@@ -0,0 +1,149 @@
+// Package txstatus defines the transaction status streaming RPCs of access nodes.
+//
+// The pinned flow protobuf does not define the RPCs yet, so their service is described by hand. Its
+// messages are the existing messages of the access API, so the service uses the default protobuf
+// codec and can be called by any gRPC client knowing the method names. The service is served by
+// the gRPC servers of access nodes alongside the access API.
+package txstatus
+
+import (
+	"context"
+
+	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
+	"google.golang.org/grpc"
+)
+
+const (
+	// ServiceName is the full name of the gRPC service.
+	ServiceName = "flow.access.TransactionStatusesAPI"
+
+	sendAndSubscribeTransactionStatusesMethod = "/" + ServiceName + "/SendAndSubscribeTransactionStatuses"
+	subscribeTransactionStatusesMethod        = "/" + ServiceName + "/SubscribeTransactionStatuses"
+)
+
+// Server is the server API of the service.
+type Server interface {
+	// SendAndSubscribeTransactionStatuses sends the transaction to a collection node, then streams
+	// the transaction's result each time its status changes, until it is sealed or expired.
+	SendAndSubscribeTransactionStatuses(*accessproto.SendTransactionRequest, TransactionStatusesServer) error
+
+	// SubscribeTransactionStatuses streams the result of the transaction each time its status
+	// changes, starting with its current status, until it is sealed or expired.
+	SubscribeTransactionStatuses(*accessproto.GetTransactionRequest, TransactionStatusesServer) error
+}
+
+// TransactionStatusesServer is the server side of a transaction status stream.
+type TransactionStatusesServer interface {
+	Send(*accessproto.TransactionResultResponse) error
+	grpc.ServerStream
+}
+
+// Client is the client API of the service.
+type Client interface {
+	// SendAndSubscribeTransactionStatuses sends the transaction to a collection node, then streams
+	// the transaction's result each time its status changes, until it is sealed or expired.
+	SendAndSubscribeTransactionStatuses(ctx context.Context, in *accessproto.SendTransactionRequest, opts ...grpc.CallOption) (TransactionStatusesClient, error)
+
+	// SubscribeTransactionStatuses streams the result of the transaction each time its status
+	// changes, starting with its current status, until it is sealed or expired.
+	SubscribeTransactionStatuses(ctx context.Context, in *accessproto.GetTransactionRequest, opts ...grpc.CallOption) (TransactionStatusesClient, error)
+}
+
+// TransactionStatusesClient is the client side of a transaction status stream. Recv returns io.EOF
+// once the transaction is sealed or expired.
+type TransactionStatusesClient interface {
+	Recv() (*accessproto.TransactionResultResponse, error)
+	grpc.ClientStream
+}
+
+// RegisterServer registers the service implementation with the gRPC server.
+func RegisterServer(s *grpc.Server, srv Server) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Server)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SendAndSubscribeTransactionStatuses",
+			Handler:       sendAndSubscribeTransactionStatusesHandler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeTransactionStatuses",
+			Handler:       subscribeTransactionStatusesHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+func sendAndSubscribeTransactionStatusesHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(accessproto.SendTransactionRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(Server).SendAndSubscribeTransactionStatuses(in, &transactionStatusesServer{stream})
+}
+
+func subscribeTransactionStatusesHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(accessproto.GetTransactionRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(Server).SubscribeTransactionStatuses(in, &transactionStatusesServer{stream})
+}
+
+type transactionStatusesServer struct {
+	grpc.ServerStream
+}
+
+func (s *transactionStatusesServer) Send(m *accessproto.TransactionResultResponse) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+type client struct {
+	cc grpc.ClientConnInterface
+}
+
+var _ Client = (*client)(nil)
+
+// NewClient returns a client of the service using the given connection.
+func NewClient(cc grpc.ClientConnInterface) Client {
+	return &client{cc: cc}
+}
+
+func (c *client) SendAndSubscribeTransactionStatuses(ctx context.Context, in *accessproto.SendTransactionRequest, opts ...grpc.CallOption) (TransactionStatusesClient, error) {
+	return c.subscribe(ctx, &serviceDesc.Streams[0], sendAndSubscribeTransactionStatusesMethod, in, opts...)
+}
+
+func (c *client) SubscribeTransactionStatuses(ctx context.Context, in *accessproto.GetTransactionRequest, opts ...grpc.CallOption) (TransactionStatusesClient, error) {
+	return c.subscribe(ctx, &serviceDesc.Streams[1], subscribeTransactionStatusesMethod, in, opts...)
+}
+
+func (c *client) subscribe(ctx context.Context, desc *grpc.StreamDesc, method string, in interface{}, opts ...grpc.CallOption) (TransactionStatusesClient, error) {
+	stream, err := c.cc.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return &transactionStatusesClient{stream}, nil
+}
+
+type transactionStatusesClient struct {
+	grpc.ClientStream
+}
+
+func (c *transactionStatusesClient) Recv() (*accessproto.TransactionResultResponse, error) {
+	m := new(accessproto.TransactionResultResponse)
+	if err := c.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
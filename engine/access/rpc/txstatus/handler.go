@@ -0,0 +1,77 @@
+package txstatus
+
+import (
+	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Handler serves the service from the transaction status subscriptions of an access API.
+type Handler struct {
+	api   access.API
+	chain flow.Chain
+}
+
+var _ Server = (*Handler)(nil)
+
+func NewHandler(api access.API, chain flow.Chain) *Handler {
+	return &Handler{
+		api:   api,
+		chain: chain,
+	}
+}
+
+func (h *Handler) SendAndSubscribeTransactionStatuses(req *accessproto.SendTransactionRequest, stream TransactionStatusesServer) error {
+	tx, err := convert.MessageToTransaction(req.GetTransaction(), h.chain)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub := h.api.SendAndSubscribeTransactionStatuses(stream.Context(), &tx)
+
+	return streamTransactionStatuses(sub, stream)
+}
+
+func (h *Handler) SubscribeTransactionStatuses(req *accessproto.GetTransactionRequest, stream TransactionStatusesServer) error {
+	id, err := convert.TransactionID(req.GetId())
+	if err != nil {
+		return err
+	}
+
+	sub := h.api.SubscribeTransactionStatuses(stream.Context(), id)
+
+	return streamTransactionStatuses(sub, stream)
+}
+
+// streamTransactionStatuses sends the results of the subscription to the stream, until the
+// subscription is closed or fails.
+func streamTransactionStatuses(sub state_stream.Subscription, stream TransactionStatusesServer) error {
+	for {
+		v, ok := <-sub.Channel()
+		if !ok {
+			err := sub.Err()
+			if err == nil {
+				return nil
+			}
+			if _, ok := status.FromError(err); ok {
+				return err
+			}
+			return status.Errorf(codes.Internal, "stream encountered an error: %v", err)
+		}
+
+		result, ok := v.(*access.TransactionResult)
+		if !ok {
+			return status.Errorf(codes.Internal, "unexpected response type: %T", v)
+		}
+
+		err := stream.Send(access.TransactionResultToMessage(result))
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package txstatus
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/onflow/flow-go/access"
+	accessmock "github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestHandler tests that transaction statuses are streamed over gRPC from the subscriptions of the
+// access API, until the subscription is closed or fails.
+func TestHandler(t *testing.T) {
+	api := accessmock.NewAPI(t)
+
+	server := grpc.NewServer()
+	RegisterServer(server, NewHandler(api, flow.Testnet.Chain()))
+
+	listener := bufconn.Listen(1 << 20)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := NewClient(conn)
+
+	txID := unittest.IdentifierFixture()
+	blockID := unittest.IdentifierFixture()
+	results := []*access.TransactionResult{
+		{TransactionID: txID, Status: flow.TransactionStatusPending},
+		{TransactionID: txID, BlockID: blockID, Status: flow.TransactionStatusFinalized},
+		{TransactionID: txID, BlockID: blockID, Status: flow.TransactionStatusSealed, Events: []flow.Event{unittest.EventFixture(flow.EventAccountCreated, 0, 0, txID, 0)}},
+	}
+
+	t.Run("subscribe", func(t *testing.T) {
+		sub := state_stream.NewSubscription(len(results))
+		for _, result := range results {
+			require.NoError(t, sub.Send(context.Background(), result, time.Second))
+		}
+		sub.Close()
+		api.On("SubscribeTransactionStatuses", mock.Anything, txID).Return(sub).Once()
+
+		stream, err := client.SubscribeTransactionStatuses(context.Background(), &accessproto.GetTransactionRequest{Id: txID[:]})
+		require.NoError(t, err)
+
+		for _, result := range results {
+			resp, err := stream.Recv()
+			require.NoError(t, err)
+			require.Equal(t, uint32(result.Status), uint32(resp.GetStatus()))
+			require.Equal(t, result.BlockID[:], resp.GetBlockId())
+			require.Len(t, resp.GetEvents(), len(result.Events))
+		}
+		_, err = stream.Recv()
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("send and subscribe", func(t *testing.T) {
+		tx := unittest.TransactionBodyFixture()
+
+		sub := state_stream.NewSubscription(1)
+		sub.Fail(status.Error(codes.InvalidArgument, "invalid transaction"))
+		api.On("SendAndSubscribeTransactionStatuses", mock.Anything, mock.MatchedBy(func(sent *flow.TransactionBody) bool {
+			return sent.ID() == tx.ID()
+		})).Return(sub).Once()
+
+		stream, err := client.SendAndSubscribeTransactionStatuses(context.Background(), &accessproto.SendTransactionRequest{
+			Transaction: convert.TransactionToMessage(tx),
+		})
+		require.NoError(t, err)
+
+		// the error of the subscription is returned with its status code
+		_, err = stream.Recv()
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("invalid transaction ID", func(t *testing.T) {
+		stream, err := client.SubscribeTransactionStatuses(context.Background(), &accessproto.GetTransactionRequest{})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -2,9 +2,17 @@ package state_stream
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/model/flow"
@@ -12,28 +20,63 @@ import (
 	"github.com/onflow/flow-go/storage"
 )
 
+const (
+	// DefaultSendTimeout is the default timeout for sending a message to the client. After the timeout
+	// expires, the connection is closed.
+	DefaultSendTimeout = 30 * time.Second
+)
+
 type API interface {
 	GetExecutionDataByBlockID(ctx context.Context, blockID flow.Identifier) (*entities.BlockExecutionData, error)
+	SubscribeExecutionData(ctx context.Context, startBlockID flow.Identifier, startBlockHeight uint64) Subscription
+	SubscribeEvents(ctx context.Context, startBlockID flow.Identifier, startHeight uint64, filter EventFilter) Subscription
+}
+
+// ExecutionDataResponse is the response type sent to subscribers of SubscribeExecutionData
+type ExecutionDataResponse struct {
+	Height        uint64
+	ExecutionData *execution_data.BlockExecutionData
 }
 
 type StateStreamBackend struct {
+	log           zerolog.Logger
 	headers       storage.Headers
 	seals         storage.Seals
 	results       storage.ExecutionResults
 	execDataStore execution_data.ExecutionDataStore
+	broadcaster   *engine.Broadcaster
+
+	rootBlockHeight uint64
+	sendTimeout     time.Duration
+	sendBufferSize  int
+
+	// highestHeight contains the highest consecutive block height for which we have received a
+	// new Execution Data notification.
+	highestHeight *atomic.Uint64
 }
 
 func New(
+	log zerolog.Logger,
+	config Config,
 	headers storage.Headers,
 	seals storage.Seals,
 	results storage.ExecutionResults,
 	execDataStore execution_data.ExecutionDataStore,
+	broadcaster *engine.Broadcaster,
+	rootHeight uint64,
+	highestAvailableHeight uint64,
 ) *StateStreamBackend {
 	return &StateStreamBackend{
-		headers:       headers,
-		seals:         seals,
-		results:       results,
-		execDataStore: execDataStore,
+		log:             log.With().Str("module", "state_stream_api").Logger(),
+		headers:         headers,
+		seals:           seals,
+		results:         results,
+		execDataStore:   execDataStore,
+		broadcaster:     broadcaster,
+		rootBlockHeight: rootHeight,
+		sendTimeout:     config.ClientSendTimeout,
+		sendBufferSize:  int(config.ClientSendBufferSize),
+		highestHeight:   atomic.NewUint64(highestAvailableHeight),
 	}
 }
 
@@ -43,24 +86,150 @@ func (s *StateStreamBackend) GetExecutionDataByBlockID(ctx context.Context, bloc
 		return nil, rpc.ConvertStorageError(err)
 	}
 
-	seal, err := s.seals.FinalizedSealForBlock(header.ID())
+	blockExecData, err := s.getExecutionData(ctx, header.ID())
 	if err != nil {
-		return nil, rpc.ConvertStorageError(err)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, rpc.ConvertStorageError(err)
+		}
+		return nil, err
 	}
 
-	result, err := s.results.ByID(seal.ResultID)
+	message, err := convert.BlockExecutionDataToMessage(blockExecData)
 	if err != nil {
-		return nil, rpc.ConvertStorageError(err)
+		return nil, err
 	}
+	return message, nil
+}
 
-	blockExecData, err := s.execDataStore.GetExecutionData(ctx, result.ExecutionDataID)
+// SubscribeExecutionData streams the execution data for all sealed blocks, in height order,
+// starting at the provided block ID or height. Only one of startBlockID and startHeight may be
+// provided. If neither is provided, streaming starts from the latest block with available
+// execution data.
+//
+// Each message sent on the subscription channel is an *ExecutionDataResponse. Data for new blocks
+// is sent as soon as the execution data requester has fetched it. If the client reads slower
+// than the data is produced, sending blocks until either the client catches up or the send
+// timeout expires, at which point the subscription is failed.
+func (s *StateStreamBackend) SubscribeExecutionData(ctx context.Context, startBlockID flow.Identifier, startHeight uint64) Subscription {
+	nextHeight, err := s.getStartHeight(startBlockID, startHeight)
 	if err != nil {
-		return nil, err
+		return s.failedSubscription(err)
 	}
 
-	message, err := convert.BlockExecutionDataToMessage(blockExecData)
+	sub := NewHeightBasedSubscription(s.sendBufferSize, nextHeight, func(ctx context.Context, height uint64) (interface{}, error) {
+		return s.getExecutionDataResponse(ctx, height)
+	})
+
+	go NewStreamer(s.log, s.broadcaster, s.sendTimeout, sub).Stream(ctx)
+
+	return sub
+}
+
+// failedSubscription returns a subscription that has already failed with an error that occurred
+// while looking up the start height.
+func (s *StateStreamBackend) failedSubscription(err error) Subscription {
+	sub := NewSubscription(s.sendBufferSize)
+	if st, ok := status.FromError(err); ok {
+		sub.Fail(status.Errorf(st.Code(), "could not get start height: %s", st.Message()))
+		return sub
+	}
+
+	sub.Fail(fmt.Errorf("could not get start height: %w", err))
+	return sub
+}
+
+// setHighestHeight sets the highest height for which execution data is available. The height is
+// never decreased, since notifications may be repeated.
+func (s *StateStreamBackend) setHighestHeight(height uint64) bool {
+	for {
+		current := s.highestHeight.Load()
+		if height <= current {
+			return false
+		}
+		if s.highestHeight.CAS(current, height) {
+			return true
+		}
+	}
+}
+
+// getExecutionDataResponse returns the execution data response for the block at the given height.
+// Expected errors:
+// - storage.ErrNotFound if the execution data is not available yet
+func (s *StateStreamBackend) getExecutionDataResponse(ctx context.Context, height uint64) (*ExecutionDataResponse, error) {
+	// make sure we don't try to read heights that have not been fetched yet, otherwise the
+	// streamer would read partially downloaded execution data from the blobstore
+	if height > s.highestHeight.Load() {
+		return nil, fmt.Errorf("execution data for block %d is not available yet: %w", height, storage.ErrNotFound)
+	}
+
+	header, err := s.headers.ByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("could not get block header for height %d: %w", height, err)
+	}
+
+	executionData, err := s.getExecutionData(ctx, header.ID())
 	if err != nil {
 		return nil, err
 	}
-	return message, nil
+
+	return &ExecutionDataResponse{
+		Height:        header.Height,
+		ExecutionData: executionData,
+	}, nil
+}
+
+// getExecutionData returns the execution data for the given block from the local execution data
+// store.
+// Expected errors:
+// - storage.ErrNotFound if the block, its seal or execution result are not known
+// - execution_data.BlobNotFoundError if the execution data is not in the local store
+func (s *StateStreamBackend) getExecutionData(ctx context.Context, blockID flow.Identifier) (*execution_data.BlockExecutionData, error) {
+	seal, err := s.seals.FinalizedSealForBlock(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get finalized seal for block: %w", err)
+	}
+
+	result, err := s.results.ByID(seal.ResultID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get execution result: %w", err)
+	}
+
+	blockExecData, err := s.execDataStore.GetExecutionData(ctx, result.ExecutionDataID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get execution data: %w", err)
+	}
+
+	return blockExecData, nil
+}
+
+// getStartHeight returns the first height to stream for a subscription.
+func (s *StateStreamBackend) getStartHeight(startBlockID flow.Identifier, startHeight uint64) (uint64, error) {
+	// make sure only one of start block ID and start height is provided
+	if startBlockID != flow.ZeroID && startHeight > 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "only one of start block ID and start height may be provided")
+	}
+
+	if startHeight > 0 {
+		if startHeight < s.rootBlockHeight {
+			return 0, status.Errorf(codes.InvalidArgument, "start height must be greater than or equal to the root height %d", s.rootBlockHeight)
+		}
+
+		return startHeight, nil
+	}
+
+	if startBlockID != flow.ZeroID {
+		header, err := s.headers.ByBlockID(startBlockID)
+		if err != nil {
+			return 0, rpc.ConvertStorageError(err)
+		}
+
+		if header.Height < s.rootBlockHeight {
+			return 0, status.Errorf(codes.InvalidArgument, "start block must have a height greater than or equal to the root height %d", s.rootBlockHeight)
+		}
+
+		return header.Height, nil
+	}
+
+	// if no start block was provided, use the latest block with execution data
+	return s.highestHeight.Load(), nil
 }
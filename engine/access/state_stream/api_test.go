@@ -9,15 +9,21 @@ import (
 
 	"github.com/ipfs/go-datastore"
 	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/testutils"
+	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/blobs"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/storage"
 	storagemock "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -46,7 +52,11 @@ func (suite *Suite) TestGetExecutionDataByBlockID() {
 	// create the handler with the mock
 	bs := blobs.NewBlobstore(dssync.MutexWrap(datastore.NewMapDatastore()))
 	eds := execution_data.NewExecutionDataStore(bs, execution_data.DefaultSerializer)
-	client := New(suite.headers, suite.seals, suite.results, eds)
+	config := Config{
+		ClientSendTimeout:    DefaultSendTimeout,
+		ClientSendBufferSize: DefaultSendBufferSize,
+	}
+	client := New(zerolog.Nop(), config, suite.headers, suite.seals, suite.results, eds, engine.NewBroadcaster(), 0, 0)
 
 	// mock parameters
 	ctx := context.Background()
@@ -95,6 +105,186 @@ func (suite *Suite) TestGetExecutionDataByBlockID() {
 	suite.results.AssertExpectations(suite.T())
 }
 
+func (suite *Suite) TestSubscribeExecutionData() {
+	bs := blobs.NewBlobstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	eds := execution_data.NewExecutionDataStore(bs, execution_data.DefaultSerializer)
+	broadcaster := engine.NewBroadcaster()
+	config := Config{
+		ClientSendTimeout:    DefaultSendTimeout,
+		ClientSendBufferSize: DefaultSendBufferSize,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// create a chain of blocks with execution data for each block
+	blockCount := 5
+	blocks := unittest.ChainFixtureFrom(blockCount, unittest.BlockHeaderFixture())
+	rootHeight := blocks[0].Header.Height
+	for _, block := range blocks {
+		execData := &execution_data.BlockExecutionData{
+			BlockID:             block.ID(),
+			ChunkExecutionDatas: []*execution_data.ChunkExecutionData{generateChunkExecutionData(suite.T(), 0)},
+		}
+		execDataID, err := eds.AddExecutionData(ctx, execData)
+		require.NoError(suite.T(), err)
+
+		result := unittest.ExecutionResultFixture(unittest.WithBlock(block))
+		result.ExecutionDataID = execDataID
+		seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+
+		suite.headers.On("ByHeight", block.Header.Height).Return(block.Header, nil).Maybe()
+		suite.seals.On("FinalizedSealForBlock", block.ID()).Return(seal, nil).Maybe()
+		suite.results.On("ByID", seal.ResultID).Return(result, nil).Maybe()
+	}
+
+	// only the first 3 blocks are available when subscribing
+	highestHeight := rootHeight + 2
+	backend := New(zerolog.Nop(), config, suite.headers, suite.seals, suite.results, eds, broadcaster, rootHeight, highestHeight)
+
+	sub := backend.SubscribeExecutionData(ctx, flow.ZeroID, rootHeight)
+
+	receive := func(expected *flow.Block) {
+		select {
+		case v, ok := <-sub.Channel():
+			require.True(suite.T(), ok, "subscription closed unexpectedly: %v", sub.Err())
+			resp, ok := v.(*ExecutionDataResponse)
+			require.True(suite.T(), ok, "unexpected response type: %T", v)
+			assert.Equal(suite.T(), expected.Header.Height, resp.Height)
+			assert.Equal(suite.T(), expected.ID(), resp.ExecutionData.BlockID)
+		case <-time.After(time.Second):
+			suite.T().Fatalf("timed out waiting for execution data for height %d", expected.Header.Height)
+		}
+	}
+
+	for _, block := range blocks[:3] {
+		receive(block)
+	}
+
+	// no more data is available until the highest height is updated
+	select {
+	case v := <-sub.Channel():
+		suite.T().Fatalf("unexpected response: %v", v)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	for _, block := range blocks[3:] {
+		backend.setHighestHeight(block.Header.Height)
+		broadcaster.Publish()
+		receive(block)
+	}
+
+	// cancelling the context closes the subscription
+	cancel()
+	select {
+	case _, ok := <-sub.Channel():
+		assert.False(suite.T(), ok)
+	case <-time.After(time.Second):
+		suite.T().Fatal("timed out waiting for subscription to close")
+	}
+	assert.ErrorIs(suite.T(), sub.Err(), context.Canceled)
+}
+
+func (suite *Suite) TestSubscribeEvents() {
+	bs := blobs.NewBlobstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	eds := execution_data.NewExecutionDataStore(bs, execution_data.DefaultSerializer)
+	config := Config{
+		ClientSendTimeout:    DefaultSendTimeout,
+		ClientSendBufferSize: DefaultSendBufferSize,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	matchingType := flow.EventType("A.0000000000000001.Contract1.EventA")
+	otherType := flow.EventType("A.0000000000000002.Contract2.EventB")
+
+	blocks := unittest.ChainFixtureFrom(3, unittest.BlockHeaderFixture())
+	rootHeight := blocks[0].Header.Height
+	for _, block := range blocks {
+		txID := unittest.IdentifierFixture()
+		chunk := generateChunkExecutionData(suite.T(), 0)
+		chunk.Events = flow.EventsList{
+			unittest.EventFixture(matchingType, 0, 0, txID, 0),
+			unittest.EventFixture(otherType, 0, 1, txID, 0),
+			unittest.EventFixture(matchingType, 0, 2, txID, 0),
+		}
+		execData := &execution_data.BlockExecutionData{
+			BlockID:             block.ID(),
+			ChunkExecutionDatas: []*execution_data.ChunkExecutionData{chunk},
+		}
+		execDataID, err := eds.AddExecutionData(ctx, execData)
+		require.NoError(suite.T(), err)
+
+		result := unittest.ExecutionResultFixture(unittest.WithBlock(block))
+		result.ExecutionDataID = execDataID
+		seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+
+		suite.headers.On("ByHeight", block.Header.Height).Return(block.Header, nil)
+		suite.seals.On("FinalizedSealForBlock", block.ID()).Return(seal, nil)
+		suite.results.On("ByID", seal.ResultID).Return(result, nil)
+	}
+
+	backend := New(zerolog.Nop(), config, suite.headers, suite.seals, suite.results, eds, engine.NewBroadcaster(), rootHeight, blocks[len(blocks)-1].Header.Height)
+
+	filter, err := NewEventFilter(DefaultEventFilterConfig, flow.Emulator.Chain(), []string{string(matchingType)}, nil, nil)
+	require.NoError(suite.T(), err)
+
+	sub := backend.SubscribeEvents(ctx, flow.ZeroID, rootHeight, filter)
+
+	for _, block := range blocks {
+		select {
+		case v, ok := <-sub.Channel():
+			require.True(suite.T(), ok, "subscription closed unexpectedly: %v", sub.Err())
+			resp, ok := v.(*EventsResponse)
+			require.True(suite.T(), ok, "unexpected response type: %T", v)
+
+			assert.Equal(suite.T(), block.ID(), resp.BlockID)
+			assert.Equal(suite.T(), block.Header.Height, resp.Height)
+			require.Len(suite.T(), resp.Events, 2)
+			for _, event := range resp.Events {
+				assert.Equal(suite.T(), matchingType, event.Type)
+			}
+		case <-time.After(time.Second):
+			suite.T().Fatalf("timed out waiting for events for height %d", block.Header.Height)
+		}
+	}
+}
+
+func (suite *Suite) TestSubscribeExecutionDataInvalidStart() {
+	bs := blobs.NewBlobstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	eds := execution_data.NewExecutionDataStore(bs, execution_data.DefaultSerializer)
+	config := Config{
+		ClientSendTimeout:    DefaultSendTimeout,
+		ClientSendBufferSize: DefaultSendBufferSize,
+	}
+	backend := New(zerolog.Nop(), config, suite.headers, suite.seals, suite.results, eds, engine.NewBroadcaster(), 10, 10)
+
+	suite.Run("both start block ID and height provided", func() {
+		sub := backend.SubscribeExecutionData(context.Background(), unittest.IdentifierFixture(), 20)
+		_, ok := <-sub.Channel()
+		assert.False(suite.T(), ok)
+		assert.Equal(suite.T(), codes.InvalidArgument, status.Code(sub.Err()))
+	})
+
+	suite.Run("start height below root height", func() {
+		sub := backend.SubscribeExecutionData(context.Background(), flow.ZeroID, 5)
+		_, ok := <-sub.Channel()
+		assert.False(suite.T(), ok)
+		assert.Equal(suite.T(), codes.InvalidArgument, status.Code(sub.Err()))
+	})
+
+	suite.Run("unknown start block", func() {
+		blockID := unittest.IdentifierFixture()
+		suite.headers.On("ByBlockID", blockID).Return(nil, storage.ErrNotFound).Once()
+
+		sub := backend.SubscribeExecutionData(context.Background(), blockID, 0)
+		_, ok := <-sub.Channel()
+		assert.False(suite.T(), ok)
+		assert.Equal(suite.T(), codes.NotFound, status.Code(sub.Err()))
+	})
+}
+
 func generateChunkExecutionData(t *testing.T, minSerializedSize uint64) *execution_data.ChunkExecutionData {
 	ced := &execution_data.ChunkExecutionData{
 		TrieUpdate: testutils.TrieUpdateFixture(1, 1, 8),
@@ -0,0 +1,63 @@
+package state_stream
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// EventsResponse is the response type sent to subscribers of SubscribeEvents. It contains all of
+// the events matching the subscription's filter for a single block.
+type EventsResponse struct {
+	BlockID flow.Identifier
+	Height  uint64
+	Events  flow.EventsList
+}
+
+// SubscribeEvents streams events matching the provided filter for all sealed blocks, in height
+// order, starting at the provided block ID or height. Only one of startBlockID and startHeight
+// may be provided. If neither is provided, streaming starts from the latest block with available
+// execution data.
+//
+// Each message sent on the subscription channel is an *EventsResponse containing the matching
+// events for a single block. A response is sent for every block, even if no events matched, so
+// clients can track their progress through the chain.
+func (s *StateStreamBackend) SubscribeEvents(ctx context.Context, startBlockID flow.Identifier, startHeight uint64, filter EventFilter) Subscription {
+	nextHeight, err := s.getStartHeight(startBlockID, startHeight)
+	if err != nil {
+		return s.failedSubscription(err)
+	}
+
+	sub := NewHeightBasedSubscription(s.sendBufferSize, nextHeight, s.getEventsResponseFactory(filter))
+
+	go NewStreamer(s.log, s.broadcaster, s.sendTimeout, sub).Stream(ctx)
+
+	return sub
+}
+
+// getEventsResponseFactory returns a GetDataByHeightFunc that extracts the events matching the
+// filter from the execution data of the block at the requested height.
+func (s *StateStreamBackend) getEventsResponseFactory(filter EventFilter) GetDataByHeightFunc {
+	return func(ctx context.Context, height uint64) (interface{}, error) {
+		executionData, err := s.getExecutionDataResponse(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+
+		events := flow.EventsList{}
+		for _, chunkExecutionData := range executionData.ExecutionData.ChunkExecutionDatas {
+			events = append(events, filter.Filter(chunkExecutionData.Events)...)
+		}
+
+		s.log.Trace().
+			Hex("block_id", executionData.ExecutionData.BlockID[:]).
+			Uint64("height", height).
+			Msgf("sending %d events", len(events))
+
+		return &EventsResponse{
+			BlockID: executionData.ExecutionData.BlockID,
+			Height:  executionData.Height,
+			Events:  events,
+		}, nil
+	}
+}
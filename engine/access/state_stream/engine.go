@@ -3,18 +3,21 @@ package state_stream
 import (
 	"fmt"
 	"net"
+	"time"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	access "github.com/onflow/flow/protobuf/go/flow/executiondata"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 
+	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/component"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
 )
 
 // Config defines the configurable options for the ingress server.
@@ -22,6 +25,16 @@ type Config struct {
 	ListenAddr              string
 	MaxExecutionDataMsgSize uint // in bytes
 	RpcMetricsEnabled       bool // enable GRPC metrics
+
+	// ClientSendTimeout is the timeout for sending a single response to a streaming client. After
+	// the timeout expires, the subscription is closed.
+	ClientSendTimeout time.Duration
+
+	// ClientSendBufferSize is the size of the response buffer for each streaming client.
+	ClientSendBufferSize uint
+
+	// EventFilterConfig is used to configure the limits for EventFilters
+	EventFilterConfig EventFilterConfig
 }
 
 // Engine exposes the server with the state stream API.
@@ -36,6 +49,9 @@ type Engine struct {
 	chain   flow.Chain
 	handler *Handler
 
+	headers     storage.Headers
+	broadcaster *engine.Broadcaster
+
 	stateStreamGrpcAddress net.Addr
 }
 
@@ -48,6 +64,8 @@ func NewEng(
 	results storage.ExecutionResults,
 	log zerolog.Logger,
 	chainID flow.ChainID,
+	initialBlockHeight uint64, // the lowest height for which execution data may be available
+	highestBlockHeight uint64, // the highest consecutive height for which execution data is already available
 	apiRatelimits map[string]int, // the api rate limit (max calls per second) for each of the gRPC API e.g. Ping->100, GetExecutionDataByBlockID->300
 	apiBurstLimits map[string]int, // the api burst limit (max calls at the same time) for each of the gRPC API e.g. Ping->50, GetExecutionDataByBlockID->10
 ) *Engine {
@@ -79,15 +97,19 @@ func NewEng(
 
 	server := grpc.NewServer(grpcOpts...)
 
-	backend := New(headers, seals, results, execDataStore)
+	broadcaster := engine.NewBroadcaster()
+
+	backend := New(log, config, headers, seals, results, execDataStore, broadcaster, initialBlockHeight, highestBlockHeight)
 
 	e := &Engine{
-		log:     log.With().Str("engine", "state_stream_rpc").Logger(),
-		backend: backend,
-		server:  server,
-		chain:   chainID.Chain(),
-		config:  config,
-		handler: NewHandler(backend, chainID.Chain()),
+		log:         log.With().Str("engine", "state_stream_rpc").Logger(),
+		backend:     backend,
+		server:      server,
+		chain:       chainID.Chain(),
+		config:      config,
+		handler:     NewHandler(backend, chainID.Chain()),
+		headers:     headers,
+		broadcaster: broadcaster,
 	}
 
 	e.ComponentManager = component.NewComponentManagerBuilder().
@@ -98,6 +120,37 @@ func NewEng(
 	return e
 }
 
+// API returns the state stream API served by the engine.
+func (e *Engine) API() API {
+	return e.backend
+}
+
+// OnExecutionData is called to notify the engine when a new execution data is received.
+// It updates the highest available height and notifies all active subscriptions.
+// It is called by the execution data requester in consecutive height order, and must be non-blocking.
+func (e *Engine) OnExecutionData(executionData *execution_data.BlockExecutionData) {
+	lg := e.log.With().Hex("block_id", logging.ID(executionData.BlockID)).Logger()
+
+	lg.Trace().Msg("received execution data")
+
+	header, err := e.headers.ByBlockID(executionData.BlockID)
+	if err != nil {
+		// if the execution data is available, the block must be locally finalized
+		lg.Fatal().Err(err).Msg("failed to get header for execution data")
+		return
+	}
+
+	if ok := e.backend.setHighestHeight(header.Height); !ok {
+		// this means that the height was lower than the current highest height
+		// OnExecutionData is guaranteed by the requester to be called in order, but may be called
+		// multiple times for the same block.
+		lg.Debug().Uint64("height", header.Height).Msg("execution data for height already received")
+		return
+	}
+
+	e.broadcaster.Publish()
+}
+
 // serve starts the gRPC server.
 // When this function returns, the server is considered ready.
 func (e *Engine) serve(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
@@ -0,0 +1,190 @@
+package state_stream
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+var eventTypes = map[flow.EventType]bool{
+	"flow.AccountCreated":                 true,
+	"flow.AccountKeyAdded":                true,
+	"A.0000000000000001.Contract1.EventA": true,
+	"A.0000000000000001.Contract1.EventB": true,
+	"A.0000000000000001.Contract2.EventA": true,
+	"A.0000000000000001.Contract3.EventA": true,
+	"A.0000000000000002.Contract1.EventA": true,
+	"A.0000000000000002.Contract4.EventC": true,
+	"A.0000000000000003.Contract5.EventA": true,
+	"A.0000000000000003.Contract5.EventD": true,
+	"A.0000000000000004.Contract6.EventE": true,
+}
+
+func TestConstructor(t *testing.T) {
+	t.Parallel()
+
+	chain := flow.MonotonicEmulator.Chain()
+
+	t.Run("empty filter", func(t *testing.T) {
+		filter, err := NewEventFilter(DefaultEventFilterConfig, chain, nil, nil, nil)
+		require.NoError(t, err)
+		assert.False(t, filter.hasFilters)
+	})
+
+	t.Run("valid filters", func(t *testing.T) {
+		filter, err := NewEventFilter(
+			DefaultEventFilterConfig,
+			chain,
+			[]string{"flow.AccountCreated", "A.0000000000000001.Contract1.EventA"},
+			[]string{"0x0000000000000001", "2"},
+			[]string{"A.0000000000000003.Contract5", "flow"},
+		)
+		require.NoError(t, err)
+		assert.True(t, filter.hasFilters)
+		assert.Len(t, filter.EventTypes, 2)
+		assert.Contains(t, filter.Addresses, "0000000000000001")
+		assert.Contains(t, filter.Addresses, "0000000000000002")
+		assert.Len(t, filter.Contracts, 2)
+	})
+
+	t.Run("invalid event type", func(t *testing.T) {
+		for _, eventType := range []string{"invalid", "A.0000000000000001.Contract1", "flow.Event.Extra", "B.0000000000000001.Contract1.EventA"} {
+			_, err := NewEventFilter(DefaultEventFilterConfig, chain, []string{eventType}, nil, nil)
+			assert.Error(t, err, "expected error for event type %s", eventType)
+		}
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		_, err := NewEventFilter(DefaultEventFilterConfig, chain, nil, []string{"0xffffffffffffffff"}, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid contract", func(t *testing.T) {
+		for _, contract := range []string{"invalid", "A.0000000000000001", "A.0000000000000001.Contract1.EventA"} {
+			_, err := NewEventFilter(DefaultEventFilterConfig, chain, nil, nil, []string{contract})
+			assert.Error(t, err, "expected error for contract %s", contract)
+		}
+	})
+
+	t.Run("too many filters", func(t *testing.T) {
+		config := EventFilterConfig{MaxEventTypes: 1, MaxAddresses: 1, MaxContracts: 1}
+
+		_, err := NewEventFilter(config, chain, []string{"flow.AccountCreated", "flow.AccountKeyAdded"}, nil, nil)
+		assert.Error(t, err)
+
+		_, err = NewEventFilter(config, chain, nil, []string{"1", "2"}, nil)
+		assert.Error(t, err)
+
+		_, err = NewEventFilter(config, chain, nil, nil, []string{"flow", "A.0000000000000001.Contract1"})
+		assert.Error(t, err)
+	})
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	chain := flow.MonotonicEmulator.Chain()
+
+	tests := []struct {
+		name       string
+		eventTypes []string
+		addresses  []string
+		contracts  []string
+		matches    map[flow.EventType]bool
+	}{
+		{
+			name:    "no filters",
+			matches: eventTypes,
+		},
+		{
+			name:       "eventtype filter",
+			eventTypes: []string{"flow.AccountCreated", "A.0000000000000001.Contract1.EventA"},
+			matches: map[flow.EventType]bool{
+				"flow.AccountCreated":                 true,
+				"A.0000000000000001.Contract1.EventA": true,
+			},
+		},
+		{
+			name:      "address filter",
+			addresses: []string{"0000000000000001", "0000000000000002"},
+			matches: map[flow.EventType]bool{
+				"A.0000000000000001.Contract1.EventA": true,
+				"A.0000000000000001.Contract1.EventB": true,
+				"A.0000000000000001.Contract2.EventA": true,
+				"A.0000000000000001.Contract3.EventA": true,
+				"A.0000000000000002.Contract1.EventA": true,
+				"A.0000000000000002.Contract4.EventC": true,
+			},
+		},
+		{
+			name:      "contract filter",
+			contracts: []string{"A.0000000000000001.Contract1", "A.0000000000000002.Contract4"},
+			matches: map[flow.EventType]bool{
+				"A.0000000000000001.Contract1.EventA": true,
+				"A.0000000000000001.Contract1.EventB": true,
+				"A.0000000000000002.Contract4.EventC": true,
+			},
+		},
+		{
+			name:      "protocol contract filter",
+			contracts: []string{"flow"},
+			matches: map[flow.EventType]bool{
+				"flow.AccountCreated":  true,
+				"flow.AccountKeyAdded": true,
+			},
+		},
+		{
+			name:       "multiple filters",
+			eventTypes: []string{"A.0000000000000001.Contract1.EventA"},
+			addresses:  []string{"0000000000000002"},
+			contracts:  []string{"flow", "A.0000000000000001.Contract1", "A.0000000000000003.Contract5"},
+			matches: map[flow.EventType]bool{
+				"flow.AccountCreated":                 true,
+				"flow.AccountKeyAdded":                true,
+				"A.0000000000000001.Contract1.EventA": true,
+				"A.0000000000000001.Contract1.EventB": true,
+				"A.0000000000000002.Contract1.EventA": true,
+				"A.0000000000000002.Contract4.EventC": true,
+				"A.0000000000000003.Contract5.EventA": true,
+				"A.0000000000000003.Contract5.EventD": true,
+			},
+		},
+	}
+
+	events := make(flow.EventsList, 0, len(eventTypes))
+	for eventType := range eventTypes {
+		events = append(events, unittest.EventFixture(eventType, 0, 0, unittest.IdentifierFixture(), 0))
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := NewEventFilter(DefaultEventFilterConfig, chain, test.eventTypes, test.addresses, test.contracts)
+			require.NoError(t, err)
+
+			for _, event := range events {
+				assert.Equal(t, test.matches[event.Type], filter.Match(event), "event type: %s", event.Type)
+			}
+
+			filtered := filter.Filter(events)
+			assert.Len(t, filtered, len(test.matches))
+			for _, event := range filtered {
+				assert.True(t, test.matches[event.Type], "unexpected event type: %s", event.Type)
+			}
+		})
+	}
+
+	t.Run("malformed event types never match", func(t *testing.T) {
+		filter, err := NewEventFilter(DefaultEventFilterConfig, chain, nil, []string{"0000000000000001"}, []string{"flow"})
+		require.NoError(t, err)
+
+		for _, eventType := range []string{"invalid", "A.0000000000000001", strings.Repeat(".", 3)} {
+			event := unittest.EventFixture(flow.EventType(eventType), 0, 0, unittest.IdentifierFixture(), 0)
+			assert.False(t, filter.Match(event))
+		}
+	})
+}
@@ -9,6 +9,8 @@ import (
 	entities "github.com/onflow/flow/protobuf/go/flow/entities"
 
 	mock "github.com/stretchr/testify/mock"
+
+	state_stream "github.com/onflow/flow-go/engine/access/state_stream"
 )
 
 // API is an autogenerated mock type for the API type
@@ -39,6 +41,38 @@ func (_m *API) GetExecutionDataByBlockID(ctx context.Context, blockID flow.Ident
 	return r0, r1
 }
 
+// SubscribeEvents provides a mock function with given fields: ctx, startBlockID, startHeight, filter
+func (_m *API) SubscribeEvents(ctx context.Context, startBlockID flow.Identifier, startHeight uint64, filter state_stream.EventFilter) state_stream.Subscription {
+	ret := _m.Called(ctx, startBlockID, startHeight, filter)
+
+	var r0 state_stream.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier, uint64, state_stream.EventFilter) state_stream.Subscription); ok {
+		r0 = rf(ctx, startBlockID, startHeight, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(state_stream.Subscription)
+		}
+	}
+
+	return r0
+}
+
+// SubscribeExecutionData provides a mock function with given fields: ctx, startBlockID, startBlockHeight
+func (_m *API) SubscribeExecutionData(ctx context.Context, startBlockID flow.Identifier, startBlockHeight uint64) state_stream.Subscription {
+	ret := _m.Called(ctx, startBlockID, startBlockHeight)
+
+	var r0 state_stream.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier, uint64) state_stream.Subscription); ok {
+		r0 = rf(ctx, startBlockID, startBlockHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(state_stream.Subscription)
+		}
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewAPI interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"sync"
+)
+
+// Broadcaster is a distributor for Notifier objects. It implements a simple generic pub/sub
+// pattern. Callers can subscribe to single-channel notifications by passing a Notifier object to
+// the Subscribe method. When Publish is called, all subscribed Notifiers are notified.
+//
+// Since each subscriber receives notifications through its own Notifier, a slow subscriber never
+// blocks the publisher or other subscribers. Consecutive notifications are collapsed into a single
+// one if the subscriber has not consumed the previous notification yet.
+type Broadcaster struct {
+	subscribers map[Notifier]struct{}
+	mu          sync.RWMutex
+}
+
+// NewBroadcaster creates a new Broadcaster
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		subscribers: make(map[Notifier]struct{}),
+	}
+}
+
+// Subscribe adds a Notifier to the list of subscribers to be notified when Publish is called
+func (b *Broadcaster) Subscribe(n Notifier) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers[n] = struct{}{}
+}
+
+// Unsubscribe removes a Notifier from the list of subscribers. It is a no-op if the Notifier
+// was never subscribed.
+func (b *Broadcaster) Unsubscribe(n Notifier) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subscribers, n)
+}
+
+// Publish sends notifications to all current subscribers
+func (b *Broadcaster) Publish() {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for n := range b.subscribers {
+		n.Notify()
+	}
+}
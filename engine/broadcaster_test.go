@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBroadcaster_Publish verifies that all subscribed notifiers receive a notification
+func TestBroadcaster_Publish(t *testing.T) {
+	t.Parallel()
+	b := NewBroadcaster()
+
+	notifiers := make([]Notifier, 5)
+	for i := range notifiers {
+		notifiers[i] = NewNotifier()
+		b.Subscribe(notifiers[i])
+	}
+
+	b.Publish()
+
+	for _, n := range notifiers {
+		select {
+		case <-n.Channel(): // expected
+		default:
+			t.Fail()
+		}
+	}
+}
+
+// TestBroadcaster_Unsubscribe verifies that unsubscribed notifiers are not notified
+func TestBroadcaster_Unsubscribe(t *testing.T) {
+	t.Parallel()
+	b := NewBroadcaster()
+
+	subscribed := NewNotifier()
+	unsubscribed := NewNotifier()
+	b.Subscribe(subscribed)
+	b.Subscribe(unsubscribed)
+	b.Unsubscribe(unsubscribed)
+
+	b.Publish()
+
+	select {
+	case <-subscribed.Channel(): // expected
+	default:
+		t.Fail()
+	}
+
+	select {
+	case <-unsubscribed.Channel():
+		t.Fail()
+	default: // expected
+	}
+
+	// unsubscribing a notifier that was never subscribed is a no-op
+	assert.NotPanics(t, func() { b.Unsubscribe(NewNotifier()) })
+}
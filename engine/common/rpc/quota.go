@@ -0,0 +1,375 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/module"
+)
+
+const (
+	// APIKeyHeader is the gRPC metadata key and HTTP header used by clients to provide their API key.
+	APIKeyHeader = "x-api-key"
+
+	// RateLimitLimitHeader, RateLimitRemainingHeader and RetryAfterHeader are the headers returned
+	// to clients with the burst of the quota of the method, the number of requests remaining in it,
+	// and the number of seconds to wait before retrying a rejected request.
+	RateLimitLimitHeader     = "x-ratelimit-limit"
+	RateLimitRemainingHeader = "x-ratelimit-remaining"
+	RetryAfterHeader         = "retry-after"
+
+	// AnonymousClient is the client name used in logs and metrics for requests without API key.
+	AnonymousClient = "anonymous"
+
+	// DefaultQuotaIPCacheSize is the maximum number of client IPs whose quota usage is tracked.
+	// The least recently seen IPs are forgotten first, which resets their quota.
+	DefaultQuotaIPCacheSize = 10_000
+)
+
+// Results of a quota check, used in metrics.
+const (
+	QuotaResultAllowed         = "allowed"
+	QuotaResultRejected        = "rejected"
+	QuotaResultUnauthenticated = "unauthenticated"
+)
+
+var (
+	// ErrAPIKeyRequired is returned by Quotas.Check for requests without API key, if API keys are required.
+	ErrAPIKeyRequired = errors.New("API key required")
+
+	// ErrInvalidAPIKey is returned by Quotas.Check for requests with an unknown API key.
+	ErrInvalidAPIKey = errors.New("invalid API key")
+
+	// ErrQuotaExceeded is returned by Quotas.Check for requests exceeding the quota of the client.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// QuotaLimit is the quota of a client for a method: requests are allowed at the sustained rate
+// per second, with bursts of up to burst requests.
+type QuotaLimit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// QuotaLimits are the quotas of a client. Methods are identified by their gRPC method name, e.g.
+// "ExecuteScriptAtLatestBlock", or their REST route name, e.g. "executeScript".
+type QuotaLimits struct {
+	// Default is the quota for methods without explicit quota, or nil if they are not limited.
+	Default *QuotaLimit `json:"default,omitempty"`
+
+	// Methods are the quotas for each method.
+	Methods map[string]QuotaLimit `json:"methods,omitempty"`
+}
+
+// APIKeyConfig is the configuration of an API key.
+type APIKeyConfig struct {
+	// Name identifies the client in logs and metrics, so the key itself is never exposed.
+	Name string `json:"name"`
+	Key  string `json:"key"`
+
+	QuotaLimits
+}
+
+// QuotaConfig is the configuration of the per-client quotas, usually loaded from a JSON file with
+// LoadQuotaConfig.
+type QuotaConfig struct {
+	// RequireAPIKey rejects all requests without API key if true.
+	RequireAPIKey bool `json:"require_api_key"`
+
+	// Anonymous are the quotas applied to each client IP for requests without API key.
+	Anonymous QuotaLimits `json:"anonymous"`
+
+	// APIKeys are the known API keys and the quotas applied to the requests made with each.
+	APIKeys []APIKeyConfig `json:"api_keys"`
+}
+
+// LoadQuotaConfig reads the quota configuration from the JSON file at the given path.
+func LoadQuotaConfig(path string) (QuotaConfig, error) {
+	var config QuotaConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("could not read quota config: %w", err)
+	}
+
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return config, fmt.Errorf("could not decode quota config: %w", err)
+	}
+
+	return config, nil
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c QuotaConfig) Validate() error {
+	err := c.Anonymous.validate()
+	if err != nil {
+		return fmt.Errorf("invalid anonymous quota: %w", err)
+	}
+
+	keys := make(map[string]struct{}, len(c.APIKeys))
+	for i, key := range c.APIKeys {
+		if key.Name == "" || key.Key == "" {
+			return fmt.Errorf("API key %d must have a name and a key", i)
+		}
+		if key.Name == AnonymousClient {
+			return fmt.Errorf("API key name %s is reserved", AnonymousClient)
+		}
+		if _, ok := keys[key.Key]; ok {
+			return fmt.Errorf("API key %s is not unique", key.Name)
+		}
+		keys[key.Key] = struct{}{}
+
+		err := key.QuotaLimits.validate()
+		if err != nil {
+			return fmt.Errorf("invalid quota for API key %s: %w", key.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (l QuotaLimits) validate() error {
+	if l.Default != nil {
+		err := l.Default.validate()
+		if err != nil {
+			return fmt.Errorf("invalid default quota: %w", err)
+		}
+	}
+	for method, limit := range l.Methods {
+		err := limit.validate()
+		if err != nil {
+			return fmt.Errorf("invalid quota for %s: %w", method, err)
+		}
+	}
+	return nil
+}
+
+func (l QuotaLimit) validate() error {
+	if l.Rate <= 0 || l.Burst <= 0 {
+		return fmt.Errorf("rate and burst must be positive")
+	}
+	return nil
+}
+
+// QuotaStatus is the state of the quota of a client for a method after a request was checked.
+type QuotaStatus struct {
+	// Client is the name of the API key, or AnonymousClient.
+	Client string
+
+	// Limited is false if the method is not limited for the client, in which case the other
+	// fields are not set.
+	Limited bool
+
+	// Burst is the maximum number of requests allowed at once.
+	Burst int
+
+	// Remaining is the number of requests that are allowed right away.
+	Remaining int
+
+	// RetryAfter is the time to wait until the next request is allowed, if the request was rejected.
+	RetryAfter time.Duration
+}
+
+// Quotas enforces per-client quotas for each API method. Clients are identified by their API key,
+// or by their IP if they do not provide one. The configuration can be updated while the node is
+// running, which resets the quota usage of all clients.
+//
+// Quotas is safe for concurrent use.
+type Quotas struct {
+	log     zerolog.Logger
+	metrics module.APIQuotaMetrics
+
+	mu            sync.RWMutex
+	requireAPIKey bool
+	anonymous     QuotaLimits
+	keys          map[string]*quotaClient // API key -> client
+	ips           *lru.Cache              // IP -> *quotaClient
+}
+
+// NewQuotas returns new quotas enforcing the given configuration.
+func NewQuotas(log zerolog.Logger, metrics module.APIQuotaMetrics, config QuotaConfig) (*Quotas, error) {
+	q := &Quotas{
+		log:     log.With().Str("component", "api_quotas").Logger(),
+		metrics: metrics,
+	}
+
+	err := q.Update(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// Update replaces the configuration of the quotas, and resets the quota usage of all clients.
+// The configuration is not changed if it is invalid.
+func (q *Quotas) Update(config QuotaConfig) error {
+	err := config.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid quota config: %w", err)
+	}
+
+	ips, err := lru.New(DefaultQuotaIPCacheSize)
+	if err != nil {
+		return fmt.Errorf("could not create IP cache: %w", err)
+	}
+
+	keys := make(map[string]*quotaClient, len(config.APIKeys))
+	for _, key := range config.APIKeys {
+		keys[key.Key] = newQuotaClient(key.Name, key.QuotaLimits)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.requireAPIKey = config.RequireAPIKey
+	q.anonymous = config.Anonymous
+	q.keys = keys
+	q.ips = ips
+
+	q.log.Info().
+		Int("api_keys", len(keys)).
+		Bool("require_api_key", config.RequireAPIKey).
+		Msg("updated API quotas")
+
+	return nil
+}
+
+// Check checks whether a request to the method by the client with the given API key or IP is
+// within the client's quota, and consumes one request of the quota if it is. The API key is empty
+// if the client did not provide one.
+//
+// Expected errors:
+// - ErrAPIKeyRequired if no API key is provided but API keys are required
+// - ErrInvalidAPIKey if the API key is unknown
+// - ErrQuotaExceeded if the request exceeds the quota of the client
+func (q *Quotas) Check(apiKey string, ip string, method string) (QuotaStatus, error) {
+	client, err := q.client(apiKey, ip)
+	if err != nil {
+		q.metrics.APIRequestQuotaChecked(AnonymousClient, method, QuotaResultUnauthenticated)
+		return QuotaStatus{Client: AnonymousClient}, err
+	}
+
+	status, ok := client.take(method, time.Now())
+	if !ok {
+		q.metrics.APIRequestQuotaChecked(client.name, method, QuotaResultRejected)
+		q.log.Trace().
+			Str("client", client.name).
+			Str("ip", ip).
+			Str("method", method).
+			Msg("quota exceeded")
+		return status, ErrQuotaExceeded
+	}
+
+	q.metrics.APIRequestQuotaChecked(client.name, method, QuotaResultAllowed)
+	return status, nil
+}
+
+// client returns the client for the given API key or IP.
+func (q *Quotas) client(apiKey string, ip string) (*quotaClient, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if apiKey != "" {
+		client, ok := q.keys[apiKey]
+		if !ok {
+			return nil, ErrInvalidAPIKey
+		}
+		return client, nil
+	}
+
+	if q.requireAPIKey {
+		return nil, ErrAPIKeyRequired
+	}
+
+	if cached, ok := q.ips.Get(ip); ok {
+		return cached.(*quotaClient), nil
+	}
+
+	// if another request from the same IP raced to add a client, one of them is forgotten, which
+	// at most allows one extra request
+	client := newQuotaClient(AnonymousClient, q.anonymous)
+	q.ips.Add(ip, client)
+	return client, nil
+}
+
+// quotaClient tracks the quota usage of a client for each method.
+type quotaClient struct {
+	name   string
+	limits QuotaLimits
+
+	mu      sync.Mutex
+	buckets map[string]*quotaBucket // method -> bucket
+}
+
+func newQuotaClient(name string, limits QuotaLimits) *quotaClient {
+	return &quotaClient{
+		name:    name,
+		limits:  limits,
+		buckets: make(map[string]*quotaBucket),
+	}
+}
+
+// take consumes one request of the client's quota for the method, and returns false if there is
+// none left.
+func (c *quotaClient) take(method string, now time.Time) (QuotaStatus, bool) {
+	limit, ok := c.limits.Methods[method]
+	if !ok {
+		if c.limits.Default == nil {
+			return QuotaStatus{Client: c.name}, true
+		}
+		limit = *c.limits.Default
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bucket, ok := c.buckets[method]
+	if !ok {
+		bucket = &quotaBucket{tokens: float64(limit.Burst), last: now}
+		c.buckets[method] = bucket
+	}
+
+	remaining, retryAfter, ok := bucket.take(limit, now)
+	return QuotaStatus{
+		Client:     c.name,
+		Limited:    true,
+		Burst:      limit.Burst,
+		Remaining:  remaining,
+		RetryAfter: retryAfter,
+	}, ok
+}
+
+// quotaBucket is a token bucket. golang.org/x/time/rate is not used, since it does not expose the
+// number of remaining tokens returned to clients.
+type quotaBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills the bucket for the time elapsed since the last request, and consumes a token if
+// there is one. It returns the number of remaining whole tokens, and if there is no token, the
+// time until the next one is available and false.
+func (b *quotaBucket) take(limit QuotaLimit, now time.Time) (int, time.Duration, bool) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed.Seconds()*limit.Rate)
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		wait := time.Duration(math.Ceil((1 - b.tokens) / limit.Rate * float64(time.Second)))
+		return 0, wait, false
+	}
+
+	b.tokens--
+	return int(b.tokens), 0, true
+}
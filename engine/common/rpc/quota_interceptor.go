@@ -0,0 +1,76 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor enforces the per-client quotas for each gRPC request. Clients provide
+// their API key in the x-api-key metadata, and the quota status is returned in the response
+// headers.
+func (q *Quotas) UnaryServerInterceptor(ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (resp interface{}, err error) {
+
+	// remove the package name (e.g. "/flow.access.AccessAPI/Ping" to "Ping")
+	methodName := filepath.Base(info.FullMethod)
+
+	var apiKey string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(APIKeyHeader); len(values) > 0 {
+			apiKey = values[0]
+		}
+	}
+
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok {
+		ip = RemoteIP(p.Addr.String())
+	}
+
+	quotaStatus, err := q.Check(apiKey, ip, methodName)
+	if quotaStatus.Limited {
+		// failing to set the headers only means the client does not see them
+		_ = grpc.SetHeader(ctx, metadata.New(QuotaHeaders(quotaStatus)))
+	}
+	if err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			return nil, status.Errorf(codes.ResourceExhausted, "%s quota exceeded, please retry later", info.FullMethod)
+		}
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return handler(ctx, req)
+}
+
+// QuotaHeaders returns the headers informing the client about the state of its quota.
+func QuotaHeaders(quotaStatus QuotaStatus) map[string]string {
+	headers := map[string]string{
+		RateLimitLimitHeader:     strconv.Itoa(quotaStatus.Burst),
+		RateLimitRemainingHeader: strconv.Itoa(quotaStatus.Remaining),
+	}
+	if quotaStatus.RetryAfter > 0 {
+		headers[RetryAfterHeader] = strconv.Itoa(int(math.Ceil(float64(quotaStatus.RetryAfter) / float64(time.Second))))
+	}
+	return headers
+}
+
+// RemoteIP returns the IP of the given remote address, or the address itself if it has no port.
+func RemoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+func testQuotaConfig() QuotaConfig {
+	return QuotaConfig{
+		Anonymous: QuotaLimits{
+			Default: &QuotaLimit{Rate: 1, Burst: 2},
+		},
+		APIKeys: []APIKeyConfig{{
+			Name: "wallet",
+			Key:  "secret",
+			QuotaLimits: QuotaLimits{
+				Methods: map[string]QuotaLimit{"ExecuteScriptAtLatestBlock": {Rate: 1, Burst: 1}},
+			},
+		}},
+	}
+}
+
+func TestQuotas_Check(t *testing.T) {
+	quotas, err := NewQuotas(zerolog.Nop(), metrics.NewNoopCollector(), testQuotaConfig())
+	require.NoError(t, err)
+
+	t.Run("anonymous clients are limited per IP", func(t *testing.T) {
+		for i := 1; i >= 0; i-- {
+			quotaStatus, err := quotas.Check("", "10.0.0.1", "Ping")
+			require.NoError(t, err)
+			assert.Equal(t, AnonymousClient, quotaStatus.Client)
+			assert.True(t, quotaStatus.Limited)
+			assert.Equal(t, 2, quotaStatus.Burst)
+			assert.Equal(t, i, quotaStatus.Remaining)
+		}
+
+		quotaStatus, err := quotas.Check("", "10.0.0.1", "Ping")
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+		assert.Equal(t, 0, quotaStatus.Remaining)
+		assert.Greater(t, quotaStatus.RetryAfter, time.Duration(0))
+
+		// other IPs and methods have their own quota
+		_, err = quotas.Check("", "10.0.0.2", "Ping")
+		require.NoError(t, err)
+		_, err = quotas.Check("", "10.0.0.1", "GetLatestBlock")
+		require.NoError(t, err)
+	})
+
+	t.Run("API key clients are limited per key", func(t *testing.T) {
+		quotaStatus, err := quotas.Check("secret", "10.0.0.3", "ExecuteScriptAtLatestBlock")
+		require.NoError(t, err)
+		assert.Equal(t, "wallet", quotaStatus.Client)
+
+		// the quota is shared by all IPs using the key
+		_, err = quotas.Check("secret", "10.0.0.4", "ExecuteScriptAtLatestBlock")
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+
+		// methods without quota are not limited
+		for i := 0; i < 10; i++ {
+			quotaStatus, err = quotas.Check("secret", "10.0.0.3", "Ping")
+			require.NoError(t, err)
+			assert.False(t, quotaStatus.Limited)
+		}
+	})
+
+	t.Run("unknown API key", func(t *testing.T) {
+		_, err := quotas.Check("unknown", "10.0.0.5", "Ping")
+		require.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("update resets quotas", func(t *testing.T) {
+		config := testQuotaConfig()
+		config.RequireAPIKey = true
+		require.NoError(t, quotas.Update(config))
+
+		_, err := quotas.Check("", "10.0.0.6", "Ping")
+		require.ErrorIs(t, err, ErrAPIKeyRequired)
+
+		_, err = quotas.Check("secret", "10.0.0.3", "ExecuteScriptAtLatestBlock")
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid update is rejected", func(t *testing.T) {
+		config := testQuotaConfig()
+		config.APIKeys = append(config.APIKeys, APIKeyConfig{Name: "other", Key: "secret"})
+		require.Error(t, quotas.Update(config))
+
+		// the previous configuration is still used
+		_, err := quotas.Check("", "10.0.0.6", "Ping")
+		require.ErrorIs(t, err, ErrAPIKeyRequired)
+	})
+}
+
+func TestQuotaBucket(t *testing.T) {
+	limit := QuotaLimit{Rate: 2, Burst: 2}
+	now := time.Now()
+	bucket := &quotaBucket{tokens: float64(limit.Burst), last: now}
+
+	remaining, _, ok := bucket.take(limit, now)
+	require.True(t, ok)
+	assert.Equal(t, 1, remaining)
+
+	remaining, _, ok = bucket.take(limit, now)
+	require.True(t, ok)
+	assert.Equal(t, 0, remaining)
+
+	_, retryAfter, ok := bucket.take(limit, now)
+	require.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// tokens are refilled at the rate, up to the burst
+	_, _, ok = bucket.take(limit, now.Add(500*time.Millisecond))
+	require.True(t, ok)
+
+	remaining, _, ok = bucket.take(limit, now.Add(time.Hour))
+	require.True(t, ok)
+	assert.Equal(t, 1, remaining)
+}
+
+func TestQuotas_UnaryServerInterceptor(t *testing.T) {
+	quotas, err := NewQuotas(zerolog.Nop(), metrics.NewNoopCollector(), testQuotaConfig())
+	require.NoError(t, err)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/flow.access.AccessAPI/ExecuteScriptAtLatestBlock"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}})
+	keyCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(APIKeyHeader, "secret"))
+
+	resp, err := quotas.UnaryServerInterceptor(keyCtx, nil, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	_, err = quotas.UnaryServerInterceptor(keyCtx, nil, info, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	invalidKeyCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(APIKeyHeader, "unknown"))
+	_, err = quotas.UnaryServerInterceptor(invalidKeyCtx, nil, info, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// anonymous requests from the same IP have their own quota
+	resp, err = quotas.UnaryServerInterceptor(ctx, nil, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+}
@@ -129,6 +129,24 @@ type blockComputer struct {
 	executionDataProvider *provider.Provider
 	signer                module.Local
 	spockHasher           hash.Hasher
+
+	// parallelExecutionWorkers is the number of workers executing the
+	// transactions of a collection speculatively in parallel.  Transactions
+	// are executed sequentially when it is smaller than 2.
+	parallelExecutionWorkers int
+}
+
+// BlockComputerOption configures optional features of the block computer.
+type BlockComputerOption func(*blockComputer)
+
+// WithParallelExecution enables the optimistic parallel execution of the
+// transactions of each collection with the given number of workers, see
+// executeTransactionsInParallel.  The virtual machine of the block computer
+// must be a fvm.SpeculativeVM.
+func WithParallelExecution(workers int) BlockComputerOption {
+	return func(computer *blockComputer) {
+		computer.parallelExecutionWorkers = workers
+	}
 }
 
 func SystemChunkContext(vmCtx fvm.Context, logger zerolog.Logger) fvm.Context {
@@ -145,7 +163,9 @@ func SystemChunkContext(vmCtx fvm.Context, logger zerolog.Logger) fvm.Context {
 	)
 }
 
-// NewBlockComputer creates a new block executor.
+// NewBlockComputer creates a new block executor.  The execution data
+// provider may be nil when the execution data of the blocks is not needed,
+// e.g. when blocks are re-executed offline.
 func NewBlockComputer(
 	vm fvm.VM,
 	vmCtx fvm.Context,
@@ -155,13 +175,14 @@ func NewBlockComputer(
 	committer ViewCommitter,
 	signer module.Local,
 	executionDataProvider *provider.Provider,
+	options ...BlockComputerOption,
 ) (BlockComputer, error) {
 	systemChunkCtx := SystemChunkContext(vmCtx, logger)
 	vmCtx = fvm.NewContextFromParent(
 		vmCtx,
 		fvm.WithMetricsReporter(metrics),
 		fvm.WithTracer(tracer))
-	return &blockComputer{
+	computer := &blockComputer{
 		vm:                    vm,
 		vmCtx:                 vmCtx,
 		metrics:               metrics,
@@ -172,7 +193,20 @@ func NewBlockComputer(
 		executionDataProvider: executionDataProvider,
 		signer:                signer,
 		spockHasher:           utils.NewSPOCKHasher(),
-	}, nil
+	}
+
+	for _, option := range options {
+		option(computer)
+	}
+
+	if computer.parallelExecutionWorkers > 1 {
+		_, ok := vm.(fvm.SpeculativeVM)
+		if !ok {
+			return nil, fmt.Errorf("parallel execution requires a speculative vm, got %T", vm)
+		}
+	}
+
+	return computer, nil
 }
 
 // ExecuteBlock executes a block and returns the resulting chunks.
@@ -356,6 +390,10 @@ func (e *blockComputer) executeBlock(
 
 	e.metrics.ExecutionBlockCachedPrograms(derivedBlockData.CachedPrograms())
 
+	if e.executionDataProvider == nil {
+		return res, nil
+	}
+
 	executionDataID, err := e.executionDataProvider.Provide(
 		ctx,
 		block.Height(),
@@ -422,10 +460,22 @@ func (e *blockComputer) executeCollection(
 		Logger()
 	logger.Debug().Msg("executing collection")
 
-	for _, txn := range txns {
-		err := e.executeTransaction(blockSpan, txn, collectionView, collector)
+	if e.parallelExecutionWorkers > 1 && len(txns) > 1 {
+		txnIndex, err := e.executeTransactionsInParallel(
+			blockSpan,
+			startTxIndex,
+			txns,
+			collectionView,
+			collector)
 		if err != nil {
-			return txn.txnIndex, err
+			return txnIndex, err
+		}
+	} else {
+		for _, txn := range txns {
+			_, err := e.executeTransaction(blockSpan, txn, collectionView, collector)
+			if err != nil {
+				return txn.txnIndex, err
+			}
 		}
 	}
 
@@ -446,7 +496,52 @@ func (e *blockComputer) executeTransaction(
 	txn transaction,
 	collectionView state.View,
 	collector *resultCollector,
-) error {
+) (
+	state.View,
+	error,
+) {
+	executed := e.startTransaction(parentSpan, txn)
+	defer executed.span.End()
+
+	executed.logger.Info().Msg("executing transaction in fvm")
+
+	executed.proc = fvm.NewTransaction(txn.txnId, txn.txnIndex, txn.TransactionBody)
+	executed.view = collectionView.NewChild()
+	err := e.vm.Run(executed.ctx, executed.proc, executed.view)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction %v for block %s at height %v: %w",
+			txn.txnIdStr,
+			txn.blockIdStr,
+			txn.ctx.BlockHeader.Height,
+			err)
+	}
+
+	err = e.commitTransaction(executed, collectionView, collector)
+	if err != nil {
+		return nil, err
+	}
+
+	return executed.view, nil
+}
+
+// executedTransaction is a transaction executed on a child view of its
+// collection view.
+type executedTransaction struct {
+	transaction
+
+	proc *fvm.TransactionProcedure
+	view state.View
+
+	span           otelTrace.Span
+	logger         zerolog.Logger
+	startedAt      time.Time
+	memAllocBefore uint64
+}
+
+func (e *blockComputer) startTransaction(
+	parentSpan otelTrace.Span,
+	txn transaction,
+) *executedTransaction {
 	startedAt := time.Now()
 	memAllocBefore := debug.GetHeapAllocsBytes()
 
@@ -459,7 +554,6 @@ func (e *blockComputer) executeTransaction(
 		attribute.Int64("tx_index", int64(txn.txnIndex)),
 		attribute.Int("col_index", txn.collectionIndex),
 	)
-	defer txSpan.End()
 
 	logger := e.log.With().
 		Str("tx_id", txn.txnIdStr).
@@ -469,45 +563,50 @@ func (e *blockComputer) executeTransaction(
 		Bool("system_chunk", txn.isSystemTransaction).
 		Bool("system_transaction", txn.isSystemTransaction).
 		Logger()
-	logger.Info().Msg("executing transaction in fvm")
-
-	proc := fvm.NewTransaction(txn.txnId, txn.txnIndex, txn.TransactionBody)
 
 	txn.ctx = fvm.NewContextFromParent(txn.ctx, fvm.WithSpan(txSpan))
 
-	txView := collectionView.NewChild()
-	err := e.vm.Run(txn.ctx, proc, txView)
-	if err != nil {
-		return fmt.Errorf("failed to execute transaction %v for block %s at height %v: %w",
-			txn.txnIdStr,
-			txn.blockIdStr,
-			txn.ctx.BlockHeader.Height,
-			err)
+	return &executedTransaction{
+		transaction:    txn,
+		span:           txSpan,
+		logger:         logger,
+		startedAt:      startedAt,
+		memAllocBefore: memAllocBefore,
 	}
+}
 
-	postProcessSpan := e.tracer.StartSpanFromParent(txSpan, trace.EXEPostProcessTransaction)
+// commitTransaction merges the view of an executed transaction into its
+// collection view, and collects its result.
+func (e *blockComputer) commitTransaction(
+	executed *executedTransaction,
+	collectionView state.View,
+	collector *resultCollector,
+) error {
+	proc := executed.proc
+
+	postProcessSpan := e.tracer.StartSpanFromParent(executed.span, trace.EXEPostProcessTransaction)
 	defer postProcessSpan.End()
 
 	// always merge the view, fvm take cares of reverting changes
 	// of failed transaction invocation
 
-	err = e.mergeView(collectionView, txView, postProcessSpan, trace.EXEMergeTransactionView)
+	err := e.mergeView(collectionView, executed.view, postProcessSpan, trace.EXEMergeTransactionView)
 	if err != nil {
 		return fmt.Errorf(
 			"merging tx view to collection view failed for tx %v: %w",
-			txn.txnIdStr,
+			executed.txnIdStr,
 			err)
 	}
 
-	collector.AddTransactionResult(txn.collectionIndex, proc)
+	collector.AddTransactionResult(executed.collectionIndex, proc)
 
 	memAllocAfter := debug.GetHeapAllocsBytes()
 
-	logger = logger.With().
+	logger := executed.logger.With().
 		Uint64("computation_used", proc.ComputationUsed).
 		Uint64("memory_used", proc.MemoryEstimate).
-		Uint64("mem_alloc", memAllocAfter-memAllocBefore).
-		Int64("time_spent_in_ms", time.Since(startedAt).Milliseconds()).
+		Uint64("mem_alloc", memAllocAfter-executed.memAllocBefore).
+		Int64("time_spent_in_ms", time.Since(executed.startedAt).Milliseconds()).
 		Logger()
 
 	if proc.Err != nil {
@@ -517,7 +616,7 @@ func (e *blockComputer) executeTransaction(
 			Logger()
 		logger.Info().Msg("transaction execution failed")
 
-		if txn.isSystemTransaction {
+		if executed.isSystemTransaction {
 			// This log is used as the data source for an alert on grafana.
 			// The system_chunk_error field must not be changed without adding
 			// the corresponding changes in grafana.
@@ -533,10 +632,10 @@ func (e *blockComputer) executeTransaction(
 	}
 
 	e.metrics.ExecutionTransactionExecuted(
-		time.Since(startedAt),
+		time.Since(executed.startedAt),
 		proc.ComputationUsed,
 		proc.MemoryEstimate,
-		memAllocAfter-memAllocBefore,
+		memAllocAfter-executed.memAllocBefore,
 		len(proc.Events),
 		flow.EventsList(proc.Events).ByteSize(),
 		proc.Err != nil,
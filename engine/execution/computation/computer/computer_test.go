@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strings"
 	"testing"
 
 	"github.com/onflow/cadence"
@@ -1103,6 +1104,303 @@ func Test_ExecutingSystemCollection(t *testing.T) {
 	committer.AssertExpectations(t)
 }
 
+func TestBlockExecutor_ExecuteBlockInParallel(t *testing.T) {
+
+	chain := flow.Localnet.Chain()
+	serviceAddress := chain.ServiceAddress()
+
+	execCtx := fvm.NewContext(
+		fvm.WithChain(chain),
+		fvm.WithBlocks(&environment.NoopBlockFinder{}),
+		fvm.WithAuthorizationChecksEnabled(false),
+		fvm.WithSequenceNumberCheckAndIncrementEnabled(false),
+	)
+
+	vm := fvm.NewVirtualMachine()
+
+	ledger := testutil.RootBootstrappedLedger(vm, execCtx)
+
+	me := new(modulemock.Local)
+	me.On("SignFunc", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, nil)
+
+	// all the transactions read the storage of the service account, so the
+	// transactions writing to storage use other accounts.
+	storageAddress := fvm.FungibleTokenAddress(chain)
+	otherAddress := fvm.FlowTokenAddress(chain)
+	transaction := func(script string, payer flow.Address) *flow.TransactionBody {
+		return flow.NewTransactionBody().
+			SetScript([]byte(script)).
+			SetPayer(payer).
+			SetProposalKey(payer, 0, 0)
+	}
+	noop := func(i int) *flow.TransactionBody {
+		return transaction(fmt.Sprintf(`
+			transaction {
+				execute { log(%d) }
+			}`, i), otherAddress)
+	}
+	save := func(path string, value int) *flow.TransactionBody {
+		return transaction(fmt.Sprintf(`
+			transaction {
+				prepare(signer: AuthAccount) {
+					signer.load<Int>(from: /storage/%s)
+					signer.save(%d, to: /storage/%s)
+				}
+			}`, path, value, path), storageAddress).
+			AddAuthorizer(storageAddress)
+	}
+	useContract := func(i int) *flow.TransactionBody {
+		return transaction(fmt.Sprintf(`
+			import Counter from 0x%s
+
+			transaction {
+				execute { log(Counter.get() + %d) }
+			}`, serviceAddress.Hex(), i), otherAddress)
+	}
+	deployContract := testutil.CreateContractDeploymentTransaction(
+		"Counter",
+		`pub contract Counter { pub fun get(): Int { return 42 } }`,
+		serviceAddress,
+		chain).
+		SetPayer(serviceAddress).
+		SetProposalKey(serviceAddress, 0, 0)
+
+	// the transactions conflict on the storage of the service account, and
+	// on the contract deployed in the first collection.
+	collections := [][]*flow.TransactionBody{
+		{
+			save("a", 1),
+			noop(1),
+			save("b", 2),
+			noop(2),
+			deployContract,
+			useContract(1),
+			noop(3),
+		},
+		{
+			useContract(2),
+			noop(4),
+			save("a", 3),
+			useContract(3),
+			noop(5),
+		},
+	}
+
+	sequential, parallel := executeBlockSequentiallyAndInParallel(t, vm, execCtx, ledger, collections)
+
+	require.Len(t, sequential.TransactionResults, 13) // +1 system chunk tx
+	for _, result := range sequential.TransactionResults {
+		require.Empty(t, result.ErrorMessage)
+	}
+
+	assertSameResults(t, sequential, parallel)
+
+	t.Run("requires speculative vm", func(t *testing.T) {
+		_, err := computer.NewBlockComputer(
+			new(computermock.VirtualMachine),
+			execCtx,
+			metrics.NewNoopCollector(),
+			trace.NewNoopTracer(),
+			zerolog.Nop(),
+			committer.NewNoopViewCommitter(),
+			me,
+			nil,
+			computer.WithParallelExecution(4))
+		assert.Error(t, err)
+	})
+}
+
+// TestBlockExecutor_ExecuteBlockInParallelWithFees checks the parallel
+// execution with transaction fees enabled.  The fees are deducted when the
+// speculatively executed transactions are committed, on the balances left by
+// the transactions before them, so only the transactions paid by the same
+// payer as a previous transaction conflict.  The results are still identical
+// to the sequential execution.
+func TestBlockExecutor_ExecuteBlockInParallelWithFees(t *testing.T) {
+
+	chain := flow.Localnet.Chain()
+	serviceAddress := chain.ServiceAddress()
+
+	execCtx := fvm.NewContext(
+		fvm.WithChain(chain),
+		fvm.WithBlocks(&environment.NoopBlockFinder{}),
+		fvm.WithAuthorizationChecksEnabled(false),
+		fvm.WithSequenceNumberCheckAndIncrementEnabled(false),
+		fvm.WithTransactionFeesEnabled(true),
+	)
+
+	vm := fvm.NewVirtualMachine()
+
+	ledger := testutil.RootBootstrappedLedger(
+		vm,
+		execCtx,
+		fvm.WithTransactionFee(fvm.DefaultTransactionFees))
+
+	privateKeys, err := testutil.GenerateAccountPrivateKeys(3)
+	require.NoError(t, err)
+	payers, err := testutil.CreateAccounts(
+		vm,
+		ledger,
+		derived.NewEmptyDerivedBlockData(),
+		privateKeys,
+		chain)
+	require.NoError(t, err)
+	payers = append(payers, serviceAddress)
+
+	// fund the payers other than the service account
+	for _, payer := range payers[:3] {
+		fund := fvm.Transaction(
+			testutil.CreateTokenTransferTransaction(chain, 1_000_000, payer, serviceAddress).
+				SetPayer(serviceAddress).
+				SetProposalKey(serviceAddress, 0, 0),
+			0)
+		err = vm.Run(execCtx, fund, ledger)
+		require.NoError(t, err)
+		require.NoError(t, fund.Err)
+	}
+
+	transaction := func(i int, payer flow.Address) *flow.TransactionBody {
+		return flow.NewTransactionBody().
+			SetScript([]byte(fmt.Sprintf(`
+				transaction {
+					prepare(signer: AuthAccount) {
+						signer.load<Int>(from: /storage/fees%d)
+						signer.save(%d, to: /storage/fees%d)
+					}
+				}`, i, i, i))).
+			SetPayer(payer).
+			SetProposalKey(payer, 0, 0).
+			AddAuthorizer(payer)
+	}
+
+	collections := [][]*flow.TransactionBody{
+		{
+			transaction(1, payers[0]),
+			transaction(2, payers[1]),
+			transaction(3, payers[2]),
+			transaction(4, payers[0]),
+		},
+		{
+			transaction(5, payers[3]),
+			transaction(6, payers[3]),
+			transaction(7, payers[1]),
+		},
+	}
+
+	sequential, parallel := executeBlockSequentiallyAndInParallel(t, vm, execCtx, ledger, collections)
+
+	require.Len(t, sequential.TransactionResults, 8) // +1 system chunk tx
+	for _, result := range sequential.TransactionResults {
+		require.Empty(t, result.ErrorMessage)
+	}
+
+	// the fees were deducted
+	feesDeducted := 0
+	for _, event := range sequential.Events[0] {
+		if strings.HasSuffix(string(event.Type), ".FlowFees.FeesDeducted") {
+			feesDeducted++
+		}
+	}
+	require.Equal(t, 4, feesDeducted)
+
+	assertSameResults(t, sequential, parallel)
+}
+
+// executeBlockSequentiallyAndInParallel executes a block of the given
+// collections on the ledger, both sequentially and in parallel.
+func executeBlockSequentiallyAndInParallel(
+	t *testing.T,
+	vm fvm.VM,
+	execCtx fvm.Context,
+	ledger state.View,
+	collections [][]*flow.TransactionBody,
+) (
+	*execution.ComputationResult,
+	*execution.ComputationResult,
+) {
+	me := new(modulemock.Local)
+	me.On("SignFunc", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, nil)
+
+	completeCollections := make(map[flow.Identifier]*entity.CompleteCollection)
+	guarantees := make([]*flow.CollectionGuarantee, 0, len(collections))
+	for _, transactions := range collections {
+		collection := flow.Collection{Transactions: transactions}
+		guarantee := &flow.CollectionGuarantee{CollectionID: collection.ID()}
+		guarantees = append(guarantees, guarantee)
+		completeCollections[guarantee.ID()] = &entity.CompleteCollection{
+			Guarantee:    guarantee,
+			Transactions: transactions,
+		}
+	}
+
+	block := &entity.ExecutableBlock{
+		Block: &flow.Block{
+			Header: &flow.Header{
+				Timestamp: flow.GenesisTime,
+				Height:    42,
+				View:      42,
+			},
+			Payload: &flow.Payload{
+				Guarantees: guarantees,
+			},
+		},
+		CompleteCollections: completeCollections,
+		StartState:          unittest.StateCommitmentPointerFixture(),
+	}
+
+	execute := func(options ...computer.BlockComputerOption) *execution.ComputationResult {
+		bservice := requesterunit.MockBlobService(blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore())))
+		trackerStorage := mocktracker.NewMockStorage()
+
+		prov := provider.NewProvider(
+			zerolog.Nop(),
+			metrics.NewNoopCollector(),
+			execution_data.DefaultSerializer,
+			bservice,
+			trackerStorage,
+		)
+
+		exe, err := computer.NewBlockComputer(
+			vm,
+			execCtx,
+			metrics.NewNoopCollector(),
+			trace.NewNoopTracer(),
+			zerolog.Nop(),
+			committer.NewNoopViewCommitter(),
+			me,
+			prov,
+			options...)
+		require.NoError(t, err)
+
+		view := delta.NewDeltaView(ledger.Get)
+
+		result, err := exe.ExecuteBlock(context.Background(), block, view, derived.NewEmptyDerivedBlockData())
+		require.NoError(t, err)
+
+		return result
+	}
+
+	return execute(), execute(computer.WithParallelExecution(4))
+}
+
+// assertSameResults asserts that the results of the parallel execution of a
+// block are identical to the results of its sequential execution.
+func assertSameResults(t *testing.T, sequential *execution.ComputationResult, parallel *execution.ComputationResult) {
+	assert.Equal(t, sequential.TransactionResults, parallel.TransactionResults)
+	assert.Equal(t, sequential.Events, parallel.Events)
+	assert.Equal(t, sequential.EventsHashes, parallel.EventsHashes)
+	assert.Equal(t, sequential.ServiceEvents, parallel.ServiceEvents)
+	assert.Equal(t, sequential.ComputationIntensities, parallel.ComputationIntensities)
+
+	// the collection deltas, register touches and SPoCK secrets are identical
+	require.Len(t, parallel.StateSnapshots, len(sequential.StateSnapshots))
+	for i, snapshot := range sequential.StateSnapshots {
+		assert.Equal(t, snapshot, parallel.StateSnapshots[i])
+	}
+}
+
 func generateBlock(collectionCount, transactionCount int, addressGenerator flow.AddressGenerator) *entity.ExecutableBlock {
 	return generateBlockWithVisitor(collectionCount, transactionCount, addressGenerator, nil)
 }
@@ -0,0 +1,274 @@
+package computer
+
+import (
+	"fmt"
+	"sync"
+
+	otelTrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// speculativeTransaction is a transaction executed speculatively on the
+// snapshot of its collection view taken before the execution of the
+// collection.
+type speculativeTransaction struct {
+	*executedTransaction
+
+	txn *fvm.SpeculativeTransaction
+	err error
+}
+
+// executeTransactionsInParallel executes the transactions of a collection
+// with optimistic concurrency control.  The results are identical to the
+// results of the sequential execution of the transactions:
+//
+//  1. All the transactions are executed speculatively in parallel, each on a
+//     child view of the collection view taken before the execution of the
+//     collection.  The views record the registers touched (i.e., read or
+//     written) by the transactions.
+//  2. The transactions are validated and committed in order.  A transaction
+//     is valid if it touched none of the registers written by the
+//     transactions committed before it in the collection, and if the derived
+//     data it used was not invalidated by them.  Its speculative execution
+//     then observed the same register values as its sequential execution
+//     would have, hence produced the same results.  Its fees are then
+//     deducted on its view, which reads the fee registers from the collection
+//     view as committed by the transactions before it, and its view is merged
+//     into the collection view.  An invalid transaction is re-executed on the
+//     collection view instead.
+//
+// Since the fees are only deducted when the transactions are committed, the
+// fee vault written by every fee deduction does not make the transactions
+// conflict.  Transactions paid by the same payer still conflict, since the
+// payer balance check of a transaction reads the balance written by the fee
+// deduction of the previous one.
+//
+// It returns the index of the transaction which failed to execute, if any.
+func (e *blockComputer) executeTransactionsInParallel(
+	parentSpan otelTrace.Span,
+	startTxIndex uint32,
+	txns []transaction,
+	collectionView state.View,
+	collector *resultCollector,
+) (
+	uint32,
+	error,
+) {
+	speculations := e.executeTransactionsSpeculatively(
+		parentSpan,
+		startTxIndex,
+		txns,
+		collectionView)
+
+	// the speculative transactions after a failed transaction are discarded
+	validated := 0
+	defer func() {
+		for _, speculation := range speculations[validated:] {
+			if speculation.txn != nil {
+				speculation.txn.Discard()
+			}
+		}
+	}()
+
+	// registers written by the transactions committed in the collection
+	written := make(map[flow.RegisterID]struct{})
+
+	reexecuted := 0
+	for i, txn := range txns {
+		txView, err := e.commitSpeculativeTransaction(
+			speculations[i],
+			written,
+			collectionView,
+			collector)
+		validated++
+		if err != nil {
+			return txn.txnIndex, err
+		}
+
+		if txView == nil {
+			reexecuted++
+			txView, err = e.executeTransaction(
+				parentSpan,
+				txn,
+				collectionView,
+				collector)
+			if err != nil {
+				return txn.txnIndex, err
+			}
+		}
+
+		for _, id := range txView.UpdatedRegisterIDs() {
+			written[id] = struct{}{}
+		}
+	}
+
+	e.log.Debug().
+		Str("block_id", txns[0].blockIdStr).
+		Int("collection_index", txns[0].collectionIndex).
+		Int("number_of_transactions", len(txns)).
+		Int("number_of_reexecuted_transactions", reexecuted).
+		Msg("transactions executed in parallel")
+
+	return startTxIndex + uint32(len(txns)), nil
+}
+
+// executeTransactionsSpeculatively executes the transactions of a collection
+// speculatively in parallel, on the current collection view.  The collection
+// view must not be modified until all the transactions are executed.
+func (e *blockComputer) executeTransactionsSpeculatively(
+	parentSpan otelTrace.Span,
+	startTxIndex uint32,
+	txns []transaction,
+	collectionView state.View,
+) []*speculativeTransaction {
+	// checked by NewBlockComputer
+	vm := e.vm.(fvm.SpeculativeVM)
+
+	speculations := make([]*speculativeTransaction, len(txns))
+
+	indices := make(chan int, len(txns))
+	for i := range txns {
+		indices <- i
+	}
+	close(indices)
+
+	workers := e.parallelExecutionWorkers
+	if workers > len(txns) {
+		workers = len(txns)
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				speculations[i] = e.executeTransactionSpeculatively(
+					vm,
+					parentSpan,
+					startTxIndex,
+					txns[i],
+					collectionView)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return speculations
+}
+
+func (e *blockComputer) executeTransactionSpeculatively(
+	vm fvm.SpeculativeVM,
+	parentSpan otelTrace.Span,
+	snapshotTxIndex uint32,
+	txn transaction,
+	collectionView state.View,
+) (
+	speculation *speculativeTransaction,
+) {
+	executed := e.startTransaction(parentSpan, txn)
+	defer executed.span.End()
+
+	speculation = &speculativeTransaction{
+		executedTransaction: executed,
+	}
+
+	// A transaction panicking on its snapshot is re-executed sequentially,
+	// which reproduces the panic if it was not caused by the snapshot.
+	defer func() {
+		if r := recover(); r != nil {
+			speculation.err = fmt.Errorf(
+				"speculative execution panicked: %v",
+				r)
+		}
+	}()
+
+	executed.logger.Debug().Msg("executing transaction speculatively in fvm")
+
+	executed.proc = fvm.NewSpeculativeTransaction(
+		txn.txnId,
+		snapshotTxIndex,
+		txn.txnIndex,
+		txn.TransactionBody)
+	executed.view = collectionView.NewChild()
+	speculation.txn, speculation.err = vm.RunSpeculatively(
+		executed.ctx,
+		executed.proc,
+		executed.view)
+
+	return speculation
+}
+
+// commitSpeculativeTransaction validates a speculatively executed
+// transaction against the registers written by the transactions committed
+// before it in the collection, and finishes and commits it if it is valid.
+// It returns the view of the committed transaction, or nil if the transaction
+// is invalid and must be re-executed.
+func (e *blockComputer) commitSpeculativeTransaction(
+	speculation *speculativeTransaction,
+	written map[flow.RegisterID]struct{},
+	collectionView state.View,
+	collector *resultCollector,
+) (
+	state.View,
+	error,
+) {
+	logger := speculation.logger
+
+	if speculation.err != nil {
+		logger.Debug().
+			Err(speculation.err).
+			Msg("speculative execution failed, re-executing transaction")
+		return nil, nil
+	}
+
+	for _, id := range speculation.txn.AllRegisterIDs() {
+		if _, ok := written[id]; ok {
+			logger.Debug().
+				Str("register_id", id.String()).
+				Msg("speculative execution conflicted, re-executing transaction")
+			speculation.txn.Discard()
+			return nil, nil
+		}
+	}
+
+	derivedTxnData := speculation.txn.DerivedTransactionData()
+	err := derivedTxnData.Validate()
+	if err != nil {
+		logger.Debug().
+			Err(err).
+			Msg("speculative execution used invalidated derived data, re-executing transaction")
+		speculation.txn.Discard()
+		return nil, nil
+	}
+
+	err = speculation.txn.Finish()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to deduct fees of tx %v: %w",
+			speculation.txnIdStr,
+			err)
+	}
+
+	err = derivedTxnData.Commit()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to commit derived data of tx %v: %w",
+			speculation.txnIdStr,
+			err)
+	}
+
+	err = e.commitTransaction(
+		speculation.executedTransaction,
+		collectionView,
+		collector)
+	if err != nil {
+		return nil, err
+	}
+
+	return speculation.view, nil
+}
@@ -15,8 +15,10 @@ import (
 
 	"github.com/onflow/flow-go/engine/execution"
 	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/fvm/derived"
+	"github.com/onflow/flow-go/fvm/meter"
 	reusableRuntime "github.com/onflow/flow-go/fvm/runtime"
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/model/flow"
@@ -39,6 +41,7 @@ const (
 
 type ComputationManager interface {
 	ExecuteScript(context.Context, []byte, [][]byte, *flow.Header, state.View) ([]byte, error)
+	ExecuteScriptWithTrace(context.Context, []byte, [][]byte, *flow.Header, state.View) ([]byte, *ScriptTrace, error)
 	ComputeBlock(
 		ctx context.Context,
 		block *entity.ExecutableBlock,
@@ -47,6 +50,23 @@ type ComputationManager interface {
 	GetAccount(addr flow.Address, header *flow.Header, view state.View) (*flow.Account, error)
 }
 
+// ScriptTrace is the trace of the execution of a script.
+type ScriptTrace struct {
+	// RegisterReads are the registers read by the script from the execution
+	// state, in the order of their first read.
+	RegisterReads []RegisterRead
+
+	// ComputationIntensities are the computation intensities metered by the
+	// script, by computation kind.
+	ComputationIntensities meter.MeteredComputationIntensities
+}
+
+// RegisterRead is a register read by a script, with the size of its value.
+type RegisterRead struct {
+	ID   flow.RegisterID
+	Size int
+}
+
 type ComputationConfig struct {
 	CadenceTracing           bool
 	ExtensiveTracing         bool
@@ -54,6 +74,18 @@ type ComputationConfig struct {
 	ScriptLogThreshold       time.Duration
 	ScriptExecutionTimeLimit time.Duration
 
+	// ScriptQueue configures the admission of scripts for execution, which
+	// bounds the resources used by scripts next to block execution.
+	ScriptQueue ScriptQueueConfig
+
+	// ParallelExecutionWorkers is the number of workers executing the
+	// transactions of each collection optimistically in parallel.  The
+	// transactions are executed sequentially when it is smaller than 2.
+	// With transaction fees enabled, all the transactions conflict on the fee
+	// vault and are re-executed, so the parallel execution is slower than the
+	// sequential execution (see BenchmarkComputeBlock).
+	ParallelExecutionWorkers int
+
 	// When NewCustomVirtualMachine is nil, the manager will create a standard
 	// fvm virtual machine via fvm.NewVirtualMachine.  Otherwise, the manager
 	// will create a virtual machine using this function.
@@ -75,6 +107,8 @@ type Manager struct {
 	derivedChainData         *derived.DerivedChainData
 	scriptLogThreshold       time.Duration
 	scriptExecutionTimeLimit time.Duration
+	scriptMemoryLimit        uint64
+	scriptQueue              *scriptQueue
 	rngLock                  *sync.Mutex
 	rng                      *rand.Rand
 }
@@ -122,6 +156,7 @@ func New(
 		committer,
 		me,
 		executionDataProvider,
+		computer.WithParallelExecution(params.ParallelExecutionWorkers),
 	)
 
 	if err != nil {
@@ -145,6 +180,8 @@ func New(
 		derivedChainData:         derivedChainData,
 		scriptLogThreshold:       params.ScriptLogThreshold,
 		scriptExecutionTimeLimit: params.ScriptExecutionTimeLimit,
+		scriptMemoryLimit:        params.ScriptQueue.ScriptMemoryLimit,
+		scriptQueue:              newScriptQueue(params.ScriptQueue, metrics),
 		rngLock:                  &sync.Mutex{},
 		rng:                      rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
@@ -163,6 +200,73 @@ func (e *Manager) ExecuteScript(
 	blockHeader *flow.Header,
 	view state.View,
 ) ([]byte, error) {
+	encodedValue, _, err := e.executeScript(ctx, code, arguments, blockHeader, view)
+	return encodedValue, err
+}
+
+// ExecuteScriptWithTrace executes a script like ExecuteScript, and also
+// returns the trace of its execution.  The trace is also returned when the
+// script fails, e.g. because it exceeded the computation or interaction
+// limits, but not when the script could not be executed.
+func (e *Manager) ExecuteScriptWithTrace(
+	ctx context.Context,
+	code []byte,
+	arguments [][]byte,
+	blockHeader *flow.Header,
+	view state.View,
+) ([]byte, *ScriptTrace, error) {
+	trace := &ScriptTrace{}
+
+	// The registers written by the script are held by the tracing view, hence
+	// only the registers read from the execution state reach the read
+	// function.
+	read := make(map[flow.RegisterID]struct{})
+	tracingView := delta.NewDeltaView(
+		func(id flow.RegisterID) (flow.RegisterValue, error) {
+			value, err := view.Get(id)
+			if err != nil {
+				return nil, err
+			}
+
+			_, ok := read[id]
+			if !ok {
+				read[id] = struct{}{}
+				trace.RegisterReads = append(
+					trace.RegisterReads,
+					RegisterRead{ID: id, Size: len(value)})
+			}
+			return value, nil
+		})
+
+	encodedValue, script, err := e.executeScript(
+		ctx,
+		code,
+		arguments,
+		blockHeader,
+		tracingView)
+	if script == nil {
+		return nil, nil, err
+	}
+
+	trace.ComputationIntensities = script.ComputationIntensities
+	return encodedValue, trace, err
+}
+
+// executeScript executes a script and returns its encoded value.  The script
+// procedure is returned if the script was executed, even if it failed.
+func (e *Manager) executeScript(
+	ctx context.Context,
+	code []byte,
+	arguments [][]byte,
+	blockHeader *flow.Header,
+	view state.View,
+) ([]byte, *fvm.ScriptProcedure, error) {
+
+	done, err := e.scriptQueue.admit(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to admit script for execution: %w", err)
+	}
+	defer done()
 
 	startedAt := time.Now()
 	memAllocBefore := debug.GetHeapAllocsBytes()
@@ -186,13 +290,17 @@ func (e *Manager) ExecuteScript(
 	defer cancel()
 
 	script := fvm.NewScriptWithContextAndArgs(code, requestCtx, arguments...)
-	blockCtx := fvm.NewContextFromParent(
-		e.vmCtx,
+	options := []fvm.Option{
 		fvm.WithBlockHeader(blockHeader),
 		fvm.WithDerivedBlockData(
-			e.derivedChainData.NewDerivedBlockDataForScript(blockHeader.ID())))
+			e.derivedChainData.NewDerivedBlockDataForScript(blockHeader.ID())),
+	}
+	if e.scriptMemoryLimit > 0 {
+		options = append(options, fvm.WithMemoryLimit(e.scriptMemoryLimit))
+	}
+	blockCtx := fvm.NewContextFromParent(e.vmCtx, options...)
 
-	err := func() (err error) {
+	err = func() (err error) {
 
 		start := time.Now()
 
@@ -229,7 +337,7 @@ func (e *Manager) ExecuteScript(
 		return e.vm.Run(blockCtx, script, view)
 	}()
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute script (internal error): %w", err)
+		return nil, nil, fmt.Errorf("failed to execute script (internal error): %w", err)
 	}
 
 	if script.Err != nil {
@@ -243,18 +351,18 @@ func (e *Manager) ExecuteScript(
 			scriptErrMsg = sb.String()
 		}
 
-		return nil, fmt.Errorf("failed to execute script at block (%s): %s", blockHeader.ID(), scriptErrMsg)
+		return nil, script, fmt.Errorf("failed to execute script at block (%s): %s", blockHeader.ID(), scriptErrMsg)
 	}
 
 	encodedValue, err := jsoncdc.Encode(script.Value)
 	if err != nil {
-		return nil, fmt.Errorf("failed to encode runtime value: %w", err)
+		return nil, script, fmt.Errorf("failed to encode runtime value: %w", err)
 	}
 
 	memAllocAfter := debug.GetHeapAllocsBytes()
 	e.metrics.ExecutionScriptExecuted(time.Since(startedAt), script.GasUsed, memAllocAfter-memAllocBefore, script.MemoryEstimate)
 
-	return encodedValue, nil
+	return encodedValue, script, nil
 }
 
 func (e *Manager) ComputeBlock(
@@ -38,6 +38,7 @@ import (
 type testAccount struct {
 	address    flow.Address
 	privateKey flow.AccountPrivateKey
+	seq        uint64
 }
 
 type testAccounts struct {
@@ -143,29 +144,10 @@ func BenchmarkComputeBlock(b *testing.B) {
 		trackerStorage,
 	)
 
-	// TODO(rbtz): add real ledger
-	blockComputer, err := computer.NewBlockComputer(
-		vm,
-		execCtx,
-		metrics.NewNoopCollector(),
-		tracer,
-		zerolog.Nop(),
-		committer.NewNoopViewCommitter(),
-		me,
-		prov)
-	require.NoError(b, err)
-
 	derivedChainData, err := derived.NewDerivedChainData(
 		derived.DefaultDerivedDataCacheSize)
 	require.NoError(b, err)
 
-	engine := &Manager{
-		blockComputer:    blockComputer,
-		tracer:           tracer,
-		me:               me,
-		derivedChainData: derivedChainData,
-	}
-
 	view := delta.NewDeltaView(ledger.Get)
 	blockView := view.NewChild()
 
@@ -181,36 +163,73 @@ func BenchmarkComputeBlock(b *testing.B) {
 		txes = 128
 	)
 
-	b.Run(fmt.Sprintf("%d/cols/%d/txes", cols, txes), func(b *testing.B) {
-		b.StopTimer()
-		b.ResetTimer()
+	// The transactions are either all paid by the service account, which makes
+	// them conflict on its balance and sequence number, or each paid by its
+	// sender.  The fees are deducted when the transactions are committed, so
+	// the fee vault does not make them conflict, but the token transfers still
+	// conflict on the UUID counter written by every withdrawn vault.
+	for _, distinctPayers := range []bool{false, true} {
+		for _, workers := range []int{0, 4} {
+			// TODO(rbtz): add real ledger
+			blockComputer, err := computer.NewBlockComputer(
+				vm,
+				execCtx,
+				metrics.NewNoopCollector(),
+				tracer,
+				zerolog.Nop(),
+				committer.NewNoopViewCommitter(),
+				me,
+				prov,
+				computer.WithParallelExecution(workers))
+			require.NoError(b, err)
 
-		var elapsed time.Duration
-		for i := 0; i < b.N; i++ {
-			executableBlock := createBlock(b, parentBlock, accs, cols, txes)
-			parentBlock = executableBlock.Block
+			engine := &Manager{
+				blockComputer:    blockComputer,
+				tracer:           tracer,
+				me:               me,
+				derivedChainData: derivedChainData,
+			}
 
-			b.StartTimer()
-			start := time.Now()
-			res, err := engine.ComputeBlock(context.Background(), executableBlock, blockView)
-			elapsed += time.Since(start)
-			b.StopTimer()
+			name := fmt.Sprintf("%d/cols/%d/txes", cols, txes)
+			if distinctPayers {
+				name = fmt.Sprintf("%s/distinct_payers", name)
+			}
+			if workers > 1 {
+				name = fmt.Sprintf("%s/%d/workers", name, workers)
+			}
 
-			require.NoError(b, err)
-			for j, r := range res.TransactionResults {
-				// skip system transactions
-				if j >= cols*txes {
-					break
+			b.Run(name, func(b *testing.B) {
+				b.StopTimer()
+				b.ResetTimer()
+
+				var elapsed time.Duration
+				for i := 0; i < b.N; i++ {
+					executableBlock := createBlock(b, parentBlock, accs, cols, txes, distinctPayers)
+					parentBlock = executableBlock.Block
+
+					b.StartTimer()
+					start := time.Now()
+					res, err := engine.ComputeBlock(context.Background(), executableBlock, blockView)
+					elapsed += time.Since(start)
+					b.StopTimer()
+
+					require.NoError(b, err)
+					for j, r := range res.TransactionResults {
+						// skip system transactions
+						if j >= cols*txes {
+							break
+						}
+						require.Emptyf(b, r.ErrorMessage, "Transaction %d failed", j)
+					}
 				}
-				require.Emptyf(b, r.ErrorMessage, "Transaction %d failed", j)
-			}
+				totalTxes := int64(cols) * int64(txes) * int64(b.N)
+				b.ReportMetric(float64(elapsed.Nanoseconds()/totalTxes/int64(time.Microsecond)), "us/tx")
+			})
 		}
-		totalTxes := int64(cols) * int64(txes) * int64(b.N)
-		b.ReportMetric(float64(elapsed.Nanoseconds()/totalTxes/int64(time.Microsecond)), "us/tx")
-	})
+	}
 }
 
-func createBlock(b *testing.B, parentBlock *flow.Block, accs *testAccounts, colNum int, txNum int) *entity.ExecutableBlock {
+func createBlock(b *testing.B, parentBlock *flow.Block, accs *testAccounts, colNum int, txNum int, distinctPayers bool) *entity.ExecutableBlock {
 	completeCollections := make(map[flow.Identifier]*entity.CompleteCollection, colNum)
 	collections := make([]*flow.Collection, colNum)
 	guarantees := make([]*flow.CollectionGuarantee, colNum)
@@ -218,7 +237,7 @@ func createBlock(b *testing.B, parentBlock *flow.Block, accs *testAccounts, colN
 	for c := 0; c < colNum; c++ {
 		transactions := make([]*flow.TransactionBody, txNum)
 		for t := 0; t < txNum; t++ {
-			transactions[t] = createTokenTransferTransaction(b, accs)
+			transactions[t] = createTokenTransferTransaction(b, accs, distinctPayers)
 		}
 
 		collection := &flow.Collection{Transactions: transactions}
@@ -249,14 +268,28 @@ func createBlock(b *testing.B, parentBlock *flow.Block, accs *testAccounts, colN
 	}
 }
 
-func createTokenTransferTransaction(b *testing.B, accs *testAccounts) *flow.TransactionBody {
+// createTokenTransferTransaction creates a transfer between random accounts,
+// paid either by the service account or by the sender.
+func createTokenTransferTransaction(b *testing.B, accs *testAccounts, payBySender bool) *flow.TransactionBody {
 	var err error
 
 	rnd := rand.Intn(len(accs.accounts))
-	src := accs.accounts[rnd]
+	src := &accs.accounts[rnd]
 	dst := accs.accounts[(rnd+1)%len(accs.accounts)]
 
 	tx := testutil.CreateTokenTransferTransaction(chain, 1, dst.address, src.address)
+	if payBySender {
+		tx.SetProposalKey(src.address, 0, src.seq).
+			SetGasLimit(1000).
+			SetPayer(src.address)
+		src.seq++
+
+		err = testutil.SignEnvelope(tx, src.address, src.privateKey)
+		require.NoError(b, err)
+
+		return tx
+	}
+
 	tx.SetProposalKey(chain.ServiceAddress(), 0, accs.seq).
 		SetGasLimit(1000).
 		SetPayer(chain.ServiceAddress())
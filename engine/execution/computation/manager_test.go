@@ -328,6 +328,141 @@ func TestExecuteScript_BalanceScriptFailsIfViewIsEmpty(t *testing.T) {
 	require.ErrorContains(t, err, "error getting register")
 }
 
+func TestExecuteScriptWithTrace(t *testing.T) {
+
+	logger := zerolog.Nop()
+
+	execCtx := fvm.NewContext(fvm.WithLogger(logger))
+
+	vm := fvm.NewVirtualMachine()
+
+	ledger := testutil.RootBootstrappedLedger(vm, execCtx, fvm.WithExecutionMemoryLimit(math.MaxUint64))
+
+	view := delta.NewDeltaView(ledger.Get)
+
+	manager, err := New(logger,
+		metrics.NewNoopCollector(),
+		trace.NewNoopTracer(),
+		nil,
+		nil,
+		execCtx,
+		committer.NewNoopViewCommitter(),
+		nil,
+		ComputationConfig{
+			DerivedDataCacheSize:     derived.DefaultDerivedDataCacheSize,
+			ScriptLogThreshold:       scriptLogThreshold,
+			ScriptExecutionTimeLimit: DefaultScriptExecutionTimeLimit,
+		},
+	)
+	require.NoError(t, err)
+
+	address := fvm.FungibleTokenAddress(execCtx.Chain)
+	header := unittest.BlockHeaderFixture()
+
+	t.Run("registers read and computation intensities are traced", func(t *testing.T) {
+		script := []byte(fmt.Sprintf(
+			`
+				pub fun main(): UFix64 {
+					return getAccount(%s).balance
+				}
+			`,
+			address.HexWithPrefix(),
+		))
+
+		value, scriptTrace, err := manager.ExecuteScriptWithTrace(context.Background(), script, nil, header, view.NewChild())
+		require.NoError(t, err)
+		require.NotNil(t, value)
+		require.NotNil(t, scriptTrace)
+
+		read := make(map[flow.RegisterID]int, len(scriptTrace.RegisterReads))
+		for _, register := range scriptTrace.RegisterReads {
+			require.NotContains(t, read, register.ID, "registers are traced once")
+			read[register.ID] = register.Size
+		}
+
+		statusID := flow.AccountStatusRegisterID(address)
+		require.Contains(t, read, statusID)
+		status, err := view.Get(statusID)
+		require.NoError(t, err)
+		require.Equal(t, len(status), read[statusID])
+
+		require.NotZero(t, scriptTrace.ComputationIntensities[common.ComputationKindStatement])
+	})
+
+	t.Run("failed scripts are traced", func(t *testing.T) {
+		script := []byte(fmt.Sprintf(
+			`
+				pub fun main() {
+					getAccount(%s).balance
+					panic("failed")
+				}
+			`,
+			address.HexWithPrefix(),
+		))
+
+		_, scriptTrace, err := manager.ExecuteScriptWithTrace(context.Background(), script, nil, header, view.NewChild())
+		require.ErrorContains(t, err, "failed")
+		require.NotNil(t, scriptTrace)
+		require.NotEmpty(t, scriptTrace.RegisterReads)
+		require.NotEmpty(t, scriptTrace.ComputationIntensities)
+	})
+}
+
+func TestExecuteScript_MemoryLimit(t *testing.T) {
+
+	logger := zerolog.Nop()
+
+	execCtx := fvm.NewContext(fvm.WithLogger(logger))
+
+	vm := fvm.NewVirtualMachine()
+
+	ledger := testutil.RootBootstrappedLedger(vm, execCtx, fvm.WithExecutionMemoryLimit(math.MaxUint64))
+
+	view := delta.NewDeltaView(ledger.Get)
+
+	newManager := func(memoryLimit uint64) *Manager {
+		manager, err := New(logger,
+			metrics.NewNoopCollector(),
+			trace.NewNoopTracer(),
+			nil,
+			nil,
+			execCtx,
+			committer.NewNoopViewCommitter(),
+			nil,
+			ComputationConfig{
+				DerivedDataCacheSize:     derived.DefaultDerivedDataCacheSize,
+				ScriptLogThreshold:       scriptLogThreshold,
+				ScriptExecutionTimeLimit: DefaultScriptExecutionTimeLimit,
+				ScriptQueue: ScriptQueueConfig{
+					ScriptMemoryLimit: memoryLimit,
+				},
+			},
+		)
+		require.NoError(t, err)
+		return manager
+	}
+
+	script := []byte(`
+		pub fun main(): Int {
+			var a: [Int] = []
+			var i = 0
+			while i < 1000 {
+				a.append(i)
+				i = i + 1
+			}
+			return a.length
+		}
+	`)
+	header := unittest.BlockHeaderFixture()
+
+	_, err := newManager(0).ExecuteScript(context.Background(), script, nil, header, view.NewChild())
+	require.NoError(t, err)
+
+	// the memory limit of scripts caps the on-chain memory limit
+	_, err = newManager(10_000).ExecuteScript(context.Background(), script, nil, header, view.NewChild())
+	require.ErrorContains(t, err, fvmErrors.ErrCodeMemoryLimitExceededError.String())
+}
+
 func TestExecuteScripPanicsAreHandled(t *testing.T) {
 
 	ctx := fvm.NewContext()
@@ -5,9 +5,12 @@ package mock
 import (
 	context "context"
 
-	execution "github.com/onflow/flow-go/engine/execution"
+	computation "github.com/onflow/flow-go/engine/execution/computation"
+
 	entity "github.com/onflow/flow-go/module/mempool/entity"
 
+	execution "github.com/onflow/flow-go/engine/execution"
+
 	flow "github.com/onflow/flow-go/model/flow"
 
 	mock "github.com/stretchr/testify/mock"
@@ -66,6 +69,38 @@ func (_m *ComputationManager) ExecuteScript(_a0 context.Context, _a1 []byte, _a2
 	return r0, r1
 }
 
+// ExecuteScriptWithTrace provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *ComputationManager) ExecuteScriptWithTrace(_a0 context.Context, _a1 []byte, _a2 [][]byte, _a3 *flow.Header, _a4 state.View) ([]byte, *computation.ScriptTrace, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, [][]byte, *flow.Header, state.View) []byte); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 *computation.ScriptTrace
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, [][]byte, *flow.Header, state.View) *computation.ScriptTrace); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*computation.ScriptTrace)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, []byte, [][]byte, *flow.Header, state.View) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetAccount provides a mock function with given fields: addr, header, view
 func (_m *ComputationManager) GetAccount(addr flow.Address, header *flow.Header, view state.View) (*flow.Account, error) {
 	ret := _m.Called(addr, header, view)
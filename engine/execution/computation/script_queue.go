@@ -0,0 +1,213 @@
+package computation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/module"
+)
+
+const (
+	DefaultMaxConcurrentScripts      = 50
+	DefaultMaxQueuedScripts          = 1000
+	DefaultMaxQueuedScriptsPerCaller = 100
+
+	// DefaultScriptMemoryLimit is the default memory limit of each script, as
+	// metered by the fvm.  With DefaultMaxConcurrentScripts, it bounds the
+	// metered memory of the scripts executing concurrently to 50 GiB.
+	DefaultScriptMemoryLimit uint64 = 1 << 30 // 1 GiB
+)
+
+// ErrScriptQueueFull is returned when a script is rejected because too many
+// scripts are waiting for execution.
+var ErrScriptQueueFull = errors.New("too many scripts waiting for execution")
+
+// ScriptQueueConfig configures the admission of scripts for execution.
+//
+// The queue is not a worker pool: scripts are executed by the goroutines of
+// the requests they were received with, which block while the scripts wait.
+// The number of goroutines of script requests is hence bounded by
+// MaxConcurrentScripts + MaxQueuedScripts, and their memory is only bounded
+// when ScriptMemoryLimit is set.
+type ScriptQueueConfig struct {
+	// MaxConcurrentScripts is the maximum number of scripts executing
+	// concurrently.  Scripts are executed as soon as they are received, and
+	// none of the other limits are applied, when it is 0.
+	MaxConcurrentScripts int
+
+	// MaxQueuedScripts is the maximum number of scripts waiting for
+	// execution.  Further scripts are rejected with ErrScriptQueueFull.
+	MaxQueuedScripts int
+
+	// MaxQueuedScriptsPerCaller is the maximum number of scripts of a single
+	// caller waiting for execution.  Further scripts of the caller are
+	// rejected with ErrScriptQueueFull.  Callers are only limited by
+	// MaxQueuedScripts when it is 0.
+	MaxQueuedScriptsPerCaller int
+
+	// ScriptMemoryLimit is the memory limit of each script, as metered by the
+	// fvm memory meter.  It caps the on-chain memory limit when it is lower,
+	// hence the memory used by the scripts executing concurrently is bounded
+	// by MaxConcurrentScripts * ScriptMemoryLimit.  It is not applied when it
+	// is 0.
+	ScriptMemoryLimit uint64
+}
+
+type scriptCallerKey struct{}
+
+// WithScriptCaller returns a context identifying the caller of the scripts
+// executed with it, e.g. the address of the node requesting their execution.
+// Waiting scripts are executed in turn across callers, so that a single
+// caller cannot starve the others.
+func WithScriptCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, scriptCallerKey{}, caller)
+}
+
+func scriptCaller(ctx context.Context) string {
+	caller, _ := ctx.Value(scriptCallerKey{}).(string)
+	return caller
+}
+
+// queuedScript is a script waiting for execution.  Its channel is closed when
+// it may start executing.
+type queuedScript struct {
+	admitted chan struct{}
+}
+
+// scriptQueue bounds the number of scripts executing concurrently.  Scripts
+// exceeding the bound wait in per-caller FIFO queues, which are served in
+// round robin.
+type scriptQueue struct {
+	mu sync.Mutex
+
+	config  ScriptQueueConfig
+	metrics module.ExecutionMetrics
+
+	executing int
+	queued    int
+	queues    map[string][]*queuedScript // scripts waiting for execution, by caller
+	callers   []string                   // callers with waiting scripts, in the order they are served
+}
+
+func newScriptQueue(config ScriptQueueConfig, metrics module.ExecutionMetrics) *scriptQueue {
+	return &scriptQueue{
+		config:  config,
+		metrics: metrics,
+		queues:  make(map[string][]*queuedScript),
+	}
+}
+
+// admit waits until a script of the caller of the given context may start
+// executing.  The returned function must be called once the script is
+// executed.  It returns ErrScriptQueueFull if too many scripts are waiting,
+// or the error of the context if it is done before the script is admitted.
+func (q *scriptQueue) admit(ctx context.Context) (func(), error) {
+	if q.config.MaxConcurrentScripts <= 0 {
+		return func() {}, nil
+	}
+
+	start := time.Now()
+	caller := scriptCaller(ctx)
+
+	q.mu.Lock()
+	if q.executing < q.config.MaxConcurrentScripts && q.queued == 0 {
+		q.executing++
+		q.mu.Unlock()
+
+		q.metrics.ExecutionScriptQueueWaitTime(0)
+		return q.done, nil
+	}
+
+	if q.queued >= q.config.MaxQueuedScripts ||
+		(q.config.MaxQueuedScriptsPerCaller > 0 &&
+			len(q.queues[caller]) >= q.config.MaxQueuedScriptsPerCaller) {
+		q.mu.Unlock()
+
+		q.metrics.ExecutionScriptRejected()
+		return nil, ErrScriptQueueFull
+	}
+
+	script := &queuedScript{admitted: make(chan struct{})}
+	if len(q.queues[caller]) == 0 {
+		q.callers = append(q.callers, caller)
+	}
+	q.queues[caller] = append(q.queues[caller], script)
+	q.queued++
+	q.metrics.ExecutionScriptQueueDepth(q.queued)
+	q.mu.Unlock()
+
+	select {
+	case <-script.admitted:
+		q.metrics.ExecutionScriptQueueWaitTime(time.Since(start))
+		return q.done, nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	removed := q.remove(caller, script)
+	q.mu.Unlock()
+
+	// the script was admitted while the context was done
+	if !removed {
+		q.done()
+	}
+
+	return nil, ctx.Err()
+}
+
+// done releases the execution slot of an executed script, and admits the next
+// waiting script, if any.
+func (q *scriptQueue) done() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.executing--
+	for q.executing < q.config.MaxConcurrentScripts && len(q.callers) > 0 {
+		caller := q.callers[0]
+		q.callers = q.callers[1:]
+
+		queue := q.queues[caller]
+		script := queue[0]
+		if len(queue) == 1 {
+			delete(q.queues, caller)
+		} else {
+			q.queues[caller] = queue[1:]
+			q.callers = append(q.callers, caller)
+		}
+
+		q.queued--
+		q.executing++
+		close(script.admitted)
+	}
+	q.metrics.ExecutionScriptQueueDepth(q.queued)
+}
+
+// remove removes a waiting script from the queue of its caller.  It returns
+// false if the script is not waiting anymore.
+func (q *scriptQueue) remove(caller string, script *queuedScript) bool {
+	queue := q.queues[caller]
+	for i, queued := range queue {
+		if queued != script {
+			continue
+		}
+
+		if len(queue) > 1 {
+			q.queues[caller] = append(queue[:i:i], queue[i+1:]...)
+		} else {
+			delete(q.queues, caller)
+			for j, c := range q.callers {
+				if c == caller {
+					q.callers = append(q.callers[:j:j], q.callers[j+1:]...)
+					break
+				}
+			}
+		}
+
+		q.queued--
+		q.metrics.ExecutionScriptQueueDepth(q.queued)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package computation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+// admitAsync admits a script of the given caller in the background, once it
+// is waiting for execution.  It returns the channel receiving the function
+// releasing the script once it is admitted.
+func admitAsync(t *testing.T, q *scriptQueue, ctx context.Context, caller string) <-chan func() {
+	q.mu.Lock()
+	waiting := len(q.queues[caller])
+	q.mu.Unlock()
+
+	admitted := make(chan func(), 1)
+	go func() {
+		done, err := q.admit(WithScriptCaller(ctx, caller))
+		if err == nil {
+			admitted <- done
+		}
+	}()
+
+	require.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return len(q.queues[caller]) > waiting
+	}, time.Second, time.Millisecond)
+
+	return admitted
+}
+
+func TestScriptQueue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("scripts are admitted immediately without concurrency limit", func(t *testing.T) {
+		q := newScriptQueue(ScriptQueueConfig{}, metrics.NewNoopCollector())
+		for i := 0; i < 10; i++ {
+			_, err := q.admit(ctx)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("waiting scripts are admitted in turn across callers", func(t *testing.T) {
+		q := newScriptQueue(ScriptQueueConfig{
+			MaxConcurrentScripts: 1,
+			MaxQueuedScripts:     10,
+		}, metrics.NewNoopCollector())
+
+		done, err := q.admit(WithScriptCaller(ctx, "a"))
+		require.NoError(t, err)
+
+		// caller a queues two scripts before caller b queues one
+		a1 := admitAsync(t, q, ctx, "a")
+		a2 := admitAsync(t, q, ctx, "a")
+		b1 := admitAsync(t, q, ctx, "b")
+
+		done()
+		doneA1 := <-a1
+		assert.Empty(t, b1)
+
+		doneA1()
+		doneB1 := <-b1
+		assert.Empty(t, a2)
+
+		doneB1()
+		doneA2 := <-a2
+		doneA2()
+
+		assert.Equal(t, 0, q.executing)
+		assert.Equal(t, 0, q.queued)
+	})
+
+	t.Run("scripts are rejected when too many are waiting", func(t *testing.T) {
+		q := newScriptQueue(ScriptQueueConfig{
+			MaxConcurrentScripts:      1,
+			MaxQueuedScripts:          2,
+			MaxQueuedScriptsPerCaller: 1,
+		}, metrics.NewNoopCollector())
+
+		_, err := q.admit(ctx)
+		require.NoError(t, err)
+
+		admitAsync(t, q, ctx, "a")
+
+		// caller a has too many scripts waiting
+		_, err = q.admit(WithScriptCaller(ctx, "a"))
+		assert.ErrorIs(t, err, ErrScriptQueueFull)
+
+		admitAsync(t, q, ctx, "b")
+
+		// too many scripts are waiting
+		_, err = q.admit(WithScriptCaller(ctx, "c"))
+		assert.ErrorIs(t, err, ErrScriptQueueFull)
+	})
+
+	t.Run("scripts stop waiting when their context is done", func(t *testing.T) {
+		q := newScriptQueue(ScriptQueueConfig{
+			MaxConcurrentScripts: 1,
+			MaxQueuedScripts:     10,
+		}, metrics.NewNoopCollector())
+
+		done, err := q.admit(ctx)
+		require.NoError(t, err)
+
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = q.admit(WithScriptCaller(cancelledCtx, "a"))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, q.queued)
+		assert.Empty(t, q.callers)
+
+		done()
+		assert.Equal(t, 0, q.executing)
+	})
+}
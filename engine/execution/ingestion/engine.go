@@ -20,6 +20,7 @@ import (
 	"github.com/onflow/flow-go/engine/execution/ingestion/uploader"
 	"github.com/onflow/flow-go/engine/execution/provider"
 	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/engine/execution/utils"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
@@ -1066,21 +1067,41 @@ func (e *Engine) matchAndFindMissingCollections(
 }
 
 func (e *Engine) ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, error) {
+	block, blockView, err := e.scriptView(ctx, script, arguments, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.computationManager.ExecuteScript(ctx, script, arguments, block, blockView)
+}
+
+func (e *Engine) ExecuteScriptAtBlockIDWithTrace(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, *computation.ScriptTrace, error) {
+	block, blockView, err := e.scriptView(ctx, script, arguments, blockID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return e.computationManager.ExecuteScriptWithTrace(ctx, script, arguments, block, blockView)
+}
+
+// scriptView returns the header of the given block and a view of the execution state at the end of
+// the block, to execute scripts on.
+func (e *Engine) scriptView(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) (*flow.Header, *delta.View, error) {
 
 	stateCommit, err := e.execState.StateCommitmentByBlockID(ctx, blockID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get state commitment for block (%s): %w", blockID, err)
+		return nil, nil, fmt.Errorf("failed to get state commitment for block (%s): %w", blockID, err)
 	}
 
 	// return early if state with the given state commitment is not in memory
 	// and already purged. This reduces allocations for scripts targeting old blocks.
 	if !e.execState.HasState(stateCommit) {
-		return nil, fmt.Errorf("failed to execute script at block (%s): state commitment not found (%s). this error usually happens if the reference block for this script is not set to a recent block", blockID.String(), hex.EncodeToString(stateCommit[:]))
+		return nil, nil, fmt.Errorf("failed to execute script at block (%s): state commitment not found (%s). this error usually happens if the reference block for this script is not set to a recent block", blockID.String(), hex.EncodeToString(stateCommit[:]))
 	}
 
 	block, err := e.state.AtBlockID(blockID).Head()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
+		return nil, nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
 	}
 
 	blockView := e.execState.NewView(stateCommit)
@@ -1098,7 +1119,7 @@ func (e *Engine) ExecuteScriptAtBlockID(ctx context.Context, script []byte, argu
 			Str("args", strings.Join(args[:], ",")).
 			Msg("extensive log: executed script content")
 	}
-	return e.computationManager.ExecuteScript(ctx, script, arguments, block, blockView)
+	return block, blockView, nil
 }
 
 func (e *Engine) GetRegisterAtBlockID(ctx context.Context, owner, key []byte, blockID flow.Identifier) ([]byte, error) {
@@ -1119,6 +1140,60 @@ func (e *Engine) GetRegisterAtBlockID(ctx context.Context, owner, key []byte, bl
 	return data, nil
 }
 
+func (e *Engine) GetRegistersAtBlockID(
+	ctx context.Context,
+	registerIDs []flow.RegisterID,
+	blockID flow.Identifier,
+) ([]flow.RegisterValue, error) {
+
+	stateCommit, err := e.execState.StateCommitmentByBlockID(ctx, blockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state commitment for block (%s): %w", blockID, err)
+	}
+
+	blockView := e.execState.NewView(stateCommit)
+
+	values := make([]flow.RegisterValue, 0, len(registerIDs))
+	for _, id := range registerIDs {
+		value, err := blockView.Get(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the register (%s): %w", id, err)
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func (e *Engine) GetRegistersWithProofAtBlockID(
+	ctx context.Context,
+	registerIDs []flow.RegisterID,
+	blockID flow.Identifier,
+) ([]flow.RegisterValue, flow.StorageProof, flow.StateCommitment, error) {
+
+	stateCommit, err := e.execState.StateCommitmentByBlockID(ctx, blockID)
+	if err != nil {
+		return nil, nil, flow.DummyStateCommitment, fmt.Errorf("failed to get state commitment for block (%s): %w", blockID, err)
+	}
+
+	// proofs can only be generated from the tries still held by the ledger
+	if !e.execState.HasState(stateCommit) {
+		return nil, nil, flow.DummyStateCommitment, fmt.Errorf("failed to get registers at block (%s): state commitment not found (%s)", blockID, hex.EncodeToString(stateCommit[:]))
+	}
+
+	values, err := e.execState.GetRegisters(ctx, stateCommit, registerIDs)
+	if err != nil {
+		return nil, nil, flow.DummyStateCommitment, fmt.Errorf("failed to get registers at block (%s): %w", blockID, err)
+	}
+
+	proof, err := e.execState.GetProof(ctx, stateCommit, registerIDs)
+	if err != nil {
+		return nil, nil, flow.DummyStateCommitment, fmt.Errorf("failed to get proof of registers at block (%s): %w", blockID, err)
+	}
+
+	return values, proof, stateCommit, nil
+}
+
 func (e *Engine) GetAccount(ctx context.Context, addr flow.Address, blockID flow.Identifier) (*flow.Account, error) {
 	stateCommit, err := e.execState.StateCommitmentByBlockID(ctx, blockID)
 	if err != nil {
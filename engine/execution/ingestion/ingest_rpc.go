@@ -3,6 +3,7 @@ package ingestion
 import (
 	"context"
 
+	"github.com/onflow/flow-go/engine/execution/computation"
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -12,9 +13,20 @@ type IngestRPC interface {
 	// ExecuteScriptAtBlockID executes a script at the given Block id
 	ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, error)
 
+	// ExecuteScriptAtBlockIDWithTrace executes a script at the given Block id, and returns the trace
+	// of its execution
+	ExecuteScriptAtBlockIDWithTrace(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, *computation.ScriptTrace, error)
+
 	// GetAccount returns the Account details at the given Block id
 	GetAccount(ctx context.Context, address flow.Address, blockID flow.Identifier) (*flow.Account, error)
 
 	// GetRegisterAtBlockID returns the value of a register at the given Block id (if available)
 	GetRegisterAtBlockID(ctx context.Context, owner, key []byte, blockID flow.Identifier) ([]byte, error)
+
+	// GetRegistersAtBlockID returns the values of the registers at the given Block id (if available)
+	GetRegistersAtBlockID(ctx context.Context, registerIDs []flow.RegisterID, blockID flow.Identifier) ([]flow.RegisterValue, error)
+
+	// GetRegistersWithProofAtBlockID returns the values of the registers at the given Block id, with a batch
+	// proof of the values against the state commitment of the block, which is also returned
+	GetRegistersWithProofAtBlockID(ctx context.Context, registerIDs []flow.RegisterID, blockID flow.Identifier) ([]flow.RegisterValue, flow.StorageProof, flow.StateCommitment, error)
 }
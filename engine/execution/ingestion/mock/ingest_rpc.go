@@ -5,6 +5,8 @@ package mock
 import (
 	context "context"
 
+	computation "github.com/onflow/flow-go/engine/execution/computation"
+
 	flow "github.com/onflow/flow-go/model/flow"
 
 	mock "github.com/stretchr/testify/mock"
@@ -38,6 +40,38 @@ func (_m *IngestRPC) ExecuteScriptAtBlockID(ctx context.Context, script []byte,
 	return r0, r1
 }
 
+// ExecuteScriptAtBlockIDWithTrace provides a mock function with given fields: ctx, script, arguments, blockID
+func (_m *IngestRPC) ExecuteScriptAtBlockIDWithTrace(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, *computation.ScriptTrace, error) {
+	ret := _m.Called(ctx, script, arguments, blockID)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, [][]byte, flow.Identifier) []byte); ok {
+		r0 = rf(ctx, script, arguments, blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 *computation.ScriptTrace
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, [][]byte, flow.Identifier) *computation.ScriptTrace); ok {
+		r1 = rf(ctx, script, arguments, blockID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*computation.ScriptTrace)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, []byte, [][]byte, flow.Identifier) error); ok {
+		r2 = rf(ctx, script, arguments, blockID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetAccount provides a mock function with given fields: ctx, address, blockID
 func (_m *IngestRPC) GetAccount(ctx context.Context, address flow.Address, blockID flow.Identifier) (*flow.Account, error) {
 	ret := _m.Called(ctx, address, blockID)
@@ -84,6 +118,70 @@ func (_m *IngestRPC) GetRegisterAtBlockID(ctx context.Context, owner []byte, key
 	return r0, r1
 }
 
+// GetRegistersAtBlockID provides a mock function with given fields: ctx, registerIDs, blockID
+func (_m *IngestRPC) GetRegistersAtBlockID(ctx context.Context, registerIDs []flow.RegisterID, blockID flow.Identifier) ([][]byte, error) {
+	ret := _m.Called(ctx, registerIDs, blockID)
+
+	var r0 [][]byte
+	if rf, ok := ret.Get(0).(func(context.Context, []flow.RegisterID, flow.Identifier) [][]byte); ok {
+		r0 = rf(ctx, registerIDs, blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([][]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []flow.RegisterID, flow.Identifier) error); ok {
+		r1 = rf(ctx, registerIDs, blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRegistersWithProofAtBlockID provides a mock function with given fields: ctx, registerIDs, blockID
+func (_m *IngestRPC) GetRegistersWithProofAtBlockID(ctx context.Context, registerIDs []flow.RegisterID, blockID flow.Identifier) ([][]byte, []byte, flow.StateCommitment, error) {
+	ret := _m.Called(ctx, registerIDs, blockID)
+
+	var r0 [][]byte
+	if rf, ok := ret.Get(0).(func(context.Context, []flow.RegisterID, flow.Identifier) [][]byte); ok {
+		r0 = rf(ctx, registerIDs, blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([][]byte)
+		}
+	}
+
+	var r1 []byte
+	if rf, ok := ret.Get(1).(func(context.Context, []flow.RegisterID, flow.Identifier) []byte); ok {
+		r1 = rf(ctx, registerIDs, blockID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	var r2 flow.StateCommitment
+	if rf, ok := ret.Get(2).(func(context.Context, []flow.RegisterID, flow.Identifier) flow.StateCommitment); ok {
+		r2 = rf(ctx, registerIDs, blockID)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(flow.StateCommitment)
+		}
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(context.Context, []flow.RegisterID, flow.Identifier) error); ok {
+		r3 = rf(ctx, registerIDs, blockID)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
 type mockConstructorTestingTNewIngestRPC interface {
 	mock.TestingT
 	Cleanup(func())
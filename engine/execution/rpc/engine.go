@@ -6,26 +6,58 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/flow/protobuf/go/flow/execution"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/execution/computation"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
+	"github.com/onflow/flow-go/engine/execution/rpc/registers"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
 
+const (
+	// ScriptTraceHeader is the request metadata key enabling the tracing of the script executed by
+	// ExecuteScriptAtBlockID, when set to "true".
+	ScriptTraceHeader = "x-script-trace"
+
+	// ScriptRegisterReadsHeader is the response metadata key of the registers read by a traced
+	// script, with one value per register in the order they were first read, for at most
+	// MaxScriptTraceRegisterReads registers. All the registers read are returned in the
+	// ScriptRegisterReadsBinTrailer response trailer.
+	ScriptRegisterReadsHeader = "x-script-register-reads"
+
+	// ScriptRegisterReadsTruncatedHeader is the response metadata key of the total number of
+	// registers read by a traced script, only set when it exceeds MaxScriptTraceRegisterReads and
+	// the registers returned in ScriptRegisterReadsHeader are truncated.
+	ScriptRegisterReadsTruncatedHeader = "x-script-register-reads-truncated"
+
+	// ScriptComputationIntensitiesHeader is the response metadata key of the computation
+	// intensities metered by a traced script, with one value per computation kind.
+	ScriptComputationIntensitiesHeader = "x-script-computation-intensities"
+)
+
+// MaxScriptTraceRegisterReads is the maximum number of registers read by a traced script returned
+// in the response metadata, which keeps the response headers within the size limits of clients.
+const MaxScriptTraceRegisterReads = 100
+
 // Config defines the configurable options for the gRPC server.
 type Config struct {
 	ListenAddr        string
@@ -53,6 +85,7 @@ func New(
 	exeResults storage.ExecutionResults,
 	txResults storage.TransactionResults,
 	commits storage.Commits,
+	seals storage.Seals,
 	chainID flow.ChainID,
 	signerIndicesDecoder hotstuff.BlockSignerDecoder,
 	apiRatelimits map[string]int, // the api rate limit (max calls per second) for each of the gRPC API e.g. Ping->100, ExecuteScriptAtBlockID->300
@@ -96,6 +129,7 @@ func New(
 			exeResults:           exeResults,
 			transactionResults:   txResults,
 			commits:              commits,
+			seals:                seals,
 			log:                  log,
 		},
 		server: server,
@@ -108,6 +142,7 @@ func New(
 	}
 
 	execution.RegisterExecutionAPIServer(eng.server, eng.handler)
+	registers.RegisterServer(eng.server, eng.handler)
 
 	return eng
 }
@@ -156,9 +191,11 @@ type handler struct {
 	transactionResults   storage.TransactionResults
 	log                  zerolog.Logger
 	commits              storage.Commits
+	seals                storage.Seals
 }
 
 var _ execution.ExecutionAPIServer = &handler{}
+var _ registers.Server = &handler{}
 
 // Ping responds to requests when the server is up.
 func (h *handler) Ping(_ context.Context, _ *execution.PingRequest) (*execution.PingResponse, error) {
@@ -175,8 +212,37 @@ func (h *handler) ExecuteScriptAtBlockID(
 		return nil, err
 	}
 
-	value, err := h.engine.ExecuteScriptAtBlockID(ctx, req.GetScript(), req.GetArguments(), blockID)
+	// waiting scripts are executed in turn across the nodes requesting them
+	if p, ok := peer.FromContext(ctx); ok {
+		ctx = computation.WithScriptCaller(ctx, rpc.RemoteIP(p.Addr.String()))
+	}
+
+	var value []byte
+	if scriptTraceRequested(ctx) {
+		var trace *computation.ScriptTrace
+		value, trace, err = h.engine.ExecuteScriptAtBlockIDWithTrace(ctx, req.GetScript(), req.GetArguments(), blockID)
+		// the trace of a failed script is returned along with the error
+		if trace != nil {
+			headerErr := grpc.SetHeader(ctx, ScriptTraceMetadata(trace))
+			if headerErr != nil {
+				return nil, status.Errorf(codes.Internal, "could not set script trace metadata: %v", headerErr)
+			}
+			trailer, trailerErr := ScriptTraceTrailer(trace)
+			if trailerErr != nil {
+				return nil, status.Errorf(codes.Internal, "could not encode script trace: %v", trailerErr)
+			}
+			trailerErr = grpc.SetTrailer(ctx, trailer)
+			if trailerErr != nil {
+				return nil, status.Errorf(codes.Internal, "could not set script trace trailer: %v", trailerErr)
+			}
+		}
+	} else {
+		value, err = h.engine.ExecuteScriptAtBlockID(ctx, req.GetScript(), req.GetArguments(), blockID)
+	}
 	if err != nil {
+		if errors.Is(err, computation.ErrScriptQueueFull) {
+			return nil, status.Errorf(codes.ResourceExhausted, "failed to execute script: %v", err)
+		}
 		// return code 3 as this passes the litmus test in our context
 		return nil, status.Errorf(codes.InvalidArgument, "failed to execute script: %v", err)
 	}
@@ -188,6 +254,46 @@ func (h *handler) ExecuteScriptAtBlockID(
 	return res, nil
 }
 
+// scriptTraceRequested returns true if the request metadata enables the tracing of scripts.
+func scriptTraceRequested(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	values := md.Get(ScriptTraceHeader)
+	return len(values) > 0 && values[0] == "true"
+}
+
+// ScriptTraceMetadata returns the response metadata of the given script trace, e.g.
+// "owner=1654653399040a61 key=7075626c69635f6b65795f30 size=80" for each register read and
+// "kind=Statement intensity=12" for each computation kind. Only the first
+// MaxScriptTraceRegisterReads registers read are returned, and the total number of registers read is
+// returned in ScriptRegisterReadsTruncatedHeader when there are more. See ScriptTraceTrailer for
+// all the registers read.
+func ScriptTraceMetadata(trace *computation.ScriptTrace) metadata.MD {
+	md := metadata.MD{}
+	reads := trace.RegisterReads
+	if len(reads) > MaxScriptTraceRegisterReads {
+		md.Set(ScriptRegisterReadsTruncatedHeader, strconv.Itoa(len(reads)))
+		reads = reads[:MaxScriptTraceRegisterReads]
+	}
+	for _, read := range reads {
+		md.Append(ScriptRegisterReadsHeader,
+			fmt.Sprintf("owner=%x key=%x size=%d", read.ID.Owner, read.ID.Key, read.Size))
+	}
+
+	kinds := make([]common.ComputationKind, 0, len(trace.ComputationIntensities))
+	for kind := range trace.ComputationIntensities {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	for _, kind := range kinds {
+		md.Append(ScriptComputationIntensitiesHeader,
+			fmt.Sprintf("kind=%s intensity=%d", kind, trace.ComputationIntensities[kind]))
+	}
+	return md
+}
+
 func (h *handler) GetRegisterAtBlockID(
 	ctx context.Context,
 	req *execution.GetRegisterAtBlockIDRequest,
@@ -213,6 +319,92 @@ func (h *handler) GetRegisterAtBlockID(
 	return res, nil
 }
 
+// GetRegistersAtBlockID returns the values of the given registers at an executed block, fetching
+// them with a single request instead of one GetRegisterAtBlockID request per register.
+func (h *handler) GetRegistersAtBlockID(
+	ctx context.Context,
+	req *registers.GetRegistersAtBlockIDRequest,
+) (*registers.GetRegistersAtBlockIDResponse, error) {
+
+	blockID, err := convert.BlockID(req.BlockId)
+	if err != nil {
+		return nil, err
+	}
+
+	registerIDs := req.RegisterIds
+	if len(registerIDs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no registers requested")
+	}
+	if len(registerIDs) > registers.MaxRegisters {
+		return nil, status.Errorf(codes.InvalidArgument, "too many registers requested: %d > %d", len(registerIDs), registers.MaxRegisters)
+	}
+
+	values, err := h.engine.GetRegistersAtBlockID(ctx, registerIDs, blockID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to collect registers: %v", err)
+	}
+
+	res := &registers.GetRegistersAtBlockIDResponse{
+		Values: make([][]byte, len(values)),
+	}
+	for i, value := range values {
+		res.Values[i] = value
+	}
+
+	return res, nil
+}
+
+// GetRegistersWithProofAtBlockID returns the values of the given registers at a sealed block, with a
+// batch proof of the values against the sealed state commitment of the block.  Clients can verify
+// the values with the proof, e.g. with a partial ledger, without trusting the execution node.
+func (h *handler) GetRegistersWithProofAtBlockID(
+	ctx context.Context,
+	req *registers.GetRegistersWithProofAtBlockIDRequest,
+) (*registers.GetRegistersWithProofAtBlockIDResponse, error) {
+
+	blockID, err := convert.BlockID(req.BlockId)
+	if err != nil {
+		return nil, err
+	}
+
+	registerIDs := req.RegisterIds
+	if len(registerIDs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no registers requested")
+	}
+	if len(registerIDs) > registers.MaxRegisters {
+		return nil, status.Errorf(codes.InvalidArgument, "too many registers requested: %d > %d", len(registerIDs), registers.MaxRegisters)
+	}
+
+	seal, err := h.seals.FinalizedSealForBlock(blockID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "block %s is not sealed", blockID)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get seal of block %s: %v", blockID, err)
+	}
+
+	values, proof, commit, err := h.engine.GetRegistersWithProofAtBlockID(ctx, registerIDs, blockID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to collect registers with proof: %v", err)
+	}
+
+	// the proof would not verify against the sealed state commitment
+	if commit != seal.FinalState {
+		return nil, status.Errorf(codes.Internal, "state commitment of block %s (%x) differs from the sealed state commitment (%x)", blockID, commit, seal.FinalState)
+	}
+
+	res := &registers.GetRegistersWithProofAtBlockIDResponse{
+		Values:          make([][]byte, len(values)),
+		Proof:           proof,
+		StateCommitment: commit[:],
+	}
+	for i, value := range values {
+		res.Values[i] = value
+	}
+
+	return res, nil
+}
+
 func (h *handler) GetEventsForBlockIDs(_ context.Context,
 	req *execution.GetEventsForBlockIDsRequest) (*execution.GetEventsForBlockIDsResponse, error) {
 
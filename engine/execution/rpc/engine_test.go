@@ -3,25 +3,42 @@ package rpc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/rand"
+	"net"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/testing/protocmp"
 
 	"github.com/onflow/flow/protobuf/go/flow/entities"
 	"github.com/onflow/flow/protobuf/go/flow/execution"
 
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/execution/computation"
 	ingestion "github.com/onflow/flow-go/engine/execution/ingestion/mock"
+	"github.com/onflow/flow-go/engine/execution/rpc/registers"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/fvm/meter"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	"github.com/onflow/flow-go/ledger/partial"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
 	realstorage "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/grpcutils"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -88,6 +105,99 @@ func (suite *Suite) TestExecuteScriptAtBlockID() {
 		errors.Is(err, status.Error(codes.InvalidArgument, ""))
 	})
 
+	suite.Run("script rejected because too many scripts are waiting", func() {
+		mockEngine.On("ExecuteScriptAtBlockID", ctx, script, arguments, mockIdentifier).
+			Return(nil, fmt.Errorf("failed to admit script for execution: %w", computation.ErrScriptQueueFull)).Once()
+		_, err := handler.ExecuteScriptAtBlockID(ctx, &executionReq)
+		suite.Require().Error(err)
+		suite.Assert().Equal(codes.ResourceExhausted, status.Code(err))
+	})
+
+	suite.Run("traced script execution returns the trace as metadata", func() {
+		owner := unittest.RandomAddressFixture()
+		trace := &computation.ScriptTrace{
+			RegisterReads: []computation.RegisterRead{
+				{ID: flow.AccountStatusRegisterID(owner), Size: 4},
+				{ID: flow.PublicKeyRegisterID(owner, 0), Size: 80},
+			},
+			ComputationIntensities: meter.MeteredComputationIntensities{
+				common.ComputationKindLoop:      2,
+				common.ComputationKindStatement: 12,
+			},
+		}
+
+		stream := &headerStream{}
+		tracedCtx := grpc.NewContextWithServerTransportStream(
+			metadata.NewIncomingContext(ctx, metadata.Pairs(ScriptTraceHeader, "true")),
+			stream)
+		mockEngine.On("ExecuteScriptAtBlockIDWithTrace", tracedCtx, script, arguments, mockIdentifier).
+			Return(scriptExecValue, trace, nil).Once()
+
+		response, err := handler.ExecuteScriptAtBlockID(tracedCtx, &executionReq)
+		suite.Require().NoError(err)
+		suite.Require().Equal(&executionResp, response)
+		suite.Assert().Equal([]string{
+			fmt.Sprintf("owner=%x key=%x size=4", owner[:], flow.AccountStatusKey),
+			fmt.Sprintf("owner=%x key=%x size=80", owner[:], "public_key_0"),
+		}, stream.header.Get(ScriptRegisterReadsHeader))
+		suite.Assert().Equal([]string{
+			"kind=Statement intensity=12",
+			"kind=Loop intensity=2",
+		}, stream.header.Get(ScriptComputationIntensitiesHeader))
+		suite.Assert().Empty(stream.header.Get(ScriptRegisterReadsTruncatedHeader))
+
+		encoded := stream.trailer.Get(ScriptRegisterReadsBinTrailer)
+		suite.Require().Len(encoded, 1)
+		reads, err := DecodeScriptRegisterReads([]byte(encoded[0]))
+		suite.Require().NoError(err)
+		suite.Assert().Equal(trace.RegisterReads, reads)
+		suite.Assert().Empty(stream.trailer.Get(ScriptRegisterReadsBinTruncatedTrailer))
+		mockEngine.AssertExpectations(suite.T())
+	})
+
+	suite.Run("traced register reads are truncated", func() {
+		owner := unittest.RandomAddressFixture()
+		trace := &computation.ScriptTrace{}
+		for i := 0; i < MaxScriptTraceRegisterReads+10; i++ {
+			trace.RegisterReads = append(trace.RegisterReads, computation.RegisterRead{
+				ID:   flow.PublicKeyRegisterID(owner, uint64(i)),
+				Size: 80,
+			})
+		}
+
+		md := ScriptTraceMetadata(trace)
+		suite.Assert().Len(md.Get(ScriptRegisterReadsHeader), MaxScriptTraceRegisterReads)
+		suite.Assert().Equal([]string{fmt.Sprint(MaxScriptTraceRegisterReads + 10)}, md.Get(ScriptRegisterReadsTruncatedHeader))
+
+		// the trailer still returns all the registers read
+		trailer, err := ScriptTraceTrailer(trace)
+		suite.Require().NoError(err)
+		reads, err := DecodeScriptRegisterReads([]byte(trailer.Get(ScriptRegisterReadsBinTrailer)[0]))
+		suite.Require().NoError(err)
+		suite.Assert().Equal(trace.RegisterReads, reads)
+		suite.Assert().Empty(trailer.Get(ScriptRegisterReadsBinTruncatedTrailer))
+	})
+
+	suite.Run("encoded register reads are truncated by size", func() {
+		owner := unittest.RandomAddressFixture()
+		reads := make([]computation.RegisterRead, 0, 10000)
+		for i := 0; i < cap(reads); i++ {
+			reads = append(reads, computation.RegisterRead{
+				ID:   flow.NewRegisterID(string(owner[:]), string(unittest.RandomBytes(32))),
+				Size: i,
+			})
+		}
+
+		encoded, count, err := EncodeScriptRegisterReads(reads, 1024)
+		suite.Require().NoError(err)
+		suite.Require().Greater(count, 0)
+		suite.Require().Less(count, len(reads))
+
+		decoded, err := DecodeScriptRegisterReads(encoded)
+		suite.Require().NoError(err)
+		suite.Assert().Equal(reads[:count], decoded)
+	})
+
 	suite.Run("invalid request with nil blockID", func() {
 		executionReqWithNilBlock := execution.ExecuteScriptAtBlockIDRequest{
 			BlockId: nil,
@@ -102,6 +212,30 @@ func (suite *Suite) TestExecuteScriptAtBlockID() {
 
 }
 
+// headerStream is a server transport stream recording the headers and trailers set by the handler.
+type headerStream struct {
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *headerStream) Method() string {
+	return ""
+}
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *headerStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *headerStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
 // TestGetEventsForBlockIDs tests the GetEventsForBlockIDs API call
 func (suite *Suite) TestGetEventsForBlockIDs() {
 
@@ -343,6 +477,202 @@ func (suite *Suite) TestGetRegisterAtBlockID() {
 	})
 }
 
+// TestGetRegistersAtBlockID tests the GetRegistersAtBlockID API call
+func (suite *Suite) TestGetRegistersAtBlockID() {
+
+	id := unittest.IdentifierFixture()
+	registerIDs := []flow.RegisterID{
+		flow.NewRegisterID("owner", "set"),
+		flow.NewRegisterID("owner", "unset"),
+	}
+	values := []flow.RegisterValue{{1, 2, 3}, {}}
+
+	mockEngine := new(ingestion.IngestRPC)
+
+	// create the handler
+	handler := &handler{
+		engine: mockEngine,
+		chain:  flow.Mainnet,
+	}
+
+	suite.Run("happy path", func() {
+		mockEngine.On("GetRegistersAtBlockID", mock.Anything, registerIDs, id).Return(values, nil).Once()
+
+		resp, err := handler.GetRegistersAtBlockID(context.Background(), &registers.GetRegistersAtBlockIDRequest{
+			BlockId:     id[:],
+			RegisterIds: registerIDs,
+		})
+		suite.Require().NoError(err)
+		suite.Assert().Equal([][]byte{{1, 2, 3}, {}}, resp.Values)
+	})
+
+	suite.Run("invalid request with no registers", func() {
+		_, err := handler.GetRegistersAtBlockID(context.Background(), &registers.GetRegistersAtBlockIDRequest{
+			BlockId: id[:],
+		})
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("invalid request with too many registers", func() {
+		_, err := handler.GetRegistersAtBlockID(context.Background(), &registers.GetRegistersAtBlockIDRequest{
+			BlockId:     id[:],
+			RegisterIds: make([]flow.RegisterID, registers.MaxRegisters+1),
+		})
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	mockEngine.AssertExpectations(suite.T())
+}
+
+// TestGetRegistersWithProofAtBlockID tests the GetRegistersWithProofAtBlockID API call
+func (suite *Suite) TestGetRegistersWithProofAtBlockID() {
+
+	id := unittest.IdentifierFixture()
+	set := flow.NewRegisterID("owner", "set")
+	unset := flow.NewRegisterID("owner", "unset")
+	registerIDs := []flow.RegisterID{set, unset}
+
+	// prove the registers from a ledger holding one of them
+	ldg, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+	suite.Require().NoError(err)
+	compactor := fixtures.NewNoopCompactor(ldg)
+	<-compactor.Ready()
+	defer func() {
+		<-ldg.Done()
+		<-compactor.Done()
+	}()
+
+	update, err := ledger.NewUpdate(
+		ldg.InitialState(),
+		[]ledger.Key{state.RegisterIDToKey(set)},
+		[]ledger.Value{{1, 2, 3}},
+	)
+	suite.Require().NoError(err)
+	sealedState, _, err := ldg.Set(update)
+	suite.Require().NoError(err)
+
+	keys := []ledger.Key{state.RegisterIDToKey(set), state.RegisterIDToKey(unset)}
+	query, err := ledger.NewQuery(sealedState, keys)
+	suite.Require().NoError(err)
+	proof, err := ldg.Prove(query)
+	suite.Require().NoError(err)
+
+	commit := flow.StateCommitment(sealedState)
+	values := []flow.RegisterValue{{1, 2, 3}, {}}
+
+	mockEngine := new(ingestion.IngestRPC)
+	seals := new(storage.Seals)
+
+	// create the handler
+	handler := &handler{
+		engine: mockEngine,
+		chain:  flow.Mainnet,
+		seals:  seals,
+	}
+
+	req := &registers.GetRegistersWithProofAtBlockIDRequest{
+		BlockId:     id[:],
+		RegisterIds: registerIDs,
+	}
+
+	suite.Run("happy path with verifiable proof", func() {
+		seals.On("FinalizedSealForBlock", id).Return(&flow.Seal{BlockID: id, FinalState: commit}, nil).Once()
+		mockEngine.On("GetRegistersWithProofAtBlockID", mock.Anything, registerIDs, id).Return(values, flow.StorageProof(proof), commit, nil).Once()
+
+		resp, err := handler.GetRegistersWithProofAtBlockID(context.Background(), req)
+		suite.Require().NoError(err)
+		suite.Require().Equal([][]byte{{1, 2, 3}, {}}, resp.Values)
+		suite.Require().Equal(commit[:], resp.StateCommitment)
+
+		// the values are verified against the sealed state commitment
+		verifier, err := partial.NewLedger(resp.Proof, ledger.State(commit), partial.DefaultPathFinderVersion)
+		suite.Require().NoError(err)
+		verifyQuery, err := ledger.NewQuery(ledger.State(commit), keys)
+		suite.Require().NoError(err)
+		verified, err := verifier.Get(verifyQuery)
+		suite.Require().NoError(err)
+		suite.Require().Len(verified, len(resp.Values))
+		for i, value := range verified {
+			suite.Require().Equal(resp.Values[i], []byte(value))
+		}
+
+		// the proof does not verify against another state commitment
+		_, err = partial.NewLedger(resp.Proof, ledger.State(unittest.StateCommitmentFixture()), partial.DefaultPathFinderVersion)
+		suite.Require().Error(err)
+	})
+
+	suite.Run("served by the gRPC server of the engine", func() {
+		eng := New(suite.log, Config{MaxMsgSize: grpcutils.DefaultMaxMsgSize}, nil, nil, nil, nil, nil, nil, nil, seals, flow.Mainnet, nil, nil, nil)
+		eng.handler.engine = mockEngine
+
+		listener := bufconn.Listen(1 << 20)
+		go func() {
+			_ = eng.server.Serve(listener)
+		}()
+		defer eng.server.Stop()
+
+		conn, err := grpc.Dial("bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		suite.Require().NoError(err)
+		defer conn.Close()
+		client := registers.NewClient(conn)
+
+		seals.On("FinalizedSealForBlock", id).Return(&flow.Seal{BlockID: id, FinalState: commit}, nil).Once()
+		mockEngine.On("GetRegistersWithProofAtBlockID", mock.Anything, registerIDs, id).Return(values, flow.StorageProof(proof), commit, nil).Once()
+
+		resp, err := client.GetRegistersWithProofAtBlockID(context.Background(), req)
+		suite.Require().NoError(err)
+		suite.Require().Len(resp.Values, 2)
+		suite.Require().Equal([]byte{1, 2, 3}, resp.Values[0])
+		suite.Require().Empty(resp.Values[1])
+		suite.Require().Equal([]byte(proof), resp.Proof)
+		suite.Require().Equal(commit[:], resp.StateCommitment)
+
+		// errors are returned with their status code
+		_, err = client.GetRegistersWithProofAtBlockID(context.Background(), &registers.GetRegistersWithProofAtBlockIDRequest{
+			BlockId: id[:],
+		})
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("unsealed block", func() {
+		seals.On("FinalizedSealForBlock", id).Return(nil, realstorage.ErrNotFound).Once()
+
+		_, err := handler.GetRegistersWithProofAtBlockID(context.Background(), req)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+	})
+
+	suite.Run("state commitment differing from the sealed one", func() {
+		seals.On("FinalizedSealForBlock", id).Return(&flow.Seal{BlockID: id, FinalState: unittest.StateCommitmentFixture()}, nil).Once()
+		mockEngine.On("GetRegistersWithProofAtBlockID", mock.Anything, registerIDs, id).Return(values, flow.StorageProof(proof), commit, nil).Once()
+
+		_, err := handler.GetRegistersWithProofAtBlockID(context.Background(), req)
+		suite.Require().Equal(codes.Internal, status.Code(err))
+	})
+
+	suite.Run("invalid request with no registers", func() {
+		_, err := handler.GetRegistersWithProofAtBlockID(context.Background(), &registers.GetRegistersWithProofAtBlockIDRequest{
+			BlockId: id[:],
+		})
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("invalid request with too many registers", func() {
+		_, err := handler.GetRegistersWithProofAtBlockID(context.Background(), &registers.GetRegistersWithProofAtBlockIDRequest{
+			BlockId:     id[:],
+			RegisterIds: make([]flow.RegisterID, registers.MaxRegisters+1),
+		})
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	mockEngine.AssertExpectations(suite.T())
+	seals.AssertExpectations(suite.T())
+}
+
 // TestGetTransactionResult tests the GetTransactionResult and GetTransactionResultByIndex API calls
 func (suite *Suite) TestGetTransactionResult() {
 
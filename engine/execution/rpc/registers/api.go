@@ -0,0 +1,204 @@
+// Package registers defines the batched register RPCs of execution nodes, GetRegistersAtBlockID and
+// GetRegistersWithProofAtBlockID.
+//
+// The pinned flow protobuf does not define the RPC yet, so its service is described by hand and
+// its messages are plain Go structs encoded with gob instead of protobuf. The service is served
+// by the gRPC server of execution nodes alongside the execution API, and clients must use the
+// Client of this package, which selects the gob codec for its calls.
+package registers
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// ServiceName is the full name of the gRPC service.
+	ServiceName = "flow.execution.RegistersWithProofAPI"
+
+	// CodecName is the name of the codec of the messages of the service, used as the content
+	// subtype of its calls.
+	CodecName = "flow-gob"
+
+	getRegistersAtBlockIDMethod          = "/" + ServiceName + "/GetRegistersAtBlockID"
+	getRegistersWithProofAtBlockIDMethod = "/" + ServiceName + "/GetRegistersWithProofAtBlockID"
+)
+
+// MaxRegisters is the maximum number of registers requested at once from the RPCs of the service.
+const MaxRegisters = 1000
+
+// GetRegistersAtBlockIDRequest is the request of GetRegistersAtBlockID.
+type GetRegistersAtBlockIDRequest struct {
+	BlockId     []byte
+	RegisterIds []flow.RegisterID
+}
+
+// GetRegistersAtBlockIDResponse is the response of GetRegistersAtBlockID.
+type GetRegistersAtBlockIDResponse struct {
+	// Values are the values of the requested registers, in the order of the request.  Registers
+	// which are not set have empty values.
+	Values [][]byte
+}
+
+// GetRegistersWithProofAtBlockIDRequest is the request of GetRegistersWithProofAtBlockID.
+type GetRegistersWithProofAtBlockIDRequest struct {
+	BlockId     []byte
+	RegisterIds []flow.RegisterID
+}
+
+// GetRegistersWithProofAtBlockIDResponse is the response of GetRegistersWithProofAtBlockID.
+type GetRegistersWithProofAtBlockIDResponse struct {
+	// Values are the values of the requested registers, in the order of the request.  Registers
+	// which are not set have empty values.
+	Values [][]byte
+
+	// Proof is the trie batch proof of the values, encoded by ledger.EncodeTrieBatchProof.
+	Proof []byte
+
+	// StateCommitment is the sealed state commitment of the block, which the proof is against.
+	StateCommitment []byte
+}
+
+// Server is the server API of the service.
+type Server interface {
+	// GetRegistersAtBlockID returns the values of the given registers at an executed block.
+	GetRegistersAtBlockID(context.Context, *GetRegistersAtBlockIDRequest) (*GetRegistersAtBlockIDResponse, error)
+
+	// GetRegistersWithProofAtBlockID returns the values of the given registers at a sealed block,
+	// with a batch proof of the values against the sealed state commitment of the block.
+	GetRegistersWithProofAtBlockID(context.Context, *GetRegistersWithProofAtBlockIDRequest) (*GetRegistersWithProofAtBlockIDResponse, error)
+}
+
+// Client is the client API of the service.
+type Client interface {
+	// GetRegistersAtBlockID returns the values of the given registers at an executed block.
+	GetRegistersAtBlockID(ctx context.Context, in *GetRegistersAtBlockIDRequest, opts ...grpc.CallOption) (*GetRegistersAtBlockIDResponse, error)
+
+	// GetRegistersWithProofAtBlockID returns the values of the given registers at a sealed block,
+	// with a batch proof of the values against the sealed state commitment of the block.
+	GetRegistersWithProofAtBlockID(ctx context.Context, in *GetRegistersWithProofAtBlockIDRequest, opts ...grpc.CallOption) (*GetRegistersWithProofAtBlockIDResponse, error)
+}
+
+// RegisterServer registers the service implementation with the gRPC server.
+func RegisterServer(s *grpc.Server, srv Server) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRegistersAtBlockID",
+			Handler:    getRegistersAtBlockIDHandler,
+		},
+		{
+			MethodName: "GetRegistersWithProofAtBlockID",
+			Handler:    getRegistersWithProofAtBlockIDHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+func getRegistersAtBlockIDHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegistersAtBlockIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Server).GetRegistersAtBlockID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: getRegistersAtBlockIDMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Server).GetRegistersAtBlockID(ctx, req.(*GetRegistersAtBlockIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func getRegistersWithProofAtBlockIDHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegistersWithProofAtBlockIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Server).GetRegistersWithProofAtBlockID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: getRegistersWithProofAtBlockIDMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Server).GetRegistersWithProofAtBlockID(ctx, req.(*GetRegistersWithProofAtBlockIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+type client struct {
+	cc grpc.ClientConnInterface
+}
+
+var _ Client = (*client)(nil)
+
+// NewClient returns a client of the service using the given connection.
+func NewClient(cc grpc.ClientConnInterface) Client {
+	return &client{cc: cc}
+}
+
+func (c *client) GetRegistersAtBlockID(ctx context.Context, in *GetRegistersAtBlockIDRequest, opts ...grpc.CallOption) (*GetRegistersAtBlockIDResponse, error) {
+	out := new(GetRegistersAtBlockIDResponse)
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(CodecName)}, opts...)
+	err := c.cc.Invoke(ctx, getRegistersAtBlockIDMethod, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *client) GetRegistersWithProofAtBlockID(ctx context.Context, in *GetRegistersWithProofAtBlockIDRequest, opts ...grpc.CallOption) (*GetRegistersWithProofAtBlockIDResponse, error) {
+	out := new(GetRegistersWithProofAtBlockIDResponse)
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(CodecName)}, opts...)
+	err := c.cc.Invoke(ctx, getRegistersWithProofAtBlockIDMethod, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec encodes the messages of the service with gob, which unlike JSON preserves the arbitrary
+// bytes of register owners and keys.
+type codec struct{}
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode message: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+	if err != nil {
+		return fmt.Errorf("could not decode message: %w", err)
+	}
+	return nil
+}
+
+func (codec) Name() string {
+	return CodecName
+}
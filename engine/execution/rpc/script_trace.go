@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flow-go/engine/execution/computation"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// ScriptRegisterReadsBinTrailer is the response trailer key of all the registers read by a
+	// traced script, in the order they were first read, encoded by EncodeScriptRegisterReads.
+	// Unlike ScriptRegisterReadsHeader, the registers are only truncated when their encoding
+	// exceeds MaxScriptTraceRegisterReadsBinSize.
+	ScriptRegisterReadsBinTrailer = "x-script-register-reads-bin"
+
+	// ScriptRegisterReadsBinTruncatedTrailer is the response trailer key of the total number of
+	// registers read by a traced script, only set when the registers returned in
+	// ScriptRegisterReadsBinTrailer are truncated.
+	ScriptRegisterReadsBinTruncatedTrailer = "x-script-register-reads-bin-truncated"
+)
+
+// MaxScriptTraceRegisterReadsBinSize is the approximate maximum size of the encoded registers read
+// by a traced script returned in the response trailer, which keeps the trailer well within the
+// default header list size limit of gRPC clients.
+const MaxScriptTraceRegisterReadsBinSize = 4 << 20
+
+// ScriptTraceTrailer returns the response trailer of the given script trace, with all registers
+// read by the script, unless their encoding exceeds MaxScriptTraceRegisterReadsBinSize.
+// No errors are expected during normal operation.
+func ScriptTraceTrailer(trace *computation.ScriptTrace) (metadata.MD, error) {
+	encoded, count, err := EncodeScriptRegisterReads(trace.RegisterReads, MaxScriptTraceRegisterReadsBinSize)
+	if err != nil {
+		return nil, err
+	}
+
+	md := metadata.MD{}
+	md.Set(ScriptRegisterReadsBinTrailer, string(encoded))
+	if count < len(trace.RegisterReads) {
+		md.Set(ScriptRegisterReadsBinTruncatedTrailer, strconv.Itoa(len(trace.RegisterReads)))
+	}
+	return md, nil
+}
+
+// EncodeScriptRegisterReads encodes the registers read by a script as the gzip compressed sequence
+// of the length prefixed owner and key of each register followed by the size of its value, all
+// lengths and sizes being unsigned varints. Registers are encoded until the compressed output
+// exceeds about maxSize bytes, and the number of registers encoded is returned.
+// No errors are expected during normal operation.
+func EncodeScriptRegisterReads(reads []computation.RegisterRead, maxSize int) ([]byte, int, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+
+	scratch := make([]byte, binary.MaxVarintLen64)
+	writeBytes := func(b []byte) error {
+		n := binary.PutUvarint(scratch, uint64(len(b)))
+		_, err := writer.Write(scratch[:n])
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(b)
+		return err
+	}
+
+	count := 0
+	for _, read := range reads {
+		if buf.Len() > maxSize {
+			break
+		}
+
+		err := writeBytes([]byte(read.ID.Owner))
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not encode register owner: %w", err)
+		}
+		err = writeBytes([]byte(read.ID.Key))
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not encode register key: %w", err)
+		}
+		n := binary.PutUvarint(scratch, uint64(read.Size))
+		_, err = writer.Write(scratch[:n])
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not encode register size: %w", err)
+		}
+		count++
+	}
+
+	err := writer.Close()
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not compress register reads: %w", err)
+	}
+
+	return buf.Bytes(), count, nil
+}
+
+// DecodeScriptRegisterReads decodes the registers read by a script encoded by
+// EncodeScriptRegisterReads.
+func DecodeScriptRegisterReads(encoded []byte) ([]computation.RegisterRead, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress register reads: %w", err)
+	}
+	defer gzipReader.Close()
+
+	reader := bufio.NewReader(gzipReader)
+	readBytes := func() ([]byte, error) {
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, length)
+		_, err = io.ReadFull(reader, b)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+
+	var reads []computation.RegisterRead
+	for {
+		owner, err := readBytes()
+		if err == io.EOF {
+			return reads, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode register owner: %w", err)
+		}
+		key, err := readBytes()
+		if err != nil {
+			return nil, fmt.Errorf("could not decode register key: %w", err)
+		}
+		size, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode register size: %w", err)
+		}
+
+		reads = append(reads, computation.RegisterRead{
+			ID:   flow.NewRegisterID(string(owner), string(key)),
+			Size: int(size),
+		})
+	}
+}
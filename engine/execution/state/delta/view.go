@@ -136,8 +136,15 @@ func (v *View) DropDelta() {
 	v.delta = NewDelta()
 }
 
+// AllRegisterIDs returns the ids of the registers touched by this view.  Unlike
+// Interactions, it does not compute the SPoCK secret, so the view may still be
+// written to afterward.
 func (v *View) AllRegisterIDs() []flow.RegisterID {
-	return v.Interactions().AllRegisterIDs()
+	ids := make([]flow.RegisterID, 0, len(v.regTouchSet))
+	for id := range v.regTouchSet {
+		ids = append(ids, id)
+	}
+	return ids
 }
 
 // UpdatedRegisterIDs returns a list of updated registers' ids.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/dgraph-io/badger/v2"
@@ -107,6 +108,20 @@ func RegisterIDToKey(reg flow.RegisterID) ledger.Key {
 	})
 }
 
+// KeyToRegisterID converts a ledger key into the register ID it was created from by RegisterIDToKey.
+func KeyToRegisterID(key ledger.Key) (flow.RegisterID, error) {
+	if len(key.KeyParts) != 2 ||
+		key.KeyParts[0].Type != KeyPartOwner ||
+		key.KeyParts[1].Type != KeyPartKey {
+		return flow.RegisterID{}, fmt.Errorf("key not in expected format %s", key.String())
+	}
+
+	return flow.NewRegisterID(
+		string(key.KeyParts[0].Value),
+		string(key.KeyParts[1].Value),
+	), nil
+}
+
 // NewExecutionState returns a new execution state access layer for the given ledger storage.
 func NewExecutionState(
 	ls ledger.Ledger,
@@ -172,13 +187,20 @@ func RegisterEntriesToKeysValues(
 	return keys, values
 }
 
+// LedgerGetRegister returns a function reading registers from the ledger at
+// the given state commitment.  The function is safe for concurrent use, so
+// that transactions can be executed in parallel on views of the same state.
 func LedgerGetRegister(ldg ledger.Ledger, commitment flow.StateCommitment) delta.GetRegisterFunc {
 
 	readCache := make(map[flow.RegisterID]flow.RegisterEntry)
+	readCacheLock := sync.RWMutex{}
 
 	return func(regID flow.RegisterID) (flow.RegisterValue, error) {
-		if value, ok := readCache[regID]; ok {
-			return value.Value, nil
+		readCacheLock.RLock()
+		entry, ok := readCache[regID]
+		readCacheLock.RUnlock()
+		if ok {
+			return entry.Value, nil
 		}
 
 		query, err := makeSingleValueQuery(commitment, regID)
@@ -199,7 +221,9 @@ func LedgerGetRegister(ldg ledger.Ledger, commitment flow.StateCommitment) delta
 		}
 
 		// don't cache value with len zero
+		readCacheLock.Lock()
 		readCache[regID] = flow.RegisterEntry{Key: regID, Value: value}
+		readCacheLock.Unlock()
 
 		return value, nil
 	}
@@ -29,6 +29,29 @@ type AccountKeyReader interface {
 		error,
 	)
 	AccountKeysCount(address common.Address) (uint64, error)
+
+	// GetAccountPublicKey retrieves the public key with the given index of an
+	// existing account, including its sequence number, which account keys
+	// exposed to Cadence do not have.
+	//
+	// Like GetAccountKey, this function returns a nil key with no errors, if a
+	// key doesn't exist at the given index.
+	GetAccountPublicKey(
+		address common.Address,
+		keyIndex uint64,
+	) (
+		*flow.AccountPublicKey,
+		error,
+	)
+
+	// GetAccountPublicKeys retrieves all public keys of an existing account,
+	// including their sequence numbers.
+	GetAccountPublicKeys(
+		address common.Address,
+	) (
+		[]flow.AccountPublicKey,
+		error,
+	)
 }
 
 type ParseRestrictedAccountKeyReader struct {
@@ -70,6 +93,34 @@ func (reader ParseRestrictedAccountKeyReader) AccountKeysCount(address common.Ad
 	)
 }
 
+func (reader ParseRestrictedAccountKeyReader) GetAccountPublicKey(
+	address common.Address,
+	keyIndex uint64,
+) (
+	*flow.AccountPublicKey,
+	error,
+) {
+	return parseRestrict2Arg1Ret(
+		reader.txnState,
+		trace.FVMEnvGetAccountKey,
+		reader.impl.GetAccountPublicKey,
+		address,
+		keyIndex)
+}
+
+func (reader ParseRestrictedAccountKeyReader) GetAccountPublicKeys(
+	address common.Address,
+) (
+	[]flow.AccountPublicKey,
+	error,
+) {
+	return parseRestrict1Arg1Ret(
+		reader.txnState,
+		trace.FVMEnvGetAccountKey,
+		reader.impl.GetAccountPublicKeys,
+		address)
+}
+
 type accountKeyReader struct {
 	tracer tracing.TracerSpan
 	meter  Meter
@@ -157,6 +208,78 @@ func (reader *accountKeyReader) AccountKeysCount(address common.Address) (uint64
 	return reader.accounts.GetPublicKeyCount(accountAddress)
 }
 
+func (reader *accountKeyReader) GetAccountPublicKey(
+	address common.Address,
+	keyIndex uint64,
+) (
+	*flow.AccountPublicKey,
+	error,
+) {
+	defer reader.tracer.StartChildSpan(trace.FVMEnvGetAccountKey).End()
+
+	formatErr := func(err error) (*flow.AccountPublicKey, error) {
+		return nil, fmt.Errorf("getting account public key failed: %w", err)
+	}
+
+	err := reader.meter.MeterComputation(ComputationKindGetAccountKey, 1)
+	if err != nil {
+		return formatErr(err)
+	}
+
+	// address verification is also done in this step
+	accountPublicKey, err := reader.accounts.GetPublicKey(
+		flow.Address(address),
+		keyIndex)
+	if err != nil {
+		if errors.IsAccountAccountPublicKeyNotFoundError(err) {
+			return nil, nil
+		}
+
+		return formatErr(err)
+	}
+
+	return &accountPublicKey, nil
+}
+
+func (reader *accountKeyReader) GetAccountPublicKeys(
+	address common.Address,
+) (
+	[]flow.AccountPublicKey,
+	error,
+) {
+	defer reader.tracer.StartChildSpan(trace.FVMEnvGetAccountKey).End()
+
+	formatErr := func(err error) ([]flow.AccountPublicKey, error) {
+		return nil, fmt.Errorf("getting account public keys failed: %w", err)
+	}
+
+	accountAddress := flow.Address(address)
+
+	count, err := reader.accounts.GetPublicKeyCount(accountAddress)
+	if err != nil {
+		return formatErr(err)
+	}
+
+	err = reader.meter.MeterComputation(
+		ComputationKindGetAccountKey,
+		uint(count))
+	if err != nil {
+		return formatErr(err)
+	}
+
+	publicKeys := make([]flow.AccountPublicKey, 0, count)
+	for i := uint64(0); i < count; i++ {
+		publicKey, err := reader.accounts.GetPublicKey(accountAddress, i)
+		if err != nil {
+			return formatErr(err)
+		}
+
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	return publicKeys, nil
+}
+
 func FlowToRuntimeAccountKey(flowKey flow.AccountPublicKey) (*runtime.AccountKey, error) {
 	signAlgo := crypto.CryptoToRuntimeSigningAlgorithm(flowKey.SignAlgo)
 	if signAlgo == runtime.SignatureAlgorithmUnknown {
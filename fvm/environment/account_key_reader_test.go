@@ -107,3 +107,21 @@ func TestAccountKeyReader_get_key_count(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestAccountKeyReader_get_public_keys(t *testing.T) {
+	t.Parallel()
+	address := bytesToAddress(1, 2, 3, 4)
+	reader := newDummyAccountKeyReader(t, 3)
+
+	key, err := reader.GetAccountPublicKey(address, 2)
+	require.NoError(t, err)
+	require.Equal(t, FakePublicKey{}.toAccountPublicKey(), *key)
+
+	key, err = reader.GetAccountPublicKey(address, 3)
+	require.NoError(t, err)
+	require.Nil(t, key)
+
+	keys, err := reader.GetAccountPublicKeys(address)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+}
@@ -71,6 +71,21 @@ type Environment interface {
 	// AccountInfo
 	GetAccount(address flow.Address) (*flow.Account, error)
 
+	// AccountKeyReader
+	GetAccountPublicKey(
+		address common.Address,
+		keyIndex uint64,
+	) (
+		*flow.AccountPublicKey,
+		error,
+	)
+	GetAccountPublicKeys(
+		address common.Address,
+	) (
+		[]flow.AccountPublicKey,
+		error,
+	)
+
 	AccountFreezer
 
 	// FlushPendingUpdates flushes pending updates from the stateful environment
@@ -166,13 +166,45 @@ func NewTransactionEnvironment(
 	params EnvironmentParams,
 	txnState *state.TransactionState,
 	derivedTxnData DerivedTransactionData,
+) *facadeEnvironment {
+	return newTransactionEnvironment(
+		tracer,
+		params,
+		txnState,
+		derivedTxnData,
+		NewMeter(txnState))
+}
+
+// NewCancellableTransactionEnvironment creates a transaction environment
+// which interrupts the transaction when the given context is done.
+func NewCancellableTransactionEnvironment(
+	ctx context.Context,
+	tracer tracing.TracerSpan,
+	params EnvironmentParams,
+	txnState *state.TransactionState,
+	derivedTxnData DerivedTransactionData,
+) *facadeEnvironment {
+	return newTransactionEnvironment(
+		tracer,
+		params,
+		txnState,
+		derivedTxnData,
+		NewCancellableMeter(ctx, txnState))
+}
+
+func newTransactionEnvironment(
+	tracer tracing.TracerSpan,
+	params EnvironmentParams,
+	txnState *state.TransactionState,
+	derivedTxnData DerivedTransactionData,
+	meter Meter,
 ) *facadeEnvironment {
 	env := newFacadeEnvironment(
 		tracer,
 		params,
 		txnState,
 		derivedTxnData,
-		NewMeter(txnState),
+		meter,
 	)
 
 	env.TransactionInfo = NewTransactionInfo(
@@ -5,6 +5,8 @@ package mock
 import (
 	common "github.com/onflow/cadence/runtime/common"
 
+	flow "github.com/onflow/flow-go/model/flow"
+
 	mock "github.com/stretchr/testify/mock"
 
 	stdlib "github.com/onflow/cadence/runtime/stdlib"
@@ -59,6 +61,52 @@ func (_m *AccountKeyReader) GetAccountKey(address common.Address, keyIndex int)
 	return r0, r1
 }
 
+// GetAccountPublicKey provides a mock function with given fields: address, keyIndex
+func (_m *AccountKeyReader) GetAccountPublicKey(address common.Address, keyIndex uint64) (*flow.AccountPublicKey, error) {
+	ret := _m.Called(address, keyIndex)
+
+	var r0 *flow.AccountPublicKey
+	if rf, ok := ret.Get(0).(func(common.Address, uint64) *flow.AccountPublicKey); ok {
+		r0 = rf(address, keyIndex)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.AccountPublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, uint64) error); ok {
+		r1 = rf(address, keyIndex)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountPublicKeys provides a mock function with given fields: address
+func (_m *AccountKeyReader) GetAccountPublicKeys(address common.Address) ([]flow.AccountPublicKey, error) {
+	ret := _m.Called(address)
+
+	var r0 []flow.AccountPublicKey
+	if rf, ok := ret.Get(0).(func(common.Address) []flow.AccountPublicKey); ok {
+		r0 = rf(address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flow.AccountPublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewAccountKeyReader interface {
 	mock.TestingT
 	Cleanup(func())
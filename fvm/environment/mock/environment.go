@@ -593,6 +593,52 @@ func (_m *Environment) GetAccountKey(address common.Address, index int) (*stdlib
 	return r0, r1
 }
 
+// GetAccountPublicKey provides a mock function with given fields: address, keyIndex
+func (_m *Environment) GetAccountPublicKey(address common.Address, keyIndex uint64) (*flow.AccountPublicKey, error) {
+	ret := _m.Called(address, keyIndex)
+
+	var r0 *flow.AccountPublicKey
+	if rf, ok := ret.Get(0).(func(common.Address, uint64) *flow.AccountPublicKey); ok {
+		r0 = rf(address, keyIndex)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.AccountPublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, uint64) error); ok {
+		r1 = rf(address, keyIndex)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountPublicKeys provides a mock function with given fields: address
+func (_m *Environment) GetAccountPublicKeys(address common.Address) ([]flow.AccountPublicKey, error) {
+	ret := _m.Called(address)
+
+	var r0 []flow.AccountPublicKey
+	if rf, ok := ret.Get(0).(func(common.Address) []flow.AccountPublicKey); ok {
+		r0 = rf(address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flow.AccountPublicKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBlockAtHeight provides a mock function with given fields: height
 func (_m *Environment) GetBlockAtHeight(height uint64) (stdlib.Block, bool, error) {
 	ret := _m.Called(height)
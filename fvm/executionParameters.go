@@ -70,7 +70,17 @@ func getBodyMeterParameters(
 	}
 
 	if overrides.MemoryLimit != nil {
-		procParams = procParams.WithMemoryLimit(*overrides.MemoryLimit)
+		memoryLimit := *overrides.MemoryLimit
+
+		// NOTE: The memory limit of scripts is capped by the memory limit of
+		// the context, which nodes may lower to bound the memory used by the
+		// scripts they execute.
+		if proc.Type() == ScriptProcedureType &&
+			proc.MemoryLimit(ctx) < memoryLimit {
+			memoryLimit = proc.MemoryLimit(ctx)
+		}
+
+		procParams = procParams.WithMemoryLimit(memoryLimit)
 	}
 
 	// NOTE: The memory limit (and interaction limit) may be overridden by the
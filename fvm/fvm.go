@@ -71,7 +71,21 @@ type VM interface {
 	GetAccount(Context, flow.Address, state.View) (*flow.Account, error)
 }
 
+// SpeculativeVM is a VM which can also run transactions speculatively
+type SpeculativeVM interface {
+	VM
+	RunSpeculatively(
+		Context,
+		*TransactionProcedure,
+		state.View,
+	) (
+		*SpeculativeTransaction,
+		error,
+	)
+}
+
 var _ VM = (*VirtualMachine)(nil)
+var _ SpeculativeVM = (*VirtualMachine)(nil)
 
 // A VirtualMachine augments the Cadence runtime with Flow host functionality.
 type VirtualMachine struct {
@@ -87,6 +101,145 @@ func (vm *VirtualMachine) Run(
 	proc Procedure,
 	v state.View,
 ) error {
+	derivedTxnData, err := vm.run(ctx, proc, v)
+	if err != nil {
+		return err
+	}
+
+	// Note: it is safe to skip committing derived data for non-normal
+	// transactions (i.e., bootstrap and script) since these do not invalidate
+	// derived data entries.
+	if proc.Type() == TransactionProcedureType {
+		// NOTE: It is not safe to ignore derivedTxnData' commit error for
+		// transactions that trigger derived data invalidation.
+		return derivedTxnData.Commit()
+	}
+
+	return nil
+}
+
+// RunSpeculatively runs a transaction like Run, up to the deduction of its
+// fees.  The transaction may run on a snapshot taken before its execution time
+// (i.e., its InitialSnapshotTxIndex may be smaller than its TxIndex).  The
+// caller is responsible for validating the transaction against the
+// transactions committed since its snapshot, and for finishing the returned
+// speculative transaction and committing its derived transaction data in
+// execution order.
+//
+// The fees are only deducted when the transaction is finished, since the fee
+// deduction of every transaction reads and writes the fee vault, which would
+// otherwise make all the transactions executed speculatively conflict.
+func (vm *VirtualMachine) RunSpeculatively(
+	ctx Context,
+	proc *TransactionProcedure,
+	v state.View,
+) (
+	*SpeculativeTransaction,
+	error,
+) {
+	executor, derivedTxnData, err := vm.newExecutor(ctx, proc, v)
+	if err != nil {
+		return nil, err
+	}
+
+	txnExecutor := executor.(*transactionExecutor)
+	txnExecutor.deferFeeDeduction = true
+
+	speculation := &SpeculativeTransaction{
+		executor:       txnExecutor,
+		derivedTxnData: derivedTxnData,
+	}
+
+	succeeded := false
+	defer func() {
+		// the executor is cleaned up when the transaction is finished or
+		// discarded, unless it failed (or panicked) here
+		if !succeeded {
+			executor.Cleanup()
+		}
+	}()
+
+	err = executor.Preprocess()
+	if err != nil {
+		return nil, err
+	}
+
+	err = executor.Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded = true
+	return speculation, nil
+}
+
+// SpeculativeTransaction is a transaction executed speculatively by
+// RunSpeculatively up to the deduction of its fees.  Once it is validated,
+// it must be finished in execution order, or discarded otherwise.
+type SpeculativeTransaction struct {
+	executor       *transactionExecutor
+	derivedTxnData *derived.DerivedTransactionData
+}
+
+// DerivedTransactionData returns the derived data of the transaction, which
+// must be validated before the transaction is finished and committed after.
+func (txn *SpeculativeTransaction) DerivedTransactionData() *derived.DerivedTransactionData {
+	return txn.derivedTxnData
+}
+
+// AllRegisterIDs returns the ids of the registers touched by the
+// transaction so far, which must be validated before it is finished.  Unlike
+// the registers of the view the transaction runs on, they include the
+// registers touched by the nested transactions not committed to the view yet.
+func (txn *SpeculativeTransaction) AllRegisterIDs() []flow.RegisterID {
+	return txn.executor.txnState.AllRegisterIDs()
+}
+
+// Finish deducts the fees of the transaction (or reverts the transaction and
+// deducts its fees if it failed), and completes its execution.  The fee
+// registers are read from the view of the transaction at the time Finish is
+// called, which must reflect all the transactions executed before it.
+func (txn *SpeculativeTransaction) Finish() error {
+	defer txn.executor.Cleanup()
+
+	return txn.executor.FinishDeferredFeeDeduction()
+}
+
+// Discard releases the resources of a transaction which is not committed.
+func (txn *SpeculativeTransaction) Discard() {
+	txn.executor.Cleanup()
+}
+
+func (vm *VirtualMachine) run(
+	ctx Context,
+	proc Procedure,
+	v state.View,
+) (
+	*derived.DerivedTransactionData,
+	error,
+) {
+	executor, derivedTxnData, err := vm.newExecutor(ctx, proc, v)
+	if err != nil {
+		return nil, err
+	}
+
+	err = Run(executor)
+	if err != nil {
+		return nil, err
+	}
+
+	return derivedTxnData, nil
+}
+
+func (vm *VirtualMachine) newExecutor(
+	ctx Context,
+	proc Procedure,
+	v state.View,
+) (
+	ProcedureExecutor,
+	*derived.DerivedTransactionData,
+	error,
+) {
 	derivedBlockData := ctx.DerivedBlockData
 	if derivedBlockData == nil {
 		derivedBlockData = derived.NewEmptyDerivedBlockDataWithTransactionOffset(
@@ -105,11 +258,11 @@ func (vm *VirtualMachine) Run(
 			proc.InitialSnapshotTime(),
 			proc.ExecutionTime())
 	default:
-		return fmt.Errorf("invalid proc type: %v", proc.Type())
+		return nil, nil, fmt.Errorf("invalid proc type: %v", proc.Type())
 	}
 
 	if err != nil {
-		return fmt.Errorf("error creating derived transaction data: %w", err)
+		return nil, nil, fmt.Errorf("error creating derived transaction data: %w", err)
 	}
 
 	txnState := state.NewTransactionState(
@@ -119,21 +272,7 @@ func (vm *VirtualMachine) Run(
 			WithMaxKeySizeAllowed(ctx.MaxStateKeySize).
 			WithMaxValueSizeAllowed(ctx.MaxStateValueSize))
 
-	err = Run(proc.NewExecutor(ctx, txnState, derivedTxnData))
-	if err != nil {
-		return err
-	}
-
-	// Note: it is safe to skip committing derived data for non-normal
-	// transactions (i.e., bootstrap and script) since these do not invalidate
-	// derived data entries.
-	if proc.Type() == TransactionProcedureType {
-		// NOTE: It is not safe to ignore derivedTxnData' commit error for
-		// transactions that trigger derived data invalidation.
-		return derivedTxnData.Commit()
-	}
-
-	return nil
+	return proc.NewExecutor(ctx, txnState, derivedTxnData), derivedTxnData, nil
 }
 
 // GetAccount returns an account by address or an error if none exists.
@@ -11,6 +11,7 @@ import (
 	"github.com/onflow/flow-go/fvm/derived"
 	"github.com/onflow/flow-go/fvm/environment"
 	"github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/fvm/meter"
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/hash"
@@ -27,6 +28,10 @@ type ScriptProcedure struct {
 	GasUsed        uint64
 	MemoryEstimate uint64
 	Err            errors.CodedError
+
+	// ComputationIntensities are the computation intensities metered by the
+	// script, also set when the script fails.
+	ComputationIntensities meter.MeteredComputationIntensities
 }
 
 func Script(code []byte) *ScriptProcedure {
@@ -201,6 +206,8 @@ func (executor *scriptExecutor) execute() error {
 		},
 		common.ScriptLocation(executor.proc.ID))
 
+	executor.proc.ComputationIntensities = executor.env.ComputationIntensities()
+
 	if err != nil {
 		return err
 	}
@@ -288,10 +288,12 @@ func (s *TransactionState) Resume(pausedState *State) {
 
 // AttachAndCommit commits the changes in the cached nested transaction state
 // to the current (nested) transaction.
+//
+// Note: cached states are shared by the transactions reading the same
+// derived data, which may run concurrently.  The cached state is therefore
+// merged as is, without being modified.
 func (s *TransactionState) AttachAndCommit(cachedState *State) error {
-	s.push(cachedState, nil)
-	_, err := s.mergeIntoParent()
-	return err
+	return s.currentState().MergeState(cachedState)
 }
 
 // RestartNestedTransaction merges all changes that belongs to the nested
@@ -401,6 +403,23 @@ func (s *TransactionState) UpdatedRegisters() flow.RegisterEntries {
 	return s.currentState().UpdatedRegisters()
 }
 
+// AllRegisterIDs returns the ids of the registers touched by the main
+// transaction and its uncommitted nested transactions.
+func (s *TransactionState) AllRegisterIDs() []flow.RegisterID {
+	touched := make(map[flow.RegisterID]struct{})
+	for _, frame := range s.nestedTransactions {
+		for _, id := range frame.state.View().AllRegisterIDs() {
+			touched[id] = struct{}{}
+		}
+	}
+
+	ids := make([]flow.RegisterID, 0, len(touched))
+	for id := range touched {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 // RunWithAllLimitsDisabled runs f with limits disabled
 func (s *TransactionState) RunWithAllLimitsDisabled(f func()) {
 	s.currentState().RunWithAllLimitsDisabled(f)
@@ -1,6 +1,8 @@
 package fvm
 
 import (
+	"context"
+
 	"github.com/onflow/flow-go/fvm/derived"
 	"github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/fvm/meter"
@@ -15,8 +17,6 @@ func Transaction(
 	return NewTransaction(txn.ID(), txnIndex, txn)
 }
 
-// TODO(patrick): pass in initial snapshot time when we start supporting
-// speculative pre-processing / execution.
 func NewTransaction(
 	txnId flow.Identifier,
 	txnIndex uint32,
@@ -31,12 +31,31 @@ func NewTransaction(
 	}
 }
 
+// NewSpeculativeTransaction creates a transaction executed speculatively on
+// the snapshot committed by the transactions before initialSnapshotTxIndex,
+// rather than on the snapshot committed by all the transactions before it.
+func NewSpeculativeTransaction(
+	txnId flow.Identifier,
+	initialSnapshotTxIndex uint32,
+	txnIndex uint32,
+	txnBody *flow.TransactionBody,
+) *TransactionProcedure {
+	proc := NewTransaction(txnId, txnIndex, txnBody)
+	proc.InitialSnapshotTxIndex = initialSnapshotTxIndex
+	return proc
+}
+
 type TransactionProcedure struct {
 	ID                     flow.Identifier
 	Transaction            *flow.TransactionBody
 	InitialSnapshotTxIndex uint32
 	TxIndex                uint32
 
+	// RequestContext is the context of the request executing the transaction
+	// outside of a block, if any, e.g. to simulate it.  The transaction is
+	// interrupted when the context is done.
+	RequestContext context.Context
+
 	Logs                   []string
 	Events                 flow.EventsList
 	ServiceEvents          flow.EventsList
@@ -73,6 +73,13 @@ type transactionExecutor struct {
 
 	cadenceRuntime  *reusableRuntime.ReusableCadenceRuntime
 	txnBodyExecutor runtime.Executor
+
+	// deferFeeDeduction stops the execution of the transaction body before
+	// the deduction of its fees, which is then finished by
+	// FinishDeferredFeeDeduction.  See VirtualMachine.RunSpeculatively.
+	deferFeeDeduction bool
+	feesDeferred      bool
+	invalidator       derived.TransactionInvalidator
 }
 
 func newTransactionExecutor(
@@ -88,11 +95,21 @@ func newTransactionExecutor(
 	ctx.TxId = proc.ID
 	ctx.TxBody = proc.Transaction
 
-	env := environment.NewTransactionEnvironment(
-		span,
-		ctx.EnvironmentParams,
-		txnState,
-		derivedTxnData)
+	var env environment.Environment
+	if proc.RequestContext != nil {
+		env = environment.NewCancellableTransactionEnvironment(
+			proc.RequestContext,
+			span,
+			ctx.EnvironmentParams,
+			txnState,
+			derivedTxnData)
+	} else {
+		env = environment.NewTransactionEnvironment(
+			span,
+			ctx.EnvironmentParams,
+			txnState,
+			derivedTxnData)
+	}
 
 	return &transactionExecutor{
 		TransactionExecutorParams: ctx.TransactionExecutorParams,
@@ -266,16 +283,51 @@ func (executor *transactionExecutor) abortPreprocessed() error {
 func (executor *transactionExecutor) ExecuteTransactionBody() error {
 	executor.txnState.Resume(executor.pausedState)
 
-	var invalidator derived.TransactionInvalidator
 	if !executor.errs.CollectedError() {
 
 		var txError error
-		invalidator, txError = executor.normalExecution()
+		executor.invalidator, txError = executor.normalExecution()
 		if executor.errs.Collect(txError).CollectedFailure() {
 			return executor.errs.ErrorOrNil()
 		}
 	}
 
+	if executor.deferFeeDeduction {
+		executor.feesDeferred = true
+		return nil
+	}
+
+	return executor.deductFeesAndCommit()
+}
+
+// FinishDeferredFeeDeduction finishes the execution of a transaction body
+// stopped before the deduction of its fees.  It does nothing if the execution
+// did not reach the fee deduction, e.g. because the authorization checks
+// failed.
+func (executor *transactionExecutor) FinishDeferredFeeDeduction() error {
+	if !executor.feesDeferred {
+		return nil
+	}
+	executor.feesDeferred = false
+
+	return executor.handleError(executor.deductFeesAndCommit(), "deducting fees")
+}
+
+// deductFeesAndCommit deducts the fees of the executed transaction body, or
+// reverts the transaction body and deducts the fees if it failed, and
+// commits the transaction.
+func (executor *transactionExecutor) deductFeesAndCommit() error {
+	if !executor.errs.CollectedError() {
+		var feesError error
+		executor.txnState.RunWithAllLimitsDisabled(func() {
+			feesError = executor.deductTransactionFees()
+		})
+		if executor.errs.Collect(feesError).CollectedFailure() {
+			return executor.errs.ErrorOrNil()
+		}
+	}
+
+	invalidator := executor.invalidator
 	if executor.errs.CollectedError() {
 		invalidator = nil
 		executor.txnState.RunWithAllLimitsDisabled(executor.errorExecution)
@@ -392,14 +444,6 @@ func (executor *transactionExecutor) normalExecution() (
 			maxTxFees)
 	})
 
-	if err != nil {
-		return
-	}
-
-	executor.txnState.RunWithAllLimitsDisabled(func() {
-		err = executor.deductTransactionFees()
-	})
-
 	return
 }
 
@@ -268,6 +268,58 @@ func ReadNode(reader io.Reader, scratch []byte, getNode func(nodeIndex uint64) (
 	return n, nil
 }
 
+// ReadLeafPayload reads a serialized node from reader, and returns its payload if it is a leaf node.
+// It returns false if the node is an interim node. Unlike ReadNode, the children of interim nodes
+// are not looked up, so nodes can be read without keeping the previously read nodes in memory.
+func ReadLeafPayload(reader io.Reader, scratch []byte) (*ledger.Payload, bool, error) {
+
+	// minBufSize should be large enough for interim node and leaf node with small payload.
+	const minBufSize = 1024
+
+	if len(scratch) < minBufSize {
+		scratch = make([]byte, minBufSize)
+	}
+
+	// fixLengthSize is the size of shared data of leaf node and interim node
+	const fixLengthSize = encNodeTypeSize + encHeightSize + encHashSize
+
+	_, err := io.ReadFull(reader, scratch[:fixLengthSize])
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read fixed-length part of serialized node: %w", err)
+	}
+
+	// Decode node type (1 byte)
+	nType := scratch[0]
+
+	switch nType {
+	case byte(leafNodeType):
+		// Read and discard path (32 bytes)
+		_, err := io.ReadFull(reader, scratch[:encPathSize])
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read path of serialized node: %w", err)
+		}
+
+		payload, err := readPayloadFromReader(reader, scratch)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read and decode payload of serialized node: %w", err)
+		}
+
+		return payload, true, nil
+
+	case byte(interimNodeType):
+		// Read and discard left and right child index (16 bytes)
+		_, err = io.ReadFull(reader, scratch[:encNodeIndexSize*2])
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read child index of serialized node: %w", err)
+		}
+
+		return nil, false, nil
+
+	default:
+		return nil, false, fmt.Errorf("failed to decode node type %d", nType)
+	}
+}
+
 // EncodeTrie encodes trie in the following format:
 // - root node index (8 byte)
 // - allocated reg count (8 byte)
@@ -356,6 +408,29 @@ func ReadTrie(reader io.Reader, scratch []byte, getNode func(nodeIndex uint64) (
 	return mtrie, nil
 }
 
+// ReadTrieRootHash reads a serialized trie from reader and returns its root hash, without
+// looking up its root node.
+func ReadTrieRootHash(reader io.Reader, scratch []byte) (ledger.RootHash, error) {
+
+	if len(scratch) < encodedTrieSize {
+		scratch = make([]byte, encodedTrieSize)
+	}
+
+	_, err := io.ReadFull(reader, scratch[:encodedTrieSize])
+	if err != nil {
+		return ledger.RootHash{}, fmt.Errorf("failed to read serialized trie: %w", err)
+	}
+
+	// Skip root node index, reg count and reg size, then decode root node hash
+	pos := encNodeIndexSize + encRegCountSize + encRegSizeSize
+	rootHash, err := hash.ToHash(scratch[pos : pos+encHashSize])
+	if err != nil {
+		return ledger.RootHash{}, fmt.Errorf("failed to decode hash of serialized trie: %w", err)
+	}
+
+	return ledger.RootHash(rootHash), nil
+}
+
 // readPayloadFromReader reads and decodes payload from reader.
 // Returned payload is a copy.
 func readPayloadFromReader(reader io.Reader, scratch []byte) (*ledger.Payload, error) {
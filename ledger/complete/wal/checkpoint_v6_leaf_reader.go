@@ -0,0 +1,255 @@
+package wal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+)
+
+// ReadLeafPayloadsFromCheckpointV6 reads the payloads of all leaf nodes of the V6 checkpoint with
+// the given file name in dir, without reconstructing its tries, so the memory used does not grow
+// with the size of the execution state. Since the leaf nodes of different tries can not be told
+// apart without reconstructing the tries, the checkpoint must contain a single trie, which must
+// have the given root hash. Both are checked before any payload is processed.
+//
+// The payloads are passed to processPayloads in batches of at most batchSize payloads, in no
+// particular order. The batch is reused once processPayloads returns, so it must not be retained.
+func ReadLeafPayloadsFromCheckpointV6(
+	dir string,
+	fileName string,
+	rootHash ledger.RootHash,
+	batchSize int,
+	processPayloads func(payloads []*ledger.Payload) error,
+	logger *zerolog.Logger,
+) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+
+	headerPath := filePathCheckpointHeader(dir, fileName)
+
+	lg := logger.With().Str("checkpoint_file", headerPath).Logger()
+	lg.Info().Msgf("reading leaf payloads of v6 checkpoint file")
+
+	subtrieChecksums, topTrieChecksum, err := readCheckpointHeader(headerPath, logger)
+	if err != nil {
+		return fmt.Errorf("could not read header: %w", err)
+	}
+
+	err = allPartFileExist(dir, fileName, len(subtrieChecksums))
+	if err != nil {
+		return fmt.Errorf("fail to check all checkpoint part file exist: %w", err)
+	}
+
+	// the top level file is read first, so the trie of the checkpoint is checked before any
+	// payload is processed
+	topLevelPayloads, rootHashes, expectedSubtrieNodeCount, err := readTopLevelLeafPayloads(dir, fileName, topTrieChecksum, &lg)
+	if err != nil {
+		return fmt.Errorf("could not read top level nodes or tries: %w", err)
+	}
+
+	if len(rootHashes) != 1 {
+		return fmt.Errorf("checkpoint contains %d tries, but leaf payloads can only be read from a checkpoint with a single trie", len(rootHashes))
+	}
+	if rootHashes[0] != rootHash {
+		return fmt.Errorf("checkpoint trie has root hash %v, expected %v", rootHashes[0], rootHash)
+	}
+
+	batch := make([]*ledger.Payload, 0, batchSize)
+	addPayload := func(payload *ledger.Payload) error {
+		batch = append(batch, payload)
+		if len(batch) < batchSize {
+			return nil
+		}
+		err := processPayloads(batch)
+		batch = batch[:0]
+		return err
+	}
+
+	subtrieNodeCount := uint64(0)
+	for i, checksum := range subtrieChecksums {
+		nodesCount, err := readSubTrieLeafPayloads(dir, fileName, i, checksum, addPayload, &lg)
+		if err != nil {
+			return fmt.Errorf("fail to read %v-th subtrie: %w", i, err)
+		}
+		subtrieNodeCount += nodesCount
+	}
+
+	if subtrieNodeCount != expectedSubtrieNodeCount {
+		return fmt.Errorf("mismatch subtrie node count, top level file has (%v), but read (%v)",
+			expectedSubtrieNodeCount, subtrieNodeCount)
+	}
+
+	for _, payload := range topLevelPayloads {
+		err := addPayload(payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(batch) > 0 {
+		err := processPayloads(batch)
+		if err != nil {
+			return err
+		}
+	}
+
+	lg.Info().Msgf("finish reading leaf payloads of v6 checkpoint file")
+
+	return nil
+}
+
+// readSubTrieLeafPayloads reads the subtrie file with the given index, passing the payload of each
+// of its leaf nodes to addPayload. It returns the number of nodes of the file.
+func readSubTrieLeafPayloads(
+	dir string,
+	fileName string,
+	index int,
+	checksum uint32,
+	addPayload func(*ledger.Payload) error,
+	logger *zerolog.Logger,
+) (uint64, error) {
+	var count uint64
+	err := processCheckpointSubTrie(dir, fileName, index, checksum, logger,
+		func(reader *Crc32Reader, nodesCount uint64) error {
+			scratch := make([]byte, 1024*4) // must not be less than 1024
+			logging := logProgress(fmt.Sprintf("reading leaf payloads of %v-th sub trie", index), int(nodesCount), logger)
+
+			for i := uint64(1); i <= nodesCount; i++ {
+				payload, isLeaf, err := flattener.ReadLeafPayload(reader, scratch)
+				if err != nil {
+					return fmt.Errorf("cannot read node %d: %w", i, err)
+				}
+				if isLeaf {
+					err = addPayload(payload)
+					if err != nil {
+						return err
+					}
+				}
+				logging(i)
+			}
+
+			count = nodesCount
+			return nil
+		})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// readTopLevelLeafPayloads reads the top level trie file, returning the payloads of its leaf nodes,
+// the root hashes of its tries and the total node count of the subtrie files.
+func readTopLevelLeafPayloads(dir string, fileName string, topTrieChecksum uint32, logger *zerolog.Logger) (
+	payloads []*ledger.Payload,
+	rootHashes []ledger.RootHash,
+	subtrieNodeCount uint64,
+	errToReturn error,
+) {
+	filepath, _ := filePathTopTries(dir, fileName)
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("could not open file %v: %w", filepath, err)
+	}
+	defer func(file *os.File) {
+		evictErr := evictFileFromLinuxPageCache(file, false, logger)
+		if evictErr != nil {
+			logger.Warn().Msgf("failed to evict top trie file %s from Linux page cache: %s", filepath, evictErr)
+			// No need to return this error because it's possible to continue normal operations.
+		}
+		errToReturn = closeAndMergeError(file, errToReturn)
+	}(file)
+
+	// read and validate magic bytes and version
+	err = validateFileHeader(MagicBytesCheckpointToptrie, VersionV6, file)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	topLevelNodesCount, triesCount, expectedSum, err := readTopTriesFooter(file)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("could not read top tries footer: %w", err)
+	}
+
+	if topTrieChecksum != expectedSum {
+		return nil, nil, 0, fmt.Errorf("mismatch top trie checksum, header file has %v, toptrie file has %v",
+			topTrieChecksum, expectedSum)
+	}
+
+	// restart from the beginning of the file, make sure CRC32Reader has seen all the bytes
+	// in order to compute the correct checksum
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("could not seek to 0: %w", err)
+	}
+
+	reader := NewCRC32Reader(bufio.NewReaderSize(file, defaultBufioReadSize))
+
+	// read version again for calculating checksum
+	_, _, err = readFileHeader(reader)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("could not read version for top trie: %w", err)
+	}
+
+	scratch := make([]byte, 1024*4) // must not be less than 1024
+
+	_, err = io.ReadFull(reader, scratch[:encNodeCountSize])
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("could not read subtrie node count: %w", err)
+	}
+	subtrieNodeCount, err = decodeNodeCount(scratch[:encNodeCountSize])
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("could not decode node count: %w", err)
+	}
+
+	for i := uint64(1); i <= topLevelNodesCount; i++ {
+		payload, isLeaf, err := flattener.ReadLeafPayload(reader, scratch)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("cannot read node at index %d: %w", i, err)
+		}
+		if isLeaf {
+			payloads = append(payloads, payload)
+		}
+	}
+
+	rootHashes = make([]ledger.RootHash, triesCount)
+	for i := uint16(0); i < triesCount; i++ {
+		rootHashes[i], err = flattener.ReadTrieRootHash(reader, scratch)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("cannot read root trie at index %d: %w", i, err)
+		}
+	}
+
+	// read footer and discard, since we only care about checksum
+	_, err = io.ReadFull(reader, scratch[:encNodeCountSize+encTrieCountSize])
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("cannot read footer: %w", err)
+	}
+
+	actualSum := reader.Crc32()
+
+	if actualSum != expectedSum {
+		return nil, nil, 0, fmt.Errorf("invalid checksum in top level trie, expected %v, actual %v",
+			expectedSum, actualSum)
+	}
+
+	// read the checksum and discard, since we only care about whether ensureReachedEOF
+	_, err = io.ReadFull(reader, scratch[:crc32SumSize])
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("could not read checksum from top trie file: %w", err)
+	}
+
+	err = ensureReachedEOF(reader)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("fail to read top trie file: %w", err)
+	}
+
+	return payloads, rootHashes, subtrieNodeCount, nil
+}
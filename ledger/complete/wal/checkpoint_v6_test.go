@@ -257,6 +257,76 @@ func TestWriteAndReadCheckpointV6MultipleTries(t *testing.T) {
 	})
 }
 
+func TestReadLeafPayloadsFromCheckpointV6(t *testing.T) {
+	readPayloads := func(t *testing.T, dir string, fileName string, rootHash ledger.RootHash, batchSize int) ([]ledger.Payload, error) {
+		logger := unittest.Logger()
+		var payloads []ledger.Payload
+		err := ReadLeafPayloadsFromCheckpointV6(dir, fileName, rootHash, batchSize, func(batch []*ledger.Payload) error {
+			require.NotEmpty(t, batch)
+			require.LessOrEqual(t, len(batch), batchSize)
+			for _, payload := range batch {
+				payloads = append(payloads, *payload)
+			}
+			return nil
+		}, &logger)
+		return payloads, err
+	}
+
+	t.Run("payloads of subtries are read in batches", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			paths, payloads := randNPathPayloads(1000)
+			tr, _, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads, true)
+			require.NoError(t, err)
+
+			logger := unittest.Logger()
+			require.NoError(t, StoreCheckpointV6Concurrently([]*trie.MTrie{tr}, dir, "checkpoint", &logger))
+
+			read, err := readPayloads(t, dir, "checkpoint", tr.RootHash(), 100)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tr.AllPayloads(), read)
+		})
+	})
+
+	t.Run("payloads of top level leaves are read", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			// the paths differ at the first bit, so the trie only has leaves above the subtrie level
+			paths := []ledger.Path{testutils.PathByUint8(0), testutils.PathByUint8(255)}
+			payloads := []ledger.Payload{*testutils.LightPayload8('A', 'a'), *testutils.LightPayload8('B', 'b')}
+			tr, _, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads, true)
+			require.NoError(t, err)
+
+			logger := unittest.Logger()
+			require.NoError(t, StoreCheckpointV6Concurrently([]*trie.MTrie{tr}, dir, "checkpoint", &logger))
+
+			read, err := readPayloads(t, dir, "checkpoint", tr.RootHash(), 1)
+			require.NoError(t, err)
+			require.ElementsMatch(t, payloads, read)
+		})
+	})
+
+	t.Run("checkpoint with another root hash", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			tries := createSimpleTrie(t)
+			logger := unittest.Logger()
+			require.NoError(t, StoreCheckpointV6Concurrently(tries[1:], dir, "checkpoint", &logger))
+
+			_, err := readPayloads(t, dir, "checkpoint", tries[0].RootHash(), 10)
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("checkpoint with multiple tries", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			tries := createSimpleTrie(t)
+			logger := unittest.Logger()
+			require.NoError(t, StoreCheckpointV6Concurrently(tries, dir, "checkpoint", &logger))
+
+			_, err := readPayloads(t, dir, "checkpoint", tries[1].RootHash(), 10)
+			require.Error(t, err)
+		})
+	})
+}
+
 // test running checkpointing twice will produce the same checkpoint file
 func TestCheckpointV6IsDeterminstic(t *testing.T) {
 	unittest.RunWithTempDir(t, func(dir string) {
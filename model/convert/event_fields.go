@@ -0,0 +1,90 @@
+package convert
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/json"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// EventContractAddress returns the address of the account the contract that emitted the event is
+// deployed to. Events of type A.<address>.<contract>.<event> are emitted by contracts, all other
+// events, like the flow.* protocol events, are not.
+func EventContractAddress(eventType flow.EventType) (flow.Address, bool) {
+	parts := strings.Split(string(eventType), ".")
+	if len(parts) != 4 || parts[0] != "A" {
+		return flow.EmptyAddress, false
+	}
+
+	b, err := hex.DecodeString(parts[1])
+	if err != nil || len(b) != flow.AddressLength {
+		return flow.EmptyAddress, false
+	}
+
+	return flow.BytesToAddress(b), true
+}
+
+// EventFieldValues decodes the JSON-CDC encoded payload of the event, and returns the string
+// representation of all top-level fields that hold simple values, by field name. Optional values
+// are unwrapped, and fields that are nil or hold composite, array, dictionary or other non-simple
+// values are omitted.
+//
+// Addresses are represented in hex without prefix, strings and characters by their raw value, and
+// all other values by their Cadence string representation, e.g. "10.00000000" for a UFix64.
+func EventFieldValues(event flow.Event) (map[string]string, error) {
+	payload, err := json.Decode(nil, event.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal event payload: %w", err)
+	}
+
+	cdcEvent, ok := payload.(cadence.Event)
+	if !ok {
+		return nil, invalidCadenceTypeError("payload", payload, cadence.Event{})
+	}
+
+	if cdcEvent.EventType == nil || len(cdcEvent.EventType.Fields) != len(cdcEvent.Fields) {
+		return nil, fmt.Errorf("event fields do not match the event type")
+	}
+
+	values := make(map[string]string, len(cdcEvent.Fields))
+	for i, field := range cdcEvent.EventType.Fields {
+		value, ok := simpleValueString(cdcEvent.Fields[i])
+		if ok {
+			values[field.Identifier] = value
+		}
+	}
+
+	return values, nil
+}
+
+// simpleValueString returns the string representation of a simple cadence value, and false for
+// all other values.
+func simpleValueString(value cadence.Value) (string, bool) {
+	for {
+		optional, ok := value.(cadence.Optional)
+		if !ok {
+			break
+		}
+		if optional.Value == nil {
+			return "", false
+		}
+		value = optional.Value
+	}
+
+	switch v := value.(type) {
+	case cadence.Address:
+		return flow.Address(v).Hex(), true
+	case cadence.String:
+		return string(v), true
+	case cadence.Character:
+		return string(v), true
+	case cadence.Bool, cadence.NumberValue:
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
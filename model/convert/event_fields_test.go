@@ -0,0 +1,48 @@
+package convert_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/convert"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestEventContractAddress(t *testing.T) {
+	contract := unittest.RandomAddressFixture()
+
+	address, ok := convert.EventContractAddress(flow.EventType("A." + contract.Hex() + ".FlowToken.TokensDeposited"))
+	require.True(t, ok)
+	assert.Equal(t, contract, address)
+
+	for _, eventType := range []flow.EventType{
+		flow.EventAccountCreated,
+		"A.invalid.FlowToken.TokensDeposited",
+		"A.0102.FlowToken.TokensDeposited",
+		"A." + flow.EventType(contract.Hex()) + ".TokensDeposited",
+	} {
+		_, ok := convert.EventContractAddress(eventType)
+		assert.False(t, ok, "unexpected address for event type %s", eventType)
+	}
+}
+
+func TestEventFieldValues(t *testing.T) {
+	contract := unittest.RandomAddressFixture()
+	recipient := unittest.RandomAddressFixture()
+
+	event := unittest.TokensDepositedEventFixture(contract, recipient, 1_50000000, unittest.IdentifierFixture(), 0, 0)
+
+	values, err := convert.EventFieldValues(event)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"amount": "1.50000000",
+		"to":     recipient.Hex(),
+	}, values)
+
+	event.Payload = []byte("invalid")
+	_, err = convert.EventFieldValues(event)
+	assert.Error(t, err)
+}
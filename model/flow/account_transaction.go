@@ -0,0 +1,72 @@
+package flow
+
+// TransactionRoles is the set of roles an account has in a transaction. An account can have
+// several roles in the same transaction, e.g. when it proposes and pays for the transaction.
+type TransactionRoles uint8
+
+const (
+	TransactionRoleProposer TransactionRoles = 1 << iota
+	TransactionRolePayer
+	TransactionRoleAuthorizer
+)
+
+// Has returns true if the set contains all the given roles.
+func (r TransactionRoles) Has(roles TransactionRoles) bool {
+	return r&roles == roles
+}
+
+// Strings returns the names of the roles in the set, in the order proposer, payer, authorizer.
+func (r TransactionRoles) Strings() []string {
+	names := make([]string, 0, 3)
+	if r.Has(TransactionRoleProposer) {
+		names = append(names, "proposer")
+	}
+	if r.Has(TransactionRolePayer) {
+		names = append(names, "payer")
+	}
+	if r.Has(TransactionRoleAuthorizer) {
+		names = append(names, "authorizer")
+	}
+	return names
+}
+
+// AccountTransaction is a transaction an account participated in as proposer, payer or
+// authorizer, and the height of the finalized block that includes the transaction.
+type AccountTransaction struct {
+	Address       Address
+	BlockHeight   uint64
+	TransactionID Identifier
+	Roles         TransactionRoles
+}
+
+// AccountTransactionCursor is the position of an account transaction within the transactions of
+// the account, which are ordered by block height and transaction ID.
+type AccountTransactionCursor struct {
+	BlockHeight   uint64
+	TransactionID Identifier
+}
+
+// AccountTransactionsForTransaction returns the accounts that participated in the transaction, and
+// their roles, for the block at the given height.
+func AccountTransactionsForTransaction(tx *TransactionBody, height uint64) []AccountTransaction {
+	txID := tx.ID()
+
+	roles := make(map[Address]TransactionRoles)
+	roles[tx.ProposalKey.Address] |= TransactionRoleProposer
+	roles[tx.Payer] |= TransactionRolePayer
+	for _, authorizer := range tx.Authorizers {
+		roles[authorizer] |= TransactionRoleAuthorizer
+	}
+
+	accountTxs := make([]AccountTransaction, 0, len(roles))
+	for address, accountRoles := range roles {
+		accountTxs = append(accountTxs, AccountTransaction{
+			Address:       address,
+			BlockHeight:   height,
+			TransactionID: txID,
+			Roles:         accountRoles,
+		})
+	}
+
+	return accountTxs
+}
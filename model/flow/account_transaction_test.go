@@ -0,0 +1,35 @@
+package flow_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestAccountTransactionsForTransaction(t *testing.T) {
+	proposer := unittest.RandomAddressFixture()
+	payer := unittest.RandomAddressFixture()
+	authorizer := unittest.RandomAddressFixture()
+
+	tx := flow.NewTransactionBody().
+		SetScript([]byte("transaction {}")).
+		SetProposalKey(proposer, 0, 0).
+		SetPayer(payer).
+		AddAuthorizer(proposer).
+		AddAuthorizer(authorizer)
+
+	accountTxs := flow.AccountTransactionsForTransaction(tx, 10)
+	assert.ElementsMatch(t, []flow.AccountTransaction{
+		{Address: proposer, BlockHeight: 10, TransactionID: tx.ID(), Roles: flow.TransactionRoleProposer | flow.TransactionRoleAuthorizer},
+		{Address: payer, BlockHeight: 10, TransactionID: tx.ID(), Roles: flow.TransactionRolePayer},
+		{Address: authorizer, BlockHeight: 10, TransactionID: tx.ID(), Roles: flow.TransactionRoleAuthorizer},
+	}, accountTxs)
+
+	roles := flow.TransactionRoleProposer | flow.TransactionRoleAuthorizer
+	assert.True(t, roles.Has(flow.TransactionRoleAuthorizer))
+	assert.False(t, roles.Has(flow.TransactionRolePayer|flow.TransactionRoleProposer))
+	assert.Equal(t, []string{"proposer", "authorizer"}, roles.Strings())
+}
@@ -0,0 +1,386 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/derived"
+	"github.com/onflow/flow-go/fvm/environment"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/fvm/meter"
+	reusableRuntime "github.com/onflow/flow-go/fvm/runtime"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+const (
+	// DefaultScriptExecutionTimeLimit is the default maximum time a script may run.
+	DefaultScriptExecutionTimeLimit = 10 * time.Second
+
+	// reusableCadenceRuntimePoolSize is the number of cadence runtimes kept for reuse.
+	reusableCadenceRuntimePoolSize = 1000
+)
+
+// ScriptExecutionError is returned when a script was executed, but failed, for example because
+// it is invalid or encountered a runtime error.
+type ScriptExecutionError struct {
+	err error
+}
+
+func NewScriptExecutionError(err error) ScriptExecutionError {
+	return ScriptExecutionError{err: err}
+}
+
+func (e ScriptExecutionError) Error() string {
+	return e.err.Error()
+}
+
+func (e ScriptExecutionError) Unwrap() error {
+	return e.err
+}
+
+// IsScriptExecutionError returns whether the given error is a ScriptExecutionError.
+func IsScriptExecutionError(err error) bool {
+	var scriptErr ScriptExecutionError
+	return errors.As(err, &scriptErr)
+}
+
+// Scripts executes scripts and reads accounts using the execution state from the local register
+// index, instead of requesting them from execution nodes.
+type Scripts struct {
+	log                      zerolog.Logger
+	vm                       fvm.VM
+	vmCtx                    fvm.Context
+	derivedChainData         *derived.DerivedChainData
+	headers                  storage.Headers
+	registers                storage.RegisterIndex
+	scriptExecutionTimeLimit time.Duration
+}
+
+// NewScripts creates a new script executor, using the given fvm options to create the context
+// scripts are executed in.
+// No errors are expected during normal operation.
+func NewScripts(
+	log zerolog.Logger,
+	fvmOptions []fvm.Option,
+	headers storage.Headers,
+	registers storage.RegisterIndex,
+	scriptExecutionTimeLimit time.Duration,
+) (*Scripts, error) {
+	derivedChainData, err := derived.NewDerivedChainData(derived.DefaultDerivedDataCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create derived data cache: %w", err)
+	}
+
+	options := make([]fvm.Option, 0, len(fvmOptions)+1)
+	options = append(options, fvmOptions...)
+	options = append(options,
+		fvm.WithReusableCadenceRuntimePool(
+			reusableRuntime.NewReusableCadenceRuntimePool(
+				reusableCadenceRuntimePoolSize,
+				runtime.Config{})),
+	)
+
+	return &Scripts{
+		log:                      log.With().Str("module", "script_executor").Logger(),
+		vm:                       fvm.NewVirtualMachine(),
+		vmCtx:                    fvm.NewContext(options...),
+		derivedChainData:         derivedChainData,
+		headers:                  headers,
+		registers:                registers,
+		scriptExecutionTimeLimit: scriptExecutionTimeLimit,
+	}, nil
+}
+
+// ExecuteAtBlockHeight executes the script against the execution state at the given height, and
+// returns the JSON-CDC encoded result.
+// Expected errors:
+// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+// - storage.ErrNotBootstrapped if the register index is not bootstrapped yet
+// - storage.ErrNotFound if the block at the height is not known
+// - ScriptExecutionError if the script failed
+func (s *Scripts) ExecuteAtBlockHeight(
+	ctx context.Context,
+	code []byte,
+	arguments [][]byte,
+	height uint64,
+) ([]byte, error) {
+	header, view, err := s.viewAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, s.scriptExecutionTimeLimit)
+	defer cancel()
+
+	script := fvm.NewScriptWithContextAndArgs(code, requestCtx, arguments...)
+	blockCtx := s.blockContext(header)
+
+	err = func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				s.log.Error().
+					Hex("script_hex", code).
+					Interface("recovered", r).
+					Msg("script execution caused runtime panic")
+
+				err = fmt.Errorf("cadence runtime error: %s", r)
+			}
+		}()
+
+		return s.vm.Run(blockCtx, script, view)
+	}()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute script (internal error): %w", err)
+	}
+
+	if script.Err != nil {
+		return nil, NewScriptExecutionError(fmt.Errorf("failed to execute script at block (%s): %w", header.ID(), script.Err))
+	}
+
+	encodedValue, err := jsoncdc.Encode(script.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode runtime value: %w", err)
+	}
+
+	return encodedValue, nil
+}
+
+// GetAccountAtBlockHeight returns the account with the given address at the given height.
+// Expected errors:
+// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+// - storage.ErrNotBootstrapped if the register index is not bootstrapped yet
+// - storage.ErrNotFound if the block at the height or the account is not known
+func (s *Scripts) GetAccountAtBlockHeight(
+	_ context.Context,
+	address flow.Address,
+	height uint64,
+) (*flow.Account, error) {
+	header, view, err := s.viewAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.vm.GetAccount(s.blockContext(header), address, view)
+	if err != nil {
+		if fvmerrors.IsAccountNotFoundError(err) {
+			return nil, fmt.Errorf("account (%s) not found at block (%s): %w", address, header.ID(), storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get account (%s) at block (%s): %w", address, header.ID(), err)
+	}
+
+	return account, nil
+}
+
+// GetAccountKeysAtBlockHeight returns the public keys of the account with the given address at the
+// given height. Unlike GetAccountAtBlockHeight, the account's contracts are not loaded.
+// Expected errors:
+// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+// - storage.ErrNotBootstrapped if the register index is not bootstrapped yet
+// - storage.ErrNotFound if the block at the height or the account is not known
+func (s *Scripts) GetAccountKeysAtBlockHeight(
+	ctx context.Context,
+	address flow.Address,
+	height uint64,
+) ([]flow.AccountPublicKey, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, s.scriptExecutionTimeLimit)
+	defer cancel()
+
+	header, keyReader, err := s.accountEnvironment(requestCtx, address, height)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := keyReader.GetAccountPublicKeys(common.Address(address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keys of account (%s) at block (%s): %w", address, header.ID(), err)
+	}
+
+	return keys, nil
+}
+
+// GetAccountKeyAtBlockHeight returns the public key with the given index of the account with the
+// given address at the given height.
+// Expected errors:
+// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+// - storage.ErrNotBootstrapped if the register index is not bootstrapped yet
+// - storage.ErrNotFound if the block at the height, the account or the key is not known
+func (s *Scripts) GetAccountKeyAtBlockHeight(
+	ctx context.Context,
+	address flow.Address,
+	keyIndex uint64,
+	height uint64,
+) (*flow.AccountPublicKey, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, s.scriptExecutionTimeLimit)
+	defer cancel()
+
+	header, keyReader, err := s.accountEnvironment(requestCtx, address, height)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keyReader.GetAccountPublicKey(common.Address(address), keyIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key %d of account (%s) at block (%s): %w", keyIndex, address, header.ID(), err)
+	}
+	if key == nil {
+		return nil, fmt.Errorf("key %d of account (%s) not found at block (%s): %w", keyIndex, address, header.ID(), storage.ErrNotFound)
+	}
+
+	return key, nil
+}
+
+// GetAccountBalanceAtBlockHeight returns the balance of the account with the given address at the
+// given height.
+// Expected errors:
+// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+// - storage.ErrNotBootstrapped if the register index is not bootstrapped yet
+// - storage.ErrNotFound if the block at the height or the account is not known
+func (s *Scripts) GetAccountBalanceAtBlockHeight(
+	ctx context.Context,
+	address flow.Address,
+	height uint64,
+) (uint64, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, s.scriptExecutionTimeLimit)
+	defer cancel()
+
+	header, env, err := s.accountEnvironment(requestCtx, address, height)
+	if err != nil {
+		return 0, err
+	}
+
+	balance, err := env.GetAccountBalance(common.Address(address))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balance of account (%s) at block (%s): %w", address, header.ID(), err)
+	}
+
+	return balance, nil
+}
+
+// accountEnvironment returns the header of the block at the given height, and a script
+// environment for reading parts of the account with the given address from the execution state
+// after the block was executed, without loading the account's contracts. The account is checked
+// to exist.
+// Expected errors:
+// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+// - storage.ErrNotBootstrapped if the register index is not bootstrapped yet
+// - storage.ErrNotFound if the block at the height or the account is not known
+func (s *Scripts) accountEnvironment(
+	ctx context.Context,
+	address flow.Address,
+	height uint64,
+) (*flow.Header, environment.Environment, error) {
+	header, view, err := s.viewAtHeight(height)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blockCtx := s.blockContext(header)
+	txnState := transactionState(blockCtx, view)
+
+	err = accountExists(environment.NewAccounts(txnState), address, header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	derivedTxnData, err := blockCtx.DerivedBlockData.NewSnapshotReadDerivedTransactionData(
+		derived.EndOfBlockExecutionTime,
+		derived.EndOfBlockExecutionTime)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create derived transaction data: %w", err)
+	}
+
+	env := environment.NewScriptEnvironment(
+		ctx,
+		blockCtx.TracerSpan,
+		blockCtx.EnvironmentParams,
+		txnState,
+		derivedTxnData)
+
+	return header, env, nil
+}
+
+// accountExists returns storage.ErrNotFound if the account with the given address does not exist.
+func accountExists(accounts environment.Accounts, address flow.Address, header *flow.Header) error {
+	exists, err := accounts.Exists(address)
+	if err != nil {
+		return fmt.Errorf("failed to check if account (%s) exists at block (%s): %w", address, header.ID(), err)
+	}
+	if !exists {
+		return fmt.Errorf("account (%s) not found at block (%s): %w", address, header.ID(), storage.ErrNotFound)
+	}
+	return nil
+}
+
+// transactionState returns a read only transaction state for the view, using the limits of the
+// given fvm context.
+func transactionState(ctx fvm.Context, view *delta.View) *state.TransactionState {
+	return state.NewTransactionState(
+		view,
+		state.DefaultParameters().
+			WithMaxKeySizeAllowed(ctx.MaxStateKeySize).
+			WithMaxValueSizeAllowed(ctx.MaxStateValueSize).
+			WithMeterParameters(
+				meter.DefaultParameters().
+					WithStorageInteractionLimit(ctx.MaxStateInteractionSize)))
+}
+
+// viewAtHeight returns the header of the block at the given height, and a view of the execution
+// state after the block was executed.
+// Expected errors:
+// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+// - storage.ErrNotBootstrapped if the register index is not bootstrapped yet
+// - storage.ErrNotFound if the block at the height is not known
+func (s *Scripts) viewAtHeight(height uint64) (*flow.Header, *delta.View, error) {
+	latestHeight, err := s.registers.LatestHeight()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// check the height before reading registers, so a missing height is reported as such instead
+	// of as an error from within the fvm
+	if height > latestHeight {
+		return nil, nil, fmt.Errorf("execution state for height %d is not indexed yet, latest indexed height is %d: %w",
+			height, latestHeight, storage.ErrHeightNotIndexed)
+	}
+
+	firstHeight, err := s.registers.FirstHeight()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if height < firstHeight {
+		return nil, nil, fmt.Errorf("execution state for height %d is not indexed, first indexed height is %d: %w",
+			height, firstHeight, storage.ErrHeightNotIndexed)
+	}
+
+	header, err := s.headers.ByHeight(height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get header for height %d: %w", height, err)
+	}
+
+	view := delta.NewDeltaView(func(id flow.RegisterID) (flow.RegisterValue, error) {
+		return s.registers.Get(id, height)
+	})
+
+	return header, view, nil
+}
+
+// blockContext returns the fvm context for executing against the state of the given block.
+func (s *Scripts) blockContext(header *flow.Header) fvm.Context {
+	return fvm.NewContextFromParent(
+		s.vmCtx,
+		fvm.WithBlockHeader(header),
+		fvm.WithDerivedBlockData(
+			s.derivedChainData.NewDerivedBlockDataForScript(header.ID())))
+}
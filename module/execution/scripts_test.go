@@ -0,0 +1,133 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestScripts(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		chain := flow.Localnet.Chain()
+		options := []fvm.Option{fvm.WithChain(chain)}
+
+		// bootstrap the execution state and index it at the height of the block
+		view := delta.NewDeltaView(nil)
+		err := fvm.NewVirtualMachine().Run(
+			fvm.NewContext(options...),
+			fvm.Bootstrap(unittest.ServiceAccountPublicKey, fvm.WithInitialTokenSupply(unittest.GenesisTokenSupply)),
+			view)
+		require.NoError(t, err)
+
+		header := unittest.BlockHeaderFixture()
+
+		registers, err := badgerstorage.NewRegisters(db)
+		require.NoError(t, err)
+		err = registers.Bootstrap(header.Height, view.UpdatedRegisters())
+		require.NoError(t, err)
+
+		headers := storagemock.NewHeaders(t)
+		headers.On("ByHeight", header.Height).Return(header, nil).Maybe()
+
+		scripts, err := NewScripts(zerolog.Nop(), options, headers, registers, DefaultScriptExecutionTimeLimit)
+		require.NoError(t, err)
+
+		ctx := context.Background()
+
+		t.Run("execute script", func(t *testing.T) {
+			script := []byte(fmt.Sprintf(`
+				pub fun main(): Bool {
+					return getAccount(%s).balance > 0.0
+				}
+			`, chain.ServiceAddress().HexWithPrefix()))
+
+			result, err := scripts.ExecuteAtBlockHeight(ctx, script, nil, header.Height)
+			require.NoError(t, err)
+
+			value, err := jsoncdc.Decode(nil, result)
+			require.NoError(t, err)
+			assert.Equal(t, cadence.NewBool(true), value)
+		})
+
+		t.Run("failing script", func(t *testing.T) {
+			script := []byte(`pub fun main() { panic("failed") }`)
+
+			_, err := scripts.ExecuteAtBlockHeight(ctx, script, nil, header.Height)
+			require.Error(t, err)
+			assert.True(t, IsScriptExecutionError(err))
+		})
+
+		t.Run("get account", func(t *testing.T) {
+			account, err := scripts.GetAccountAtBlockHeight(ctx, chain.ServiceAddress(), header.Height)
+			require.NoError(t, err)
+			assert.Equal(t, chain.ServiceAddress(), account.Address)
+			assert.NotEmpty(t, account.Keys)
+		})
+
+		t.Run("get account keys", func(t *testing.T) {
+			account, err := scripts.GetAccountAtBlockHeight(ctx, chain.ServiceAddress(), header.Height)
+			require.NoError(t, err)
+
+			keys, err := scripts.GetAccountKeysAtBlockHeight(ctx, chain.ServiceAddress(), header.Height)
+			require.NoError(t, err)
+			assert.Equal(t, account.Keys, keys)
+
+			key, err := scripts.GetAccountKeyAtBlockHeight(ctx, chain.ServiceAddress(), 0, header.Height)
+			require.NoError(t, err)
+			assert.Equal(t, account.Keys[0], *key)
+
+			_, err = scripts.GetAccountKeyAtBlockHeight(ctx, chain.ServiceAddress(), uint64(len(keys)), header.Height)
+			require.ErrorIs(t, err, storage.ErrNotFound)
+		})
+
+		t.Run("get account balance", func(t *testing.T) {
+			account, err := scripts.GetAccountAtBlockHeight(ctx, chain.ServiceAddress(), header.Height)
+			require.NoError(t, err)
+
+			balance, err := scripts.GetAccountBalanceAtBlockHeight(ctx, chain.ServiceAddress(), header.Height)
+			require.NoError(t, err)
+			assert.Equal(t, account.Balance, balance)
+			assert.Greater(t, balance, uint64(0))
+		})
+
+		t.Run("unknown account", func(t *testing.T) {
+			address, err := chain.AddressAtIndex(1000)
+			require.NoError(t, err)
+
+			_, err = scripts.GetAccountAtBlockHeight(ctx, address, header.Height)
+			require.ErrorIs(t, err, storage.ErrNotFound)
+
+			_, err = scripts.GetAccountKeysAtBlockHeight(ctx, address, header.Height)
+			require.ErrorIs(t, err, storage.ErrNotFound)
+
+			_, err = scripts.GetAccountKeyAtBlockHeight(ctx, address, 0, header.Height)
+			require.ErrorIs(t, err, storage.ErrNotFound)
+
+			_, err = scripts.GetAccountBalanceAtBlockHeight(ctx, address, header.Height)
+			require.ErrorIs(t, err, storage.ErrNotFound)
+		})
+
+		t.Run("height not indexed", func(t *testing.T) {
+			_, err := scripts.ExecuteAtBlockHeight(ctx, []byte(`pub fun main() {}`), nil, header.Height+1)
+			require.ErrorIs(t, err, storage.ErrHeightNotIndexed)
+
+			_, err = scripts.GetAccountAtBlockHeight(ctx, chain.ServiceAddress(), header.Height-1)
+			require.ErrorIs(t, err, storage.ErrHeightNotIndexed)
+		})
+	})
+}
@@ -0,0 +1,203 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/environment"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// TransactionSimulation is the result of executing a transaction against the local execution
+// state, without committing any of its changes.
+type TransactionSimulation struct {
+	// ComputationUsed is the computation used by the transaction, excluding fee deduction.
+	ComputationUsed uint64
+
+	// MemoryEstimate is the estimated memory used by the transaction.
+	MemoryEstimate uint64
+
+	// Events are the events emitted by the transaction, including the fee deduction events.
+	Events flow.EventsList
+
+	// StorageDelta is the change of the storage used in bytes, for every account whose
+	// registers were updated by the transaction.
+	StorageDelta map[flow.Address]int64
+
+	// Fee is the fee deducted from the payer, or zero if transaction fees are not enabled.
+	Fee uint64
+
+	// Err is the error the transaction failed with, or nil if it succeeded.
+	Err fvmerrors.CodedError
+}
+
+// SimulateTransactionAtBlockHeight executes the transaction against the execution state at the
+// given height, and returns the resources it used and the changes it made. Signatures and the
+// proposal key sequence number are not checked, so the transaction may be simulated before it is
+// signed. A failing transaction is not an error, it is reported in TransactionSimulation.Err.
+// The simulation is stopped when the context is done, or when it runs longer than the script
+// execution time limit.
+// Expected errors:
+// - storage.ErrHeightNotIndexed if the execution state at the height is not available locally
+// - storage.ErrNotBootstrapped if the register index is not bootstrapped yet
+// - storage.ErrNotFound if the block at the height is not known
+// - context.DeadlineExceeded or context.Canceled if the simulation was stopped
+func (s *Scripts) SimulateTransactionAtBlockHeight(
+	ctx context.Context,
+	tx *flow.TransactionBody,
+	height uint64,
+) (*TransactionSimulation, error) {
+	header, view, err := s.viewAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	txCtx := fvm.NewContextFromParent(
+		s.blockContext(header),
+		fvm.WithAuthorizationChecksEnabled(false),
+		fvm.WithSequenceNumberCheckAndIncrementEnabled(false))
+
+	requestCtx, cancel := context.WithTimeout(ctx, s.scriptExecutionTimeLimit)
+	defer cancel()
+
+	proc := fvm.Transaction(tx, 0)
+	proc.RequestContext = requestCtx
+
+	err = func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				s.log.Error().
+					Hex("tx_id", proc.ID[:]).
+					Interface("recovered", r).
+					Msg("transaction simulation caused runtime panic")
+
+				err = fmt.Errorf("cadence runtime error: %s", r)
+			}
+		}()
+
+		return s.vm.Run(txCtx, proc, view)
+	}()
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate transaction (internal error): %w", err)
+	}
+
+	// the transaction failed because it was interrupted, not because of its own code
+	if proc.Err != nil && requestCtx.Err() != nil {
+		return nil, fmt.Errorf("transaction simulation was stopped: %w", requestCtx.Err())
+	}
+
+	fee, err := s.deductedFee(proc.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	storageDelta, err := s.storageDelta(view, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionSimulation{
+		ComputationUsed: proc.ComputationUsed,
+		MemoryEstimate:  proc.MemoryEstimate,
+		Events:          proc.Events,
+		StorageDelta:    storageDelta,
+		Fee:             fee,
+		Err:             proc.Err,
+	}, nil
+}
+
+// deductedFee returns the amount of the fee deduction event, or zero if there is none because
+// transaction fees are not enabled.
+func (s *Scripts) deductedFee(events flow.EventsList) (uint64, error) {
+	feesDeducted := flow.EventType(fmt.Sprintf("A.%s.FlowFees.FeesDeducted", environment.FlowFeesAddress(s.vmCtx.Chain)))
+
+	for _, event := range events {
+		if event.Type != feesDeducted {
+			continue
+		}
+
+		value, err := jsoncdc.Decode(nil, event.Payload)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode fee deduction event: %w", err)
+		}
+
+		feeEvent, ok := value.(cadence.Event)
+		if !ok {
+			return 0, fmt.Errorf("unexpected fee deduction event type: %T", value)
+		}
+
+		fields := feeEvent.Fields
+		if len(fields) == 0 {
+			return 0, fmt.Errorf("fee deduction event has no fields")
+		}
+
+		amount, ok := fields[0].(cadence.UFix64)
+		if !ok {
+			return 0, fmt.Errorf("unexpected fee deduction amount type: %T", fields[0])
+		}
+
+		return uint64(amount), nil
+	}
+
+	return 0, nil
+}
+
+// storageDelta returns the change of the storage used by every account with registers updated
+// in the view, compared to the execution state at the given height.
+func (s *Scripts) storageDelta(view *delta.View, height uint64) (map[flow.Address]int64, error) {
+	before := environment.NewAccounts(transactionState(s.vmCtx, delta.NewDeltaView(
+		func(id flow.RegisterID) (flow.RegisterValue, error) {
+			return s.registers.Get(id, height)
+		})))
+	after := environment.NewAccounts(transactionState(s.vmCtx, view))
+
+	storageDelta := make(map[flow.Address]int64)
+	for _, id := range view.UpdatedRegisterIDs() {
+		if id.Owner == "" {
+			continue
+		}
+
+		address := flow.BytesToAddress([]byte(id.Owner))
+		if _, ok := storageDelta[address]; ok {
+			continue
+		}
+
+		usedBefore, err := storageUsed(before, address)
+		if err != nil {
+			return nil, err
+		}
+
+		usedAfter, err := storageUsed(after, address)
+		if err != nil {
+			return nil, err
+		}
+
+		storageDelta[address] = int64(usedAfter) - int64(usedBefore)
+	}
+
+	return storageDelta, nil
+}
+
+// storageUsed returns the storage used by the account, or zero if the account does not exist.
+func storageUsed(accounts *environment.StatefulAccounts, address flow.Address) (uint64, error) {
+	exists, err := accounts.Exists(address)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check if account (%s) exists: %w", address, err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	used, err := accounts.GetStorageUsed(address)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get storage used by account (%s): %w", address, err)
+	}
+
+	return used, nil
+}
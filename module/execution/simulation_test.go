@@ -0,0 +1,132 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestSimulateTransaction(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		chain := flow.Localnet.Chain()
+		options := []fvm.Option{
+			fvm.WithChain(chain),
+			fvm.WithAccountStorageLimit(true),
+			fvm.WithTransactionFeesEnabled(true),
+		}
+
+		// bootstrap the execution state with fees and storage limits, and index it at the height
+		// of the block
+		view := delta.NewDeltaView(nil)
+		err := fvm.NewVirtualMachine().Run(
+			fvm.NewContext(options...),
+			fvm.Bootstrap(
+				unittest.ServiceAccountPublicKey,
+				fvm.WithInitialTokenSupply(unittest.GenesisTokenSupply),
+				fvm.WithMinimumStorageReservation(fvm.DefaultMinimumStorageReservation),
+				fvm.WithAccountCreationFee(fvm.DefaultAccountCreationFee),
+				fvm.WithStorageMBPerFLOW(fvm.DefaultStorageMBPerFLOW),
+				fvm.WithTransactionFee(fvm.DefaultTransactionFees),
+			),
+			view)
+		require.NoError(t, err)
+
+		header := unittest.BlockHeaderFixture()
+
+		registers, err := badgerstorage.NewRegisters(db)
+		require.NoError(t, err)
+		err = registers.Bootstrap(header.Height, view.UpdatedRegisters())
+		require.NoError(t, err)
+
+		headers := storagemock.NewHeaders(t)
+		headers.On("ByHeight", header.Height).Return(header, nil).Maybe()
+
+		scripts, err := NewScripts(zerolog.Nop(), options, headers, registers, DefaultScriptExecutionTimeLimit)
+		require.NoError(t, err)
+
+		ctx := context.Background()
+
+		// unsigned transactions with an arbitrary sequence number are simulated
+		transaction := func(script string) *flow.TransactionBody {
+			return flow.NewTransactionBody().
+				SetScript([]byte(script)).
+				SetProposalKey(chain.ServiceAddress(), 0, 42).
+				AddAuthorizer(chain.ServiceAddress()).
+				SetPayer(chain.ServiceAddress()).
+				SetGasLimit(flow.DefaultMaxTransactionGasLimit)
+		}
+
+		t.Run("successful transaction", func(t *testing.T) {
+			tx := transaction(`
+				transaction {
+					prepare(signer: AuthAccount) {
+						var i = 0
+						while i < 10 { i = i + 1 }
+						signer.save("simulated", to: /storage/simulated)
+					}
+				}
+			`)
+
+			result, err := scripts.SimulateTransactionAtBlockHeight(ctx, tx, header.Height)
+			require.NoError(t, err)
+			require.NoError(t, result.Err)
+
+			assert.Greater(t, result.ComputationUsed, uint64(10))
+			assert.Greater(t, result.MemoryEstimate, uint64(0))
+			assert.Greater(t, result.Fee, uint64(0))
+			assert.NotEmpty(t, result.Events)
+			assert.Greater(t, result.StorageDelta[chain.ServiceAddress()], int64(0))
+
+			// the changes are not committed, otherwise saving to the same path again would fail
+			result, err = scripts.SimulateTransactionAtBlockHeight(ctx, tx, header.Height)
+			require.NoError(t, err)
+			require.NoError(t, result.Err)
+		})
+
+		t.Run("failing transaction", func(t *testing.T) {
+			tx := transaction(`
+				transaction {
+					prepare(signer: AuthAccount) {
+						panic("failed")
+					}
+				}
+			`)
+
+			result, err := scripts.SimulateTransactionAtBlockHeight(ctx, tx, header.Height)
+			require.NoError(t, err)
+			require.Error(t, result.Err)
+			assert.Contains(t, result.Err.Error(), "failed")
+
+			// fees are deducted from failed transactions as well
+			assert.Greater(t, result.Fee, uint64(0))
+		})
+
+		t.Run("cancelled simulation", func(t *testing.T) {
+			tx := transaction(`
+				transaction {
+					prepare(signer: AuthAccount) {
+						var i = 0
+						while true { i = i + 1 }
+					}
+				}
+			`)
+
+			cancelledCtx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			_, err := scripts.SimulateTransactionAtBlockHeight(cancelledCtx, tx, header.Height)
+			assert.ErrorIs(t, err, context.Canceled)
+		})
+	})
+}
@@ -496,6 +496,27 @@ type ExecutionDataPrunerMetrics interface {
 	Pruned(height uint64, duration time.Duration)
 }
 
+// APIQuotaMetrics tracks the outcome of the quota checks of API requests.
+type APIQuotaMetrics interface {
+	// APIRequestQuotaChecked tracks the result of the quota check of a request to the method by the
+	// client, which is the name of its API key, or "anonymous" for requests without API key.
+	APIRequestQuotaChecked(client string, method string, result string)
+}
+
+// ExecutionNodeHealthMetrics tracks the health of the execution nodes access nodes send requests to.
+type ExecutionNodeHealthMetrics interface {
+	// ExecutionNodeRequest tracks the duration and outcome of a request to the execution node.
+	ExecutionNodeRequest(nodeID flow.Identifier, duration time.Duration, failed bool)
+
+	// ExecutionNodeHealth tracks the health score of the execution node, and whether requests are
+	// sent to it.
+	ExecutionNodeHealth(nodeID flow.Identifier, score float64, available bool)
+
+	// ExecutionNodeResultMismatch tracks a result of the execution node which does not match the
+	// result of the majority of execution nodes.
+	ExecutionNodeResultMismatch(nodeID flow.Identifier)
+}
+
 type AccessMetrics interface {
 	// TotalConnectionsInPool updates the number connections to collection/execution nodes stored in the pool, and the size of the pool
 	TotalConnectionsInPool(connectionCount uint, connectionPoolSize uint)
@@ -585,6 +606,15 @@ type ExecutionMetrics interface {
 	// ExecutionScriptExecuted reports the time and memory spent on executing an script
 	ExecutionScriptExecuted(dur time.Duration, compUsed, memoryUsed, memoryEstimate uint64)
 
+	// ExecutionScriptQueueDepth reports the number of scripts waiting for execution
+	ExecutionScriptQueueDepth(depth int)
+
+	// ExecutionScriptQueueWaitTime reports the time a script waited for execution
+	ExecutionScriptQueueWaitTime(dur time.Duration)
+
+	// ExecutionScriptRejected reports a script rejected because too many scripts were waiting for execution
+	ExecutionScriptRejected()
+
 	// ExecutionCollectionRequestSent reports when a request for a collection is sent to a collection node
 	ExecutionCollectionRequestSent()
 
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type APIQuotaCollector struct {
+	requests *prometheus.CounterVec
+}
+
+func NewAPIQuotaCollector() *APIQuotaCollector {
+	return &APIQuotaCollector{
+		requests: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceAccess,
+			Subsystem: subsystemAPIQuota,
+			Name:      "requests_total",
+			Help:      "the number of API requests checked against the per-client quotas, by client, method and result",
+		}, []string{"client", "method", "result"}),
+	}
+}
+
+func (qc *APIQuotaCollector) APIRequestQuotaChecked(client string, method string, result string) {
+	qc.requests.With(prometheus.Labels{
+		"client": client,
+		"method": method,
+		"result": result,
+	}).Inc()
+}
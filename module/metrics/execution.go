@@ -71,6 +71,9 @@ type ExecutionCollector struct {
 	scriptMemoryUsage                      prometheus.Histogram
 	scriptMemoryEstimate                   prometheus.Histogram
 	scriptMemoryDifference                 prometheus.Histogram
+	scriptQueueDepth                       prometheus.Gauge
+	scriptQueueWaitTime                    prometheus.Histogram
+	scriptsRejected                        prometheus.Counter
 	numberOfAccounts                       prometheus.Gauge
 	programsCacheMiss                      prometheus.Counter
 	programsCacheHit                       prometheus.Counter
@@ -626,6 +629,28 @@ func NewExecutionCollector(tracer module.Tracer) *ExecutionCollector {
 			Help:      "the total number of scripts that have been executed",
 		}),
 
+		scriptQueueDepth: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "script_queue_depth",
+			Help:      "the number of scripts waiting for execution",
+		}),
+
+		scriptQueueWaitTime: promauto.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "script_queue_wait_time_milliseconds",
+			Help:      "the time scripts waited for execution in milliseconds",
+			Buckets:   []float64{1, 2, 4, 8, 16, 32, 64, 100, 250, 500, 1000, 5000},
+		}),
+
+		scriptsRejected: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Name:      "total_rejected_scripts",
+			Help:      "the total number of scripts rejected because too many scripts were waiting for execution",
+		}),
+
 		lastExecutedBlockHeightGauge: promauto.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespaceExecution,
 			Subsystem: subsystemRuntime,
@@ -771,6 +796,21 @@ func (ec *ExecutionCollector) ExecutionScriptExecuted(dur time.Duration, compUse
 	ec.scriptMemoryDifference.Observe(float64(memoryEstimated) - float64(memoryUsed))
 }
 
+// ExecutionScriptQueueDepth reports the number of scripts waiting for execution
+func (ec *ExecutionCollector) ExecutionScriptQueueDepth(depth int) {
+	ec.scriptQueueDepth.Set(float64(depth))
+}
+
+// ExecutionScriptQueueWaitTime reports the time a script waited for execution
+func (ec *ExecutionCollector) ExecutionScriptQueueWaitTime(dur time.Duration) {
+	ec.scriptQueueWaitTime.Observe(float64(dur.Milliseconds()))
+}
+
+// ExecutionScriptRejected reports a script rejected because too many scripts were waiting for execution
+func (ec *ExecutionCollector) ExecutionScriptRejected() {
+	ec.scriptsRejected.Inc()
+}
+
 // ExecutionStateStorageDiskTotal reports the total storage size of the execution state on disk in bytes
 func (ec *ExecutionCollector) ExecutionStateStorageDiskTotal(bytes int64) {
 	ec.stateStorageDiskTotal.Set(float64(bytes))
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+type ExecutionNodeHealthCollector struct {
+	requestDuration *prometheus.HistogramVec
+	score           *prometheus.GaugeVec
+	available       *prometheus.GaugeVec
+	mismatches      *prometheus.CounterVec
+}
+
+func NewExecutionNodeHealthCollector() *ExecutionNodeHealthCollector {
+	return &ExecutionNodeHealthCollector{
+		requestDuration: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespaceAccess,
+			Subsystem: subsystemExecutionNodeHealth,
+			Name:      "request_duration_seconds",
+			Help:      "the duration of requests to execution nodes, by node and outcome",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+		}, []string{"node_id", "result"}),
+		score: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceAccess,
+			Subsystem: subsystemExecutionNodeHealth,
+			Name:      "score",
+			Help:      "the health score of execution nodes, between 0 and 1",
+		}, []string{"node_id"}),
+		available: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceAccess,
+			Subsystem: subsystemExecutionNodeHealth,
+			Name:      "available",
+			Help:      "whether requests are sent to execution nodes (1) or not after too many failures (0)",
+		}, []string{"node_id"}),
+		mismatches: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceAccess,
+			Subsystem: subsystemExecutionNodeHealth,
+			Name:      "result_mismatches_total",
+			Help:      "the number of results of execution nodes which did not match the majority of execution nodes",
+		}, []string{"node_id"}),
+	}
+}
+
+func (hc *ExecutionNodeHealthCollector) ExecutionNodeRequest(nodeID flow.Identifier, duration time.Duration, failed bool) {
+	result := "success"
+	if failed {
+		result = "failure"
+	}
+	hc.requestDuration.With(prometheus.Labels{
+		"node_id": nodeID.String(),
+		"result":  result,
+	}).Observe(duration.Seconds())
+}
+
+func (hc *ExecutionNodeHealthCollector) ExecutionNodeHealth(nodeID flow.Identifier, score float64, available bool) {
+	hc.score.WithLabelValues(nodeID.String()).Set(score)
+	if available {
+		hc.available.WithLabelValues(nodeID.String()).Set(1)
+	} else {
+		hc.available.WithLabelValues(nodeID.String()).Set(0)
+	}
+}
+
+func (hc *ExecutionNodeHealthCollector) ExecutionNodeResultMismatch(nodeID flow.Identifier) {
+	hc.mismatches.WithLabelValues(nodeID.String()).Inc()
+}
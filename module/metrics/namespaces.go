@@ -40,6 +40,8 @@ const (
 	subsystemTransactionTiming     = "transaction_timing"
 	subsystemTransactionSubmission = "transaction_submission"
 	subsystemConnectionPool        = "connection_pool"
+	subsystemAPIQuota              = "api_quota"
+	subsystemExecutionNodeHealth   = "execution_node_health"
 )
 
 // Observer subsystem
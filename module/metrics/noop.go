@@ -147,6 +147,7 @@ func (nc *NoopCollector) NewConnectionEstablished()
 func (nc *NoopCollector) ConnectionFromPoolInvalidated()                                        {}
 func (nc *NoopCollector) ConnectionFromPoolUpdated()                                            {}
 func (nc *NoopCollector) ConnectionFromPoolEvicted()                                            {}
+func (nc *NoopCollector) APIRequestQuotaChecked(client string, method string, result string)    {}
 func (nc *NoopCollector) StartBlockReceivedToExecuted(blockID flow.Identifier)                  {}
 func (nc *NoopCollector) FinishBlockReceivedToExecuted(blockID flow.Identifier)                 {}
 func (nc *NoopCollector) ExecutionComputationUsedPerBlock(computation uint64)                   {}
@@ -161,6 +162,9 @@ func (nc *NoopCollector) ExecutionTransactionExecuted(_ time.Duration, _, _, _ u
 }
 func (nc *NoopCollector) ExecutionChunkDataPackGenerated(_, _ int)                         {}
 func (nc *NoopCollector) ExecutionScriptExecuted(dur time.Duration, compUsed, _, _ uint64) {}
+func (nc *NoopCollector) ExecutionScriptQueueDepth(depth int)                              {}
+func (nc *NoopCollector) ExecutionScriptQueueWaitTime(dur time.Duration)                   {}
+func (nc *NoopCollector) ExecutionScriptRejected()                                         {}
 func (nc *NoopCollector) ForestApproxMemorySize(bytes uint64)                              {}
 func (nc *NoopCollector) ForestNumberOfTrees(number uint64)                                {}
 func (nc *NoopCollector) LatestTrieRegCount(number uint64)                                 {}
@@ -263,3 +267,7 @@ func (nc *NoopCollector) BlockService(svc string)
 func (nc *NoopCollector) BlockServicePeer(svc string, p peer.ID)                          {}
 func (nc *NoopCollector) AllowMemory(size int)                                            {}
 func (nc *NoopCollector) BlockMemory(size int)                                            {}
+
+func (nc *NoopCollector) ExecutionNodeRequest(flow.Identifier, time.Duration, bool) {}
+func (nc *NoopCollector) ExecutionNodeHealth(flow.Identifier, float64, bool)        {}
+func (nc *NoopCollector) ExecutionNodeResultMismatch(flow.Identifier)               {}
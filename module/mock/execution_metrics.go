@@ -86,6 +86,21 @@ func (_m *ExecutionMetrics) ExecutionScriptExecuted(dur time.Duration, compUsed
 	_m.Called(dur, compUsed, memoryUsed, memoryEstimate)
 }
 
+// ExecutionScriptQueueDepth provides a mock function with given fields: depth
+func (_m *ExecutionMetrics) ExecutionScriptQueueDepth(depth int) {
+	_m.Called(depth)
+}
+
+// ExecutionScriptQueueWaitTime provides a mock function with given fields: dur
+func (_m *ExecutionMetrics) ExecutionScriptQueueWaitTime(dur time.Duration) {
+	_m.Called(dur)
+}
+
+// ExecutionScriptRejected provides a mock function with given fields:
+func (_m *ExecutionMetrics) ExecutionScriptRejected() {
+	_m.Called()
+}
+
 // ExecutionStorageStateCommitment provides a mock function with given fields: bytes
 func (_m *ExecutionMetrics) ExecutionStorageStateCommitment(bytes int64) {
 	_m.Called(bytes)
@@ -0,0 +1,76 @@
+package indexer
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// bootstrapBatchSize is the maximum number of registers written at once while bootstrapping the
+// register index.
+const bootstrapBatchSize = 1000
+
+// Bootstrap initializes the register index with the full execution state at the given height.
+// The state is read from the V6 checkpoint file at the given path, which must contain a single trie
+// with the given state commitment, usually the root checkpoint of the spork.
+//
+// The leaf nodes of the checkpoint are streamed into the index in batches, so the execution state
+// is never held in memory. If bootstrapping is interrupted, the index is not bootstrapped, and the
+// registers already written are overwritten when bootstrapping again.
+// No errors are expected during normal operation.
+func Bootstrap(
+	log zerolog.Logger,
+	registers storage.RegisterIndex,
+	checkpointFile string,
+	height uint64,
+	commit flow.StateCommitment,
+) error {
+	log.Info().
+		Str("checkpoint", checkpointFile).
+		Uint64("height", height).
+		Msg("reading execution state checkpoint to bootstrap register index")
+
+	count := 0
+	entries := make(flow.RegisterEntries, 0, bootstrapBatchSize)
+	dir, fileName := filepath.Split(checkpointFile)
+	err := wal.ReadLeafPayloadsFromCheckpointV6(dir, fileName, ledger.RootHash(commit), bootstrapBatchSize,
+		func(payloads []*ledger.Payload) error {
+			entries = entries[:0]
+			for _, payload := range payloads {
+				id, value, err := registerFromPayload(payload)
+				if err != nil {
+					return err
+				}
+				entries = append(entries, flow.RegisterEntry{Key: id, Value: value})
+			}
+
+			err := registers.StoreBootstrapEntries(height, entries)
+			if err != nil {
+				return fmt.Errorf("could not store registers: %w", err)
+			}
+
+			count += len(entries)
+			return nil
+		}, &log)
+	if err != nil {
+		return fmt.Errorf("could not read registers from checkpoint: %w", err)
+	}
+
+	err = registers.Bootstrap(height, nil)
+	if err != nil {
+		return fmt.Errorf("could not bootstrap register index: %w", err)
+	}
+
+	log.Info().
+		Uint64("height", height).
+		Int("register_count", count).
+		Msg("register index bootstrapped")
+
+	return nil
+}
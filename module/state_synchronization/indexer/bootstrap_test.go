@@ -0,0 +1,84 @@
+package indexer
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestBootstrap tests that the register index is bootstrapped with all registers of the trie of a
+// checkpoint, spanning several write batches.
+func TestBootstrap(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		entries := make(flow.RegisterEntries, 2*bootstrapBatchSize+1)
+		paths := make([]ledger.Path, len(entries))
+		payloads := make([]ledger.Payload, len(entries))
+		for i := range entries {
+			entries[i] = flow.RegisterEntry{
+				Key:   flow.NewRegisterID("owner", fmt.Sprintf("key%d", i)),
+				Value: []byte(fmt.Sprintf("value%d", i)),
+			}
+			key := state.RegisterIDToKey(entries[i].Key)
+			path, err := pathfinder.KeyToPath(key, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+			paths[i] = path
+			payloads[i] = *ledger.NewPayload(key, entries[i].Value)
+		}
+
+		tr, _, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads, true)
+		require.NoError(t, err)
+
+		log := unittest.Logger()
+		require.NoError(t, wal.StoreCheckpointV6Concurrently([]*trie.MTrie{tr}, dir, "root.checkpoint", &log))
+		checkpoint := filepath.Join(dir, "root.checkpoint")
+		commit := flow.StateCommitment(tr.RootHash())
+
+		t.Run("state commitment not in checkpoint", func(t *testing.T) {
+			unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+				registers, err := badgerstorage.NewRegisters(db)
+				require.NoError(t, err)
+
+				err = Bootstrap(log, registers, checkpoint, 10, unittest.StateCommitmentFixture())
+				require.Error(t, err)
+
+				_, err = registers.FirstHeight()
+				require.ErrorIs(t, err, storage.ErrNotBootstrapped)
+			})
+		})
+
+		t.Run("registers are bootstrapped", func(t *testing.T) {
+			unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+				registers, err := badgerstorage.NewRegisters(db)
+				require.NoError(t, err)
+
+				err = Bootstrap(log, registers, checkpoint, 10, commit)
+				require.NoError(t, err)
+
+				first, err := registers.FirstHeight()
+				require.NoError(t, err)
+				assert.Equal(t, uint64(10), first)
+
+				for _, entry := range entries {
+					value, err := registers.Get(entry.Key, 10)
+					require.NoError(t, err)
+					assert.Equal(t, entry.Value, value)
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,300 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/storage"
+)
+
+// Indexer indexes the register updates contained in the execution data of every sealed block into
+// a register index, which allows reading the execution state at any indexed height. If an event
+// index is configured, the events contained in the execution data are indexed as well.
+//
+// The execution data requester notifies the indexer about newly available execution data using
+// OnExecutionData. The indexer then reads the execution data of every height after the latest
+// indexed height from the local execution data store, and stores the register updates of each
+// block in consecutive height order. This also allows the indexer to catch up after restarts,
+// or when it was enabled on a node that already downloaded execution data.
+type Indexer struct {
+	component.Component
+
+	log           zerolog.Logger
+	registers     storage.RegisterIndex
+	events        storage.EventIndex
+	headers       storage.Headers
+	seals         storage.Seals
+	results       storage.ExecutionResults
+	execDataStore execution_data.ExecutionDataStore
+
+	notifier engine.Notifier
+
+	// highestHeight contains the highest consecutive block height for which execution data is
+	// available in the local execution data store.
+	highestHeight *atomic.Uint64
+}
+
+// New creates a new indexer. The register index must already be bootstrapped. The event index is
+// optional and may be nil, in which case events are not indexed. highestAvailableHeight is the highest height for which execution data is available when the
+// indexer is created.
+func New(
+	log zerolog.Logger,
+	registers storage.RegisterIndex,
+	events storage.EventIndex,
+	headers storage.Headers,
+	seals storage.Seals,
+	results storage.ExecutionResults,
+	execDataStore execution_data.ExecutionDataStore,
+	highestAvailableHeight uint64,
+) *Indexer {
+	i := &Indexer{
+		log:           log.With().Str("component", "execution_state_indexer").Logger(),
+		registers:     registers,
+		events:        events,
+		headers:       headers,
+		seals:         seals,
+		results:       results,
+		execDataStore: execDataStore,
+		notifier:      engine.NewNotifier(),
+		highestHeight: atomic.NewUint64(highestAvailableHeight),
+	}
+
+	i.Component = component.NewComponentManagerBuilder().
+		AddWorker(i.processLoop).
+		Build()
+
+	return i
+}
+
+// OnExecutionData is called to notify the indexer that execution data is available for a new block.
+// It is called by the execution data requester for each sealed block in consecutive height order,
+// and is non-blocking.
+func (i *Indexer) OnExecutionData(executionData *execution_data.BlockExecutionData) {
+	header, err := i.headers.ByBlockID(executionData.BlockID)
+	if err != nil {
+		// if the execution data is available, the block must be locally finalized
+		i.log.Fatal().Err(err).Msg("could not get header for execution data")
+		return
+	}
+
+	if i.setHighestHeight(header.Height) {
+		i.notifier.Notify()
+	}
+}
+
+// setHighestHeight sets the highest height for which execution data is available. The height is
+// never decreased, since notifications may be repeated.
+func (i *Indexer) setHighestHeight(height uint64) bool {
+	for {
+		current := i.highestHeight.Load()
+		if height <= current {
+			return false
+		}
+		if i.highestHeight.CAS(current, height) {
+			return true
+		}
+	}
+}
+
+// processLoop indexes new heights whenever new execution data is available.
+func (i *Indexer) processLoop(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	// index any heights that were downloaded while the indexer was not running
+	i.notifier.Notify()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-i.notifier.Channel():
+		}
+
+		err := i.indexAvailable(ctx)
+		if err != nil {
+			ctx.Throw(err)
+			return
+		}
+	}
+}
+
+// indexAvailable indexes all heights with available execution data after the latest indexed height.
+// No errors are expected during normal operation.
+func (i *Indexer) indexAvailable(ctx context.Context) error {
+	latestHeight, err := i.registers.LatestHeight()
+	if err != nil {
+		return fmt.Errorf("could not get latest indexed height: %w", err)
+	}
+
+	for height := latestHeight + 1; height <= i.highestHeight.Load(); height++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		header, executionData, err := i.getExecutionData(ctx, height)
+		if err != nil {
+			// the execution data should be available locally at this point, but it is safe to
+			// retry on the next notification.
+			if errors.Is(err, storage.ErrNotFound) || execution_data.IsBlobNotFoundError(err) {
+				i.log.Warn().Err(err).Uint64("height", height).Msg("execution data not available for indexing")
+				return nil
+			}
+			return fmt.Errorf("could not get execution data for height %d: %w", height, err)
+		}
+
+		err = i.indexBlockData(header, executionData)
+		if err != nil {
+			return fmt.Errorf("could not index execution data for height %d: %w", height, err)
+		}
+	}
+
+	return nil
+}
+
+// indexBlockData stores the final value of every register updated within the block, and the
+// events emitted within the block if events are indexed.
+// No errors are expected during normal operation.
+func (i *Indexer) indexBlockData(header *flow.Header, executionData *execution_data.BlockExecutionData) error {
+	// events are indexed before the registers, since the latest indexed register height determines
+	// the next height to index. If the node crashed after indexing the events of a block, they are
+	// skipped when the block is indexed again.
+	eventCount, err := i.indexEvents(header, executionData)
+	if err != nil {
+		return err
+	}
+
+	updates := make(map[flow.RegisterID]flow.RegisterValue)
+	for _, chunk := range executionData.ChunkExecutionDatas {
+		if chunk.TrieUpdate == nil {
+			continue
+		}
+
+		// later chunks overwrite the updates of earlier chunks
+		for _, payload := range chunk.TrieUpdate.Payloads {
+			id, value, err := registerFromPayload(payload)
+			if err != nil {
+				return err
+			}
+			updates[id] = value
+		}
+	}
+
+	entries := make(flow.RegisterEntries, 0, len(updates))
+	for id, value := range updates {
+		entries = append(entries, flow.RegisterEntry{Key: id, Value: value})
+	}
+
+	err = i.registers.Store(entries, header.Height)
+	if err != nil {
+		return fmt.Errorf("could not store registers: %w", err)
+	}
+
+	i.log.Debug().
+		Uint64("height", header.Height).
+		Int("register_count", len(entries)).
+		Int("event_count", eventCount).
+		Msg("indexed execution data")
+
+	return nil
+}
+
+// indexEvents stores the events of all chunks of the block in the event index, unless events are
+// not indexed or the block was already indexed. Returns the number of events stored.
+// No errors are expected during normal operation.
+func (i *Indexer) indexEvents(header *flow.Header, executionData *execution_data.BlockExecutionData) (int, error) {
+	if i.events == nil {
+		return 0, nil
+	}
+
+	latestHeight, err := i.events.LatestHeight()
+	if err == nil && latestHeight >= header.Height {
+		return 0, nil
+	}
+	if err != nil && !errors.Is(err, storage.ErrNotBootstrapped) {
+		return 0, fmt.Errorf("could not get latest indexed event height: %w", err)
+	}
+
+	var events []flow.Event
+	for _, chunk := range executionData.ChunkExecutionDatas {
+		events = append(events, chunk.Events...)
+	}
+
+	err = i.events.Store(header, events)
+	if err != nil {
+		return 0, fmt.Errorf("could not store events: %w", err)
+	}
+
+	return len(events), nil
+}
+
+// getExecutionData returns the header and the execution data for the sealed block at the given height from the
+// local execution data store.
+// Expected errors:
+// - storage.ErrNotFound if the block, its seal or execution result are not known
+// - execution_data.BlobNotFoundError if the execution data is not in the local store
+func (i *Indexer) getExecutionData(ctx context.Context, height uint64) (*flow.Header, *execution_data.BlockExecutionData, error) {
+	return sealedExecutionData(ctx, i.headers, i.seals, i.results, i.execDataStore, height)
+}
+
+// sealedExecutionData returns the header and the execution data for the sealed block at the given
+// height from the local execution data store.
+// Expected errors:
+// - storage.ErrNotFound if the block, its seal or execution result are not known
+// - execution_data.BlobNotFoundError if the execution data is not in the local store
+func sealedExecutionData(
+	ctx context.Context,
+	headers storage.Headers,
+	seals storage.Seals,
+	results storage.ExecutionResults,
+	execDataStore execution_data.ExecutionDataStore,
+	height uint64,
+) (*flow.Header, *execution_data.BlockExecutionData, error) {
+	header, err := headers.ByHeight(height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get block header: %w", err)
+	}
+
+	seal, err := seals.FinalizedSealForBlock(header.ID())
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get finalized seal for block: %w", err)
+	}
+
+	result, err := results.ByID(seal.ResultID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get execution result: %w", err)
+	}
+
+	executionData, err := execDataStore.GetExecutionData(ctx, result.ExecutionDataID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get execution data: %w", err)
+	}
+
+	return header, executionData, nil
+}
+
+// registerFromPayload converts a ledger payload into the register it contains.
+// No errors are expected during normal operation.
+func registerFromPayload(payload *ledger.Payload) (flow.RegisterID, flow.RegisterValue, error) {
+	key, err := payload.Key()
+	if err != nil {
+		return flow.RegisterID{}, nil, fmt.Errorf("could not decode payload key: %w", err)
+	}
+
+	id, err := state.KeyToRegisterID(key)
+	if err != nil {
+		return flow.RegisterID{}, nil, fmt.Errorf("could not convert payload key: %w", err)
+	}
+
+	return id, flow.RegisterValue(payload.Value()), nil
+}
@@ -0,0 +1,176 @@
+package indexer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/blobs"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestIndexer(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		bs := blobs.NewBlobstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+		eds := execution_data.NewExecutionDataStore(bs, execution_data.DefaultSerializer)
+
+		headers := storagemock.NewHeaders(t)
+		seals := storagemock.NewSeals(t)
+		results := storagemock.NewExecutionResults(t)
+
+		registers, err := badgerstorage.NewRegisters(db)
+		require.NoError(t, err)
+
+		events, err := badgerstorage.NewEventIndex(db, nil)
+		require.NoError(t, err)
+
+		contract := unittest.RandomAddressFixture()
+
+		regA := flow.NewRegisterID("owner", "a")
+		regB := flow.NewRegisterID("owner", "b")
+
+		blocks := unittest.ChainFixtureFrom(3, unittest.BlockHeaderFixture())
+		rootHeight := blocks[0].Header.Height
+
+		err = registers.Bootstrap(rootHeight, flow.RegisterEntries{
+			{Key: regA, Value: []byte("a0")},
+			{Key: regB, Value: []byte("b0")},
+		})
+		require.NoError(t, err)
+
+		// registers updated in each block after the root block, in chunk order
+		updates := [][][]flow.RegisterEntry{
+			{
+				{{Key: regA, Value: []byte("a1")}},
+				{{Key: regA, Value: []byte("a2")}},
+			},
+			{
+				{{Key: regB, Value: []byte("b1")}},
+			},
+		}
+
+		executionDatas := make([]*execution_data.BlockExecutionData, 0, len(updates))
+		expectedEvents := make([]flow.BlockEvents, 0, len(updates))
+		for i, blockUpdates := range updates {
+			block := blocks[i+1]
+
+			// every chunk with register updates emits an event
+			blockEvents := flow.BlockEvents{
+				BlockID:        block.ID(),
+				BlockHeight:    block.Header.Height,
+				BlockTimestamp: block.Header.Timestamp,
+			}
+			chunks := make([]*execution_data.ChunkExecutionData, 0, len(blockUpdates))
+			for j, chunkUpdates := range blockUpdates {
+				event := unittest.TokensDepositedEventFixture(contract, unittest.RandomAddressFixture(), 100, unittest.IdentifierFixture(), uint32(j), 0)
+				blockEvents.Events = append(blockEvents.Events, event)
+
+				chunks = append(chunks, &execution_data.ChunkExecutionData{
+					Collection: &flow.Collection{},
+					Events:     flow.EventsList{event},
+					TrieUpdate: trieUpdateFixture(chunkUpdates),
+				})
+			}
+			expectedEvents = append(expectedEvents, blockEvents)
+			// a chunk without trie update, e.g. a chunk without any register changes
+			chunks = append(chunks, &execution_data.ChunkExecutionData{Collection: &flow.Collection{}})
+
+			executionData := &execution_data.BlockExecutionData{
+				BlockID:             block.ID(),
+				ChunkExecutionDatas: chunks,
+			}
+			executionDatas = append(executionDatas, executionData)
+
+			executionDataID, err := eds.AddExecutionData(ctx, executionData)
+			require.NoError(t, err)
+
+			result := unittest.ExecutionResultFixture(unittest.WithBlock(block))
+			result.ExecutionDataID = executionDataID
+			seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+
+			headers.On("ByBlockID", block.ID()).Return(block.Header, nil).Maybe()
+			headers.On("ByHeight", block.Header.Height).Return(block.Header, nil).Maybe()
+			seals.On("FinalizedSealForBlock", block.ID()).Return(seal, nil).Maybe()
+			results.On("ByID", seal.ResultID).Return(result, nil).Maybe()
+		}
+
+		indexer := New(zerolog.Nop(), registers, events, headers, seals, results, eds, rootHeight)
+
+		signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+		indexer.Start(signalerCtx)
+		unittest.RequireComponentsReadyBefore(t, time.Second, indexer)
+
+		for _, executionData := range executionDatas {
+			indexer.OnExecutionData(executionData)
+		}
+
+		// repeated notifications are ignored
+		indexer.OnExecutionData(executionDatas[0])
+
+		require.Eventually(t, func() bool {
+			latest, err := registers.LatestHeight()
+			require.NoError(t, err)
+			return latest == blocks[len(blocks)-1].Header.Height
+		}, time.Second, 10*time.Millisecond)
+
+		expected := map[uint64][]string{
+			rootHeight:     {"a0", "b0"},
+			rootHeight + 1: {"a2", "b0"},
+			rootHeight + 2: {"a2", "b1"},
+		}
+		for height, values := range expected {
+			value, err := registers.Get(regA, height)
+			require.NoError(t, err)
+			assert.Equal(t, flow.RegisterValue(values[0]), value)
+
+			value, err = registers.Get(regB, height)
+			require.NoError(t, err)
+			assert.Equal(t, flow.RegisterValue(values[1]), value)
+		}
+
+		// events are indexed from the first height after the root height
+		first, err := events.FirstHeight()
+		require.NoError(t, err)
+		assert.Equal(t, rootHeight+1, first)
+
+		indexedEvents, err := events.ByContractAddress(contract, rootHeight+1, rootHeight+2)
+		require.NoError(t, err)
+		require.Len(t, indexedEvents, len(expectedEvents))
+		for i, blockEvents := range indexedEvents {
+			assert.Equal(t, expectedEvents[i].BlockID, blockEvents.BlockID)
+			assert.Equal(t, expectedEvents[i].Events, blockEvents.Events)
+		}
+
+		cancel()
+		unittest.RequireComponentsDoneBefore(t, time.Second, indexer)
+	})
+}
+
+func trieUpdateFixture(entries []flow.RegisterEntry) *ledger.TrieUpdate {
+	update := &ledger.TrieUpdate{
+		RootHash: ledger.RootHash(unittest.StateCommitmentFixture()),
+	}
+	for _, entry := range entries {
+		key := state.RegisterIDToKey(entry.Key)
+		update.Paths = append(update.Paths, ledger.Path(unittest.StateCommitmentFixture()))
+		update.Payloads = append(update.Payloads, ledger.NewPayload(key, ledger.Value(entry.Value)))
+	}
+	return update
+}
@@ -0,0 +1,310 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+// TransactionErrorMessages provides the error messages of failed transactions, which are not part
+// of the execution data.
+type TransactionErrorMessages interface {
+	// ByBlockID returns the error message of each transaction of a sealed block, in execution order.
+	// The error messages of successful transactions are empty.
+	ByBlockID(ctx context.Context, blockID flow.Identifier) ([]string, error)
+}
+
+// ResultsIndexer indexes the collections, events and transaction results of every sealed block, so
+// transaction results and events can be served locally instead of being requested from execution
+// nodes or other access nodes. Unlike Indexer, it does not index registers, so it does not need to
+// be bootstrapped from a checkpoint.
+//
+// The collections, events and transactions are taken from the execution data of the block. Only the
+// error messages of the failed transactions are not part of the execution data, and are requested
+// once for each block from TransactionErrorMessages.
+//
+// Like Indexer, it is notified about newly available execution data using OnExecutionData, and
+// indexes every height after the latest indexed height in consecutive height order. The latest
+// indexed height is persisted, so indexing resumes where it stopped after restarts.
+type ResultsIndexer struct {
+	component.Component
+
+	log                zerolog.Logger
+	db                 *badger.DB
+	headers            storage.Headers
+	blocks             storage.Blocks
+	collections        storage.Collections
+	events             storage.Events
+	transactionResults storage.TransactionResults
+	seals              storage.Seals
+	results            storage.ExecutionResults
+	execDataStore      execution_data.ExecutionDataStore
+	errorMessages      TransactionErrorMessages
+	progress           storage.ConsumerProgress
+
+	notifier engine.Notifier
+
+	// firstHeight is the first height indexed.
+	firstHeight uint64
+
+	// indexedHeight is the latest indexed height, which is below firstHeight until the first
+	// height is indexed.
+	indexedHeight *atomic.Uint64
+
+	// highestHeight contains the highest consecutive block height for which execution data is
+	// available in the local execution data store.
+	highestHeight *atomic.Uint64
+}
+
+// NewResultsIndexer creates a new results indexer, which indexes the heights after the given
+// initial height the first time it is started. highestAvailableHeight is the highest height for
+// which execution data is available when the indexer is created.
+// No errors are expected during normal operation.
+func NewResultsIndexer(
+	log zerolog.Logger,
+	db *badger.DB,
+	headers storage.Headers,
+	blocks storage.Blocks,
+	collections storage.Collections,
+	events storage.Events,
+	transactionResults storage.TransactionResults,
+	seals storage.Seals,
+	results storage.ExecutionResults,
+	execDataStore execution_data.ExecutionDataStore,
+	errorMessages TransactionErrorMessages,
+	progress storage.ConsumerProgress,
+	initialHeight uint64,
+	highestAvailableHeight uint64,
+) (*ResultsIndexer, error) {
+	err := progress.InitProcessedIndex(initialHeight)
+	if err != nil && !errors.Is(err, storage.ErrAlreadyExists) {
+		return nil, fmt.Errorf("could not initialize indexed height: %w", err)
+	}
+
+	indexedHeight, err := progress.ProcessedIndex()
+	if err != nil {
+		return nil, fmt.Errorf("could not get indexed height: %w", err)
+	}
+
+	i := &ResultsIndexer{
+		log:                log.With().Str("component", "execution_results_indexer").Logger(),
+		db:                 db,
+		headers:            headers,
+		blocks:             blocks,
+		collections:        collections,
+		events:             events,
+		transactionResults: transactionResults,
+		seals:              seals,
+		results:            results,
+		execDataStore:      execDataStore,
+		errorMessages:      errorMessages,
+		progress:           progress,
+		notifier:           engine.NewNotifier(),
+		firstHeight:        initialHeight + 1,
+		indexedHeight:      atomic.NewUint64(indexedHeight),
+		highestHeight:      atomic.NewUint64(highestAvailableHeight),
+	}
+
+	i.Component = component.NewComponentManagerBuilder().
+		AddWorker(i.processLoop).
+		Build()
+
+	return i, nil
+}
+
+// FirstHeight returns the first height indexed.
+func (i *ResultsIndexer) FirstHeight() uint64 {
+	return i.firstHeight
+}
+
+// LatestHeight returns the latest indexed height, which is below the first height until the first
+// height is indexed.
+func (i *ResultsIndexer) LatestHeight() uint64 {
+	return i.indexedHeight.Load()
+}
+
+// OnExecutionData is called to notify the indexer that execution data is available for a new block.
+// It is called by the execution data requester for each sealed block in consecutive height order,
+// and is non-blocking.
+func (i *ResultsIndexer) OnExecutionData(executionData *execution_data.BlockExecutionData) {
+	header, err := i.headers.ByBlockID(executionData.BlockID)
+	if err != nil {
+		// if the execution data is available, the block must be locally finalized
+		i.log.Fatal().Err(err).Msg("could not get header for execution data")
+		return
+	}
+
+	for {
+		current := i.highestHeight.Load()
+		if header.Height <= current {
+			return
+		}
+		if i.highestHeight.CAS(current, header.Height) {
+			i.notifier.Notify()
+			return
+		}
+	}
+}
+
+// processLoop indexes new heights whenever new execution data is available.
+func (i *ResultsIndexer) processLoop(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	// index any heights that were downloaded while the indexer was not running
+	i.notifier.Notify()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-i.notifier.Channel():
+		}
+
+		err := i.indexAvailable(ctx)
+		if err != nil {
+			ctx.Throw(err)
+			return
+		}
+	}
+}
+
+// indexAvailable indexes all heights with available execution data after the latest indexed height.
+// No errors are expected during normal operation.
+func (i *ResultsIndexer) indexAvailable(ctx context.Context) error {
+	for height := i.indexedHeight.Load() + 1; height <= i.highestHeight.Load(); height++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		header, executionData, err := sealedExecutionData(ctx, i.headers, i.seals, i.results, i.execDataStore, height)
+		if err != nil {
+			// the execution data should be available locally at this point, but it is safe to
+			// retry on the next notification.
+			if errors.Is(err, storage.ErrNotFound) || execution_data.IsBlobNotFoundError(err) {
+				i.log.Warn().Err(err).Uint64("height", height).Msg("execution data not available for indexing")
+				return nil
+			}
+			return fmt.Errorf("could not get execution data for height %d: %w", height, err)
+		}
+
+		// the error messages are requested from other nodes, so failures are retried on the next
+		// notification
+		errorMessages, err := i.errorMessages.ByBlockID(ctx, header.ID())
+		if err == nil && len(errorMessages) != transactionCount(executionData) {
+			err = fmt.Errorf("got %d error messages for %d transactions", len(errorMessages), transactionCount(executionData))
+		}
+		if err != nil {
+			i.log.Warn().Err(err).Uint64("height", height).Msg("transaction error messages not available for indexing")
+			return nil
+		}
+
+		err = i.indexBlockData(header, executionData, errorMessages)
+		if err != nil {
+			return fmt.Errorf("could not index execution data for height %d: %w", height, err)
+		}
+
+		err = i.progress.SetProcessedIndex(height)
+		if err != nil {
+			return fmt.Errorf("could not update indexed height to %d: %w", height, err)
+		}
+		i.indexedHeight.Store(height)
+	}
+
+	return nil
+}
+
+// indexBlockData stores the collections, events and transaction results of the block, given the
+// error message of each of its transactions in execution order. The data is stored idempotently, so a block that was
+// indexed before a crash is indexed again.
+// No errors are expected during normal operation.
+func (i *ResultsIndexer) indexBlockData(header *flow.Header, executionData *execution_data.BlockExecutionData, errorMessages []string) error {
+	blockID := header.ID()
+
+	collectionIDs := make([]flow.Identifier, 0, len(executionData.ChunkExecutionDatas))
+	events := make([]flow.EventsList, 0, len(executionData.ChunkExecutionDatas))
+	var transactionResults []flow.TransactionResult
+
+	systemChunkIndex := len(executionData.ChunkExecutionDatas) - 1
+	for index, chunk := range executionData.ChunkExecutionDatas {
+		events = append(events, chunk.Events)
+
+		if chunk.Collection == nil {
+			continue
+		}
+
+		for _, tx := range chunk.Collection.Transactions {
+			transactionResults = append(transactionResults, flow.TransactionResult{
+				TransactionID: tx.ID(),
+			})
+		}
+
+		// the system collection is the same for every block, so it is not indexed, like on
+		// access nodes which never receive it
+		if index != systemChunkIndex {
+			light := chunk.Collection.Light()
+			err := i.collections.StoreLightAndIndexByTransaction(&light)
+			if err != nil && !errors.Is(err, storage.ErrAlreadyExists) {
+				return fmt.Errorf("could not store collection %v: %w", light.ID(), err)
+			}
+			collectionIDs = append(collectionIDs, light.ID())
+		}
+	}
+
+	for j, errorMessage := range errorMessages {
+		transactionResults[j].ErrorMessage = errorMessage
+	}
+
+	err := i.blocks.IndexBlockForCollections(blockID, collectionIDs)
+	if err != nil {
+		return fmt.Errorf("could not index block for collections: %w", err)
+	}
+
+	batch := bstorage.NewBatch(i.db)
+
+	err = i.events.BatchStore(blockID, events, batch)
+	if err != nil {
+		return fmt.Errorf("could not add events to batch: %w", err)
+	}
+
+	err = i.transactionResults.BatchStore(blockID, transactionResults, batch)
+	if err != nil {
+		return fmt.Errorf("could not add transaction results to batch: %w", err)
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not store events and transaction results: %w", err)
+	}
+
+	i.log.Debug().
+		Uint64("height", header.Height).
+		Int("collection_count", len(collectionIDs)).
+		Int("transaction_count", len(transactionResults)).
+		Msg("indexed execution results")
+
+	return nil
+}
+
+// transactionCount returns the number of transactions of the block, including the system
+// transaction.
+func transactionCount(executionData *execution_data.BlockExecutionData) int {
+	count := 0
+	for _, chunk := range executionData.ChunkExecutionDatas {
+		if chunk.Collection != nil {
+			count += len(chunk.Collection.Transactions)
+		}
+	}
+	return count
+}
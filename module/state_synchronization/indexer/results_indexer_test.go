@@ -0,0 +1,177 @@
+package indexer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/blobs"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestResultsIndexer(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		bs := blobs.NewBlobstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+		eds := execution_data.NewExecutionDataStore(bs, execution_data.DefaultSerializer)
+
+		headers := storagemock.NewHeaders(t)
+		seals := storagemock.NewSeals(t)
+		results := storagemock.NewExecutionResults(t)
+
+		all := badgerstorage.InitAll(metrics.NewNoopCollector(), db)
+		progress := badgerstorage.NewConsumerProgress(db, module.ConsumeProgressResultsIndexerBlockHeight)
+
+		blocks := unittest.ChainFixtureFrom(3, unittest.BlockHeaderFixture())
+		rootHeight := blocks[0].Header.Height
+
+		collections := make([]flow.Collection, 0, len(blocks)-1)
+		systemCollections := make([]flow.Collection, 0, len(blocks)-1)
+		executionDatas := make([]*execution_data.BlockExecutionData, 0, len(blocks)-1)
+		for _, block := range blocks[1:] {
+			collection := unittest.CollectionFixture(2)
+			systemCollection := unittest.CollectionFixture(1)
+			collections = append(collections, collection)
+			systemCollections = append(systemCollections, systemCollection)
+
+			chunk := &execution_data.ChunkExecutionData{
+				Collection: &collection,
+				Events: flow.EventsList{
+					unittest.EventFixture(flow.EventAccountCreated, 0, 0, collection.Transactions[0].ID(), 0),
+					unittest.EventFixture(flow.EventAccountUpdated, 1, 0, collection.Transactions[1].ID(), 0),
+				},
+			}
+			systemChunk := &execution_data.ChunkExecutionData{
+				Collection: &systemCollection,
+				Events: flow.EventsList{
+					unittest.EventFixture(flow.EventAccountUpdated, 0, 0, systemCollection.Transactions[0].ID(), 0),
+				},
+			}
+
+			executionData := &execution_data.BlockExecutionData{
+				BlockID:             block.ID(),
+				ChunkExecutionDatas: []*execution_data.ChunkExecutionData{chunk, systemChunk},
+			}
+			executionDatas = append(executionDatas, executionData)
+
+			executionDataID, err := eds.AddExecutionData(ctx, executionData)
+			require.NoError(t, err)
+
+			result := unittest.ExecutionResultFixture(unittest.WithBlock(block))
+			result.ExecutionDataID = executionDataID
+			seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+
+			require.NoError(t, all.Blocks.Store(block))
+			headers.On("ByBlockID", block.ID()).Return(block.Header, nil).Maybe()
+			headers.On("ByHeight", block.Header.Height).Return(block.Header, nil).Maybe()
+			seals.On("FinalizedSealForBlock", block.ID()).Return(seal, nil).Maybe()
+			results.On("ByID", seal.ResultID).Return(result, nil).Maybe()
+		}
+
+		newIndexer := func() *ResultsIndexer {
+			indexer, err := NewResultsIndexer(
+				zerolog.Nop(),
+				db,
+				headers,
+				all.Blocks,
+				all.Collections,
+				all.Events,
+				all.TransactionResults,
+				seals,
+				results,
+				eds,
+				// the second transaction of every block failed
+				errorMessagesFunc(func(context.Context, flow.Identifier) ([]string, error) {
+					return []string{"", "failed", ""}, nil
+				}),
+				progress,
+				rootHeight,
+				rootHeight,
+			)
+			require.NoError(t, err)
+			return indexer
+		}
+
+		indexer := newIndexer()
+		assert.Equal(t, rootHeight+1, indexer.FirstHeight())
+		assert.Equal(t, rootHeight, indexer.LatestHeight())
+
+		signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+		indexer.Start(signalerCtx)
+		unittest.RequireComponentsReadyBefore(t, time.Second, indexer)
+
+		for _, executionData := range executionDatas {
+			indexer.OnExecutionData(executionData)
+		}
+
+		latestHeight := blocks[len(blocks)-1].Header.Height
+		require.Eventually(t, func() bool {
+			return indexer.LatestHeight() == latestHeight
+		}, time.Second, 10*time.Millisecond)
+
+		for i, executionData := range executionDatas {
+			block := blocks[i+1]
+			collection := collections[i]
+
+			// transactions can be looked up by ID
+			txID := collection.Transactions[0].ID()
+			light, err := all.Collections.LightByTransactionID(txID)
+			require.NoError(t, err)
+			assert.Equal(t, collection.ID(), light.ID())
+
+			indexedBlock, err := all.Blocks.ByCollectionID(collection.ID())
+			require.NoError(t, err)
+			assert.Equal(t, block.ID(), indexedBlock.ID())
+
+			// but not the system transaction
+			_, err = all.Collections.LightByTransactionID(systemCollections[i].Transactions[0].ID())
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+
+			events, err := all.Events.ByBlockID(block.ID())
+			require.NoError(t, err)
+			expected := append(flow.EventsList{}, executionData.ChunkExecutionDatas[0].Events...)
+			expected = append(expected, executionData.ChunkExecutionDatas[1].Events...)
+			assert.ElementsMatch(t, []flow.Event(expected), events)
+
+			transactionResults, err := all.TransactionResults.ByBlockID(block.ID())
+			require.NoError(t, err)
+			require.Len(t, transactionResults, 3)
+			assert.Equal(t, collection.Transactions[0].ID(), transactionResults[0].TransactionID)
+			assert.Empty(t, transactionResults[0].ErrorMessage)
+			assert.Equal(t, "failed", transactionResults[1].ErrorMessage)
+			assert.Equal(t, systemCollections[i].Transactions[0].ID(), transactionResults[2].TransactionID)
+		}
+
+		cancel()
+		unittest.RequireComponentsDoneBefore(t, time.Second, indexer)
+
+		// indexing resumes from the persisted height after restarts
+		restarted := newIndexer()
+		assert.Equal(t, rootHeight+1, restarted.FirstHeight())
+		assert.Equal(t, latestHeight, restarted.LatestHeight())
+	})
+}
+
+// errorMessagesFunc implements TransactionErrorMessages with a function.
+type errorMessagesFunc func(ctx context.Context, blockID flow.Identifier) ([]string, error)
+
+func (f errorMessagesFunc) ByBlockID(ctx context.Context, blockID flow.Identifier) ([]string, error) {
+	return f(ctx, blockID)
+}
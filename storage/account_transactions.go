@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// AccountTransactions indexes the transactions of finalized blocks by the accounts that
+// participated in them as proposer, payer or authorizer.
+type AccountTransactions interface {
+	// Store indexes the transactions, which are included in the finalized block at the given
+	// height, for all accounts that participated in them. Storing a transaction again is a no-op.
+	// No errors are expected during normal operation.
+	Store(height uint64, transactions []*flow.TransactionBody) error
+
+	// ByAddress returns up to limit transactions the account participated in, in descending
+	// order of block height and transaction ID, starting at the cursor (inclusive), or at the
+	// most recent transaction if the cursor is nil.
+	// No errors are expected during normal operation, even if no transactions are found.
+	ByAddress(address flow.Address, cursor *flow.AccountTransactionCursor, limit uint) ([]flow.AccountTransaction, error)
+}
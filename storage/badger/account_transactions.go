@@ -0,0 +1,63 @@
+package badger
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// AccountTransactions implements storage.AccountTransactions on top of badger. Entries are keyed by
+// address, block height and transaction ID, so the transactions of an account can be read from the
+// most recent one with a single reverse iteration.
+type AccountTransactions struct {
+	db *badger.DB
+}
+
+var _ storage.AccountTransactions = (*AccountTransactions)(nil)
+
+func NewAccountTransactions(db *badger.DB) *AccountTransactions {
+	return &AccountTransactions{
+		db: db,
+	}
+}
+
+// Store indexes the transactions, which are included in the finalized block at the given height,
+// for all accounts that participated in them. Storing a transaction again is a no-op.
+// No errors are expected during normal operation.
+func (a *AccountTransactions) Store(height uint64, transactions []*flow.TransactionBody) error {
+	writeBatch := a.db.NewWriteBatch()
+	defer writeBatch.Cancel()
+
+	for _, tx := range transactions {
+		for _, accountTx := range flow.AccountTransactionsForTransaction(tx, height) {
+			err := operation.BatchIndexAccountTransaction(accountTx)(writeBatch)
+			if err != nil {
+				return fmt.Errorf("could not add account transaction to batch: %w", err)
+			}
+		}
+	}
+
+	err := writeBatch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not index account transactions at height %d: %w", height, err)
+	}
+
+	return nil
+}
+
+// ByAddress returns up to limit transactions the account participated in, in descending order of
+// block height and transaction ID, starting at the cursor (inclusive), or at the most recent
+// transaction if the cursor is nil.
+// No errors are expected during normal operation, even if no transactions are found.
+func (a *AccountTransactions) ByAddress(address flow.Address, cursor *flow.AccountTransactionCursor, limit uint) ([]flow.AccountTransaction, error) {
+	var accountTxs []flow.AccountTransaction
+	err := a.db.View(operation.LookupAccountTransactions(address, cursor, limit, &accountTxs))
+	if err != nil {
+		return nil, fmt.Errorf("could not look up transactions of account %s: %w", address, err)
+	}
+	return accountTxs, nil
+}
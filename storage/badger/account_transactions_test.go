@@ -0,0 +1,60 @@
+package badger_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+func TestAccountTransactionsStoreByAddress(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		accountTxs := badgerstorage.NewAccountTransactions(db)
+
+		account := unittest.RandomAddressFixture()
+		payer := unittest.RandomAddressFixture()
+
+		transaction := func() *flow.TransactionBody {
+			tx := unittest.TransactionBodyFixture()
+			tx.ProposalKey.Address = account
+			tx.Payer = payer
+			tx.Authorizers = []flow.Address{account}
+			return &tx
+		}
+
+		tx1, tx2, tx3 := transaction(), transaction(), transaction()
+		require.NoError(t, accountTxs.Store(10, []*flow.TransactionBody{tx1}))
+		require.NoError(t, accountTxs.Store(11, []*flow.TransactionBody{tx2, tx3}))
+
+		// storing again is a no-op
+		require.NoError(t, accountTxs.Store(10, []*flow.TransactionBody{tx1}))
+
+		results, err := accountTxs.ByAddress(account, nil, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, uint64(11), results[0].BlockHeight)
+		assert.Equal(t, uint64(11), results[1].BlockHeight)
+		assert.Equal(t, flow.AccountTransaction{
+			Address:       account,
+			BlockHeight:   10,
+			TransactionID: tx1.ID(),
+			Roles:         flow.TransactionRoleProposer | flow.TransactionRoleAuthorizer,
+		}, results[2])
+
+		// pages continue at the cursor
+		page, err := accountTxs.ByAddress(payer, nil, 2)
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+
+		next, err := accountTxs.ByAddress(payer, &flow.AccountTransactionCursor{BlockHeight: 10, TransactionID: tx1.ID()}, 2)
+		require.NoError(t, err)
+		require.Len(t, next, 1)
+		assert.Equal(t, flow.TransactionRolePayer, next[0].Roles)
+	})
+}
@@ -0,0 +1,318 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dgraph-io/badger/v2"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/model/convert"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// EventIndex implements storage.EventIndex on top of badger. Events are stored in the same format
+// as by storage.Events, and every secondary index entry is keyed by the indexed value followed by
+// the height of the block, so a height range can be read with a single iteration.
+//
+// Since every indexed field adds an entry per event, only the fields of the configured event types
+// are indexed by value. Fields added to the configuration are only indexed for blocks stored
+// afterwards.
+//
+// Store must not be called concurrently, but all other methods are safe for concurrent use.
+type EventIndex struct {
+	db            *badger.DB
+	indexedFields IndexedEventFields
+	bootstrapped  *atomic.Bool
+	firstHeight   *atomic.Uint64
+	latestHeight  *atomic.Uint64
+}
+
+var _ storage.EventIndex = (*EventIndex)(nil)
+
+// IndexedEventFields are the names of the fields indexed by value, by event type.
+type IndexedEventFields map[flow.EventType]map[string]struct{}
+
+// ParseIndexedEventFields parses event fields given as the event type followed by the field name,
+// e.g. "A.1654653399040a61.FlowToken.TokensDeposited.to".
+func ParseIndexedEventFields(fields []string) (IndexedEventFields, error) {
+	indexed := make(IndexedEventFields)
+	for _, field := range fields {
+		i := strings.LastIndex(field, ".")
+		if i <= 0 || i == len(field)-1 {
+			return nil, fmt.Errorf("invalid event field %q, expected the event type followed by the field name", field)
+		}
+
+		eventType := flow.EventType(field[:i])
+		if _, ok := indexed[eventType]; !ok {
+			indexed[eventType] = make(map[string]struct{})
+		}
+		indexed[eventType][field[i+1:]] = struct{}{}
+	}
+	return indexed, nil
+}
+
+// isIndexed returns true if the field of the event type is indexed by value.
+func (f IndexedEventFields) isIndexed(eventType flow.EventType, field string) bool {
+	_, ok := f[eventType][field]
+	return ok
+}
+
+// NewEventIndex creates an event index using the given database, loading the indexed height range
+// if blocks were already indexed. Only the given fields are indexed by value.
+// No errors are expected during normal operation.
+func NewEventIndex(db *badger.DB, indexedFields IndexedEventFields) (*EventIndex, error) {
+	e := &EventIndex{
+		db:            db,
+		indexedFields: indexedFields,
+		bootstrapped:  atomic.NewBool(false),
+		firstHeight:   atomic.NewUint64(0),
+		latestHeight:  atomic.NewUint64(0),
+	}
+
+	var firstHeight, latestHeight uint64
+	err := db.View(func(tx *badger.Txn) error {
+		err := operation.RetrieveEventFirstHeight(&firstHeight)(tx)
+		if err != nil {
+			return err
+		}
+		return operation.RetrieveEventLatestHeight(&latestHeight)(tx)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return e, nil
+		}
+		return nil, fmt.Errorf("could not retrieve indexed event heights: %w", err)
+	}
+
+	e.firstHeight.Store(firstHeight)
+	e.latestHeight.Store(latestHeight)
+	e.bootstrapped.Store(true)
+
+	return e, nil
+}
+
+// FirstHeight returns the height of the first block indexed.
+// Expected errors:
+// - storage.ErrNotBootstrapped if no block has been indexed yet
+func (e *EventIndex) FirstHeight() (uint64, error) {
+	if !e.bootstrapped.Load() {
+		return 0, storage.ErrNotBootstrapped
+	}
+	return e.firstHeight.Load(), nil
+}
+
+// LatestHeight returns the height of the last block indexed.
+// Expected errors:
+// - storage.ErrNotBootstrapped if no block has been indexed yet
+func (e *EventIndex) LatestHeight() (uint64, error) {
+	if !e.bootstrapped.Load() {
+		return 0, storage.ErrNotBootstrapped
+	}
+	return e.latestHeight.Load(), nil
+}
+
+// Store stores and indexes the events of the given block, whose height must be the height after
+// the latest indexed height, unless no block has been indexed yet.
+// No errors are expected during normal operation.
+func (e *EventIndex) Store(header *flow.Header, events []flow.Event) error {
+	bootstrapped := e.bootstrapped.Load()
+	if bootstrapped {
+		latestHeight := e.latestHeight.Load()
+		if header.Height != latestHeight+1 {
+			return fmt.Errorf("must index events for consecutive heights, expected height %d, got %d", latestHeight+1, header.Height)
+		}
+	}
+
+	err := e.storeEvents(header, events)
+	if err != nil {
+		return err
+	}
+
+	// the latest height is updated after all events are written, so readers never observe a
+	// partially indexed height. Entries written for a height that was not marked as indexed
+	// before a crash are overwritten when the height is indexed again.
+	err = operation.RetryOnConflict(e.db.Update, func(tx *badger.Txn) error {
+		if bootstrapped {
+			return operation.UpdateEventLatestHeight(header.Height)(tx)
+		}
+		err := operation.InsertEventFirstHeight(header.Height)(tx)
+		if err != nil {
+			return err
+		}
+		return operation.InsertEventLatestHeight(header.Height)(tx)
+	})
+	if err != nil {
+		return fmt.Errorf("could not update indexed event heights: %w", err)
+	}
+
+	if !bootstrapped {
+		e.firstHeight.Store(header.Height)
+	}
+	e.latestHeight.Store(header.Height)
+	e.bootstrapped.Store(true)
+
+	return nil
+}
+
+// ByTransactionID returns the events emitted by the transaction in the blocks within the given
+// height range, grouped by block in ascending height order.
+// Expected errors:
+// - storage.ErrHeightNotIndexed if the range is not within the indexed range
+// - storage.ErrNotBootstrapped if no block has been indexed yet
+func (e *EventIndex) ByTransactionID(txID flow.Identifier, startHeight, endHeight uint64) ([]flow.BlockEvents, error) {
+	return e.lookup(startHeight, endHeight, func(locators *[]operation.EventLocator) func(*badger.Txn) error {
+		return operation.LookupEventsByTransaction(txID, startHeight, endHeight, locators)
+	})
+}
+
+// ByContractAddress returns the events emitted by contracts deployed to the given address in the
+// blocks within the given height range, grouped by block in ascending height order.
+// Expected errors:
+// - storage.ErrHeightNotIndexed if the range is not within the indexed range
+// - storage.ErrNotBootstrapped if no block has been indexed yet
+func (e *EventIndex) ByContractAddress(address flow.Address, startHeight, endHeight uint64) ([]flow.BlockEvents, error) {
+	return e.lookup(startHeight, endHeight, func(locators *[]operation.EventLocator) func(*badger.Txn) error {
+		return operation.LookupEventsByContract(address, startHeight, endHeight, locators)
+	})
+}
+
+// ByFieldValue returns the events of the given type whose field has the given value in the blocks
+// within the given height range, grouped by block in ascending height order.
+// Expected errors:
+// - storage.ErrFieldNotIndexed if the field of the event type is not indexed
+// - storage.ErrHeightNotIndexed if the range is not within the indexed range
+// - storage.ErrNotBootstrapped if no block has been indexed yet
+func (e *EventIndex) ByFieldValue(eventType flow.EventType, field string, value string, startHeight, endHeight uint64) ([]flow.BlockEvents, error) {
+	if !e.indexedFields.isIndexed(eventType, field) {
+		return nil, fmt.Errorf("field %s of event type %s is not indexed: %w", field, eventType, storage.ErrFieldNotIndexed)
+	}
+
+	return e.lookup(startHeight, endHeight, func(locators *[]operation.EventLocator) func(*badger.Txn) error {
+		return operation.LookupEventsByFieldValue(eventType, field, value, startHeight, endHeight, locators)
+	})
+}
+
+// lookup reads the event locators found by the given lookup operation and returns the events they
+// point to, grouped by block.
+func (e *EventIndex) lookup(
+	startHeight uint64,
+	endHeight uint64,
+	lookupLocators func(*[]operation.EventLocator) func(*badger.Txn) error,
+) ([]flow.BlockEvents, error) {
+	if !e.bootstrapped.Load() {
+		return nil, storage.ErrNotBootstrapped
+	}
+
+	firstHeight := e.firstHeight.Load()
+	latestHeight := e.latestHeight.Load()
+	if startHeight > endHeight || startHeight < firstHeight || endHeight > latestHeight {
+		return nil, fmt.Errorf("height range [%d, %d] is outside the indexed range [%d, %d]: %w",
+			startHeight, endHeight, firstHeight, latestHeight, storage.ErrHeightNotIndexed)
+	}
+
+	var results []flow.BlockEvents
+	err := e.db.View(func(tx *badger.Txn) error {
+		var locators []operation.EventLocator
+		err := lookupLocators(&locators)(tx)
+		if err != nil {
+			return fmt.Errorf("could not look up event locators: %w", err)
+		}
+
+		for _, locator := range locators {
+			var event flow.Event
+			err := operation.RetrieveEventByLocator(locator, &event)(tx)
+			if err != nil {
+				return fmt.Errorf("could not retrieve event %d of transaction %v: %w", locator.EventIndex, locator.TransactionID, err)
+			}
+
+			if len(results) == 0 || results[len(results)-1].BlockID != locator.BlockID {
+				results = append(results, flow.BlockEvents{
+					BlockID:        locator.BlockID,
+					BlockHeight:    locator.BlockHeight,
+					BlockTimestamp: locator.BlockTimestamp,
+				})
+			}
+			last := &results[len(results)-1]
+			last.Events = append(last.Events, event)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// storeEvents writes the events of the block and their index entries.
+// No errors are expected during normal operation.
+func (e *EventIndex) storeEvents(header *flow.Header, events []flow.Event) error {
+	blockID := header.ID()
+
+	writeBatch := e.db.NewWriteBatch()
+	defer writeBatch.Cancel()
+
+	for _, event := range events {
+		locator := operation.EventLocator{
+			BlockID:          blockID,
+			BlockHeight:      header.Height,
+			BlockTimestamp:   header.Timestamp,
+			TransactionID:    event.TransactionID,
+			TransactionIndex: event.TransactionIndex,
+			EventIndex:       event.EventIndex,
+		}
+
+		err := operation.BatchInsertEvent(blockID, event)(writeBatch)
+		if err != nil {
+			return fmt.Errorf("could not add event to batch: %w", err)
+		}
+
+		err = operation.BatchIndexEventByTransaction(locator)(writeBatch)
+		if err != nil {
+			return fmt.Errorf("could not add event transaction index entry to batch: %w", err)
+		}
+
+		address, ok := convert.EventContractAddress(event.Type)
+		if ok {
+			err = operation.BatchIndexEventByContract(address, locator)(writeBatch)
+			if err != nil {
+				return fmt.Errorf("could not add event contract index entry to batch: %w", err)
+			}
+		}
+
+		fields, ok := e.indexedFields[event.Type]
+		if !ok {
+			continue
+		}
+
+		// events whose payload cannot be decoded are still stored and indexed by transaction and
+		// contract, they are only excluded from the field index
+		values, err := convert.EventFieldValues(event)
+		if err != nil {
+			continue
+		}
+
+		for field := range fields {
+			value, ok := values[field]
+			if !ok {
+				continue
+			}
+			err = operation.BatchIndexEventByFieldValue(event.Type, field, value, locator)(writeBatch)
+			if err != nil {
+				return fmt.Errorf("could not add event field index entry to batch: %w", err)
+			}
+		}
+	}
+
+	err := writeBatch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not store events at height %d: %w", header.Height, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package badger_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+func TestEventIndexStoreQuery(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		contract := unittest.RandomAddressFixture()
+		recipient := unittest.RandomAddressFixture()
+		other := unittest.RandomAddressFixture()
+
+		// only the recipient of deposits is indexed by value
+		depositType := unittest.TokensDepositedEventFixture(contract, recipient, 100, unittest.IdentifierFixture(), 0, 0).Type
+		indexedFields, err := badgerstorage.ParseIndexedEventFields([]string{string(depositType) + ".to"})
+		require.NoError(t, err)
+
+		index, err := badgerstorage.NewEventIndex(db, indexedFields)
+		require.NoError(t, err)
+
+		// reads fail before the first block is indexed
+		_, err = index.LatestHeight()
+		require.ErrorIs(t, err, storage.ErrNotBootstrapped)
+		_, err = index.ByContractAddress(contract, 10, 10)
+		require.ErrorIs(t, err, storage.ErrNotBootstrapped)
+
+		headers := make([]*flow.Header, 3)
+		txIDs := make([]flow.Identifier, 3)
+		for i := range headers {
+			headers[i] = unittest.BlockHeaderFixture(unittest.WithHeaderHeight(uint64(10 + i)))
+			txIDs[i] = unittest.IdentifierFixture()
+		}
+
+		deposit := func(i int, to flow.Address, eventIndex uint32) flow.Event {
+			return unittest.TokensDepositedEventFixture(contract, to, 100, txIDs[i], 0, eventIndex)
+		}
+
+		blockEvents := [][]flow.Event{
+			{deposit(0, recipient, 0), deposit(0, other, 1)},
+			{unittest.EventFixture(flow.EventAccountCreated, 0, 0, txIDs[1], 0)},
+			{deposit(2, recipient, 0)},
+		}
+
+		err = index.Store(headers[0], blockEvents[0])
+		require.NoError(t, err)
+
+		// heights must be stored consecutively
+		err = index.Store(headers[2], blockEvents[2])
+		require.Error(t, err)
+
+		err = index.Store(headers[1], blockEvents[1])
+		require.NoError(t, err)
+		err = index.Store(headers[2], blockEvents[2])
+		require.NoError(t, err)
+
+		first, err := index.FirstHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), first)
+
+		latest, err := index.LatestHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(12), latest)
+
+		expected := func(i int, events ...flow.Event) flow.BlockEvents {
+			return flow.BlockEvents{
+				BlockID:        headers[i].ID(),
+				BlockHeight:    headers[i].Height,
+				BlockTimestamp: headers[i].Timestamp,
+				Events:         events,
+			}
+		}
+
+		t.Run("by transaction", func(t *testing.T) {
+			results, err := index.ByTransactionID(txIDs[0], 10, 12)
+			require.NoError(t, err)
+			assertBlockEvents(t, []flow.BlockEvents{expected(0, blockEvents[0]...)}, results)
+
+			results, err = index.ByTransactionID(txIDs[1], 10, 12)
+			require.NoError(t, err)
+			assertBlockEvents(t, []flow.BlockEvents{expected(1, blockEvents[1]...)}, results)
+		})
+
+		t.Run("by contract address", func(t *testing.T) {
+			results, err := index.ByContractAddress(contract, 10, 12)
+			require.NoError(t, err)
+			assertBlockEvents(t, []flow.BlockEvents{
+				expected(0, blockEvents[0]...),
+				expected(2, blockEvents[2]...),
+			}, results)
+
+			results, err = index.ByContractAddress(contract, 11, 11)
+			require.NoError(t, err)
+			assert.Empty(t, results)
+		})
+
+		t.Run("by field value", func(t *testing.T) {
+			eventType := blockEvents[0][0].Type
+
+			results, err := index.ByFieldValue(eventType, "to", recipient.Hex(), 10, 12)
+			require.NoError(t, err)
+			assertBlockEvents(t, []flow.BlockEvents{
+				expected(0, blockEvents[0][0]),
+				expected(2, blockEvents[2]...),
+			}, results)
+
+			results, err = index.ByFieldValue(eventType, "to", other.Hex(), 11, 12)
+			require.NoError(t, err)
+			assert.Empty(t, results)
+
+			// fields which are not configured are not indexed
+			_, err = index.ByFieldValue(eventType, "amount", "0.00000100", 10, 12)
+			assert.ErrorIs(t, err, storage.ErrFieldNotIndexed)
+
+			var locators []operation.EventLocator
+			err = db.View(operation.LookupEventsByFieldValue(eventType, "amount", "0.00000100", 10, 12, &locators))
+			require.NoError(t, err)
+			assert.Empty(t, locators)
+		})
+
+		t.Run("outside the indexed range", func(t *testing.T) {
+			_, err := index.ByTransactionID(txIDs[0], 9, 12)
+			assert.ErrorIs(t, err, storage.ErrHeightNotIndexed)
+			_, err = index.ByTransactionID(txIDs[0], 10, 13)
+			assert.ErrorIs(t, err, storage.ErrHeightNotIndexed)
+		})
+
+		// the indexed heights are loaded when the index is reopened
+		reopened, err := badgerstorage.NewEventIndex(db, indexedFields)
+		require.NoError(t, err)
+
+		first, err = reopened.FirstHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), first)
+
+		latest, err = reopened.LatestHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(12), latest)
+	})
+}
+
+func TestParseIndexedEventFields(t *testing.T) {
+	fields, err := badgerstorage.ParseIndexedEventFields([]string{
+		"A.1654653399040a61.FlowToken.TokensDeposited.to",
+		"A.1654653399040a61.FlowToken.TokensDeposited.amount",
+		"flow.AccountCreated.address",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, badgerstorage.IndexedEventFields{
+		"A.1654653399040a61.FlowToken.TokensDeposited": {"to": {}, "amount": {}},
+		"flow.AccountCreated":                          {"address": {}},
+	}, fields)
+
+	for _, invalid := range []string{"", "to", ".to", "flow.AccountCreated."} {
+		_, err := badgerstorage.ParseIndexedEventFields([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+// assertBlockEvents compares block events, ignoring the location of the block timestamps, which
+// is not preserved by the encoding.
+func assertBlockEvents(t *testing.T, expected []flow.BlockEvents, actual []flow.BlockEvents) {
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.True(t, expected[i].BlockTimestamp.Equal(actual[i].BlockTimestamp))
+		actual[i].BlockTimestamp = expected[i].BlockTimestamp
+	}
+	assert.Equal(t, expected, actual)
+}
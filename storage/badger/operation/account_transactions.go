@@ -0,0 +1,40 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// BatchIndexAccountTransaction indexes the transaction for an account that participated in it.
+// If the entry already exists in the database it will be overridden.
+// No errors are expected during normal operation.
+func BatchIndexAccountTransaction(accountTx flow.AccountTransaction) func(*badger.WriteBatch) error {
+	return batchWrite(makePrefix(codeAccountTransaction, accountTx.Address, accountTx.BlockHeight, accountTx.TransactionID), accountTx)
+}
+
+// LookupAccountTransactions retrieves up to limit transactions the account participated in, in
+// descending order of block height and transaction ID, starting at the cursor (inclusive), or at
+// the most recent transaction if the cursor is nil.
+// No errors are expected during normal operation, even if no transactions are found.
+func LookupAccountTransactions(address flow.Address, cursor *flow.AccountTransactionCursor, limit uint, accountTxs *[]flow.AccountTransaction) func(*badger.Txn) error {
+	var start []byte
+	if cursor != nil {
+		start = makePrefix(codeAccountTransaction, address, cursor.BlockHeight, cursor.TransactionID)
+	}
+
+	return traverseDescending(makePrefix(codeAccountTransaction, address), start, limit, func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var val flow.AccountTransaction
+		create := func() interface{} {
+			return &val
+		}
+		handle := func() error {
+			*accountTxs = append(*accountTxs, val)
+			return nil
+		}
+		return check, create, handle
+	})
+}
@@ -0,0 +1,60 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestAccountTransactionsIndexLookup(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		address := unittest.RandomAddressFixture()
+		other := unittest.RandomAddressFixture()
+
+		// transactions at heights 10, 11 and 12, indexed in random order
+		accountTxs := make([]flow.AccountTransaction, 0, 3)
+		for height := uint64(10); height < 13; height++ {
+			accountTxs = append(accountTxs, flow.AccountTransaction{
+				Address:       address,
+				BlockHeight:   height,
+				TransactionID: unittest.IdentifierFixture(),
+				Roles:         flow.TransactionRolePayer,
+			})
+		}
+
+		writeBatch := db.NewWriteBatch()
+		for _, i := range []int{1, 2, 0} {
+			require.NoError(t, BatchIndexAccountTransaction(accountTxs[i])(writeBatch))
+		}
+		// a transaction of another account
+		require.NoError(t, BatchIndexAccountTransaction(flow.AccountTransaction{
+			Address:       other,
+			BlockHeight:   20,
+			TransactionID: unittest.IdentifierFixture(),
+		})(writeBatch))
+		require.NoError(t, writeBatch.Flush())
+
+		// the most recent transactions are returned first
+		var actual []flow.AccountTransaction
+		err := db.View(LookupAccountTransactions(address, nil, 2, &actual))
+		require.NoError(t, err)
+		assert.Equal(t, []flow.AccountTransaction{accountTxs[2], accountTxs[1]}, actual)
+
+		// the cursor is inclusive
+		actual = nil
+		cursor := &flow.AccountTransactionCursor{BlockHeight: 11, TransactionID: accountTxs[1].TransactionID}
+		err = db.View(LookupAccountTransactions(address, cursor, 10, &actual))
+		require.NoError(t, err)
+		assert.Equal(t, []flow.AccountTransaction{accountTxs[1], accountTxs[0]}, actual)
+
+		actual = nil
+		err = db.View(LookupAccountTransactions(unittest.RandomAddressFixture(), nil, 10, &actual))
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+}
@@ -503,3 +503,102 @@ func Fail(err error) func(*badger.Txn) error {
 		return err
 	}
 }
+
+// traverseDescending iterates over the keys with the given prefix in descending order, starting
+// at the greatest key that is less than or equal to the start key, or at the greatest key with the
+// prefix if the start key is nil. Iteration stops after the given number of entities were handled.
+// No errors are expected during normal operation, even if no entries are matched.
+func traverseDescending(prefix []byte, start []byte, limit uint, iteration iterationFunc) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		if len(prefix) == 0 {
+			return fmt.Errorf("prefix must not be empty")
+		}
+
+		options := badger.DefaultIteratorOptions
+		options.Prefix = prefix
+		options.Reverse = true
+
+		// seeking in reverse returns the first key that is less than or equal to the seek key, so
+		// appending 0xff bytes up to the maximum key length includes all keys with the prefix
+		if start == nil {
+			start = make([]byte, 0, max)
+			start = append(start, prefix...)
+			for uint32(len(start)) < max {
+				start = append(start, 0xff)
+			}
+		}
+
+		it := tx.NewIterator(options)
+		defer it.Close()
+
+		handled := uint(0)
+		for it.Seek(start); it.Valid() && handled < limit; it.Next() {
+			item := it.Item()
+
+			check, create, handle := iteration()
+			if !check(item.Key()) {
+				continue
+			}
+
+			err := item.Value(func(val []byte) error {
+				entity := create()
+				err := msgpack.Unmarshal(val, entity)
+				if err != nil {
+					return fmt.Errorf("could not decode entity: %w", err)
+				}
+
+				err = handle()
+				if err != nil {
+					return fmt.Errorf("could not handle entity: %w", err)
+				}
+
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("could not process value: %w", err)
+			}
+
+			handled++
+		}
+
+		return nil
+	}
+}
+
+// findHighestAtOrBelow finds the entity stored under the key with the given prefix and the highest
+// height suffix that is at or below the given height, and decodes it into the given entity. All keys
+// with the given prefix must be the prefix followed by the big-endian encoded uint64 height.
+// Error returns:
+//   - storage.ErrNotFound if no key with the prefix and a height at or below the given height exists
+//   - generic error in case of unexpected failure from the database layer, or failure
+//     to decode an existing database value
+func findHighestAtOrBelow(prefix []byte, height uint64, entity interface{}) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		options := badger.DefaultIteratorOptions
+		options.Prefix = prefix
+		options.Reverse = true
+		options.PrefetchValues = false
+
+		it := tx.NewIterator(options)
+		defer it.Close()
+
+		// seeking in reverse returns the first key that is less than or equal to the seek key
+		seekKey := make([]byte, 0, len(prefix)+8)
+		seekKey = append(seekKey, prefix...)
+		seekKey = append(seekKey, b(height)...)
+
+		it.Seek(seekKey)
+		if !it.Valid() {
+			return storage.ErrNotFound
+		}
+
+		err := it.Item().Value(func(val []byte) error {
+			return msgpack.Unmarshal(val, entity)
+		})
+		if err != nil {
+			return fmt.Errorf("could not decode entity: %w", err)
+		}
+
+		return nil
+	}
+}
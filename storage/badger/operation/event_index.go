@@ -0,0 +1,126 @@
+package operation
+
+import (
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// EventLocator is the value of an event index entry. It identifies the event in the event storage,
+// and holds the block information needed to group the events of a query result by block.
+type EventLocator struct {
+	BlockID          flow.Identifier
+	BlockHeight      uint64
+	BlockTimestamp   time.Time
+	TransactionID    flow.Identifier
+	TransactionIndex uint32
+	EventIndex       uint32
+}
+
+// eventFieldValueID returns the key component identifying the value of a field of an event type.
+// The type and field are length-prefixed, so different combinations never collide before hashing.
+func eventFieldValueID(eventType flow.EventType, field string, value string) flow.Identifier {
+	return flow.MakeIDFromFingerPrint(makePrefix(codeEventByFieldValue,
+		uint32(len(eventType)), string(eventType), uint32(len(field)), field, value))
+}
+
+// BatchIndexEventByTransaction indexes the event by the transaction that emitted it.
+// No errors are expected during normal operation.
+func BatchIndexEventByTransaction(locator EventLocator) func(*badger.WriteBatch) error {
+	return batchWrite(makePrefix(codeEventByTransaction, locator.TransactionID,
+		locator.BlockHeight, locator.TransactionIndex, locator.EventIndex), locator)
+}
+
+// BatchIndexEventByContract indexes the event by the address of the contract that emitted it.
+// No errors are expected during normal operation.
+func BatchIndexEventByContract(address flow.Address, locator EventLocator) func(*badger.WriteBatch) error {
+	return batchWrite(makePrefix(codeEventByContract, address,
+		locator.BlockHeight, locator.TransactionIndex, locator.EventIndex), locator)
+}
+
+// BatchIndexEventByFieldValue indexes the event by the value of one of its fields.
+// No errors are expected during normal operation.
+func BatchIndexEventByFieldValue(eventType flow.EventType, field string, value string, locator EventLocator) func(*badger.WriteBatch) error {
+	return batchWrite(makePrefix(codeEventByFieldValue, eventFieldValueID(eventType, field, value),
+		locator.BlockHeight, locator.TransactionIndex, locator.EventIndex), locator)
+}
+
+// LookupEventsByTransaction retrieves the locators of the events emitted by the transaction in the
+// blocks within the given height range, in ascending order.
+// No errors are expected during normal operation, even if no events are found.
+func LookupEventsByTransaction(txID flow.Identifier, startHeight, endHeight uint64, locators *[]EventLocator) func(*badger.Txn) error {
+	return iterate(
+		makePrefix(codeEventByTransaction, txID, startHeight),
+		makePrefix(codeEventByTransaction, txID, endHeight),
+		eventLocatorIterationFunc(locators))
+}
+
+// LookupEventsByContract retrieves the locators of the events emitted by contracts deployed to the
+// address in the blocks within the given height range, in ascending order.
+// No errors are expected during normal operation, even if no events are found.
+func LookupEventsByContract(address flow.Address, startHeight, endHeight uint64, locators *[]EventLocator) func(*badger.Txn) error {
+	return iterate(
+		makePrefix(codeEventByContract, address, startHeight),
+		makePrefix(codeEventByContract, address, endHeight),
+		eventLocatorIterationFunc(locators))
+}
+
+// LookupEventsByFieldValue retrieves the locators of the events of the given type whose field has
+// the value in the blocks within the given height range, in ascending order.
+// No errors are expected during normal operation, even if no events are found.
+func LookupEventsByFieldValue(eventType flow.EventType, field string, value string, startHeight, endHeight uint64, locators *[]EventLocator) func(*badger.Txn) error {
+	id := eventFieldValueID(eventType, field, value)
+	return iterate(
+		makePrefix(codeEventByFieldValue, id, startHeight),
+		makePrefix(codeEventByFieldValue, id, endHeight),
+		eventLocatorIterationFunc(locators))
+}
+
+// RetrieveEventByLocator retrieves the event identified by the locator.
+// Error returns:
+//   - storage.ErrNotFound if the event is not stored
+func RetrieveEventByLocator(locator EventLocator, event *flow.Event) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeEvent, locator.BlockID, locator.TransactionID,
+		locator.TransactionIndex, locator.EventIndex), event)
+}
+
+func InsertEventFirstHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeEventFirstHeight), height)
+}
+
+func RetrieveEventFirstHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeEventFirstHeight), height)
+}
+
+func InsertEventLatestHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeEventLatestHeight), height)
+}
+
+func UpdateEventLatestHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codeEventLatestHeight), height)
+}
+
+func RetrieveEventLatestHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeEventLatestHeight), height)
+}
+
+// eventLocatorIterationFunc returns an iteration function which collects all event locators found
+// during iteration.
+func eventLocatorIterationFunc(locators *[]EventLocator) func() (checkFunc, createFunc, handleFunc) {
+	return func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var val EventLocator
+		create := func() interface{} {
+			return &val
+		}
+		handle := func() error {
+			*locators = append(*locators, val)
+			return nil
+		}
+		return check, create, handle
+	}
+}
@@ -0,0 +1,72 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestEventIndexInsertLookup(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		txID := unittest.IdentifierFixture()
+		address := unittest.RandomAddressFixture()
+		eventType := flow.EventType("A." + address.Hex() + ".FlowToken.TokensDeposited")
+
+		locators := make([]EventLocator, 0, 3)
+		writeBatch := db.NewWriteBatch()
+		for height := uint64(10); height < 13; height++ {
+			locator := EventLocator{
+				BlockID:       unittest.IdentifierFixture(),
+				BlockHeight:   height,
+				TransactionID: txID,
+				EventIndex:    uint32(height),
+			}
+			locators = append(locators, locator)
+
+			require.NoError(t, BatchIndexEventByTransaction(locator)(writeBatch))
+			require.NoError(t, BatchIndexEventByContract(address, locator)(writeBatch))
+			require.NoError(t, BatchIndexEventByFieldValue(eventType, "to", "01", locator)(writeBatch))
+		}
+		require.NoError(t, writeBatch.Flush())
+
+		// the end height is inclusive
+		var actual []EventLocator
+		err := db.View(LookupEventsByTransaction(txID, 11, 12, &actual))
+		require.NoError(t, err)
+		assert.Equal(t, locators[1:], actual)
+
+		actual = nil
+		err = db.View(LookupEventsByContract(address, 10, 10, &actual))
+		require.NoError(t, err)
+		assert.Equal(t, locators[:1], actual)
+
+		actual = nil
+		err = db.View(LookupEventsByFieldValue(eventType, "to", "01", 0, 100, &actual))
+		require.NoError(t, err)
+		assert.Equal(t, locators, actual)
+
+		// other values of the same field are not matched
+		actual = nil
+		err = db.View(LookupEventsByFieldValue(eventType, "to", "02", 0, 100, &actual))
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+
+		// the locator points to the event stored by block ID
+		event := unittest.EventFixture(eventType, 0, locators[0].EventIndex, txID, 0)
+		require.NoError(t, db.Update(InsertEvent(locators[0].BlockID, event)))
+
+		var retrieved flow.Event
+		err = db.View(RetrieveEventByLocator(locators[0], &retrieved))
+		require.NoError(t, err)
+		assert.Equal(t, event, retrieved)
+
+		err = db.View(RetrieveEventByLocator(locators[1], &retrieved))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
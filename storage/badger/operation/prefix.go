@@ -33,6 +33,10 @@ const (
 	codeExecutedBlock           = 23 // latest executed block with max height
 	codeRootHeight              = 24 // the height of the highest block contained in the root snapshot
 	codeLastCompleteBlockHeight = 25 // the height of the last block for which all collections were received
+	codeRegisterFirstHeight     = 26 // the height at which the register index was bootstrapped
+	codeRegisterLatestHeight    = 27 // the height of the last block for which all register updates were indexed
+	codeEventFirstHeight        = 28 // the height of the first block for which events were indexed
+	codeEventLatestHeight       = 29 // the height of the last block for which events were indexed
 
 	// codes for single entity storage
 	// 31 was used for identities before epochs
@@ -84,6 +88,13 @@ const (
 	codeJobQueue             = 71
 	codeJobQueuePointer      = 72
 
+	// codes for indexed execution state
+	codeRegister           = 80 // register values, keyed by register ID and the height at which they were set
+	codeEventByTransaction = 81 // event locators, keyed by the emitting transaction and the height of the block
+	codeEventByContract    = 82 // event locators, keyed by the emitting contract's address and the height of the block
+	codeEventByFieldValue  = 83 // event locators, keyed by the event type, field and value and the height of the block
+	codeAccountTransaction = 84 // transactions accounts participated in, keyed by address, block height and transaction ID
+
 	// legacy codes (should be cleaned up)
 	codeChunkDataPack                = 100
 	codeCommit                       = 101
@@ -133,6 +144,8 @@ func b(v interface{}) []byte {
 		return []byte{byte(i)}
 	case flow.Identifier:
 		return i[:]
+	case flow.Address:
+		return i[:]
 	case flow.ChainID:
 		return []byte(i)
 	default:
@@ -0,0 +1,48 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// registerPrefix returns the key prefix shared by all values of the given register. The owner and
+// key are length-prefixed, so the prefix of one register is never the prefix of another register.
+func registerPrefix(id flow.RegisterID) []byte {
+	return makePrefix(codeRegister, uint32(len(id.Owner)), id.Owner, uint32(len(id.Key)), id.Key)
+}
+
+// BatchInsertRegister stores the value the register was set to at the given height.
+// If the value already exists in the database it will be overridden.
+// No errors are expected during normal operation.
+func BatchInsertRegister(height uint64, id flow.RegisterID, value flow.RegisterValue) func(*badger.WriteBatch) error {
+	return batchWrite(append(registerPrefix(id), b(height)...), value)
+}
+
+// RetrieveRegister retrieves the value of the register at the given height, which is the value it
+// was last set to at or below the height.
+// Error returns:
+//   - storage.ErrNotFound if the register was never set at or below the given height
+func RetrieveRegister(height uint64, id flow.RegisterID, value *flow.RegisterValue) func(*badger.Txn) error {
+	return findHighestAtOrBelow(registerPrefix(id), height, value)
+}
+
+func InsertRegisterFirstHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeRegisterFirstHeight), height)
+}
+
+func RetrieveRegisterFirstHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeRegisterFirstHeight), height)
+}
+
+func InsertRegisterLatestHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeRegisterLatestHeight), height)
+}
+
+func UpdateRegisterLatestHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codeRegisterLatestHeight), height)
+}
+
+func RetrieveRegisterLatestHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeRegisterLatestHeight), height)
+}
@@ -0,0 +1,67 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestRegisterInsertRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		id := flow.NewRegisterID("owner", "key")
+		// a register whose key has the other register's key as a prefix
+		longer := flow.NewRegisterID("owner", "key2")
+
+		writeBatch := db.NewWriteBatch()
+		require.NoError(t, BatchInsertRegister(10, id, []byte("v10"))(writeBatch))
+		require.NoError(t, BatchInsertRegister(20, id, []byte("v20"))(writeBatch))
+		require.NoError(t, BatchInsertRegister(15, longer, []byte("other"))(writeBatch))
+		require.NoError(t, writeBatch.Flush())
+
+		var value flow.RegisterValue
+
+		err := db.View(RetrieveRegister(9, id, &value))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		for height, expected := range map[uint64]string{10: "v10", 15: "v10", 19: "v10", 20: "v20", 100: "v20"} {
+			err = db.View(RetrieveRegister(height, id, &value))
+			require.NoError(t, err)
+			assert.Equal(t, flow.RegisterValue(expected), value, "unexpected value at height %d", height)
+		}
+
+		err = db.View(RetrieveRegister(14, longer, &value))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		err = db.View(RetrieveRegister(15, longer, &value))
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("other"), value)
+
+		err = db.View(RetrieveRegister(100, flow.NewRegisterID("owner", "unknown"), &value))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
+
+func TestRegisterHeightsInsertUpdateRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		var first, latest uint64
+
+		err := db.View(RetrieveRegisterFirstHeight(&first))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		require.NoError(t, db.Update(InsertRegisterFirstHeight(10)))
+		require.NoError(t, db.Update(InsertRegisterLatestHeight(10)))
+		require.NoError(t, db.Update(UpdateRegisterLatestHeight(11)))
+
+		require.NoError(t, db.View(RetrieveRegisterFirstHeight(&first)))
+		require.NoError(t, db.View(RetrieveRegisterLatestHeight(&latest)))
+
+		assert.Equal(t, uint64(10), first)
+		assert.Equal(t, uint64(11), latest)
+	})
+}
@@ -0,0 +1,208 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// Registers implements storage.RegisterIndex on top of badger. Register values are stored under
+// the register ID and the height at which they were set, so that the value at any height can be
+// found by looking up the last value set at or below that height.
+//
+// Store must not be called concurrently, but all other methods are safe for concurrent use.
+type Registers struct {
+	db           *badger.DB
+	bootstrapped *atomic.Bool
+	firstHeight  *atomic.Uint64
+	latestHeight *atomic.Uint64
+}
+
+var _ storage.RegisterIndex = (*Registers)(nil)
+
+// NewRegisters creates a register index using the given database, loading the indexed height
+// range if the index was already bootstrapped.
+// No errors are expected during normal operation.
+func NewRegisters(db *badger.DB) (*Registers, error) {
+	r := &Registers{
+		db:           db,
+		bootstrapped: atomic.NewBool(false),
+		firstHeight:  atomic.NewUint64(0),
+		latestHeight: atomic.NewUint64(0),
+	}
+
+	var firstHeight, latestHeight uint64
+	err := db.View(func(tx *badger.Txn) error {
+		err := operation.RetrieveRegisterFirstHeight(&firstHeight)(tx)
+		if err != nil {
+			return err
+		}
+		return operation.RetrieveRegisterLatestHeight(&latestHeight)(tx)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return r, nil
+		}
+		return nil, fmt.Errorf("could not retrieve indexed register heights: %w", err)
+	}
+
+	r.firstHeight.Store(firstHeight)
+	r.latestHeight.Store(latestHeight)
+	r.bootstrapped.Store(true)
+
+	return r, nil
+}
+
+// Get returns the value of the register at the given height. Registers that were never set
+// have an empty value.
+// Expected errors:
+// - storage.ErrHeightNotIndexed if the height is outside the indexed range
+// - storage.ErrNotBootstrapped if the index has not been bootstrapped
+func (r *Registers) Get(id flow.RegisterID, height uint64) (flow.RegisterValue, error) {
+	if !r.bootstrapped.Load() {
+		return nil, storage.ErrNotBootstrapped
+	}
+
+	if height < r.firstHeight.Load() || height > r.latestHeight.Load() {
+		return nil, fmt.Errorf("height %d is outside the indexed range [%d, %d]: %w",
+			height, r.firstHeight.Load(), r.latestHeight.Load(), storage.ErrHeightNotIndexed)
+	}
+
+	var value flow.RegisterValue
+	err := r.db.View(operation.RetrieveRegister(height, id, &value))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not retrieve register %s at height %d: %w", id, height, err)
+	}
+
+	return value, nil
+}
+
+// FirstHeight returns the height at which the index was bootstrapped.
+// Expected errors:
+// - storage.ErrNotBootstrapped if the index has not been bootstrapped
+func (r *Registers) FirstHeight() (uint64, error) {
+	if !r.bootstrapped.Load() {
+		return 0, storage.ErrNotBootstrapped
+	}
+	return r.firstHeight.Load(), nil
+}
+
+// LatestHeight returns the height of the last block indexed.
+// Expected errors:
+// - storage.ErrNotBootstrapped if the index has not been bootstrapped
+func (r *Registers) LatestHeight() (uint64, error) {
+	if !r.bootstrapped.Load() {
+		return 0, storage.ErrNotBootstrapped
+	}
+	return r.latestHeight.Load(), nil
+}
+
+// Bootstrap stores the full set of registers of the execution state at the given height, which
+// becomes the first indexed height. Registers previously stored at the same height with
+// StoreBootstrapEntries are part of the bootstrapped execution state.
+// Expected errors:
+// - storage.ErrAlreadyExists if the index was already bootstrapped
+func (r *Registers) Bootstrap(height uint64, entries flow.RegisterEntries) error {
+	if r.bootstrapped.Load() {
+		return storage.ErrAlreadyExists
+	}
+
+	err := r.storeEntries(height, entries)
+	if err != nil {
+		return err
+	}
+
+	err = operation.RetryOnConflict(r.db.Update, func(tx *badger.Txn) error {
+		err := operation.InsertRegisterFirstHeight(height)(tx)
+		if err != nil {
+			return err
+		}
+		return operation.InsertRegisterLatestHeight(height)(tx)
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert indexed register heights: %w", err)
+	}
+
+	r.firstHeight.Store(height)
+	r.latestHeight.Store(height)
+	r.bootstrapped.Store(true)
+
+	return nil
+}
+
+// StoreBootstrapEntries stores part of the registers of the execution state at the given height
+// without bootstrapping the index, so an execution state too large to be held in memory can be
+// stored in batches before Bootstrap is called with the last batch. Since the index is only marked
+// as bootstrapped by Bootstrap, registers stored before a crash are overwritten when bootstrapping
+// again.
+// Expected errors:
+// - storage.ErrAlreadyExists if the index was already bootstrapped
+func (r *Registers) StoreBootstrapEntries(height uint64, entries flow.RegisterEntries) error {
+	if r.bootstrapped.Load() {
+		return storage.ErrAlreadyExists
+	}
+
+	return r.storeEntries(height, entries)
+}
+
+// Store stores the register updates of the block at the given height, which must be the
+// height after the latest indexed height.
+// Expected errors:
+// - storage.ErrNotBootstrapped if the index has not been bootstrapped
+func (r *Registers) Store(entries flow.RegisterEntries, height uint64) error {
+	if !r.bootstrapped.Load() {
+		return storage.ErrNotBootstrapped
+	}
+
+	latestHeight := r.latestHeight.Load()
+	if height != latestHeight+1 {
+		return fmt.Errorf("must store registers for consecutive heights, expected height %d, got %d", latestHeight+1, height)
+	}
+
+	err := r.storeEntries(height, entries)
+	if err != nil {
+		return err
+	}
+
+	// the latest height is updated after all values are written, so readers never observe a
+	// partially indexed height. Values written for a height that was not marked as indexed
+	// before a crash are overwritten when the height is indexed again.
+	err = operation.RetryOnConflict(r.db.Update, operation.UpdateRegisterLatestHeight(height))
+	if err != nil {
+		return fmt.Errorf("could not update latest indexed register height: %w", err)
+	}
+
+	r.latestHeight.Store(height)
+
+	return nil
+}
+
+// storeEntries writes the register values set at the given height.
+// No errors are expected during normal operation.
+func (r *Registers) storeEntries(height uint64, entries flow.RegisterEntries) error {
+	writeBatch := r.db.NewWriteBatch()
+	defer writeBatch.Cancel()
+
+	for _, entry := range entries {
+		err := operation.BatchInsertRegister(height, entry.Key, entry.Value)(writeBatch)
+		if err != nil {
+			return fmt.Errorf("could not add register %s to batch: %w", entry.Key, err)
+		}
+	}
+
+	err := writeBatch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not store registers at height %d: %w", height, err)
+	}
+
+	return nil
+}
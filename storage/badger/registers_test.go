@@ -0,0 +1,101 @@
+package badger_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+func TestRegistersBootstrapStoreGet(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		registers, err := badgerstorage.NewRegisters(db)
+		require.NoError(t, err)
+
+		id1 := flow.NewRegisterID("owner", "key1")
+		id2 := flow.NewRegisterID("owner", "key2")
+		unset := flow.NewRegisterID("owner", "unset")
+
+		// reads fail before bootstrapping
+		_, err = registers.Get(id1, 10)
+		require.ErrorIs(t, err, storage.ErrNotBootstrapped)
+		_, err = registers.LatestHeight()
+		require.ErrorIs(t, err, storage.ErrNotBootstrapped)
+		err = registers.Store(nil, 11)
+		require.ErrorIs(t, err, storage.ErrNotBootstrapped)
+
+		// the execution state is bootstrapped in batches, and only becomes readable once bootstrapped
+		err = registers.StoreBootstrapEntries(10, flow.RegisterEntries{{Key: id1, Value: []byte("a")}})
+		require.NoError(t, err)
+		_, err = registers.Get(id1, 10)
+		require.ErrorIs(t, err, storage.ErrNotBootstrapped)
+
+		err = registers.Bootstrap(10, flow.RegisterEntries{{Key: id2, Value: []byte("b")}})
+		require.NoError(t, err)
+
+		err = registers.Bootstrap(10, nil)
+		require.ErrorIs(t, err, storage.ErrAlreadyExists)
+		err = registers.StoreBootstrapEntries(10, nil)
+		require.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+		// heights must be stored consecutively
+		err = registers.Store(flow.RegisterEntries{{Key: id1, Value: []byte("c")}}, 12)
+		require.Error(t, err)
+
+		err = registers.Store(flow.RegisterEntries{{Key: id1, Value: []byte("c")}}, 11)
+		require.NoError(t, err)
+		err = registers.Store(flow.RegisterEntries{{Key: id2, Value: []byte("d")}}, 12)
+		require.NoError(t, err)
+
+		first, err := registers.FirstHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), first)
+
+		latest, err := registers.LatestHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(12), latest)
+
+		expected := map[uint64][]string{
+			10: {"a", "b"},
+			11: {"c", "b"},
+			12: {"c", "d"},
+		}
+		for height, values := range expected {
+			value, err := registers.Get(id1, height)
+			require.NoError(t, err)
+			assert.Equal(t, flow.RegisterValue(values[0]), value)
+
+			value, err = registers.Get(id2, height)
+			require.NoError(t, err)
+			assert.Equal(t, flow.RegisterValue(values[1]), value)
+
+			value, err = registers.Get(unset, height)
+			require.NoError(t, err)
+			assert.Empty(t, value)
+		}
+
+		_, err = registers.Get(id1, 9)
+		require.ErrorIs(t, err, storage.ErrHeightNotIndexed)
+		_, err = registers.Get(id1, 13)
+		require.ErrorIs(t, err, storage.ErrHeightNotIndexed)
+
+		// the indexed range is loaded when the index is reopened
+		reopened, err := badgerstorage.NewRegisters(db)
+		require.NoError(t, err)
+
+		latest, err = reopened.LatestHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(12), latest)
+
+		value, err := reopened.Get(id1, 11)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("c"), value)
+	})
+}
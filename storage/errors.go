@@ -14,4 +14,14 @@ var (
 
 	ErrAlreadyExists = errors.New("key already exists")
 	ErrDataMismatch  = errors.New("data for key is different")
+
+	// ErrHeightNotIndexed is returned when data for a height outside the indexed range is requested.
+	ErrHeightNotIndexed = errors.New("data for block height not indexed")
+
+	// ErrNotBootstrapped is returned when data is requested from an index that was not bootstrapped.
+	ErrNotBootstrapped = errors.New("index not bootstrapped")
+
+	// ErrFieldNotIndexed is returned when events are requested by the value of a field that is not
+	// indexed.
+	ErrFieldNotIndexed = errors.New("event field not indexed")
 )
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// EventIndex stores the events of every indexed block, and indexes them by the transaction that
+// emitted them, by the address of the contract that emitted them and by the values of a configured
+// set of their fields, so events can be queried across a range of heights.
+type EventIndex interface {
+	// FirstHeight returns the height of the first block indexed.
+	// Expected errors:
+	// - storage.ErrNotBootstrapped if no block has been indexed yet
+	FirstHeight() (uint64, error)
+
+	// LatestHeight returns the height of the last block indexed.
+	// Expected errors:
+	// - storage.ErrNotBootstrapped if no block has been indexed yet
+	LatestHeight() (uint64, error)
+
+	// Store stores and indexes the events of the given block, whose height must be the height
+	// after the latest indexed height, unless no block has been indexed yet.
+	// No errors are expected during normal operation.
+	Store(header *flow.Header, events []flow.Event) error
+
+	// ByTransactionID returns the events emitted by the transaction in the blocks within the
+	// given height range, grouped by block in ascending height order.
+	// Expected errors:
+	// - storage.ErrHeightNotIndexed if the range is not within the indexed range
+	// - storage.ErrNotBootstrapped if no block has been indexed yet
+	ByTransactionID(txID flow.Identifier, startHeight, endHeight uint64) ([]flow.BlockEvents, error)
+
+	// ByContractAddress returns the events emitted by contracts deployed to the given address in
+	// the blocks within the given height range, grouped by block in ascending height order.
+	// Expected errors:
+	// - storage.ErrHeightNotIndexed if the range is not within the indexed range
+	// - storage.ErrNotBootstrapped if no block has been indexed yet
+	ByContractAddress(address flow.Address, startHeight, endHeight uint64) ([]flow.BlockEvents, error)
+
+	// ByFieldValue returns the events of the given type whose field has the given value in the
+	// blocks within the given height range, grouped by block in ascending height order. Values
+	// are compared by their string representation, see convert.EventFieldValues.
+	// Expected errors:
+	// - storage.ErrFieldNotIndexed if the field of the event type is not indexed
+	// - storage.ErrHeightNotIndexed if the range is not within the indexed range
+	// - storage.ErrNotBootstrapped if no block has been indexed yet
+	ByFieldValue(eventType flow.EventType, field string, value string, startHeight, endHeight uint64) ([]flow.BlockEvents, error)
+}
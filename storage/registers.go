@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// RegisterIndex stores the values of execution state registers at every block height at which
+// they were updated, so the execution state can be read at any height within the indexed range.
+type RegisterIndex interface {
+	// Get returns the value of the register at the given height. Registers that were never set
+	// have an empty value.
+	// Expected errors:
+	// - storage.ErrHeightNotIndexed if the height is outside the indexed range
+	// - storage.ErrNotBootstrapped if the index has not been bootstrapped
+	Get(ID flow.RegisterID, height uint64) (flow.RegisterValue, error)
+
+	// FirstHeight returns the height at which the index was bootstrapped.
+	// Expected errors:
+	// - storage.ErrNotBootstrapped if the index has not been bootstrapped
+	FirstHeight() (uint64, error)
+
+	// LatestHeight returns the height of the last block indexed.
+	// Expected errors:
+	// - storage.ErrNotBootstrapped if the index has not been bootstrapped
+	LatestHeight() (uint64, error)
+
+	// Bootstrap stores the full set of registers of the execution state at the given height, which
+	// becomes the first indexed height. Registers previously stored at the same height with
+	// StoreBootstrapEntries are part of the bootstrapped execution state.
+	// Expected errors:
+	// - storage.ErrAlreadyExists if the index was already bootstrapped
+	Bootstrap(height uint64, entries flow.RegisterEntries) error
+
+	// StoreBootstrapEntries stores part of the registers of the execution state at the given height
+	// without bootstrapping the index, so an execution state too large to be held in memory can be
+	// stored in batches before Bootstrap is called with the last batch.
+	// Expected errors:
+	// - storage.ErrAlreadyExists if the index was already bootstrapped
+	StoreBootstrapEntries(height uint64, entries flow.RegisterEntries) error
+
+	// Store stores the register updates of the block at the given height, which must be the
+	// height after the latest indexed height.
+	// Expected errors:
+	// - storage.ErrNotBootstrapped if the index has not been bootstrapped
+	Store(entries flow.RegisterEntries, height uint64) error
+}
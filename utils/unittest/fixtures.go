@@ -14,6 +14,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
 
 	sdk "github.com/onflow/flow-go-sdk"
 
@@ -1571,6 +1573,34 @@ func EventFixture(eType flow.EventType, transactionIndex uint32, eventIndex uint
 	}
 }
 
+// TokensDepositedEventFixture returns a JSON-CDC encoded TokensDeposited event of the token
+// contract deployed to the given address, for a deposit of the amount to the recipient.
+func TokensDepositedEventFixture(contract flow.Address, recipient flow.Address, amount uint64, txID flow.Identifier, transactionIndex uint32, eventIndex uint32) flow.Event {
+	location := common.NewAddressLocation(nil, common.Address(contract), "FlowToken")
+	payload, err := jsoncdc.Encode(cadence.NewEvent([]cadence.Value{
+		cadence.UFix64(amount),
+		cadence.NewOptional(cadence.NewAddress(recipient)),
+	}).WithType(&cadence.EventType{
+		Location:            location,
+		QualifiedIdentifier: "FlowToken.TokensDeposited",
+		Fields: []cadence.Field{
+			{Identifier: "amount", Type: cadence.UFix64Type{}},
+			{Identifier: "to", Type: cadence.OptionalType{Type: cadence.AddressType{}}},
+		},
+	}))
+	if err != nil {
+		panic(err)
+	}
+
+	return flow.Event{
+		Type:             flow.EventType(location.TypeID(nil, "FlowToken.TokensDeposited")),
+		TransactionID:    txID,
+		TransactionIndex: transactionIndex,
+		EventIndex:       eventIndex,
+		Payload:          payload,
+	}
+}
+
 func EmulatorRootKey() (*flow.AccountPrivateKey, error) {
 
 	// TODO seems this key literal doesn't decode anymore